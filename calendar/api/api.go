@@ -28,6 +28,7 @@ func Init(h *httputils.Handler, env engine.Env, notificationProcessor engine.Not
 
 	notificationRouter := h.Router.PathPrefix(config.PathNotification).Subrouter()
 	notificationRouter.HandleFunc(config.PathEvent, api.notification).Methods(http.MethodPost)
+	notificationRouter.HandleFunc(config.PathLifecycle, api.notification).Methods(http.MethodPost)
 
 	postActionRouter := h.Router.PathPrefix(config.PathPostAction).Subrouter()
 	postActionRouter.HandleFunc(config.PathAccept, api.postActionAccept).Methods(http.MethodPost)
@@ -35,6 +36,16 @@ func Init(h *httputils.Handler, env engine.Env, notificationProcessor engine.Not
 	postActionRouter.HandleFunc(config.PathTentative, api.postActionTentative).Methods(http.MethodPost)
 	postActionRouter.HandleFunc(config.PathRespond, api.postActionRespond).Methods(http.MethodPost)
 	postActionRouter.HandleFunc(config.PathConfirmStatusChange, api.postActionConfirmStatusChange).Methods(http.MethodPost)
+	postActionRouter.HandleFunc(config.PathImport, api.postActionImportCalendar).Methods(http.MethodPost)
+	postActionRouter.HandleFunc(config.PathDeclineWithNote, api.postActionDeclineWithNote).Methods(http.MethodPost)
+	postActionRouter.HandleFunc(config.PathProposeNewTime, api.postActionProposeNewTime).Methods(http.MethodPost)
+	postActionRouter.HandleFunc(config.PathRespondWithComment, api.postActionRespondWithComment).Methods(http.MethodPost)
+	postActionRouter.HandleFunc(config.PathNudge, api.postActionNudge).Methods(http.MethodPost)
+
+	submitDialogRouter := h.Router.PathPrefix(config.PathDialogs).Subrouter()
+	submitDialogRouter.HandleFunc(config.PathRespond, api.submitResponseDialog).Methods(http.MethodPost)
+
+	h.Router.HandleFunc(config.PathFreeBusy+"/{token}", api.freeBusyFeed).Methods(http.MethodGet)
 
 	dialogRouter := h.Router.PathPrefix(config.PathAutocomplete).Subrouter()
 	dialogRouter.HandleFunc(config.PathUsers, api.autocompleteConnectedUsers)
@@ -42,7 +53,10 @@ func Init(h *httputils.Handler, env engine.Env, notificationProcessor engine.Not
 	apiRoutes := h.Router.PathPrefix(config.InternalAPIPath).Subrouter()
 	eventsRouter := apiRoutes.PathPrefix(config.PathEvents).Subrouter()
 	eventsRouter.HandleFunc(config.PathCreate, api.createEvent).Methods(http.MethodPost)
+	eventsRouter.HandleFunc(config.PathExport, api.exportEvents).Methods(http.MethodGet)
 	apiRoutes.HandleFunc(config.PathConnectedUser, api.connectedUserHandler)
+	apiRoutes.HandleFunc(config.PathFreeBusy, api.usersFreeBusy).Methods(http.MethodPost)
+	apiRoutes.HandleFunc(config.PathMetrics, api.metrics).Methods(http.MethodGet)
 
 	// Returns provider information for the plugin to use
 	apiRoutes.HandleFunc(config.PathProvider, func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,376 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils"
+)
+
+const (
+	dialogFieldResponse     = "response"
+	dialogFieldComment      = "comment"
+	dialogFieldProposedTime = "proposed_time"
+	dialogFieldSendResponse = "send_response"
+
+	dialogCommentMaxLength = 500
+)
+
+func (api *api) dialogURL(path string) string {
+	return api.Config.PluginURLPath + config.PathDialogs + path
+}
+
+func (api *api) postActionDeclineWithNote(w http.ResponseWriter, req *http.Request) {
+	request, eventID := api.preprocessDialogAction(w, req)
+	if eventID == "" {
+		return
+	}
+
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       api.dialogURL(config.PathRespond),
+		Dialog: model.Dialog{
+			CallbackId:       request.PostId,
+			Title:            "Decline with note",
+			IntroductionText: "The note is sent to the organizer along with your response.",
+			Elements: []model.DialogElement{
+				{
+					DisplayName: "Note",
+					Name:        dialogFieldComment,
+					Type:        "textarea",
+					MaxLength:   dialogCommentMaxLength,
+				},
+			},
+			SubmitLabel: "Decline",
+			State:       eventID,
+		},
+	}
+
+	api.openDialog(w, dialog)
+}
+
+func (api *api) postActionRespondWithComment(w http.ResponseWriter, req *http.Request) {
+	request, eventID := api.preprocessDialogAction(w, req)
+	if eventID == "" {
+		return
+	}
+
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       api.dialogURL(config.PathRespond),
+		Dialog: model.Dialog{
+			CallbackId: request.PostId,
+			Title:      "Respond with comment",
+			Elements: []model.DialogElement{
+				{
+					DisplayName: "Response",
+					Name:        dialogFieldResponse,
+					Type:        "radio",
+					Default:     engine.OptionYes,
+					Options: []*model.PostActionOptions{
+						{Text: "Accept", Value: engine.OptionYes},
+						{Text: "Tentatively accept", Value: engine.OptionMaybe},
+						{Text: "Decline", Value: engine.OptionNo},
+					},
+				},
+				{
+					DisplayName: "Comment",
+					Name:        dialogFieldComment,
+					Type:        "textarea",
+					MaxLength:   dialogCommentMaxLength,
+					Optional:    true,
+				},
+				{
+					DisplayName: "Notify organizer",
+					Name:        dialogFieldSendResponse,
+					Type:        "bool",
+					Default:     "true",
+					Optional:    true,
+					Placeholder: "Send the response to the organizer",
+				},
+			},
+			SubmitLabel: "Respond",
+			State:       eventID,
+		},
+	}
+
+	api.openDialog(w, dialog)
+}
+
+func (api *api) postActionProposeNewTime(w http.ResponseWriter, req *http.Request) {
+	request, eventID := api.preprocessDialogAction(w, req)
+	if eventID == "" {
+		return
+	}
+
+	mscal := engine.New(api.Env, request.UserId)
+	user := engine.NewUser(request.UserId)
+	slots, err := mscal.SuggestNewTimes(user, eventID)
+	if err != nil {
+		utils.SlackAttachmentError(w, "Error: Failed to find available times: "+err.Error())
+		return
+	}
+	if len(slots) == 0 {
+		utils.SlackAttachmentError(w, "No available times were found in the next few days. Please propose a new time directly on MS Calendar.")
+		return
+	}
+
+	options := []*model.PostActionOptions{}
+	for _, slot := range slots {
+		start := slot.Start.Time()
+		end := slot.End.Time()
+		options = append(options, &model.PostActionOptions{
+			Text:  fmt.Sprintf("%s - %s", start.Format("Mon Jan 2, 3:04PM"), end.Format(time.Kitchen)),
+			Value: start.UTC().Format(time.RFC3339) + "/" + end.UTC().Format(time.RFC3339),
+		})
+	}
+
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       api.dialogURL(config.PathRespond),
+		Dialog: model.Dialog{
+			CallbackId:       request.PostId,
+			Title:            "Propose new time",
+			IntroductionText: "The selected time is proposed to the organizer along with your response.",
+			Elements: []model.DialogElement{
+				{
+					DisplayName: "Response",
+					Name:        dialogFieldResponse,
+					Type:        "radio",
+					Default:     engine.OptionNo,
+					Options: []*model.PostActionOptions{
+						{Text: "Decline", Value: engine.OptionNo},
+						{Text: "Tentatively accept", Value: engine.OptionMaybe},
+					},
+				},
+				{
+					DisplayName: "New time",
+					Name:        dialogFieldProposedTime,
+					Type:        "select",
+					Options:     options,
+				},
+				{
+					DisplayName: "Note",
+					Name:        dialogFieldComment,
+					Type:        "textarea",
+					MaxLength:   dialogCommentMaxLength,
+					Optional:    true,
+				},
+			},
+			SubmitLabel: "Propose",
+			State:       eventID,
+		},
+	}
+
+	api.openDialog(w, dialog)
+}
+
+func (api *api) preprocessDialogAction(w http.ResponseWriter, req *http.Request) (*model.PostActionIntegrationRequest, string) {
+	mattermostUserID := req.Header.Get("Mattermost-User-ID")
+	if mattermostUserID == "" {
+		utils.SlackAttachmentError(w, "Error: not authorized")
+		return nil, ""
+	}
+
+	var request model.PostActionIntegrationRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		utils.SlackAttachmentError(w, "Error: invalid request")
+		return nil, ""
+	}
+	request.UserId = mattermostUserID
+
+	eventID, ok := request.Context[config.EventIDKey].(string)
+	if !ok || eventID == "" {
+		utils.SlackAttachmentError(w, "Error: missing event ID")
+		return nil, ""
+	}
+
+	return &request, eventID
+}
+
+func (api *api) openDialog(w http.ResponseWriter, dialog model.OpenDialogRequest) {
+	if err := api.PluginAPI.OpenInteractiveDialog(dialog); err != nil {
+		utils.SlackAttachmentError(w, "Error: Failed to open dialog: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(model.PostActionIntegrationResponse{})
+}
+
+// submitResponseDialog responds to an invitation with the comment and proposed
+// time entered in the dialog. The event is declined unless the dialog asks for
+// another response.
+func (api *api) submitResponseDialog(w http.ResponseWriter, req *http.Request) {
+	mattermostUserID := req.Header.Get("Mattermost-User-ID")
+	if mattermostUserID == "" {
+		http.Error(w, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if request.Cancelled {
+		return
+	}
+
+	eventID := request.State
+	if eventID == "" {
+		writeDialogError(w, "Missing event ID.")
+		return
+	}
+
+	opts := &remote.EventResponseOptions{}
+	opts.Comment, _ = request.Submission[dialogFieldComment].(string)
+	if value, ok := request.Submission[dialogFieldProposedTime].(string); ok && value != "" {
+		slot, err := parseProposedTime(value)
+		if err != nil {
+			writeDialogError(w, err.Error())
+			return
+		}
+		opts.ProposedNewTime = slot
+	}
+	if sendResponse, ok := request.Submission[dialogFieldSendResponse].(bool); ok {
+		opts.SendResponse = &sendResponse
+	}
+
+	option, _ := request.Submission[dialogFieldResponse].(string)
+	if option == "" {
+		option = engine.OptionNo
+	}
+
+	if option != engine.OptionYes && option != engine.OptionNo && option != engine.OptionMaybe {
+		writeDialogError(w, fmt.Sprintf("Invalid response %q.", option))
+		return
+	}
+	if opts.ProposedNewTime != nil && option == engine.OptionYes {
+		writeDialogError(w, "A new time can only be proposed when declining or tentatively accepting.")
+		return
+	}
+
+	mscal := engine.New(api.Env, mattermostUserID)
+	err := mscal.RespondToEvent(engine.NewUser(mattermostUserID), eventID, option, opts)
+	if err != nil && !isAcceptedError(err) {
+		if isCanceledError(err) {
+			writeDialogError(w, "Cannot respond to the event because it is already canceled.")
+			return
+		}
+		writeDialogError(w, "Failed to respond to event: "+err.Error())
+		return
+	}
+
+	response := fmt.Sprintf("You have %s this event", prettyOption(option))
+	if opts.ProposedNewTime != nil {
+		response += " and proposed a new time"
+	}
+	if opts.SendResponse != nil && !*opts.SendResponse {
+		response += " without notifying the organizer"
+	}
+	api.updateResponsePost(request.CallbackId, mattermostUserID, eventID, response)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(model.SubmitDialogResponse{})
+}
+
+// updateResponsePost records the response on the notification post the
+// dialog was opened from, and removes its actions. The post ID comes from the
+// client, so only a post of the bot about the event, in its DM with the user,
+// is updated.
+func (api *api) updateResponsePost(postID, mattermostUserID, eventID, response string) {
+	if postID == "" {
+		return
+	}
+
+	p, err := api.PluginAPI.GetPost(postID)
+	if err != nil {
+		api.Logger.Warnf("Failed to get post to update. err=%v", err)
+		return
+	}
+	if p.UserId != api.BotUserID {
+		return
+	}
+
+	channel, err := api.PluginAPI.GetDirectChannel(mattermostUserID, api.BotUserID)
+	if err != nil {
+		api.Logger.Warnf("Failed to get the DM channel of the user. err=%v", err)
+		return
+	}
+	if p.ChannelId != channel.Id {
+		return
+	}
+
+	sas := p.Attachments()
+	if len(sas) == 0 || !hasEventAction(sas[0], eventID) {
+		return
+	}
+
+	sa := sas[0]
+	sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
+		Title: "Response",
+		Value: response,
+		Short: false,
+	})
+	sa.Actions = []*model.PostAction{}
+	model.ParseSlackAttachment(p, []*model.SlackAttachment{sa})
+
+	if err := api.Poster.UpdatePost(p); err != nil {
+		api.Logger.Warnf("Failed to update post. err=%v", err)
+	}
+}
+
+func parseProposedTime(value string) (*remote.TimeSlot, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid proposed time %q", value)
+	}
+
+	start, err := time.Parse(time.RFC3339, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposed start time %q", parts[0])
+	}
+	end, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposed end time %q", parts[1])
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("proposed end time must be after the start time")
+	}
+
+	return &remote.TimeSlot{
+		Start: remote.NewDateTime(start.UTC(), "UTC"),
+		End:   remote.NewDateTime(end.UTC(), "UTC"),
+	}, nil
+}
+
+func writeDialogError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(model.SubmitDialogResponse{Error: message})
+}
+
+// hasEventAction tells whether one of the actions of the attachment is about
+// the event.
+func hasEventAction(sa *model.SlackAttachment, eventID string) bool {
+	for _, action := range sa.Actions {
+		if action == nil || action.Integration == nil {
+			continue
+		}
+		if id, ok := action.Integration.Context[config.EventIDKey].(string); ok && id == eventID {
+			return true
+		}
+	}
+	return false
+}
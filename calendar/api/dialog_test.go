@@ -0,0 +1,312 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestPostActionDeclineWithNote(t *testing.T) {
+	api, _, _, _, mockPluginAPI, _, _, _ := GetMockSetup(t)
+	api.Config = &config.Config{PluginURLPath: "/plugins/mscalendar"}
+
+	tests := []struct {
+		name       string
+		setup      func(*http.Request)
+		assertions func(*httptest.ResponseRecorder)
+	}{
+		{
+			name: "Missing event ID",
+			setup: func(req *http.Request) {
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				bodyBytes, _ := json.Marshal(model.PostActionIntegrationRequest{Context: map[string]interface{}{}})
+				req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "Error: missing event ID", response.EphemeralText)
+			},
+		},
+		{
+			name: "Dialog opened",
+			setup: func(req *http.Request) {
+				mockPluginAPI.EXPECT().OpenInteractiveDialog(gomock.Any()).DoAndReturn(func(dialog model.OpenDialogRequest) error {
+					assert.Equal(t, "triggerID", dialog.TriggerId)
+					assert.Equal(t, MockEventID, dialog.Dialog.State)
+					assert.Equal(t, "postID", dialog.Dialog.CallbackId)
+					assert.Equal(t, "/plugins/mscalendar"+config.PathDialogs+config.PathRespond, dialog.URL)
+					return nil
+				}).Times(1)
+
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				bodyBytes, _ := json.Marshal(model.PostActionIntegrationRequest{
+					PostId:    "postID",
+					TriggerId: "triggerID",
+					Context:   map[string]interface{}{config.EventIDKey: MockEventID},
+				})
+				req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Empty(t, response.EphemeralText)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, config.PathDeclineWithNote, nil)
+			rec := httptest.NewRecorder()
+
+			tc.setup(req)
+			api.postActionDeclineWithNote(rec, req)
+
+			tc.assertions(rec)
+		})
+	}
+}
+
+func TestSubmitResponseDialog(t *testing.T) {
+	api, mockStore, mockPoster, mockRemote, mockPluginAPI, _, _, mockClient := GetMockSetup(t)
+	api.Config.BotUserID = "botUserID"
+
+	eventPost := func(eventID string) *model.Post {
+		post := &model.Post{Id: "postID", UserId: "botUserID", ChannelId: "channelID"}
+		model.ParseSlackAttachment(post, []*model.SlackAttachment{{
+			Actions: []*model.PostAction{{
+				Name:        "Decline with note",
+				Integration: &model.PostActionIntegration{Context: map[string]interface{}{config.EventIDKey: eventID}},
+			}},
+		}})
+		return post
+	}
+	acceptEvent := func() {
+		mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+		mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
+		mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+		mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponseOptions{}).Return(nil)
+	}
+
+	tests := []struct {
+		name       string
+		submission map[string]interface{}
+		setup      func()
+		assertions func(*httptest.ResponseRecorder)
+	}{
+		{
+			name:       "Invalid proposed time",
+			submission: map[string]interface{}{dialogFieldProposedTime: "tomorrow"},
+			setup:      func() {},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Contains(t, response.Error, "invalid proposed time")
+			},
+		},
+		{
+			name:       "Error declining event",
+			submission: map[string]interface{}{dialogFieldComment: "Sorry"},
+			setup: func() {
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, &remote.EventResponseOptions{Comment: "Sorry"}).Return(errors.New("some error"))
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "Failed to respond to event: some error", response.Error)
+			},
+		},
+		{
+			name:       "Invalid response",
+			submission: map[string]interface{}{dialogFieldResponse: "maybe-not"},
+			setup:      func() {},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, `Invalid response "maybe-not".`, response.Error)
+			},
+		},
+		{
+			name: "New time proposed when accepting",
+			submission: map[string]interface{}{
+				dialogFieldResponse:     engine.OptionYes,
+				dialogFieldProposedTime: "2020-02-12T15:00:00Z/2020-02-12T16:00:00Z",
+			},
+			setup: func() {},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "A new time can only be proposed when declining or tentatively accepting.", response.Error)
+			},
+		},
+		{
+			name: "Event accepted with comment without notifying the organizer",
+			submission: map[string]interface{}{
+				dialogFieldResponse:     engine.OptionYes,
+				dialogFieldComment:      "See you there",
+				dialogFieldSendResponse: false,
+			},
+			setup: func() {
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				sendResponse := false
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponseOptions{Comment: "See you there", SendResponse: &sendResponse}).Return(nil)
+
+				mockPluginAPI.EXPECT().GetPost("postID").Return(eventPost(MockEventID), nil)
+				mockPluginAPI.EXPECT().GetDirectChannel(MockUserID, "botUserID").Return(&model.Channel{Id: "channelID"}, nil)
+				mockPoster.EXPECT().UpdatePost(gomock.Any()).DoAndReturn(func(p *model.Post) error {
+					assert.Equal(t, "You have accepted this event without notifying the organizer", p.Attachments()[0].Fields[0].Value)
+					return nil
+				})
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Empty(t, response.Error)
+			},
+		},
+		{
+			name: "Event tentatively accepted with a proposed time",
+			submission: map[string]interface{}{
+				dialogFieldResponse:     engine.OptionMaybe,
+				dialogFieldProposedTime: "2020-02-12T15:00:00Z/2020-02-12T16:00:00Z",
+			},
+			setup: func() {
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, gomock.Any()).DoAndReturn(func(_, _ string, opts *remote.EventResponseOptions) error {
+					assert.Equal(t, time.Date(2020, 2, 12, 16, 0, 0, 0, time.UTC), opts.ProposedNewTime.End.Time())
+					return nil
+				})
+
+				mockPluginAPI.EXPECT().GetPost("postID").Return(eventPost(MockEventID), nil)
+				mockPluginAPI.EXPECT().GetDirectChannel(MockUserID, "botUserID").Return(&model.Channel{Id: "channelID"}, nil)
+				mockPoster.EXPECT().UpdatePost(gomock.Any()).DoAndReturn(func(p *model.Post) error {
+					assert.Equal(t, "You have tentatively accepted this event and proposed a new time", p.Attachments()[0].Fields[0].Value)
+					return nil
+				})
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Empty(t, response.Error)
+			},
+		},
+		{
+			name: "Event declined with a proposed time",
+			submission: map[string]interface{}{
+				dialogFieldComment:      "Can we move it?",
+				dialogFieldProposedTime: "2020-02-12T15:00:00Z/2020-02-12T16:00:00Z",
+			},
+			setup: func() {
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, gomock.Any()).DoAndReturn(func(_, _ string, opts *remote.EventResponseOptions) error {
+					assert.Equal(t, "Can we move it?", opts.Comment)
+					assert.Equal(t, time.Date(2020, 2, 12, 15, 0, 0, 0, time.UTC), opts.ProposedNewTime.Start.Time())
+					return errors.New("202 Accepted")
+				})
+
+				mockPluginAPI.EXPECT().GetPost("postID").Return(eventPost(MockEventID), nil)
+				mockPluginAPI.EXPECT().GetDirectChannel(MockUserID, "botUserID").Return(&model.Channel{Id: "channelID"}, nil)
+				mockPoster.EXPECT().UpdatePost(gomock.Any()).DoAndReturn(func(p *model.Post) error {
+					sa := p.Attachments()[0]
+					assert.Empty(t, sa.Actions)
+					assert.Equal(t, "You have declined this event and proposed a new time", sa.Fields[0].Value)
+					return nil
+				})
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Empty(t, response.Error)
+			},
+		},
+		{
+			name:       "Post not created by the bot",
+			submission: map[string]interface{}{dialogFieldResponse: engine.OptionYes},
+			setup: func() {
+				acceptEvent()
+				post := eventPost(MockEventID)
+				post.UserId = "otherUserID"
+				mockPluginAPI.EXPECT().GetPost("postID").Return(post, nil)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Empty(t, response.Error)
+			},
+		},
+		{
+			name:       "Post outside of the DM of the bot with the user",
+			submission: map[string]interface{}{dialogFieldResponse: engine.OptionYes},
+			setup: func() {
+				acceptEvent()
+				mockPluginAPI.EXPECT().GetPost("postID").Return(eventPost(MockEventID), nil)
+				mockPluginAPI.EXPECT().GetDirectChannel(MockUserID, "botUserID").Return(&model.Channel{Id: "otherChannelID"}, nil)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Empty(t, response.Error)
+			},
+		},
+		{
+			name:       "Post of another event",
+			submission: map[string]interface{}{dialogFieldResponse: engine.OptionYes},
+			setup: func() {
+				acceptEvent()
+				mockPluginAPI.EXPECT().GetPost("postID").Return(eventPost("otherEventID"), nil)
+				mockPluginAPI.EXPECT().GetDirectChannel(MockUserID, "botUserID").Return(&model.Channel{Id: "channelID"}, nil)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Empty(t, response.Error)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup()
+
+			bodyBytes, _ := json.Marshal(model.SubmitDialogRequest{
+				CallbackId: "postID",
+				State:      MockEventID,
+				Submission: tc.submission,
+			})
+			req := httptest.NewRequest(http.MethodPost, config.PathDialogs+config.PathRespond, bytes.NewBuffer(bodyBytes))
+			req.Header.Set(MMUserIDHeader, MockUserID)
+			rec := httptest.NewRecorder()
+
+			api.submitResponseDialog(rec, req)
+
+			tc.assertions(rec)
+		})
+	}
+}
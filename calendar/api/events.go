@@ -14,6 +14,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/pkg/errors"
 
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
@@ -37,6 +38,12 @@ type createEventPayload struct {
 	Subject     string `json:"subject"`
 	Location    string `json:"location,omitempty"`
 	ChannelID   string `json:"channel_id"`
+	// Rooms contains the email addresses of the rooms to book for the event.
+	Rooms []string `json:"rooms,omitempty"`
+	// InviteChannelID and InviteGroup invite all the members of a channel or a
+	// user group, by name, as attendees.
+	InviteChannelID string `json:"invite_channel_id,omitempty"`
+	InviteGroup     string `json:"invite_group,omitempty"`
 }
 
 func (cep createEventPayload) ToRemoteEvent(loc *time.Location) (*remote.Event, error) {
@@ -92,6 +99,23 @@ func (cep createEventPayload) ToRemoteEvent(loc *time.Location) (*remote.Event,
 		}
 	}
 
+	// Rooms are added as resource attendees so they are actually booked.
+	for _, room := range cep.Rooms {
+		evt.Attendees = append(evt.Attendees, &remote.Attendee{
+			Type: remote.AttendeeTypeResource,
+			EmailAddress: &remote.EmailAddress{
+				Address: room,
+			},
+		})
+	}
+	if evt.Location == nil && len(cep.Rooms) == 1 {
+		evt.Location = &remote.Location{
+			DisplayName:          cep.Rooms[0],
+			LocationEmailAddress: cep.Rooms[0],
+			LocationType:         remote.LocationTypeConferenceRoom,
+		}
+	}
+
 	return &evt, nil
 }
 
@@ -186,7 +210,7 @@ func (api *api) createEvent(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	client := api.Remote.MakeUserClient(context.Background(), user.OAuth2Token, mattermostUserID, api.Poster, api.Store)
+	client := engine.NewCachedClient(api.Env, api.Remote.MakeUserClient(context.Background(), user.OAuth2Token, mattermostUserID, api.Poster, api.Store))
 
 	mailbox, errMailbox := client.GetMailboxSettings(user.Remote.ID)
 	if errMailbox != nil {
@@ -215,29 +239,54 @@ func (api *api) createEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	for _, pa := range payload.Attendees {
-		var emailAddress string
+	invitees, err := api.getInvitees(mattermostUserID, payload)
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error(), "userID": mattermostUserID}).Errorf("createEvent, error occurred while getting invitees")
+		httputils.WriteBadRequestError(w, err)
+		return
+	}
 
-		if strings.Contains(pa, "@") {
-			emailAddress = pa
-		} else {
-			attendeeUser, err := api.Store.LoadUser(pa)
-			if err != nil {
-				api.Logger.With(bot.LogContext{"err": err.Error(), "attendee_mm_id": pa}).Errorf("error loading attendee from mattermost user id")
-				continue
-			}
+	count := countAttendees(payload.Attendees, invitees)
+	if err = engine.CheckAttendeeCount(count, api.Config.GetMaxEventAttendees()); err != nil {
+		api.Logger.With(bot.LogContext{"userID": mattermostUserID, "count": count}).Errorf("createEvent, too many attendees")
+		httputils.WriteBadRequestError(w, err)
+		return
+	}
 
-			emailAddress = attendeeUser.Remote.Mail
+	addedEmails := map[string]bool{}
+	attendeeIDs := []string{}
+	for _, pa := range payload.Attendees {
+		if !strings.Contains(pa, "@") {
+			attendeeIDs = append(attendeeIDs, pa)
+			continue
 		}
-
+		if addedEmails[strings.ToLower(pa)] {
+			continue
+		}
+		addedEmails[strings.ToLower(pa)] = true
 		event.Attendees = append(event.Attendees, &remote.Attendee{
 			EmailAddress: &remote.EmailAddress{
-				Address: emailAddress,
+				Address: pa,
 			},
 		})
 	}
 
-	event, err := client.CreateEvent(user.Remote.ID, event)
+	mscal := engine.New(api.Env, mattermostUserID)
+	resolutions := mscal.ResolveAttendees(attendeeIDs)
+	resolved := map[string]bool{}
+	for _, r := range resolutions {
+		resolved[r.MattermostUserID] = true
+		if r.Outcome == engine.AttendeeNotInvited {
+			api.Logger.With(bot.LogContext{"attendee_mm_id": r.MattermostUserID}).Warnf("createEvent, no email address found for attendee")
+		}
+	}
+	for _, r := range invitees.Attendees {
+		if !resolved[r.MattermostUserID] {
+			resolutions = append(resolutions, r)
+		}
+	}
+
+	event, err = mscal.CreateEvent(&engine.User{User: user, MattermostUserID: mattermostUserID}, event, resolutions)
 	if err != nil {
 		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("createEvent, error occurred while creating event")
 		httputils.WriteInternalServerError(w, err)
@@ -285,3 +334,70 @@ func (api *api) createEvent(w http.ResponseWriter, r *http.Request) {
 
 	httputils.WriteJSONResponse(w, `{"ok": true}`, http.StatusCreated)
 }
+
+// countAttendees returns the number of attendees the event is created with,
+// counting once the listed attendees that are also members of the invited
+// channel or group.
+func countAttendees(attendees []string, invitees *engine.Invitees) int {
+	emails := invitees.Emails()
+	seen := map[string]bool{}
+	for _, email := range emails {
+		seen[strings.ToLower(email)] = true
+	}
+	for _, r := range invitees.Attendees {
+		seen[r.MattermostUserID] = true
+	}
+
+	count := len(emails)
+	for _, a := range attendees {
+		key := a
+		if strings.Contains(a, "@") {
+			key = strings.ToLower(a)
+		}
+		if !seen[key] {
+			seen[key] = true
+			count++
+		}
+	}
+	return count
+}
+
+// getInvitees resolves the members of the channel and the group to invite, if
+// any, to the email addresses of their connected accounts.
+func (api *api) getInvitees(mattermostUserID string, payload createEventPayload) (*engine.Invitees, error) {
+	invitees := &engine.Invitees{}
+	if payload.InviteChannelID == "" && payload.InviteGroup == "" {
+		return invitees, nil
+	}
+
+	mscal := engine.New(api.Env, mattermostUserID)
+	user := engine.NewUser(mattermostUserID)
+
+	seen := map[string]bool{}
+	addInvitees := func(more *engine.Invitees) {
+		for _, r := range more.Attendees {
+			if !seen[r.MattermostUserID] {
+				seen[r.MattermostUserID] = true
+				invitees.Attendees = append(invitees.Attendees, r)
+			}
+		}
+	}
+
+	if payload.InviteChannelID != "" {
+		channelInvitees, err := mscal.GetChannelInvitees(user, payload.InviteChannelID)
+		if err != nil {
+			return nil, err
+		}
+		addInvitees(channelInvitees)
+	}
+
+	if payload.InviteGroup != "" {
+		groupInvitees, err := mscal.GetGroupInvitees(user, payload.InviteGroup)
+		if err != nil {
+			return nil, err
+		}
+		addInvitees(groupInvitees)
+	}
+
+	return invitees, nil
+}
@@ -91,6 +91,22 @@ func TestToRemoteEvent(t *testing.T) {
 				assert.NoError(t, err)
 			},
 		},
+		{
+			name: "Valid event booking a room",
+			payload: func() createEventPayload {
+				payload := GetMockCreateEventPayload(false, nil, "2024-10-18", "10:00", "12:00", "Discuss the quarterly results.", "Meeting with team", "", "")
+				payload.Rooms = []string{"room@example.com"}
+				return payload
+			}(),
+			assertions: func(t *testing.T, event *remote.Event, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, event.Attendees, 1)
+				assert.Equal(t, remote.AttendeeTypeResource, event.Attendees[0].Type)
+				assert.Equal(t, "room@example.com", event.Attendees[0].EmailAddress.Address)
+				assert.Equal(t, "room@example.com", event.Location.LocationEmailAddress)
+				assert.Equal(t, remote.LocationTypeConferenceRoom, event.Location.LocationType)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -353,9 +369,10 @@ func TestCreateEvent(t *testing.T) {
 				validJSON := GetCurrentTimeRequestBodyJSON(MockChannelID)
 				req.Body = io.NopCloser(bytes.NewBufferString(validJSON))
 				mockOAauthToken := oauth2.Token{}
-				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
 				mockPluginAPI.EXPECT().CanLinkEventToChannel(MockChannelID, MockUserID).Return(true).Times(1)
-				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(2)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockRemoteClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).Return(nil, errors.New("failed to create event")).Times(1)
 				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
@@ -374,8 +391,9 @@ func TestCreateEvent(t *testing.T) {
 				validJSON := GetCurrentTimeRequestBodyJSON(MockChannelID)
 				req.Body = io.NopCloser(bytes.NewBufferString(validJSON))
 				mockOAauthToken := oauth2.Token{}
-				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
-				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(1)
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(2)
 				mockPluginAPI.EXPECT().CanLinkEventToChannel(MockChannelID, MockUserID).Return(true).Times(1)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
@@ -398,9 +416,10 @@ func TestCreateEvent(t *testing.T) {
 				validJSON := GetCurrentTimeRequestBodyJSON(MockChannelID)
 				req.Body = io.NopCloser(bytes.NewBufferString(validJSON))
 				mockOAauthToken := oauth2.Token{}
-				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
 				mockPluginAPI.EXPECT().CanLinkEventToChannel(MockChannelID, MockUserID).Return(true).Times(1)
-				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(2)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
 				mockRemoteClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).Return(mockEvent, nil).Times(1)
@@ -423,9 +442,10 @@ func TestCreateEvent(t *testing.T) {
 				validJSON := GetCurrentTimeRequestBodyJSON(MockChannelID)
 				req.Body = io.NopCloser(bytes.NewBufferString(validJSON))
 				mockOAauthToken := oauth2.Token{}
-				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
 				mockPluginAPI.EXPECT().CanLinkEventToChannel(MockChannelID, MockUserID).Return(true).Times(1)
-				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(2)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
 				mockRemoteClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).Return(mockEvent, nil).Times(1)
@@ -448,9 +468,10 @@ func TestCreateEvent(t *testing.T) {
 				validJSON := GetCurrentTimeRequestBodyJSON(MockChannelID)
 				req.Body = io.NopCloser(bytes.NewBufferString(validJSON))
 				mockOAauthToken := oauth2.Token{}
-				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
 				mockPluginAPI.EXPECT().CanLinkEventToChannel(MockChannelID, MockUserID).Return(true).Times(1)
-				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(2)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
 				mockRemoteClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).Return(mockEvent, nil).Times(1)
@@ -471,8 +492,9 @@ func TestCreateEvent(t *testing.T) {
 				validJSON := GetCurrentTimeRequestBodyJSON("")
 				req.Body = io.NopCloser(bytes.NewBufferString(validJSON))
 				mockOAauthToken := oauth2.Token{}
-				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
-				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(1)
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(2)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
 				mockRemoteClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).Return(mockEvent, nil).Times(1)
@@ -484,6 +506,180 @@ func TestCreateEvent(t *testing.T) {
 				assert.Contains(t, string(responseBody), "true")
 			},
 		},
+		{
+			name: "Event created with the channel members",
+			setup: func(req *http.Request) {
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				req.Body = io.NopCloser(bytes.NewBufferString(GetInviteChannelRequestBodyJSON(MockChannelID)))
+				mockOAauthToken := oauth2.Token{}
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(2)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(true).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUsersInChannel(MockChannelID).Return([]*model.User{
+					{Id: MockUserID, Username: "organizer"},
+					{Id: "member1", Username: "member1"},
+					{Id: "member2", Username: "member2"},
+					{Id: "member3", Username: "member3", Email: "member3@mattermost.com"},
+				}, nil).Times(1)
+				mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{
+					{MattermostUserID: MockUserID, Email: "organizer@example.com"},
+					{MattermostUserID: "member1", Email: "member1@example.com"},
+					{MattermostUserID: "member2", Email: "Other@example.com"},
+				}, nil).Times(1)
+				mockRemoteClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).DoAndReturn(func(_ string, event *remote.Event) (*remote.Event, error) {
+					emails := []string{}
+					for _, a := range event.Attendees {
+						emails = append(emails, a.EmailAddress.Address)
+					}
+					assert.Equal(t, []string{"other@example.com", "member1@example.com"}, emails)
+					return GetMockRemoteEvent(), nil
+				}).Times(1)
+				mockPoster.EXPECT().DMWithMessageAndAttachments(MockUserID, "Your event was created successfully.", gomock.Any()).Times(1)
+				mockPoster.EXPECT().DM(MockUserID, "%s", gomock.Any()).DoAndReturn(func(_, _ string, args ...interface{}) (string, error) {
+					report := args[0].(string)
+					assert.Contains(t, report, "- @member1: invited")
+					assert.Contains(t, report, "- @member2: invited")
+					assert.Contains(t, report, "- @member3: not invited, no email address was found")
+					return "", nil
+				}).Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Unconnected attendees not invited when the emails are hidden",
+			setup: func(req *http.Request) {
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				req.Body = io.NopCloser(bytes.NewBufferString(GetAttendeesRequestBodyJSON(`["connected", "unconnected", "unknown"]`)))
+				mockOAauthToken := oauth2.Token{}
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(2)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockStore.EXPECT().LoadUser("connected").Return(&store.User{MattermostUsername: "connected", Remote: &remote.User{Mail: "connected@example.com"}}, nil).Times(1)
+				mockStore.EXPECT().LoadUser("unconnected").Return(nil, store.ErrNotFound).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUser("unconnected").Return(&model.User{Username: "unconnected", Email: "unconnected@mattermost.com"}, nil).Times(1)
+				mockStore.EXPECT().LoadUser("unknown").Return(nil, store.ErrNotFound).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUser("unknown").Return(nil, errors.New("not found")).Times(1)
+				mockLogger.EXPECT().Warnf("Error getting Mattermost user %s. err=%v", "unknown", gomock.Any()).Times(1)
+				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(2)
+				mockLoggerWith.EXPECT().Warnf("createEvent, no email address found for attendee").Times(2)
+				mockRemoteClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).DoAndReturn(func(_ string, event *remote.Event) (*remote.Event, error) {
+					emails := []string{}
+					for _, a := range event.Attendees {
+						emails = append(emails, a.EmailAddress.Address)
+					}
+					assert.Equal(t, []string{"connected@example.com"}, emails)
+					return GetMockRemoteEvent(), nil
+				}).Times(1)
+				mockPoster.EXPECT().DMWithMessageAndAttachments(MockUserID, "Your event was created successfully.", gomock.Any()).Times(1)
+				mockPoster.EXPECT().DM(MockUserID, "%s", gomock.Any()).DoAndReturn(func(_, _ string, args ...interface{}) (string, error) {
+					report := args[0].(string)
+					assert.Contains(t, report, "- @connected: invited")
+					assert.Contains(t, report, "- @unconnected: not invited, no email address was found")
+					assert.Contains(t, report, "- unknown: not invited, no email address was found")
+					return "", nil
+				}).Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Unconnected attendees invited with their Mattermost email when the emails are shown",
+			setup: func(req *http.Request) {
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				api.Config.MattermostShowEmailAddress = true
+				req.Body = io.NopCloser(bytes.NewBufferString(GetAttendeesRequestBodyJSON(`["connected", "unconnected", "unknown"]`)))
+				mockOAauthToken := oauth2.Token{}
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(2)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockStore.EXPECT().LoadUser("connected").Return(&store.User{MattermostUsername: "connected", Remote: &remote.User{Mail: "connected@example.com"}}, nil).Times(1)
+				mockStore.EXPECT().LoadUser("unconnected").Return(nil, store.ErrNotFound).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUser("unconnected").Return(&model.User{Username: "unconnected", Email: "unconnected@mattermost.com"}, nil).Times(1)
+				mockPoster.EXPECT().DM("unconnected", gomock.Any(), gomock.Any()).Return("", nil).Times(1)
+				mockStore.EXPECT().LoadUser("unknown").Return(nil, store.ErrNotFound).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUser("unknown").Return(nil, errors.New("not found")).Times(1)
+				mockLogger.EXPECT().Warnf("Error getting Mattermost user %s. err=%v", "unknown", gomock.Any()).Times(1)
+				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
+				mockLoggerWith.EXPECT().Warnf("createEvent, no email address found for attendee").Times(1)
+				mockRemoteClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).DoAndReturn(func(_ string, event *remote.Event) (*remote.Event, error) {
+					emails := []string{}
+					for _, a := range event.Attendees {
+						emails = append(emails, a.EmailAddress.Address)
+					}
+					assert.Equal(t, []string{"connected@example.com", "unconnected@mattermost.com"}, emails)
+					return GetMockRemoteEvent(), nil
+				}).Times(1)
+				mockPoster.EXPECT().DMWithMessageAndAttachments(MockUserID, "Your event was created successfully.", gomock.Any()).Times(1)
+				mockPoster.EXPECT().DM(MockUserID, "%s", gomock.Any()).DoAndReturn(func(_, _ string, args ...interface{}) (string, error) {
+					report := args[0].(string)
+					assert.Contains(t, report, "- @connected: invited")
+					assert.Contains(t, report, "- @unconnected: invited at unconnected@mattermost.com, the email of their Mattermost account")
+					assert.Contains(t, report, "- unknown: not invited, no email address was found")
+					return "", nil
+				}).Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				api.Config.MattermostShowEmailAddress = false
+				assert.Equal(t, http.StatusCreated, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Too many attendees",
+			setup: func(req *http.Request) {
+				api.Config.MaxEventAttendees = 1
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				req.Body = io.NopCloser(bytes.NewBufferString(GetAttendeesRequestBodyJSON(`["one@example.com", "two@example.com"]`)))
+				mockOAauthToken := oauth2.Token{}
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(1)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
+				mockLoggerWith.EXPECT().Errorf("createEvent, too many attendees").Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				api.Config.MaxEventAttendees = 0
+				assert.Equal(t, http.StatusBadRequest, rec.Result().StatusCode)
+				responseBody, _ := io.ReadAll(rec.Body)
+				assert.Contains(t, string(responseBody), "cannot invite 2 attendees, the maximum is 1")
+			},
+		},
+		{
+			name: "Too many attendees with the channel members",
+			setup: func(req *http.Request) {
+				api.Config.MaxEventAttendees = 1
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				req.Body = io.NopCloser(bytes.NewBufferString(GetInviteChannelRequestBodyJSON(MockChannelID)))
+				mockOAauthToken := oauth2.Token{}
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(1)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(true).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUsersInChannel(MockChannelID).Return([]*model.User{
+					{Id: "member1", Username: "member1"},
+					{Id: "member2", Username: "member2"},
+					{Id: "member3", Username: "member3", Email: "member3@mattermost.com"},
+				}, nil).Times(1)
+				mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{
+					{MattermostUserID: "member1", Email: "member1@example.com"},
+					{MattermostUserID: "member2", Email: "Other@example.com"},
+				}, nil).Times(1)
+				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
+				mockLoggerWith.EXPECT().Errorf("createEvent, too many attendees").Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				api.Config.MaxEventAttendees = 0
+				assert.Equal(t, http.StatusBadRequest, rec.Result().StatusCode)
+				responseBody, _ := io.ReadAll(rec.Body)
+				assert.Contains(t, string(responseBody), "cannot invite 2 attendees, the maximum is 1")
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,61 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/httputils"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+func (api *api) exportEvents(w http.ResponseWriter, r *http.Request) {
+	mattermostUserID := r.Header.Get("Mattermost-User-Id")
+	if mattermostUserID == "" {
+		api.Logger.Errorf("exportEvents, unauthorized user")
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	_, errStore := api.Store.LoadUser(mattermostUserID)
+	if errStore != nil && !errors.Is(errStore, store.ErrNotFound) {
+		api.Logger.With(bot.LogContext{"err": errStore.Error()}).Errorf("exportEvents, error occurred while loading user from store")
+		httputils.WriteInternalServerError(w, errStore)
+		return
+	}
+	if errors.Is(errStore, store.ErrNotFound) {
+		api.Logger.With(bot.LogContext{"err": errStore.Error()}).Errorf("exportEvents, user not found in store")
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	days := engine.ExportDefaultDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		var err error
+		days, err = strconv.Atoi(value)
+		if err != nil || days < 1 || days > engine.ExportMaxDays {
+			httputils.WriteBadRequestError(w, fmt.Errorf("days must be a number between 1 and %d", engine.ExportMaxDays))
+			return
+		}
+	}
+
+	mscal := engine.New(api.Env, mattermostUserID)
+	data, err := mscal.ExportCalendar(engine.NewUser(mattermostUserID), time.Now(), days)
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error(), "userID": mattermostUserID}).Errorf("exportEvents, error occurred while exporting events")
+		httputils.WriteInternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="calendar`+ical.FileExtension+`"`)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/httputils"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+const (
+	freeBusyFormatICal = "ics"
+	freeBusyFormatJSON = "json"
+)
+
+// freeBusyFeed serves the free/busy feed of the user owning the token in the
+// URL. It is reachable without a Mattermost session, so any failure to match
+// the token to a connected user is reported as not found.
+func (api *api) freeBusyFeed(w http.ResponseWriter, r *http.Request) {
+	token := mux.Vars(r)["token"]
+	mattermostUserID, err := api.Store.LoadFreeBusyTokenUser(token)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("freeBusyFeed, error occurred while loading token")
+		}
+		httputils.WriteNotFoundError(w, fmt.Errorf("not found"))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = freeBusyFormatICal
+	}
+	if format != freeBusyFormatICal && format != freeBusyFormatJSON {
+		httputils.WriteBadRequestError(w, fmt.Errorf("format must be %q or %q", freeBusyFormatICal, freeBusyFormatJSON))
+		return
+	}
+
+	days := engine.FreeBusyDefaultDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		days, err = strconv.Atoi(value)
+		if err != nil || days < 1 || days > engine.FreeBusyMaxDays {
+			httputils.WriteBadRequestError(w, fmt.Errorf("days must be a number between 1 and %d", engine.FreeBusyMaxDays))
+			return
+		}
+	}
+
+	if _, err = api.Store.LoadUser(mattermostUserID); err != nil {
+		httputils.WriteNotFoundError(w, fmt.Errorf("not found"))
+		return
+	}
+
+	from := time.Now().UTC().Truncate(time.Hour)
+	to := from.Add(time.Duration(days) * 24 * time.Hour)
+	mscal := engine.New(api.Env, mattermostUserID)
+	user := engine.NewUser(mattermostUserID)
+
+	if format == freeBusyFormatJSON {
+		intervals, err := mscal.GetFreeBusy(user, from, to)
+		if err != nil {
+			api.Logger.With(bot.LogContext{"err": err.Error(), "userID": mattermostUserID}).Errorf("freeBusyFeed, error occurred while getting free/busy")
+			httputils.WriteInternalServerError(w, fmt.Errorf("failed to get free/busy information"))
+			return
+		}
+		_ = httputils.WriteJSONResponse(w, intervals, http.StatusOK)
+		return
+	}
+
+	data, err := mscal.ExportFreeBusy(user, from, to)
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error(), "userID": mattermostUserID}).Errorf("freeBusyFeed, error occurred while exporting free/busy")
+		httputils.WriteInternalServerError(w, fmt.Errorf("failed to get free/busy information"))
+		return
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType+"; charset=utf-8")
+	_, _ = w.Write(data)
+}
+
+type usersFreeBusyPayload struct {
+	UserIDs []string  `json:"user_ids"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// usersFreeBusy returns the busy intervals of several Mattermost users, read
+// on behalf of the connected user making the request.
+func (api *api) usersFreeBusy(w http.ResponseWriter, r *http.Request) {
+	mattermostUserID := r.Header.Get("Mattermost-User-Id")
+	if mattermostUserID == "" {
+		api.Logger.Errorf("usersFreeBusy, unauthorized user")
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	_, errStore := api.Store.LoadUser(mattermostUserID)
+	if errStore != nil && !errors.Is(errStore, store.ErrNotFound) {
+		api.Logger.With(bot.LogContext{"err": errStore.Error()}).Errorf("usersFreeBusy, error occurred while loading user from store")
+		httputils.WriteInternalServerError(w, errStore)
+		return
+	}
+	if errors.Is(errStore, store.ErrNotFound) {
+		api.Logger.With(bot.LogContext{"err": errStore.Error()}).Errorf("usersFreeBusy, user not found in store")
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	var payload usersFreeBusyPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		httputils.WriteBadRequestError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	if len(payload.UserIDs) == 0 || len(payload.UserIDs) > engine.FreeBusyMaxUsers {
+		httputils.WriteBadRequestError(w, fmt.Errorf("user_ids must list between 1 and %d users", engine.FreeBusyMaxUsers))
+		return
+	}
+	if !payload.End.After(payload.Start) {
+		httputils.WriteBadRequestError(w, fmt.Errorf("end must be after start"))
+		return
+	}
+	if payload.End.Sub(payload.Start) > time.Duration(engine.FreeBusyMaxDays)*24*time.Hour {
+		httputils.WriteBadRequestError(w, fmt.Errorf("the time range cannot be longer than %d days", engine.FreeBusyMaxDays))
+		return
+	}
+
+	mscal := engine.New(api.Env, mattermostUserID)
+	result, err := mscal.GetUsersFreeBusy(engine.NewUser(mattermostUserID), payload.UserIDs, payload.Start, payload.End)
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error(), "userID": mattermostUserID}).Errorf("usersFreeBusy, error occurred while getting free/busy")
+		httputils.WriteInternalServerError(w, fmt.Errorf("failed to get free/busy information"))
+		return
+	}
+	_ = httputils.WriteJSONResponse(w, result, http.StatusOK)
+}
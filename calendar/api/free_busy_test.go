@@ -0,0 +1,131 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestUsersFreeBusy(t *testing.T) {
+	api, mockStore, _, mockRemote, mockPluginAPI, mockLogger, mockLoggerWith, mockRemoteClient := GetMockSetup(t)
+
+	tests := []struct {
+		name       string
+		userID     string
+		body       string
+		setup      func()
+		assertions func(rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "unauthorized user",
+			body: `{}`,
+			setup: func() {
+				mockLogger.EXPECT().Errorf("usersFreeBusy, unauthorized user").Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+			},
+		},
+		{
+			name:   "user not connected",
+			userID: MockUserID,
+			body:   `{}`,
+			setup: func() {
+				mockStore.EXPECT().LoadUser(MockUserID).Return(nil, store.ErrNotFound).Times(1)
+				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
+				mockLoggerWith.EXPECT().Errorf("usersFreeBusy, user not found in store").Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+			},
+		},
+		{
+			name:   "invalid time range",
+			userID: MockUserID,
+			body:   `{"user_ids":["user1"],"start":"2024-03-15T10:00:00Z","end":"2024-03-15T09:00:00Z"}`,
+			setup: func() {
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID}, nil).Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Result().StatusCode)
+				responseBody, _ := io.ReadAll(rec.Body)
+				assert.Contains(t, string(responseBody), "end must be after start")
+			},
+		},
+		{
+			name:   "no users",
+			userID: MockUserID,
+			body:   `{"user_ids":[],"start":"2024-03-15T09:00:00Z","end":"2024-03-15T10:00:00Z"}`,
+			setup: func() {
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID}, nil).Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Result().StatusCode)
+			},
+		},
+		{
+			name:   "busy intervals of the users",
+			userID: MockUserID,
+			body:   `{"user_ids":["user1","user2"],"start":"2024-03-15T09:00:00Z","end":"2024-03-15T10:00:00Z"}`,
+			setup: func() {
+				mockOAauthToken := oauth2.Token{}
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(3)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient).Times(1)
+				mockStore.EXPECT().LoadUser("user1").Return(&store.User{Remote: &remote.User{Mail: "user1@example.com"}}, nil).Times(1)
+				mockStore.EXPECT().LoadUser("user2").Return(nil, store.ErrNotFound).Times(1)
+				mockRemoteClient.EXPECT().GetSchedule(gomock.Len(1), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*remote.ScheduleInformation{
+					{
+						ScheduleID: "user1@example.com",
+						ScheduleItems: []*remote.ScheduleItem{{
+							Status: remote.ScheduleStatusBusy,
+							Start:  &remote.DateTime{DateTime: "2024-03-15T09:00:00", TimeZone: "UTC"},
+							End:    &remote.DateTime{DateTime: "2024-03-15T09:30:00", TimeZone: "UTC"},
+						}},
+					},
+				}, nil).Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				result := []*engine.UserFreeBusy{}
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+				require.Len(t, result, 2)
+				assert.Equal(t, "user1", result[0].MattermostUserID)
+				require.Len(t, result[0].Busy, 1)
+				assert.Equal(t, remote.ScheduleStatusBusy, result[0].Busy[0].Status)
+				assert.Equal(t, "user2", result[1].MattermostUserID)
+				assert.Equal(t, "user not connected", result[1].Error)
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/freebusy", bytes.NewBufferString(tc.body))
+			if tc.userID != "" {
+				req.Header.Set(MMUserIDHeader, tc.userID)
+			}
+			rec := httptest.NewRecorder()
+
+			tc.setup()
+			api.usersFreeBusy(rec, req)
+
+			tc.assertions(rec)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/metrics"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/httputils"
+)
+
+// metricsContentType is the Prometheus text format.
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+func (api *api) metrics(w http.ResponseWriter, r *http.Request) {
+	mattermostUserID := r.Header.Get("Mattermost-User-Id")
+	if mattermostUserID == "" {
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	mscal := engine.New(api.Env, mattermostUserID)
+	isAdmin, err := mscal.IsAuthorizedAdmin(mattermostUserID)
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("metrics, error occurred while checking the user is an admin")
+		httputils.WriteInternalServerError(w, err)
+		return
+	}
+	if !isAdmin {
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	families, err := mscal.GetMetrics()
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("metrics, error occurred while loading the metrics")
+		httputils.WriteInternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", metricsContentType)
+	_ = metrics.Write(w, families...)
+}
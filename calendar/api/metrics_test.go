@@ -0,0 +1,70 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestMetrics(t *testing.T) {
+	api, mockStore, _, _, mockPluginAPI, _, _, _ := GetMockSetup(t)
+
+	tests := []struct {
+		name       string
+		setup      func(req *http.Request)
+		assertions func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Missing MattermostUserId in header",
+			setup: func(req *http.Request) {},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "User is not an admin",
+			setup: func(req *http.Request) {
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				mockPluginAPI.EXPECT().IsSysAdmin(MockUserID).Return(false, nil).Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Metrics written",
+			setup: func(req *http.Request) {
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				mockPluginAPI.EXPECT().IsSysAdmin(MockUserID).Return(true, nil).Times(1)
+				mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{{MattermostUserID: MockUserID}}, nil).Times(1)
+				mockStore.EXPECT().CountNotifications().Return(2, 0, nil).Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				assert.Equal(t, metricsContentType, rec.Header().Get("Content-Type"))
+				assert.Contains(t, rec.Body.String(), "mscalendar_connected_users 1\n")
+				assert.Contains(t, rec.Body.String(), "mscalendar_notification_queue_depth 2\n")
+				assert.Contains(t, rec.Body.String(), "mscalendar_notification_dead_letters 0\n")
+				assert.Contains(t, rec.Body.String(), "# TYPE mscalendar_graph_requests_total counter\n")
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil)
+			tc.setup(req)
+			rec := httptest.NewRecorder()
+
+			api.metrics(rec, req)
+
+			tc.assertions(t, rec)
+		})
+	}
+}
@@ -40,6 +40,21 @@ func TestNotification(t *testing.T) {
 				assert.Equal(t, 0, len(mockProcessor.queue))
 			},
 		},
+		{
+			name: "Webhook handled by the remote",
+			setup: func(mockProcessor *MockNotificationProcessor) {
+				mockProcessor.err = nil
+				mockProcessor.queue = nil
+				mockRemote.EXPECT().HandleWebhook(gomock.Any(), gomock.Any()).DoAndReturn(func(w http.ResponseWriter, _ *http.Request) []*remote.Notification {
+					w.WriteHeader(http.StatusBadRequest)
+					return nil
+				}).Times(1)
+			},
+			assertions: func(rec *httptest.ResponseRecorder, mockProcessor *MockNotificationProcessor) {
+				assert.Equal(t, http.StatusBadRequest, rec.Result().StatusCode)
+				assert.Equal(t, 0, len(mockProcessor.queue))
+			},
+		},
 		{
 			name: "Successful notification processing",
 			setup: func(mockProcessor *MockNotificationProcessor) {
@@ -48,7 +63,7 @@ func TestNotification(t *testing.T) {
 					Return([]*remote.Notification{{}, {}}).Times(1)
 			},
 			assertions: func(rec *httptest.ResponseRecorder, mockProcessor *MockNotificationProcessor) {
-				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				assert.Equal(t, http.StatusAccepted, rec.Result().StatusCode)
 				assert.Equal(t, 2, len(mockProcessor.queue))
 			},
 		},
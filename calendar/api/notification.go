@@ -11,12 +11,20 @@ import (
 )
 
 func (api *api) notification(w http.ResponseWriter, req *http.Request) {
+	notifications := api.Env.Remote.HandleWebhook(w, req)
+	if notifications == nil {
+		return
+	}
+
 	if api.NotificationProcessor != nil {
-		err := api.NotificationProcessor.Enqueue(api.Env.Remote.HandleWebhook(w, req)...)
+		// The remote delivers the notifications again unless they are
+		// accepted.
+		err := api.NotificationProcessor.Enqueue(notifications...)
 		if err != nil {
 			api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("notification, error occurred while adding webhook event to notification queue")
 			httputils.WriteInternalServerError(w, err)
 			return
 		}
 	}
+	w.WriteHeader(http.StatusAccepted)
 }
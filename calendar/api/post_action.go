@@ -48,7 +48,7 @@ func (api *api) postActionAccept(w http.ResponseWriter, req *http.Request) {
 	if eventID == "" {
 		return
 	}
-	err := localEngine.AcceptEvent(user, eventID)
+	err := localEngine.AcceptEvent(user, eventID, nil)
 	if err != nil {
 		api.Logger.Warnf("Failed to accept event. err=%v", err)
 		utils.SlackAttachmentError(w, "Error: Failed to accept event: "+err.Error())
@@ -61,7 +61,7 @@ func (api *api) postActionDecline(w http.ResponseWriter, req *http.Request) {
 	if eventID == "" {
 		return
 	}
-	err := localEngine.DeclineEvent(user, eventID)
+	err := localEngine.DeclineEvent(user, eventID, nil)
 	if err != nil {
 		utils.SlackAttachmentError(w, "Error: Failed to decline event: "+err.Error())
 		return
@@ -73,7 +73,7 @@ func (api *api) postActionTentative(w http.ResponseWriter, req *http.Request) {
 	if eventID == "" {
 		return
 	}
-	err := localEngine.TentativelyAcceptEvent(user, eventID)
+	err := localEngine.TentativelyAcceptEvent(user, eventID, nil)
 	if err != nil {
 		utils.SlackAttachmentError(w, "Error: Failed to tentatively accept event: "+err.Error())
 		return
@@ -85,7 +85,7 @@ func (api *api) postActionRespond(w http.ResponseWriter, req *http.Request) {
 	if eventID == "" {
 		return
 	}
-	err := calendar.RespondToEvent(user, eventID, option)
+	err := calendar.RespondToEvent(user, eventID, option, nil)
 	if err != nil && !isAcceptedError(err) && !isNotFoundError(err) && !isCanceledError(err) {
 		utils.SlackAttachmentError(w, "Error: Failed to respond to event: "+err.Error())
 		return
@@ -133,6 +133,32 @@ func (api *api) postActionRespond(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+func (api *api) postActionNudge(w http.ResponseWriter, req *http.Request) {
+	calendar, user, eventID, _, _ := api.preprocessAction(w, req)
+	if eventID == "" {
+		return
+	}
+
+	nudged, notConnected, err := calendar.NudgeNonResponders(user, eventID)
+	if err != nil {
+		utils.SlackAttachmentError(w, "Error: Failed to nudge attendees: "+err.Error())
+		return
+	}
+
+	text := "All attendees have responded to this event."
+	if nudged > 0 || notConnected > 0 {
+		text = fmt.Sprintf("Sent a reminder to %d attendee(s).", nudged)
+		if notConnected > 0 {
+			text += fmt.Sprintf(" %d attendee(s) could not be reminded because their account is not connected to Mattermost.", notConnected)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(model.PostActionIntegrationResponse{EphemeralText: text}); err != nil {
+		utils.SlackAttachmentError(w, "Error: unable to write response, "+err.Error())
+	}
+}
+
 func prettyOption(option string) string {
 	switch option {
 	case engine.OptionYes:
@@ -273,3 +299,50 @@ func isNotFoundError(err error) bool {
 func isCanceledError(err error) bool {
 	return strings.Contains(err.Error(), "You can't respond to a meeting that's been canceled.")
 }
+
+func (api *api) postActionImportCalendar(w http.ResponseWriter, req *http.Request) {
+	mattermostUserID := req.Header.Get("Mattermost-User-ID")
+	if mattermostUserID == "" {
+		utils.SlackAttachmentError(w, "Error: not authorized")
+		return
+	}
+
+	var request model.PostActionIntegrationRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		utils.SlackAttachmentError(w, "Error: invalid request")
+		return
+	}
+
+	fileID, ok := request.Context[engine.FileIDKey].(string)
+	if !ok {
+		utils.SlackAttachmentError(w, "Error: missing file ID")
+		return
+	}
+
+	response := model.PostActionIntegrationResponse{}
+	if _, err := api.Store.LoadUser(mattermostUserID); err != nil {
+		response.EphemeralText = fmt.Sprintf("Your account is not connected to %s. Use `/%s connect` to add events to your calendar.", config.Provider.DisplayName, config.Provider.CommandTrigger)
+	} else {
+		mscal := engine.New(api.Env, mattermostUserID)
+		events, err := mscal.ImportCalendarFile(engine.NewUser(mattermostUserID), fileID)
+		switch {
+		case err != nil && len(events) == 0:
+			api.Logger.Warnf("Failed to import calendar file. err=%v", err)
+			response.EphemeralText = "Failed to add the events to your calendar: " + err.Error()
+		case err != nil:
+			api.Logger.Warnf("Failed to import calendar file. err=%v", err)
+			response.EphemeralText = fmt.Sprintf("Only %d event(s) were added to your calendar: %s", len(events), err.Error())
+		default:
+			subjects := []string{}
+			for _, e := range events {
+				subjects = append(subjects, fmt.Sprintf("**%s**", e.Subject))
+			}
+			response.EphemeralText = "Added to your calendar: " + strings.Join(subjects, ", ")
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		utils.SlackAttachmentError(w, "Error: unable to write response, "+err.Error())
+	}
+}
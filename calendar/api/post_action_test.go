@@ -146,7 +146,7 @@ func TestPostActionAccept(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Times(2)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -203,7 +203,7 @@ func TestPostDeclineAccept(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Times(2)
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, nil).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -260,7 +260,7 @@ func TestPostActionTentative(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Times(2)
-				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -317,7 +317,7 @@ func TestPostActionRespond(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -344,7 +344,7 @@ func TestPostActionRespond(t *testing.T) {
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
 				mockPluginAPI.EXPECT().GetPost("").Return(nil, &model.AppError{Message: "error getting post"})
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -371,7 +371,7 @@ func TestPostActionRespond(t *testing.T) {
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
 				mockPluginAPI.EXPECT().GetPost("").Return(&model.Post{}, nil)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -397,7 +397,7 @@ func TestPostActionRespond(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil)
 				attachment := model.SlackAttachment{
 					Title: "Example Title",
 					Text:  "This is an example attachment.",
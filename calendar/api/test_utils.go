@@ -10,6 +10,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
@@ -70,6 +71,7 @@ func GetMockSetup(t *testing.T) (*api, *mock_store.MockStore, *mock_bot.MockPost
 	mockClient := mock_remote.NewMockClient(ctrl)
 
 	env := engine.Env{
+		Config: &config.Config{},
 		Dependencies: &engine.Dependencies{
 			Store:     mockStore,
 			Poster:    mockPoster,
@@ -121,6 +123,39 @@ func GetCurrentTimeRequestBodyJSON(channelID string) string {
 				}`, date, startTime, endTime, channelID)
 }
 
+func GetAttendeesRequestBodyJSON(attendees string) string {
+	currentTime := time.Now()
+	date := currentTime.Format("2006-01-02")
+	startTime := currentTime.Add(time.Hour).Format("15:04")
+	endTime := currentTime.Add(2 * time.Hour).Format("15:04")
+
+	return fmt.Sprintf(`{
+					"all_day": false,
+					"attendees": %s,
+					"date": "%s",
+					"start_time": "%s",
+					"end_time": "%s",
+					"subject": "Team sync"
+				}`, attendees, date, startTime, endTime)
+}
+
+func GetInviteChannelRequestBodyJSON(inviteChannelID string) string {
+	currentTime := time.Now()
+	date := currentTime.Format("2006-01-02")
+	startTime := currentTime.Add(time.Hour).Format("15:04")
+	endTime := currentTime.Add(2 * time.Hour).Format("15:04")
+
+	return fmt.Sprintf(`{
+					"all_day": false,
+					"attendees": ["other@example.com"],
+					"date": "%s",
+					"start_time": "%s",
+					"end_time": "%s",
+					"subject": "Team sync",
+					"invite_channel_id": "%s"
+				}`, date, startTime, endTime, inviteChannelID)
+}
+
 func GetMockRemoteEvent() *remote.Event {
 	currentTime := time.Now()
 	return &remote.Event{
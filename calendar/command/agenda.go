@@ -0,0 +1,66 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const agendaDateFormat = "2006-01-02"
+
+func getAgendaHelp() string {
+	return fmt.Sprintf("Please enter the dates as YYYY-MM-DD, for example:\n`/%s agenda 2024-03-04 2024-03-08`", config.Provider.CommandTrigger)
+}
+
+func (c *Command) agenda(parameters ...string) (string, bool, error) {
+	if len(parameters) > 2 {
+		return getAgendaHelp(), false, nil
+	}
+
+	timezone, err := c.Engine.GetTimezone(c.user())
+	if err != nil {
+		if strings.Contains(err.Error(), store.ErrorRefreshTokenNotSet) || strings.Contains(err.Error(), store.ErrorUserInactive) {
+			return store.ErrorUserInactive, false, nil
+		}
+
+		return "Error: No timezone found", false, err
+	}
+
+	loc, err := time.LoadLocation(tz.Go(timezone))
+	if err != nil {
+		loc = time.UTC
+	}
+
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if len(parameters) > 0 {
+		from, err = time.ParseInLocation(agendaDateFormat, parameters[0], loc)
+		if err != nil {
+			return getAgendaHelp(), false, nil
+		}
+	}
+
+	// The end date is inclusive
+	to := from.AddDate(0, 0, engine.WeeklySummaryDays)
+	if len(parameters) > 1 {
+		last, err := time.ParseInLocation(agendaDateFormat, parameters[1], loc)
+		if err != nil {
+			return getAgendaHelp(), false, nil
+		}
+		to = last.AddDate(0, 0, 1)
+	}
+
+	out, err := c.Engine.GetAgendaForUser(c.user(), from, to)
+	if err != nil {
+		return err.Error(), false, nil
+	}
+	return out, false, nil
+}
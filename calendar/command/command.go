@@ -52,16 +52,47 @@ var cmds = []*model.AutocompleteData{
 			model.NewAutocompleteData("time", "", "Set the time you would like to receive your daily summary."),
 			model.NewAutocompleteData("enable", "", "Enable your daily summary."),
 			model.NewAutocompleteData("disable", "", "Disable your daily summary."),
+			{
+				Trigger:  "weekly",
+				HelpText: "Edit the settings for your weekly summary.",
+				SubCommands: []*model.AutocompleteData{
+					model.NewAutocompleteData("settings", "", "View your settings for the weekly summary."),
+					model.NewAutocompleteData("time", "[day] [time]", "Set the day and time you would like to receive your weekly summary."),
+					model.NewAutocompleteData("enable", "", "Enable your weekly summary."),
+					model.NewAutocompleteData("disable", "", "Disable your weekly summary."),
+				},
+			},
 		},
 	},
 	model.NewAutocompleteData("viewcal", "", "View your events for the upcoming 14 days, including today."),
+	model.NewAutocompleteData("agenda", "[from] [to]", "View your events between two dates (YYYY-MM-DD), with meeting totals and conflicts."),
 	{ // Create
 		Trigger:  "event",
 		HelpText: "Manage events.",
 		SubCommands: []*model.AutocompleteData{
 			model.NewAutocompleteData("create", "", "Creates a new event (desktop only)."),
+			model.NewAutocompleteData("invite-channel", "[date] [start] [end] [subject]", "Creates a new event and invites all the members of this channel."),
+			model.NewAutocompleteData("invite-group", "[group] [date] [start] [end] [subject]", "Creates a new event and invites all the members of a user group."),
 		},
 	},
+	{ // RSVPs
+		Trigger:  "rsvps",
+		Hint:     "[subject]",
+		HelpText: "View who responded to the meetings you organize, or to the meeting matching the subject.",
+		SubCommands: []*model.AutocompleteData{
+			model.NewAutocompleteData("digest", "[minutes|off]", "Get the responses a number of minutes before each meeting you organize."),
+		},
+	},
+	{ // Notifications
+		Trigger:  "notifications",
+		HelpText: "Hold your event notifications and get them together.",
+		SubCommands: []*model.AutocompleteData{
+			model.NewAutocompleteData("quiet", "[start] [end]|off", "Hold event notifications during quiet hours, i.e. 10:00PM 7:00AM."),
+			model.NewAutocompleteData("batch", "[minutes|off]", "Get event notifications together every number of minutes."),
+		},
+	},
+	model.NewAutocompleteData("export", "[days]", "Export your upcoming events as an iCalendar (.ics) file."),
+	model.NewAutocompleteData("rooms", "[building] [time]", "List meeting rooms and whether they are free for the next 30 minutes."),
 	model.NewAutocompleteData("today", "", "Display today's events."),
 	model.NewAutocompleteData("tomorrow", "", "Display tomorrow's events."),
 	model.NewAutocompleteData("settings", "", "Edit your user personal settings."),
@@ -117,10 +148,20 @@ func (c *Command) Handle() (string, bool, error) {
 		handler = c.requireConnectedUser(c.dailySummary)
 	case "viewcal":
 		handler = c.requireConnectedUser(c.viewCalendar)
+	case "agenda":
+		handler = c.requireConnectedUser(c.agenda)
 	case "settings":
 		handler = c.requireConnectedUser(c.settings)
-	case "events":
+	case "event", "events":
 		handler = c.requireConnectedUser(c.event)
+	case "export":
+		handler = c.requireConnectedUser(c.export)
+	case "rooms":
+		handler = c.requireConnectedUser(c.rooms)
+	case "rsvps":
+		handler = c.requireConnectedUser(c.rsvps)
+	case "notifications":
+		handler = c.requireConnectedUser(c.notifications)
 	// Admin only
 	case "showcals":
 		handler = c.requireConnectedUser(c.requireAdminUser(c.showCalendars))
@@ -130,6 +171,8 @@ func (c *Command) Handle() (string, bool, error) {
 		handler = c.requireConnectedUser(c.requireAdminUser(c.subscribe))
 	case "unsubscribe":
 		handler = c.requireConnectedUser(c.requireAdminUser(c.unsubscribe))
+	case "queue":
+		handler = c.requireAdminUser(c.queue)
 	// Aliases
 	case "today":
 		parameters = []string{"today"}
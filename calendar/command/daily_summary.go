@@ -18,7 +18,8 @@ func getDailySummaryHelp() string {
 		fmt.Sprintf("`/%s summary settings` - View your settings for the daily summary\n", config.Provider.CommandTrigger) +
 		fmt.Sprintf("`/%s summary time 8:00AM` - Set the time you would like to receive your daily summary\n", config.Provider.CommandTrigger) +
 		fmt.Sprintf("`/%s summary enable` - Enable your daily summary\n", config.Provider.CommandTrigger) +
-		fmt.Sprintf("`/%s summary disable` - Disable your daily summary", config.Provider.CommandTrigger)
+		fmt.Sprintf("`/%s summary disable` - Disable your daily summary\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s summary weekly` - View the commands for your weekly summary", config.Provider.CommandTrigger)
 }
 
 func getDailySummarySetTimeErrorMessage() string {
@@ -91,6 +92,8 @@ func (c *Command) dailySummary(parameters ...string) (string, bool, error) {
 			return err.Error(), false, err
 		}
 		return dailySummaryResponse(dsum), false, nil
+	case "weekly":
+		return c.weeklySummary(parameters[1:]...)
 	}
 	return "Invalid command. Please try again\n\n" + getDailySummaryHelp(), false, nil
 }
@@ -3,14 +3,110 @@
 
 package command
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const eventDateTimeFormat = "2006-01-02 15:04"
+
+func getEventHelp() string {
+	return "### Event commands:\n" +
+		fmt.Sprintf("`/%s event invite-channel 2024-03-04 10:00 11:00 Planning` - Create an event and invite all the members of this channel\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s event invite-group developers 2024-03-04 10:00 11:00 Planning` - Create an event and invite all the members of a user group", config.Provider.CommandTrigger)
+}
+
 func (c *Command) event(parameters ...string) (string, bool, error) {
 	if len(parameters) == 0 {
-		return getDailySummaryHelp(), false, nil
+		return getEventHelp(), false, nil
 	}
 
-	if parameters[0] == "create" {
+	switch parameters[0] {
+	case "create":
 		return "Creating events is only supported on desktop.", false, nil
+	case "invite-channel":
+		return c.createEventWithInvitees("", parameters[1:]...)
+	case "invite-group":
+		if len(parameters) < 2 {
+			return getEventHelp(), false, nil
+		}
+		return c.createEventWithInvitees(parameters[1], parameters[2:]...)
+	}
+
+	return getEventHelp(), false, nil
+}
+
+// createEventWithInvitees creates an event inviting the members of the group,
+// or of the current channel if no group is given.
+func (c *Command) createEventWithInvitees(groupName string, parameters ...string) (string, bool, error) {
+	if len(parameters) < 4 {
+		return getEventHelp(), false, nil
+	}
+
+	timezone, err := c.Engine.GetTimezone(c.user())
+	if err != nil {
+		if strings.Contains(err.Error(), store.ErrorRefreshTokenNotSet) || strings.Contains(err.Error(), store.ErrorUserInactive) {
+			return store.ErrorUserInactive, false, nil
+		}
+		return "Error: No timezone found", false, err
+	}
+
+	loc, err := time.LoadLocation(tz.Go(timezone))
+	if err != nil {
+		loc = time.UTC
+	}
+
+	start, err := time.ParseInLocation(eventDateTimeFormat, parameters[0]+" "+parameters[1], loc)
+	if err != nil {
+		return "Please enter the date as YYYY-MM-DD and the start time as HH:MM.\n" + getEventHelp(), false, nil
+	}
+	end, err := time.ParseInLocation(eventDateTimeFormat, parameters[0]+" "+parameters[2], loc)
+	if err != nil {
+		return "Please enter the end time as HH:MM.\n" + getEventHelp(), false, nil
+	}
+	if !end.After(start) {
+		return "The end time must be after the start time.", false, nil
+	}
+	if start.Before(time.Now()) {
+		return "Please select a start date and time that is not prior to the current time.", false, nil
+	}
+
+	var invitees *engine.Invitees
+	if groupName == "" {
+		invitees, err = c.Engine.GetChannelInvitees(c.user(), c.ChannelID)
+	} else {
+		invitees, err = c.Engine.GetGroupInvitees(c.user(), groupName)
+	}
+	if err != nil {
+		return err.Error(), false, nil
+	}
+	emails := invitees.Emails()
+	if len(emails) == 0 {
+		return "No email address was found for the members, so there is nobody to invite.", false, nil
+	}
+	if err = engine.CheckAttendeeCount(len(emails), c.Config.GetMaxEventAttendees()); err != nil {
+		return err.Error(), false, nil
+	}
+
+	event := &remote.Event{
+		Subject: strings.Join(parameters[3:], " "),
+		Start:   remote.NewDateTime(start, loc.String()),
+		End:     remote.NewDateTime(end, loc.String()),
+	}
+
+	// The organizer is sent how each member was invited.
+	_, err = c.Engine.CreateEvent(c.user(), event, invitees.Attendees)
+	if err != nil {
+		return "", false, err
 	}
 
-	return "", false, nil
+	out := fmt.Sprintf("The event **%s** was created and %d attendees were invited.", event.Subject, len(emails))
+	return out, false, nil
 }
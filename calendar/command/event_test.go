@@ -0,0 +1,135 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestEventInvite(t *testing.T) {
+	date := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	testcase := []struct {
+		name         string
+		parameters   []string
+		setup        func(*mock_engine.MockEngine)
+		maxAttendees int
+		expected     string
+	}{
+		{
+			name:       "missing parameters",
+			parameters: []string{"invite-channel", date, "10:00"},
+			setup:      func(_ *mock_engine.MockEngine) {},
+			expected:   getEventHelp(),
+		},
+		{
+			name:       "invalid start time",
+			parameters: []string{"invite-channel", date, "10am", "11:00", "Planning"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+			},
+			expected: "Please enter the date as YYYY-MM-DD and the start time as HH:MM.\n" + getEventHelp(),
+		},
+		{
+			name:       "end before start",
+			parameters: []string{"invite-channel", date, "11:00", "10:00", "Planning"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+			},
+			expected: "The end time must be after the start time.",
+		},
+		{
+			name:       "group not found",
+			parameters: []string{"invite-group", "missing", date, "10:00", "11:00", "Planning"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				m.EXPECT().GetGroupInvitees(gomock.Any(), "missing").Return(nil, errors.New(`group "missing" not found`)).Times(1)
+			},
+			expected: `group "missing" not found`,
+		},
+		{
+			name:       "channel members invited",
+			parameters: []string{"invite-channel", date, "10:00", "11:00", "Team", "planning"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				m.EXPECT().GetChannelInvitees(gomock.Any(), "mockChannelID").Return(&engine.Invitees{
+					Attendees: []*engine.AttendeeResolution{
+						{MattermostUserID: "a", Username: "alice", Email: "a@example.com", Outcome: engine.AttendeeInvited},
+						{MattermostUserID: "b", Username: "bob", Email: "b@example.com", Outcome: engine.AttendeeInvited},
+						{MattermostUserID: "c", Username: "charlie", Outcome: engine.AttendeeNotInvited},
+					},
+				}, nil).Times(1)
+				m.EXPECT().CreateEvent(gomock.Any(), gomock.Any(), gomock.Len(3)).DoAndReturn(func(_ *engine.User, event *remote.Event, _ []*engine.AttendeeResolution) (*remote.Event, error) {
+					require.Equal(t, "Team planning", event.Subject)
+					require.Equal(t, date+"T10:00:00", event.Start.DateTime)
+					return event, nil
+				}).Times(1)
+			},
+			expected: "The event **Team planning** was created and 2 attendees were invited.",
+		},
+		{
+			name:       "group larger than the maximum",
+			parameters: []string{"invite-group", "everyone", date, "10:00", "11:00", "Planning"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				m.EXPECT().GetGroupInvitees(gomock.Any(), "everyone").Return(&engine.Invitees{
+					Attendees: []*engine.AttendeeResolution{
+						{MattermostUserID: "a", Username: "alice", Email: "a@example.com", Outcome: engine.AttendeeInvited},
+						{MattermostUserID: "b", Username: "bob", Email: "b@example.com", Outcome: engine.AttendeeInvited},
+						{MattermostUserID: "c", Username: "charlie", Email: "c@mattermost.com", Outcome: engine.AttendeeInvitedByMattermostEmail},
+					},
+				}, nil).Times(1)
+			},
+			maxAttendees: 2,
+			expected:     "cannot invite 3 attendees, the maximum is 2",
+		},
+		{
+			name:       "group without email addresses",
+			parameters: []string{"invite-group", "developers", date, "10:00", "11:00", "Planning"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				m.EXPECT().GetGroupInvitees(gomock.Any(), "developers").Return(&engine.Invitees{}, nil).Times(1)
+			},
+			expected: "No email address was found for the members, so there is nobody to invite.",
+		},
+	}
+	for _, tt := range testcase {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s event", config.Provider.CommandTrigger),
+					UserId:  "mockUserID",
+				},
+				ChannelID: "mockChannelID",
+				Config:    &config.Config{PluginURL: "http://localhost", StoredConfig: config.StoredConfig{MaxEventAttendees: tt.maxAttendees}},
+				Engine:    mscal,
+			}
+
+			tt.setup(mscal)
+
+			out, _, err := command.event(tt.parameters...)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, out)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+)
+
+func (c *Command) export(parameters ...string) (string, bool, error) {
+	days := engine.ExportDefaultDays
+	if len(parameters) > 0 {
+		var err error
+		days, err = strconv.Atoi(parameters[0])
+		if err != nil || days < 1 || days > engine.ExportMaxDays {
+			return fmt.Sprintf("Please provide a number of days between 1 and %d.", engine.ExportMaxDays), false, nil
+		}
+	}
+
+	err := c.Engine.DMCalendarExport(c.user(), time.Now(), days)
+	if err != nil {
+		return "", false, err
+	}
+
+	return "We've sent you a direct message with your calendar file.", false, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+)
+
+func getNotificationsHelp() string {
+	return "### Notification commands:\n" +
+		fmt.Sprintf("`/%s notifications quiet 10:00PM 7:00AM` - Hold event notifications during these hours and get them together when they are over\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s notifications quiet off` - Turn off quiet hours\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s notifications batch 30` - Get event notifications together every 30 minutes\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s notifications batch off` - Get event notifications right away", config.Provider.CommandTrigger)
+}
+
+func (c *Command) notifications(parameters ...string) (string, bool, error) {
+	if len(parameters) == 0 {
+		return getNotificationsHelp(), false, nil
+	}
+
+	switch parameters[0] {
+	case "quiet":
+		return c.quietHours(parameters[1:]...)
+	case "batch":
+		return c.notificationBatch(parameters[1:]...)
+	}
+
+	return getNotificationsHelp(), false, nil
+}
+
+func (c *Command) quietHours(parameters ...string) (string, bool, error) {
+	start, end := "", ""
+	switch {
+	case len(parameters) == 1 && parameters[0] == "off":
+	case len(parameters) == 2:
+		start, end = strings.ToUpper(parameters[0]), strings.ToUpper(parameters[1])
+	default:
+		return getNotificationsHelp(), false, nil
+	}
+
+	settings, err := c.Engine.SetQuietHours(c.user(), start, end)
+	if err != nil {
+		return err.Error() + "\n" + getNotificationsHelp(), false, nil
+	}
+
+	if settings.QuietHoursStart == "" {
+		return "Quiet hours are off.", false, nil
+	}
+	return fmt.Sprintf("Event notifications will be held from %s to %s (%s).", settings.QuietHoursStart, settings.QuietHoursEnd, settings.Timezone), false, nil
+}
+
+func (c *Command) notificationBatch(parameters ...string) (string, bool, error) {
+	if len(parameters) != 1 {
+		return getNotificationsHelp(), false, nil
+	}
+
+	minutes := 0
+	if parameters[0] != "off" {
+		var err error
+		minutes, err = strconv.Atoi(parameters[0])
+		if err != nil || minutes <= 0 {
+			return "Please provide a number of minutes.\n" + getNotificationsHelp(), false, nil
+		}
+	}
+
+	settings, err := c.Engine.SetNotificationBatchMinutes(c.user(), minutes)
+	if err != nil {
+		return err.Error() + "\n" + getNotificationsHelp(), false, nil
+	}
+
+	if settings.BatchMinutes == 0 {
+		return "You will get event notifications right away.", false, nil
+	}
+	return fmt.Sprintf("You will get event notifications together every %d minutes.", settings.BatchMinutes), false, nil
+}
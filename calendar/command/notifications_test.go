@@ -0,0 +1,112 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestNotifications(t *testing.T) {
+	testcase := []struct {
+		name       string
+		parameters []string
+		setup      func(*mock_engine.MockEngine)
+		expected   string
+	}{
+		{
+			name:       "no subcommand",
+			parameters: []string{},
+			setup:      func(_ *mock_engine.MockEngine) {},
+			expected:   getNotificationsHelp(),
+		},
+		{
+			name:       "quiet hours without end",
+			parameters: []string{"quiet", "10:00PM"},
+			setup:      func(_ *mock_engine.MockEngine) {},
+			expected:   getNotificationsHelp(),
+		},
+		{
+			name:       "quiet hours rejected by the engine",
+			parameters: []string{"quiet", "22:00", "7:00am"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().SetQuietHours(gomock.Any(), "22:00", "7:00AM").Return(nil, errors.New("Invalid time value: 22:00")).Times(1)
+			},
+			expected: "Invalid time value: 22:00\n" + getNotificationsHelp(),
+		},
+		{
+			name:       "quiet hours enabled",
+			parameters: []string{"quiet", "10:00pm", "7:00am"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().SetQuietHours(gomock.Any(), "10:00PM", "7:00AM").Return(&store.NotificationBatchUserSettings{QuietHoursStart: "10:00PM", QuietHoursEnd: "7:00AM", Timezone: "Eastern Standard Time"}, nil).Times(1)
+			},
+			expected: "Event notifications will be held from 10:00PM to 7:00AM (Eastern Standard Time).",
+		},
+		{
+			name:       "quiet hours disabled",
+			parameters: []string{"quiet", "off"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().SetQuietHours(gomock.Any(), "", "").Return(&store.NotificationBatchUserSettings{}, nil).Times(1)
+			},
+			expected: "Quiet hours are off.",
+		},
+		{
+			name:       "batch with invalid minutes",
+			parameters: []string{"batch", "often"},
+			setup:      func(_ *mock_engine.MockEngine) {},
+			expected:   "Please provide a number of minutes.\n" + getNotificationsHelp(),
+		},
+		{
+			name:       "batch enabled",
+			parameters: []string{"batch", "30"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().SetNotificationBatchMinutes(gomock.Any(), 30).Return(&store.NotificationBatchUserSettings{BatchMinutes: 30}, nil).Times(1)
+			},
+			expected: "You will get event notifications together every 30 minutes.",
+		},
+		{
+			name:       "batch disabled",
+			parameters: []string{"batch", "off"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().SetNotificationBatchMinutes(gomock.Any(), 0).Return(&store.NotificationBatchUserSettings{}, nil).Times(1)
+			},
+			expected: "You will get event notifications right away.",
+		},
+	}
+	for _, tt := range testcase {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s notifications", config.Provider.CommandTrigger),
+					UserId:  "mockUserID",
+				},
+				ChannelID: "mockChannelID",
+				Config:    &config.Config{PluginURL: "http://localhost"},
+				Engine:    mscal,
+			}
+
+			tt.setup(mscal)
+
+			out, _, err := command.notifications(tt.parameters...)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, out)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"time"
+)
+
+// deadLettersShown is the number of failed notifications listed, most recent
+// first.
+const deadLettersShown = 10
+
+func (c *Command) queue(parameters ...string) (string, bool, error) {
+	if len(parameters) > 0 {
+		switch parameters[0] {
+		case "retry":
+			count, err := c.Engine.RetryDeadLetterNotifications()
+			if err != nil {
+				return "", false, err
+			}
+			return fmt.Sprintf("%d failed notifications were queued again.", count), false, nil
+		case "clear":
+			count, err := c.Engine.ClearDeadLetterNotifications()
+			if err != nil {
+				return "", false, err
+			}
+			return fmt.Sprintf("%d failed notifications were removed.", count), false, nil
+		}
+	}
+
+	status, err := c.Engine.GetNotificationQueueStatus()
+	if err != nil {
+		return "", false, err
+	}
+
+	out := fmt.Sprintf("Notifications waiting to be processed: %d\nFailed notifications: %d", len(status.Pending), len(status.DeadLetters))
+	for i := len(status.DeadLetters) - 1; i >= 0 && i >= len(status.DeadLetters)-deadLettersShown; i-- {
+		q := status.DeadLetters[i]
+		out += fmt.Sprintf("\n- Subscription `%s`, queued at %s, %d attempts: %s",
+			q.Notification.SubscriptionID,
+			time.Unix(q.QueuedAt, 0).UTC().Format(time.RFC3339),
+			q.Attempts,
+			q.LastError)
+	}
+	if len(status.DeadLetters) > 0 {
+		out += "\nUse `retry` to process the failed notifications again, or `clear` to remove them."
+	}
+	return out, false, nil
+}
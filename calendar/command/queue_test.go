@@ -0,0 +1,99 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestQueue(t *testing.T) {
+	queuedAt := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC).Unix()
+
+	testcase := []struct {
+		name       string
+		parameters []string
+		setup      func(*mock_engine.MockEngine)
+		expected   string
+	}{
+		{
+			name:       "empty queue",
+			parameters: []string{},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetNotificationQueueStatus().Return(&engine.NotificationQueueStatus{}, nil).Times(1)
+			},
+			expected: "Notifications waiting to be processed: 0\nFailed notifications: 0",
+		},
+		{
+			name:       "failed notifications",
+			parameters: []string{},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetNotificationQueueStatus().Return(&engine.NotificationQueueStatus{
+					Pending: []*store.QueuedNotification{{ID: "pending"}},
+					DeadLetters: []*store.QueuedNotification{
+						{Notification: &remote.Notification{SubscriptionID: "sub1"}, QueuedAt: queuedAt, Attempts: 5, LastError: "service unavailable"},
+						{Notification: &remote.Notification{SubscriptionID: "sub2"}, QueuedAt: queuedAt, Attempts: 1, LastError: "subscription is orphaned"},
+					},
+				}, nil).Times(1)
+			},
+			expected: "Notifications waiting to be processed: 1\nFailed notifications: 2" +
+				"\n- Subscription `sub2`, queued at 2020-02-12T10:00:00Z, 1 attempts: subscription is orphaned" +
+				"\n- Subscription `sub1`, queued at 2020-02-12T10:00:00Z, 5 attempts: service unavailable" +
+				"\nUse `retry` to process the failed notifications again, or `clear` to remove them.",
+		},
+		{
+			name:       "retry",
+			parameters: []string{"retry"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().RetryDeadLetterNotifications().Return(2, nil).Times(1)
+			},
+			expected: "2 failed notifications were queued again.",
+		},
+		{
+			name:       "clear",
+			parameters: []string{"clear"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().ClearDeadLetterNotifications().Return(3, nil).Times(1)
+			},
+			expected: "3 failed notifications were removed.",
+		},
+	}
+	for _, tt := range testcase {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s queue", config.Provider.CommandTrigger),
+					UserId:  "mockUserID",
+				},
+				ChannelID: "mockChannelID",
+				Config:    &config.Config{PluginURL: "http://localhost"},
+				Engine:    mscal,
+			}
+
+			tt.setup(mscal)
+
+			out, _, err := command.queue(tt.parameters...)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, out)
+		})
+	}
+}
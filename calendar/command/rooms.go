@@ -0,0 +1,92 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const roomsAvailabilityWindow = 30 * time.Minute
+
+var roomsTimeFormats = []string{"3:04PM", "3:04pm", "15:04"}
+
+func (c *Command) rooms(parameters ...string) (string, bool, error) {
+	timezone, err := c.Engine.GetTimezone(c.user())
+	if err != nil {
+		return "", false, err
+	}
+
+	loc, err := time.LoadLocation(tz.Go(timezone))
+	if err != nil {
+		loc = time.UTC
+	}
+
+	start := time.Now().In(loc)
+	if len(parameters) > 0 {
+		if t, ok := parseRoomsTime(parameters[len(parameters)-1], start); ok {
+			start = t
+			parameters = parameters[:len(parameters)-1]
+		}
+	}
+	end := start.Add(roomsAvailabilityWindow)
+	building := strings.Join(parameters, " ")
+
+	rooms, err := c.Engine.GetRoomsAvailability(c.user(), building, start, end)
+	if err != nil {
+		return "", false, err
+	}
+
+	return renderRoomsAvailability(rooms, building, start, end), false, nil
+}
+
+// parseRoomsTime parses a time of the day and places it on the same date as now.
+func parseRoomsTime(value string, now time.Time) (time.Time, bool) {
+	for _, layout := range roomsTimeFormats {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), true
+	}
+	return time.Time{}, false
+}
+
+func renderRoomsAvailability(rooms []*engine.RoomAvailability, building string, start, end time.Time) string {
+	if len(rooms) == 0 {
+		if building != "" {
+			return fmt.Sprintf("No rooms found in building %q.", building)
+		}
+		return "No rooms found."
+	}
+
+	out := fmt.Sprintf("Room availability from %s to %s:\n", start.Format(time.Kitchen), end.Format(time.Kitchen))
+	out += "| Room | Building | Capacity | Email | Availability |\n| :-- | :-- | :-- | :-- | :-- |\n"
+	for _, r := range rooms {
+		capacity := "-"
+		if r.Room.Capacity > 0 {
+			capacity = fmt.Sprintf("%d", r.Room.Capacity)
+		}
+		out += fmt.Sprintf("| %s | %s | %s | %s | %s |\n", r.Room.DisplayName, r.Room.Building, capacity, r.Room.EmailAddress, roomStatusText(r.Status))
+	}
+	out += "\nTo book a room, add it to the rooms of a new event."
+	return out
+}
+
+func roomStatusText(status string) string {
+	switch status {
+	case remote.ScheduleStatusFree:
+		return ":white_check_mark: Free"
+	case remote.ScheduleStatusTentative:
+		return ":grey_question: Tentative"
+	case remote.ScheduleStatusBusy:
+		return ":no_entry: Busy"
+	}
+	return "Unknown"
+}
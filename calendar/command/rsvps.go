@@ -0,0 +1,85 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func getRSVPDigestHelp() string {
+	return "### RSVP digest commands:\n" +
+		fmt.Sprintf("`/%s rsvps digest 60` - Get the responses 60 minutes before each meeting you organize\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s rsvps digest off` - Stop getting the responses before your meetings", config.Provider.CommandTrigger)
+}
+
+func (c *Command) rsvps(parameters ...string) (string, bool, error) {
+	if len(parameters) > 0 && parameters[0] == "digest" {
+		return c.rsvpDigest(parameters[1:]...)
+	}
+
+	query := strings.Join(parameters, " ")
+	events, err := c.Engine.GetOrganizedEvents(c.user(), query)
+	if err != nil {
+		if strings.Contains(err.Error(), store.ErrorRefreshTokenNotSet) || strings.Contains(err.Error(), store.ErrorUserInactive) {
+			return store.ErrorUserInactive, false, nil
+		}
+		return "", false, err
+	}
+
+	if query == "" {
+		tz, err := c.Engine.GetTimezone(c.user())
+		if err != nil {
+			return "", false, err
+		}
+
+		groups := [][]*views.RSVPGroup{}
+		for _, e := range events {
+			groups = append(groups, engine.RSVPGroups(e))
+		}
+		return views.RenderRSVPList(events, tz, groups), false, nil
+	}
+
+	if len(events) == 0 {
+		return fmt.Sprintf("No meeting you organize in the next %d days matches %q.", engine.RSVPSearchDays, query), false, nil
+	}
+
+	err = c.Engine.DMEventRSVPs(c.user(), events[0])
+	if err != nil {
+		return "", false, err
+	}
+
+	return fmt.Sprintf("We've sent you a direct message with the responses to **%s**.", views.EnsureSubject(events[0].Subject)), false, nil
+}
+
+func (c *Command) rsvpDigest(parameters ...string) (string, bool, error) {
+	if len(parameters) != 1 {
+		return getRSVPDigestHelp(), false, nil
+	}
+
+	minutes := 0
+	if parameters[0] != "off" {
+		var err error
+		minutes, err = strconv.Atoi(parameters[0])
+		if err != nil || minutes <= 0 {
+			return "Please provide a number of minutes.\n" + getRSVPDigestHelp(), false, nil
+		}
+	}
+
+	digest, err := c.Engine.SetRSVPDigestMinutes(c.user(), minutes)
+	if err != nil {
+		return err.Error() + "\n" + getRSVPDigestHelp(), false, nil
+	}
+
+	if !digest.Enable {
+		return "You will no longer get the responses before your meetings.", false, nil
+	}
+	return fmt.Sprintf("You will get the responses %d minutes before each meeting you organize.", digest.MinutesBefore), false, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestRSVPs(t *testing.T) {
+	start := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+	event := &remote.Event{
+		ID:      "eventID",
+		Subject: "Planning",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(time.Hour), "UTC"),
+		Attendees: []*remote.Attendee{
+			{EmailAddress: &remote.EmailAddress{Address: "a@example.com"}, Status: &remote.EventResponseStatus{Response: engine.ResponseYes}},
+			{EmailAddress: &remote.EmailAddress{Address: "b@example.com"}, Status: &remote.EventResponseStatus{Response: engine.ResponseNone}},
+		},
+	}
+
+	testcase := []struct {
+		name       string
+		parameters []string
+		setup      func(*mock_engine.MockEngine)
+		expected   string
+	}{
+		{
+			name:       "list organized events",
+			parameters: []string{},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetOrganizedEvents(gomock.Any(), "").Return([]*remote.Event{event}, nil).Times(1)
+				m.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+			},
+			expected: "Upcoming meetings you organize:\n- [Planning]() Wed Feb 12, 10:00AM - 11:00AM: 1 accepted, 0 tentative, 0 declined, 1 no response",
+		},
+		{
+			name:       "no matching event",
+			parameters: []string{"retro"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetOrganizedEvents(gomock.Any(), "retro").Return([]*remote.Event{}, nil).Times(1)
+			},
+			expected: fmt.Sprintf("No meeting you organize in the next %d days matches \"retro\".", engine.RSVPSearchDays),
+		},
+		{
+			name:       "matching event",
+			parameters: []string{"plan"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().GetOrganizedEvents(gomock.Any(), "plan").Return([]*remote.Event{event}, nil).Times(1)
+				m.EXPECT().DMEventRSVPs(gomock.Any(), event).Return(nil).Times(1)
+			},
+			expected: "We've sent you a direct message with the responses to **Planning**.",
+		},
+		{
+			name:       "digest without minutes",
+			parameters: []string{"digest"},
+			setup:      func(_ *mock_engine.MockEngine) {},
+			expected:   getRSVPDigestHelp(),
+		},
+		{
+			name:       "digest with invalid minutes",
+			parameters: []string{"digest", "soon"},
+			setup:      func(_ *mock_engine.MockEngine) {},
+			expected:   "Please provide a number of minutes.\n" + getRSVPDigestHelp(),
+		},
+		{
+			name:       "digest rejected by the engine",
+			parameters: []string{"digest", "7"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().SetRSVPDigestMinutes(gomock.Any(), 7).Return(nil, errors.New("minutes must be a multiple of 5")).Times(1)
+			},
+			expected: "minutes must be a multiple of 5\n" + getRSVPDigestHelp(),
+		},
+		{
+			name:       "digest enabled",
+			parameters: []string{"digest", "30"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().SetRSVPDigestMinutes(gomock.Any(), 30).Return(&store.RSVPDigestUserSettings{MinutesBefore: 30, Enable: true}, nil).Times(1)
+			},
+			expected: "You will get the responses 30 minutes before each meeting you organize.",
+		},
+		{
+			name:       "digest disabled",
+			parameters: []string{"digest", "off"},
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().SetRSVPDigestMinutes(gomock.Any(), 0).Return(&store.RSVPDigestUserSettings{MinutesBefore: 30}, nil).Times(1)
+			},
+			expected: "You will no longer get the responses before your meetings.",
+		},
+	}
+	for _, tt := range testcase {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s rsvps", config.Provider.CommandTrigger),
+					UserId:  "mockUserID",
+				},
+				ChannelID: "mockChannelID",
+				Config:    &config.Config{PluginURL: "http://localhost"},
+				Engine:    mscal,
+			}
+
+			tt.setup(mscal)
+
+			out, _, err := command.rsvps(tt.parameters...)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, out)
+		})
+	}
+}
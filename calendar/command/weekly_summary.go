@@ -0,0 +1,86 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func getWeeklySummaryHelp() string {
+	return "### Weekly summary commands:\n" +
+		fmt.Sprintf("`/%s summary weekly settings` - View your settings for the weekly summary\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s summary weekly time Monday 8:00AM` - Set the day and time you would like to receive your weekly summary\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s summary weekly enable` - Enable your weekly summary\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s summary weekly disable` - Disable your weekly summary\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s agenda` - View your events for the next 7 days", config.Provider.CommandTrigger)
+}
+
+func getWeeklySummarySetTimeErrorMessage() string {
+	return fmt.Sprintf("Please enter a day and a time, for example:\n`/%s summary weekly time Monday 8:00AM`", config.Provider.CommandTrigger)
+}
+
+func (c *Command) weeklySummary(parameters ...string) (string, bool, error) {
+	if len(parameters) == 0 {
+		return getWeeklySummaryHelp(), false, nil
+	}
+
+	switch parameters[0] {
+	case "time":
+		if len(parameters) != 3 {
+			return getWeeklySummarySetTimeErrorMessage(), false, nil
+		}
+
+		wsum, err := c.Engine.SetWeeklySummaryPostTime(c.user(), parameters[1], parameters[2])
+		if err != nil {
+			if strings.Contains(err.Error(), store.ErrorRefreshTokenNotSet) || strings.Contains(err.Error(), store.ErrorUserInactive) {
+				return store.ErrorUserInactive, false, nil
+			}
+
+			return err.Error() + "\n" + getWeeklySummarySetTimeErrorMessage(), false, nil
+		}
+
+		return weeklySummaryResponse(wsum), false, nil
+	case "settings":
+		wsum, err := c.Engine.GetWeeklySummarySettingsForUser(c.user())
+		if err != nil {
+			if strings.Contains(err.Error(), store.ErrorRefreshTokenNotSet) || strings.Contains(err.Error(), store.ErrorUserInactive) {
+				return store.ErrorUserInactive, false, nil
+			}
+
+			return err.Error() + "\nYou may need to configure your weekly summary using the commands below.\n" + getWeeklySummaryHelp(), false, nil
+		}
+
+		return weeklySummaryResponse(wsum), false, nil
+	case "enable":
+		wsum, err := c.Engine.SetWeeklySummaryEnabled(c.user(), true)
+		if err != nil {
+			return err.Error(), false, err
+		}
+
+		return weeklySummaryResponse(wsum), false, nil
+	case "disable":
+		wsum, err := c.Engine.SetWeeklySummaryEnabled(c.user(), false)
+		if err != nil {
+			return err.Error(), false, err
+		}
+		return weeklySummaryResponse(wsum), false, nil
+	}
+	return "Invalid command. Please try again\n\n" + getWeeklySummaryHelp(), false, nil
+}
+
+func weeklySummaryResponse(wsum *store.WeeklySummaryUserSettings) string {
+	if wsum == nil || wsum.PostTime == "" {
+		return "Your weekly summary is not yet configured.\n" + getWeeklySummarySetTimeErrorMessage()
+	}
+
+	enableStr := ""
+	if !wsum.Enable {
+		enableStr = fmt.Sprintf(", but is disabled. Enable it with `/%s summary weekly enable`", config.Provider.CommandTrigger)
+	}
+	return fmt.Sprintf("Your weekly summary is configured to show on %s at %s %s%s.", wsum.Day, wsum.PostTime, wsum.Timezone, enableStr)
+}
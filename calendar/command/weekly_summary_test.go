@@ -0,0 +1,129 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestWeeklySummary(t *testing.T) {
+	testcase := []struct {
+		name       string
+		parameters []string
+		setup      func(engine.Engine)
+		assertions func(t *testing.T, output string, err error)
+	}{
+		{
+			name:       "no parameters",
+			parameters: []string{"weekly"},
+			setup:      func(_ engine.Engine) {},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, getWeeklySummaryHelp(), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "set time with invalid parameter count",
+			parameters: []string{"weekly", "time", "8:00AM"},
+			setup:      func(_ engine.Engine) {},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, getWeeklySummarySetTimeErrorMessage(), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "set time with invalid day",
+			parameters: []string{"weekly", "time", "Someday", "8:00AM"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().SetWeeklySummaryPostTime(gomock.Any(), "Someday", "8:00AM").Return(nil, errors.New("Invalid day value: Someday")).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, "Invalid day value: Someday\n"+getWeeklySummarySetTimeErrorMessage(), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "set time successfully",
+			parameters: []string{"weekly", "time", "Monday", "8:00AM"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().SetWeeklySummaryPostTime(gomock.Any(), "Monday", "8:00AM").Return(&store.WeeklySummaryUserSettings{
+					Day:      "Monday",
+					PostTime: "8:00AM",
+					Timezone: "UTC",
+					Enable:   true,
+				}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, "Your weekly summary is configured to show on Monday at 8:00AM UTC.", output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "settings not configured",
+			parameters: []string{"weekly", "settings"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetWeeklySummarySettingsForUser(gomock.Any()).Return(nil, nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, "Your weekly summary is not yet configured.\n"+getWeeklySummarySetTimeErrorMessage(), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "disable",
+			parameters: []string{"weekly", "disable"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().SetWeeklySummaryEnabled(gomock.Any(), false).Return(&store.WeeklySummaryUserSettings{
+					Day:      "Monday",
+					PostTime: "8:00AM",
+					Timezone: "UTC",
+				}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, fmt.Sprintf("Your weekly summary is configured to show on Monday at 8:00AM UTC, but is disabled. Enable it with `/%s summary weekly enable`.", config.Provider.CommandTrigger), output)
+				require.Nil(t, err)
+			},
+		},
+	}
+	for _, tt := range testcase {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s summary", config.Provider.CommandTrigger),
+					UserId:  "mockUserID",
+				},
+				ChannelID: "mockChannelID",
+				Config:    &config.Config{PluginURL: "http://localhost"},
+				Engine:    mscal,
+			}
+
+			tt.setup(mscal)
+
+			out, _, err := command.dailySummary(tt.parameters...)
+
+			tt.assertions(t, out, err)
+		})
+	}
+}
@@ -17,6 +17,23 @@ type StoredConfig struct {
 	EnableStatusSync   bool
 	EnableDailySummary bool
 
+	// MaxEventAttendees limits the attendees of an event, counting the members
+	// of an invited channel or group.
+	MaxEventAttendees int
+
+	// EnableCalendarCache keeps a copy of the calendar of the users, updated
+	// with the changes since the previous sync, and caches the other responses
+	// of the remote for a while.
+	EnableCalendarCache bool
+
+	// EnableRichNotifications has the remote include the changed events in
+	// the notifications, encrypted with a certificate of the plugin.
+	EnableRichNotifications bool
+
+	// EnableRoomFinder lets the users find meeting rooms. It requests the
+	// Place.Read.All permission, which needs the consent of a tenant admin.
+	EnableRoomFinder bool
+
 	EncryptionKey string
 }
 
@@ -47,13 +64,33 @@ type Config struct {
 	BuildHashShort         string
 	MattermostSiteHostname string
 	MattermostSiteURL      string
-	PluginURL              string
-	PluginURLPath          string
-	PluginVersion          string
+	// MattermostShowEmailAddress is the PrivacySettings.ShowEmailAddress of
+	// the server: the emails of the users are not shown to others without it.
+	MattermostShowEmailAddress bool
+	// BotUserID is the Mattermost user of the bot posting the DMs.
+	BotUserID     string
+	PluginURL     string
+	PluginURLPath string
+	PluginVersion string
 	StoredConfig
 	Provider ProviderConfig
 }
 
+const DefaultMaxEventAttendees = 100
+
+// GetMaxEventAttendees returns the configured maximum number of attendees, or
+// the default if not set.
+func (c *Config) GetMaxEventAttendees() int {
+	if c.MaxEventAttendees <= 0 {
+		return DefaultMaxEventAttendees
+	}
+	return c.MaxEventAttendees
+}
+
 func (c *Config) GetNotificationURL() string {
 	return c.PluginURL + FullPathEventNotification
 }
+
+func (c *Config) GetLifecycleNotificationURL() string {
+	return c.PluginURL + FullPathLifecycleNotification
+}
@@ -16,9 +16,16 @@ const (
 	PathAccept                = "/accept"
 	PathDecline               = "/decline"
 	PathTentative             = "/tentative"
+	PathDeclineWithNote       = "/decline-note"
+	PathRespondWithComment    = "/respond-comment"
+	PathNudge                 = "/nudge"
+	PathProposeNewTime        = "/propose"
 	PathConfirmStatusChange   = "/confirm"
+	PathImport                = "/import"
+	PathFreeBusy              = "/freebusy"
 	PathNotification          = "/notification/v1"
 	PathEvent                 = "/event"
+	PathLifecycle             = "/lifecycle"
 	PathVerifyDomain          = "/verify"
 
 	PathAutocomplete = "/autocomplete"
@@ -28,11 +35,14 @@ const (
 	InternalAPIPath   = "/api/v1"
 	PathEvents        = "/events"
 	PathCreate        = "/create"
+	PathExport        = "/export"
 	PathProvider      = "/provider"
 	PathConnectedUser = "/me"
+	PathMetrics       = "/metrics"
 
-	FullPathEventNotification = PathNotification + PathEvent
-	FullPathOAuth2Redirect    = PathOAuth2 + PathComplete
+	FullPathEventNotification     = PathNotification + PathEvent
+	FullPathLifecycleNotification = PathNotification + PathLifecycle
+	FullPathOAuth2Redirect        = PathOAuth2 + PathComplete
 
 	EventIDKey = "EventID"
 )
@@ -545,7 +545,12 @@ func (m *mscalendar) GetCalendarEvents(user *User, start, end time.Time, exclude
 		return nil, errors.Wrap(err, "error withClient in GetCalendarEvents")
 	}
 
-	events, err := m.client.GetEventsBetweenDates(user.Remote.ID, start, end)
+	var events []*remote.Event
+	if m.isCalendarCacheEnabled() {
+		events, err = m.getCachedEventsBetweenDates(user.Remote.ID, start, end)
+	} else {
+		events, err = m.client.GetEventsBetweenDates(user.Remote.ID, start, end)
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "error getting events for user %s", user.MattermostUserID)
 	}
@@ -578,7 +583,7 @@ func (m *mscalendar) GetCalendarViews(users []*store.User) ([]*remote.ViewCalend
 		})
 	}
 
-	return m.client.DoBatchViewCalendarRequests(params)
+	return m.viewCalendars(params)
 }
 
 func (m *mscalendar) notifyUpcomingEvents(mattermostUserID string, events []*remote.Event) {
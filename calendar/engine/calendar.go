@@ -4,6 +4,7 @@
 package engine
 
 import (
+	"strings"
 	"time"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
@@ -11,8 +12,10 @@ import (
 
 type Calendar interface {
 	CreateCalendar(user *User, calendar *remote.Calendar) (*remote.Calendar, error)
-	CreateEvent(user *User, event *remote.Event, mattermostUserIDs []string) (*remote.Event, error)
+	CreateEvent(user *User, event *remote.Event, attendees []*AttendeeResolution) (*remote.Event, error)
 	DeleteCalendar(user *User, calendarID string) error
+	DMCalendarExport(user *User, now time.Time, days int) error
+	ExportCalendar(user *User, now time.Time, days int) ([]byte, error)
 	FindMeetingTimes(user *User, meetingParams *remote.FindMeetingTimesParameters) (*remote.MeetingTimeSuggestionResults, error)
 	GetCalendars(user *User) ([]*remote.Calendar, error)
 	ViewCalendar(user *User, from, to time.Time) ([]*remote.Event, error)
@@ -43,6 +46,9 @@ func (m *mscalendar) getTodayCalendarEvents(user *User, now time.Time, timezone
 	}
 
 	from, to := getTodayHoursForTimezone(now, timezone)
+	if m.isCalendarCacheEnabled() {
+		return m.getCachedEventsBetweenDates(user.Remote.ID, from, to)
+	}
 	return m.client.GetDefaultCalendarView(user.Remote.ID, from, to)
 }
 
@@ -67,7 +73,9 @@ func (m *mscalendar) CreateCalendar(user *User, calendar *remote.Calendar) (*rem
 	return m.client.CreateCalendar(user.Remote.ID, calendar)
 }
 
-func (m *mscalendar) CreateEvent(user *User, event *remote.Event, mattermostUserIDs []string) (*remote.Event, error) {
+// CreateEvent creates the event inviting the resolved Mattermost users, and
+// reports to the organizer how each of them was invited.
+func (m *mscalendar) CreateEvent(user *User, event *remote.Event, attendees []*AttendeeResolution) (*remote.Event, error) {
 	err := m.Filter(
 		withClient,
 		withUserExpanded(user),
@@ -76,22 +84,53 @@ func (m *mscalendar) CreateEvent(user *User, event *remote.Event, mattermostUser
 		return nil, err
 	}
 
-	// invite non-mapped Mattermost
-	for id := range mattermostUserIDs {
-		mattermostUserID := mattermostUserIDs[id]
-		_, err := m.Store.LoadUser(mattermostUserID)
-		if err != nil {
-			if err.Error() == "not found" {
-				_, err = m.Poster.DM(mattermostUserID, "You have been invited to a %s event but have not linked your account.  Feel free to join us by connecting your %s account using `/%s connect`", m.Provider.DisplayName, m.Provider.DisplayName, m.Provider.CommandTrigger)
-				if err != nil {
-					m.Logger.Warnf("CreateEvent error creating DM. err=%v", err)
-					continue
-				}
+	for _, r := range attendees {
+		if r.Outcome == AttendeeInvitedByMattermostEmail && !m.Config.MattermostShowEmailAddress {
+			// The email of the Mattermost account is hidden by the server
+			r.Email = ""
+			r.Outcome = AttendeeNotInvited
+		}
+		if r.Outcome == AttendeeNotInvited {
+			continue
+		}
+		addAttendee(event, r.Email)
+
+		// invite non-mapped Mattermost
+		if r.Outcome == AttendeeInvitedByMattermostEmail {
+			_, err = m.Poster.DM(r.MattermostUserID, "You have been invited to a %s event but have not linked your account.  Feel free to join us by connecting your %s account using `/%s connect`", m.Provider.DisplayName, m.Provider.DisplayName, m.Provider.CommandTrigger)
+			if err != nil {
+				m.Logger.Warnf("CreateEvent error creating DM. err=%v", err)
 			}
 		}
 	}
 
-	return m.client.CreateEvent(user.Remote.ID, event)
+	created, err := m.client.CreateEvent(user.Remote.ID, event)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attendees) > 0 {
+		_, err = m.Poster.DM(user.MattermostUserID, "%s", FormatAttendeeOutcomes(event.Subject, attendees, m.Config.MattermostShowEmailAddress))
+		if err != nil {
+			m.Logger.Warnf("CreateEvent error reporting attendees. err=%v", err)
+		}
+	}
+	return created, nil
+}
+
+// addAttendee adds the email address to the attendees of the event, unless it
+// is already there.
+func addAttendee(event *remote.Event, email string) {
+	for _, a := range event.Attendees {
+		if a.EmailAddress != nil && strings.EqualFold(a.EmailAddress.Address, email) {
+			return
+		}
+	}
+	event.Attendees = append(event.Attendees, &remote.Attendee{
+		EmailAddress: &remote.EmailAddress{
+			Address: email,
+		},
+	})
 }
 
 func (m *mscalendar) DeleteCalendar(user *User, calendarID string) error {
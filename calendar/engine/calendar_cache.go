@@ -0,0 +1,225 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+// calendarCacheDays is the number of days after today kept in the calendar
+// caches.
+const calendarCacheDays = 8
+
+// calendarCacheWindow returns the range of the calendars kept in the caches.
+// It starts the day before today in UTC, so that today is covered in every
+// timezone, and moves every day.
+func calendarCacheWindow(now time.Time) (time.Time, time.Time) {
+	start := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	return start, start.AddDate(0, 0, calendarCacheDays+1)
+}
+
+// viewCalendars returns the events of the calendar views. When the calendar
+// cache is enabled, the events are read from the caches of the users, after
+// updating them with the changes since the previous sync. The views outside
+// of the cached range are always requested in full.
+func (m *mscalendar) viewCalendars(params []*remote.ViewCalendarParams) ([]*remote.ViewCalendarResponse, error) {
+	if !m.isCalendarCacheEnabled() {
+		return m.client.DoBatchViewCalendarRequests(params)
+	}
+
+	now := time.Now()
+	start, end := calendarCacheWindow(now)
+
+	cached := []*remote.ViewCalendarParams{}
+	uncached := []*remote.ViewCalendarParams{}
+	for _, p := range params {
+		if p.StartTime.Before(start) || p.EndTime.After(end) {
+			uncached = append(uncached, p)
+		} else {
+			cached = append(cached, p)
+		}
+	}
+
+	result := []*remote.ViewCalendarResponse{}
+	if len(uncached) > 0 {
+		views, err := m.client.DoBatchViewCalendarRequests(uncached)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, views...)
+	}
+	if len(cached) == 0 {
+		return result, nil
+	}
+
+	remoteUserIDs := []string{}
+	for _, p := range cached {
+		remoteUserIDs = append(remoteUserIDs, p.RemoteUserID)
+	}
+	caches, syncErrors, err := m.syncCalendarCaches(remoteUserIDs, start, end, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range cached {
+		view := &remote.ViewCalendarResponse{
+			RemoteUserID: p.RemoteUserID,
+		}
+		if apiErr, ok := syncErrors[p.RemoteUserID]; ok {
+			view.Error = apiErr
+		} else {
+			view.Events = cachedEvents(caches[p.RemoteUserID], p.StartTime, p.EndTime)
+		}
+		result = append(result, view)
+	}
+	return result, nil
+}
+
+func (m *mscalendar) isCalendarCacheEnabled() bool {
+	return m.Config != nil && m.Config.EnableCalendarCache
+}
+
+// getCachedEventsBetweenDates returns the events of the user between the
+// dates from their calendar cache.
+func (m *mscalendar) getCachedEventsBetweenDates(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	views, err := m.viewCalendars([]*remote.ViewCalendarParams{{
+		RemoteUserID: remoteUserID,
+		StartTime:    start,
+		EndTime:      end,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	for _, view := range views {
+		if view.Error != nil {
+			return nil, errors.New(view.Error.Message)
+		}
+		return view.Events, nil
+	}
+	return []*remote.Event{}, nil
+}
+
+// syncCalendarCaches updates the calendar caches of the remote users with the
+// changes since their previous sync, and returns them. Caches of another
+// range, or for which the changes are no longer available, are filled again
+// from scratch.
+func (m *mscalendar) syncCalendarCaches(remoteUserIDs []string, start, end, now time.Time) (map[string]*store.CalendarCache, map[string]*remote.APIError, error) {
+	caches := map[string]*store.CalendarCache{}
+	pending := []string{}
+	for _, remoteUserID := range remoteUserIDs {
+		if _, ok := caches[remoteUserID]; ok {
+			continue
+		}
+		cache, err := m.Store.LoadCalendarCache(remoteUserID)
+		if err != nil && err != store.ErrNotFound {
+			m.Logger.With(bot.LogContext{"remote_id": remoteUserID, "err": err}).Warnf("error loading calendar cache")
+		}
+		if err != nil || cache.Start != start.Unix() || cache.End != end.Unix() {
+			cache = newCalendarCache(start, end)
+		}
+		caches[remoteUserID] = cache
+		pending = append(pending, remoteUserID)
+	}
+
+	syncErrors := map[string]*remote.APIError{}
+	synced := map[string]bool{}
+	for attempt := 0; attempt < 2 && len(pending) > 0; attempt++ {
+		params := []*remote.EventsDeltaParams{}
+		for _, remoteUserID := range pending {
+			params = append(params, &remote.EventsDeltaParams{
+				RemoteUserID: remoteUserID,
+				StartTime:    start,
+				EndTime:      end,
+				DeltaLink:    caches[remoteUserID].DeltaLink,
+			})
+		}
+
+		responses, err := m.client.DoBatchEventsDeltaRequests(params)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pending = []string{}
+		for _, res := range responses {
+			cache, ok := caches[res.RemoteUserID]
+			if !ok {
+				continue
+			}
+			switch {
+			case res.Expired && cache.DeltaLink != "":
+				caches[res.RemoteUserID] = newCalendarCache(start, end)
+				pending = append(pending, res.RemoteUserID)
+			case res.Expired:
+				syncErrors[res.RemoteUserID] = &remote.APIError{Message: "calendar changes are not available"}
+			case res.Error != nil:
+				syncErrors[res.RemoteUserID] = res.Error
+			default:
+				applyEventsDelta(cache, res)
+				cache.SyncedAt = now.Unix()
+				synced[res.RemoteUserID] = true
+				err = m.Store.StoreCalendarCache(res.RemoteUserID, cache)
+				if err != nil {
+					m.Logger.With(bot.LogContext{"remote_id": res.RemoteUserID, "err": err}).Warnf("error storing calendar cache")
+				}
+			}
+		}
+	}
+
+	for remoteUserID := range caches {
+		if !synced[remoteUserID] && syncErrors[remoteUserID] == nil {
+			syncErrors[remoteUserID] = &remote.APIError{Message: "calendar could not be synced"}
+		}
+	}
+	return caches, syncErrors, nil
+}
+
+func newCalendarCache(start, end time.Time) *store.CalendarCache {
+	return &store.CalendarCache{
+		Events: map[string]*remote.Event{},
+		Start:  start.Unix(),
+		End:    end.Unix(),
+	}
+}
+
+// applyEventsDelta updates the cache with the changes. The body of the events
+// is not kept, since only the preview is ever shown from the cache.
+func applyEventsDelta(cache *store.CalendarCache, res *remote.EventsDeltaResponse) {
+	for _, id := range res.RemovedIDs {
+		delete(cache.Events, id)
+	}
+	for _, event := range res.Events {
+		event.Body = nil
+		cache.Events[event.ID] = event
+	}
+	cache.DeltaLink = res.DeltaLink
+}
+
+// cachedEvents returns the events of the cache overlapping the range, sorted
+// by start time.
+func cachedEvents(cache *store.CalendarCache, start, end time.Time) []*remote.Event {
+	events := []*remote.Event{}
+	if cache == nil {
+		return events
+	}
+	for _, event := range cache.Events {
+		if event.Start == nil || event.End == nil {
+			continue
+		}
+		if !event.Start.Time().Before(end) || !event.End.Time().After(start) {
+			continue
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Time().Before(events[j].Start.Time())
+	})
+	return events
+}
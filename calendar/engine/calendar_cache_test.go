@@ -0,0 +1,203 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+)
+
+func TestViewCalendarsFromCache(t *testing.T) {
+	start, end := calendarCacheWindow(time.Now())
+	dayStart := start.AddDate(0, 0, 1)
+	newEvent := func(id string, hour int) *remote.Event {
+		return &remote.Event{
+			ID:      id,
+			Subject: id,
+			Start:   remote.NewDateTime(dayStart.Add(time.Duration(hour)*time.Hour), "UTC"),
+			End:     remote.NewDateTime(dayStart.Add(time.Duration(hour+1)*time.Hour), "UTC"),
+		}
+	}
+	dayParams := []*remote.ViewCalendarParams{{
+		RemoteUserID: MockRemoteUserID,
+		StartTime:    dayStart,
+		EndTime:      dayStart.AddDate(0, 0, 1),
+	}}
+	storedCache := func(deltaLink string, events ...*remote.Event) *store.CalendarCache {
+		cache := &store.CalendarCache{
+			Events:    map[string]*remote.Event{},
+			DeltaLink: deltaLink,
+			Start:     start.Unix(),
+			End:       end.Unix(),
+		}
+		for _, e := range events {
+			cache.Events[e.ID] = e
+		}
+		return cache
+	}
+
+	for _, tc := range []struct {
+		name      string
+		disabled  bool
+		params    []*remote.ViewCalendarParams
+		setupMock func(*testing.T, *mockCalendarCache)
+		expected  []*remote.ViewCalendarResponse
+	}{
+		{
+			name:     "cache disabled",
+			disabled: true,
+			params:   dayParams,
+			setupMock: func(_ *testing.T, m *mockCalendarCache) {
+				m.client.EXPECT().DoBatchViewCalendarRequests(dayParams).Return([]*remote.ViewCalendarResponse{
+					{RemoteUserID: MockRemoteUserID, Events: []*remote.Event{newEvent("full", 9)}},
+				}, nil).Times(1)
+			},
+			expected: []*remote.ViewCalendarResponse{
+				{RemoteUserID: MockRemoteUserID, Events: []*remote.Event{newEvent("full", 9)}},
+			},
+		},
+		{
+			name:   "first sync",
+			params: dayParams,
+			setupMock: func(t *testing.T, m *mockCalendarCache) {
+				m.store.EXPECT().LoadCalendarCache(MockRemoteUserID).Return(nil, store.ErrNotFound).Times(1)
+				withBody := newEvent("today", 9)
+				withBody.Body = &remote.ItemBody{Content: "Agenda"}
+				m.client.EXPECT().DoBatchEventsDeltaRequests([]*remote.EventsDeltaParams{
+					{RemoteUserID: MockRemoteUserID, StartTime: start, EndTime: end},
+				}).Return([]*remote.EventsDeltaResponse{{
+					RemoteUserID: MockRemoteUserID,
+					Events:       []*remote.Event{withBody, newEvent("tomorrow", 33)},
+					DeltaLink:    "deltaLink",
+				}}, nil).Times(1)
+				m.store.EXPECT().StoreCalendarCache(MockRemoteUserID, gomock.Any()).DoAndReturn(func(_ string, cache *store.CalendarCache) error {
+					require.Equal(t, "deltaLink", cache.DeltaLink)
+					require.Len(t, cache.Events, 2)
+					require.Nil(t, cache.Events["today"].Body)
+					return nil
+				}).Times(1)
+			},
+			expected: []*remote.ViewCalendarResponse{
+				{RemoteUserID: MockRemoteUserID, Events: []*remote.Event{newEvent("today", 9)}},
+			},
+		},
+		{
+			name:   "changes since the previous sync",
+			params: dayParams,
+			setupMock: func(_ *testing.T, m *mockCalendarCache) {
+				m.store.EXPECT().LoadCalendarCache(MockRemoteUserID).Return(storedCache("deltaLink", newEvent("removed", 9), newEvent("updated", 11)), nil).Times(1)
+				updated := newEvent("updated", 11)
+				updated.Subject = "new subject"
+				m.client.EXPECT().DoBatchEventsDeltaRequests([]*remote.EventsDeltaParams{
+					{RemoteUserID: MockRemoteUserID, StartTime: start, EndTime: end, DeltaLink: "deltaLink"},
+				}).Return([]*remote.EventsDeltaResponse{{
+					RemoteUserID: MockRemoteUserID,
+					Events:       []*remote.Event{updated, newEvent("added", 10)},
+					RemovedIDs:   []string{"removed"},
+					DeltaLink:    "nextDeltaLink",
+				}}, nil).Times(1)
+				m.store.EXPECT().StoreCalendarCache(MockRemoteUserID, gomock.Any()).Return(nil).Times(1)
+			},
+			expected: []*remote.ViewCalendarResponse{
+				{RemoteUserID: MockRemoteUserID, Events: []*remote.Event{
+					newEvent("added", 10),
+					{ID: "updated", Subject: "new subject", Start: newEvent("updated", 11).Start, End: newEvent("updated", 11).End},
+				}},
+			},
+		},
+		{
+			name:   "expired changes are synced again",
+			params: dayParams,
+			setupMock: func(_ *testing.T, m *mockCalendarCache) {
+				m.store.EXPECT().LoadCalendarCache(MockRemoteUserID).Return(storedCache("expiredLink", newEvent("stale", 9)), nil).Times(1)
+				gomock.InOrder(
+					m.client.EXPECT().DoBatchEventsDeltaRequests([]*remote.EventsDeltaParams{
+						{RemoteUserID: MockRemoteUserID, StartTime: start, EndTime: end, DeltaLink: "expiredLink"},
+					}).Return([]*remote.EventsDeltaResponse{{RemoteUserID: MockRemoteUserID, Expired: true}}, nil).Times(1),
+					m.client.EXPECT().DoBatchEventsDeltaRequests([]*remote.EventsDeltaParams{
+						{RemoteUserID: MockRemoteUserID, StartTime: start, EndTime: end},
+					}).Return([]*remote.EventsDeltaResponse{{
+						RemoteUserID: MockRemoteUserID,
+						Events:       []*remote.Event{newEvent("fresh", 10)},
+						DeltaLink:    "deltaLink",
+					}}, nil).Times(1),
+				)
+				m.store.EXPECT().StoreCalendarCache(MockRemoteUserID, gomock.Any()).Return(nil).Times(1)
+			},
+			expected: []*remote.ViewCalendarResponse{
+				{RemoteUserID: MockRemoteUserID, Events: []*remote.Event{newEvent("fresh", 10)}},
+			},
+		},
+		{
+			name:   "cache of a previous day is synced again",
+			params: dayParams,
+			setupMock: func(_ *testing.T, m *mockCalendarCache) {
+				previous := storedCache("deltaLink", newEvent("stale", 9))
+				previous.Start = start.AddDate(0, 0, -1).Unix()
+				m.store.EXPECT().LoadCalendarCache(MockRemoteUserID).Return(previous, nil).Times(1)
+				m.client.EXPECT().DoBatchEventsDeltaRequests([]*remote.EventsDeltaParams{
+					{RemoteUserID: MockRemoteUserID, StartTime: start, EndTime: end},
+				}).Return([]*remote.EventsDeltaResponse{{RemoteUserID: MockRemoteUserID, DeltaLink: "deltaLink"}}, nil).Times(1)
+				m.store.EXPECT().StoreCalendarCache(MockRemoteUserID, gomock.Any()).Return(nil).Times(1)
+			},
+			expected: []*remote.ViewCalendarResponse{
+				{RemoteUserID: MockRemoteUserID, Events: []*remote.Event{}},
+			},
+		},
+		{
+			name:   "error getting the changes",
+			params: dayParams,
+			setupMock: func(_ *testing.T, m *mockCalendarCache) {
+				m.store.EXPECT().LoadCalendarCache(MockRemoteUserID).Return(storedCache("deltaLink", newEvent("cached", 9)), nil).Times(1)
+				m.client.EXPECT().DoBatchEventsDeltaRequests(gomock.Any()).Return([]*remote.EventsDeltaResponse{{
+					RemoteUserID: MockRemoteUserID,
+					Error:        &remote.APIError{Code: "ErrorAccessDenied", Message: "Access is denied."},
+				}}, nil).Times(1)
+			},
+			expected: []*remote.ViewCalendarResponse{
+				{RemoteUserID: MockRemoteUserID, Error: &remote.APIError{Code: "ErrorAccessDenied", Message: "Access is denied."}},
+			},
+		},
+		{
+			name: "view outside of the cached range",
+			params: []*remote.ViewCalendarParams{{
+				RemoteUserID: MockRemoteUserID,
+				StartTime:    end,
+				EndTime:      end.AddDate(0, 0, 1),
+			}},
+			setupMock: func(_ *testing.T, m *mockCalendarCache) {
+				m.client.EXPECT().DoBatchViewCalendarRequests(gomock.Len(1)).Return([]*remote.ViewCalendarResponse{
+					{RemoteUserID: MockRemoteUserID, Events: []*remote.Event{}},
+				}, nil).Times(1)
+			},
+			expected: []*remote.ViewCalendarResponse{
+				{RemoteUserID: MockRemoteUserID, Events: []*remote.Event{}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mscalendar, mockStore, _, _, _, mockClient, _ := GetMockSetup(t)
+			mscalendar.client = mockClient
+			mscalendar.Config.EnableCalendarCache = !tc.disabled
+			tc.setupMock(t, &mockCalendarCache{store: mockStore, client: mockClient})
+
+			views, err := mscalendar.viewCalendars(tc.params)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, views)
+		})
+	}
+}
+
+type mockCalendarCache struct {
+	store  *mock_store.MockStore
+	client *mock_remote.MockClient
+}
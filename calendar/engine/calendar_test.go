@@ -189,11 +189,13 @@ func TestCreateCalendar(t *testing.T) {
 
 func TestCreateEvent(t *testing.T) {
 	mscalendar, mockStore, mockPoster, _, mockPluginAPI, mockClient, mockLogger := GetMockSetup(t)
+	attendeeID := "attendeeMMUserID"
 
 	tests := []struct {
 		name          string
 		user          *User
 		event         *remote.Event
+		attendees     []*AttendeeResolution
 		setupMock     func()
 		assertions    func(t *testing.T, createdEvent *remote.Event, err error)
 		expectedEvent *remote.Event
@@ -213,28 +215,57 @@ func TestCreateEvent(t *testing.T) {
 			name:  "error creating direct message",
 			user:  GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, nil),
 			event: GetMockEvent(MockEventName, nil, nil, nil, nil),
+			attendees: []*AttendeeResolution{
+				{MattermostUserID: attendeeID, Username: "attendee", Email: "attendee@mattermost.com", Outcome: AttendeeInvitedByMattermostEmail},
+			},
 			setupMock: func() {
-				mockStore.EXPECT().LoadUser(MockMMUserID).Return(nil, errors.New("not found")).Times(1)
+				mscalendar.Config.MattermostShowEmailAddress = true
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockPoster.EXPECT().DM(MockMMUserID, gomock.AssignableToTypeOf(""), "testDisplayName", "testDisplayName", "testCommandTrigger").Return("", fmt.Errorf("error creating DM")).Times(1)
+				mockPoster.EXPECT().DM(attendeeID, gomock.AssignableToTypeOf(""), "testDisplayName", "testDisplayName", "testCommandTrigger").Return("", fmt.Errorf("error creating DM")).Times(1)
 				mockLogger.EXPECT().Warnf("CreateEvent error creating DM. err=%v", gomock.Any())
-				mockClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).Return(&remote.Event{}, nil).Times(1)
+				mockClient.EXPECT().CreateEvent(MockRemoteUserID, &remote.Event{
+					Subject:   MockEventName,
+					Attendees: []*remote.Attendee{{EmailAddress: &remote.EmailAddress{Address: "attendee@mattermost.com"}}},
+				}).Return(&remote.Event{}, nil).Times(1)
+				mockPoster.EXPECT().DM(MockMMUserID, "%s", "Invitations for **"+MockEventName+"**:\n- @attendee: invited at attendee@mattermost.com, the email of their Mattermost account, because they have not connected their account").Return("", nil).Times(1)
 			},
 			assertions: func(t *testing.T, createdEvent *remote.Event, err error) {
+				mscalendar.Config.MattermostShowEmailAddress = false
 				require.NoError(t, err)
 				require.NotNil(t, createdEvent)
 				require.Equal(t, &remote.Event{}, createdEvent)
 			},
 		},
+		{
+			name:  "Mattermost email hidden",
+			user:  GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, nil),
+			event: GetMockEvent(MockEventName, nil, nil, nil, nil),
+			attendees: []*AttendeeResolution{
+				{MattermostUserID: attendeeID, Username: "attendee", Email: "attendee@mattermost.com", Outcome: AttendeeInvitedByMattermostEmail},
+			},
+			setupMock: func() {
+				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
+				mockClient.EXPECT().CreateEvent(MockRemoteUserID, &remote.Event{Subject: MockEventName}).Return(&remote.Event{}, nil).Times(1)
+				mockPoster.EXPECT().DM(MockMMUserID, "%s", "Invitations for **"+MockEventName+"**:\n- @attendee: not invited, no email address was found").Return("", nil).Times(1)
+			},
+			assertions: func(t *testing.T, createdEvent *remote.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, &remote.Event{}, createdEvent)
+			},
+		},
 		{
 			name:  "error creating event",
 			user:  GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, nil),
 			event: GetMockEvent(MockEventName, nil, nil, nil, nil),
+			attendees: []*AttendeeResolution{
+				{MattermostUserID: attendeeID, Username: "attendee", Email: "attendee@example.com", Outcome: AttendeeInvited},
+			},
 			setupMock: func() {
-				mockStore.EXPECT().LoadUser(MockMMUserID).Return(nil, errors.New("not found")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockPoster.EXPECT().DM(MockMMUserID, gomock.AssignableToTypeOf(""), "testDisplayName", "testDisplayName", "testCommandTrigger").Return("", fmt.Errorf("error creating DM")).Times(1).Return("", nil)
-				mockClient.EXPECT().CreateEvent(MockRemoteUserID, &remote.Event{Subject: MockEventName}).Return(nil, fmt.Errorf("error creating event")).Times(1)
+				mockClient.EXPECT().CreateEvent(MockRemoteUserID, &remote.Event{
+					Subject:   MockEventName,
+					Attendees: []*remote.Attendee{{EmailAddress: &remote.EmailAddress{Address: "attendee@example.com"}}},
+				}).Return(nil, fmt.Errorf("error creating event")).Times(1)
 			},
 			assertions: func(t *testing.T, createdEvent *remote.Event, err error) {
 				require.EqualError(t, err, "error creating event")
@@ -250,10 +281,11 @@ func TestCreateEvent(t *testing.T) {
 				&remote.DateTime{DateTime: "2024-10-01T10:00:00", TimeZone: "UTC"},
 				[]*remote.Attendee{{EmailAddress: &remote.EmailAddress{Address: "attendee1@example.com"}}},
 			),
+			attendees: []*AttendeeResolution{
+				{MattermostUserID: attendeeID, Username: "attendee", Outcome: AttendeeNotInvited},
+			},
 			setupMock: func() {
-				mockStore.EXPECT().LoadUser(MockMMUserID).Return(nil, errors.New("not found")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockPoster.EXPECT().DM(MockMMUserID, gomock.AssignableToTypeOf(""), "testDisplayName", "testDisplayName", "testCommandTrigger").Return("", fmt.Errorf("error creating DM")).Times(1).Return("", nil)
 				mockClient.EXPECT().CreateEvent(MockRemoteUserID, &remote.Event{
 					Subject:   MockEventName,
 					Location:  &remote.Location{DisplayName: "Test Location"},
@@ -261,6 +293,7 @@ func TestCreateEvent(t *testing.T) {
 					End:       &remote.DateTime{DateTime: "2024-10-01T10:00:00", TimeZone: "UTC"},
 					Attendees: []*remote.Attendee{{EmailAddress: &remote.EmailAddress{Address: "attendee1@example.com"}}},
 				}).Return(&remote.Event{Subject: "Created Test Event", ID: "123"}, nil).Times(1)
+				mockPoster.EXPECT().DM(MockMMUserID, "%s", "Invitations for **"+MockEventName+"**:\n- @attendee: not invited, no email address was found").Return("", nil).Times(1)
 			},
 			assertions: func(t *testing.T, createdEvent *remote.Event, err error) {
 				require.NoError(t, err)
@@ -272,7 +305,7 @@ func TestCreateEvent(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
-			createdEvent, err := mscalendar.CreateEvent(tt.user, tt.event, []string{MockMMUserID})
+			createdEvent, err := mscalendar.CreateEvent(tt.user, tt.event, tt.attendees)
 			tt.assertions(t, createdEvent, err)
 		})
 	}
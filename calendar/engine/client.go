@@ -20,9 +20,14 @@ func (m *mscalendar) MakeClient() (remote.Client, error) {
 		return nil, err
 	}
 
-	return m.Remote.MakeUserClient(context.Background(), m.actingUser.OAuth2Token, m.actingUser.MattermostUserID, m.Poster, m.Store), nil
+	client := m.Remote.MakeUserClient(context.Background(), m.actingUser.OAuth2Token, m.actingUser.MattermostUserID, m.Poster, m.Store)
+	return NewCachedClient(m.Env, client), nil
 }
 
 func (m *mscalendar) MakeSuperuserClient() (remote.Client, error) {
-	return m.Remote.MakeSuperuserClient(context.Background())
+	client, err := m.Remote.MakeSuperuserClient(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return NewCachedClient(m.Env, client), nil
 }
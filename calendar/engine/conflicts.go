@@ -0,0 +1,180 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const (
+	proposalSearchDays = 5
+	maxProposalsPerDay = 4
+	maxProposals       = 20
+	proposalStep       = 30 * time.Minute
+
+	workingHoursFormat = "15:04:05"
+)
+
+var defaultWorkingDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday"}
+
+// SuggestNewTimes returns slots of the same length as the event in which the
+// user is free, within their working hours of the next few days, so they can
+// propose one of them to the organizer.
+func (m *mscalendar) SuggestNewTimes(user *User, eventID string) ([]*remote.TimeSlot, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := m.client.GetEvent(user.Remote.ID, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting event")
+	}
+	if event.Start == nil || event.End == nil {
+		return nil, errors.New("event has no time")
+	}
+	duration := event.End.Time().Sub(event.Start.Time())
+	if duration <= 0 {
+		return nil, errors.New("event has no duration")
+	}
+
+	mailbox, err := m.client.GetMailboxSettings(user.Remote.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting mailbox settings")
+	}
+	loc, err := time.LoadLocation(tz.Go(mailbox.TimeZone))
+	if err != nil {
+		loc = time.UTC
+	}
+
+	now := time.Now().In(loc)
+	from := event.Start.Time().In(loc)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	if from.Before(now) {
+		from = now
+	}
+	to := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, proposalSearchDays)
+
+	busy, err := m.GetFreeBusy(user, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []*remote.TimeSlot{}
+	for _, start := range findFreeSlots(busy, duration, from, to, mailbox.WorkingHours, loc) {
+		slots = append(slots, &remote.TimeSlot{
+			Start: remote.NewDateTime(start.UTC(), "UTC").In(mailbox.TimeZone),
+			End:   remote.NewDateTime(start.Add(duration).UTC(), "UTC").In(mailbox.TimeZone),
+		})
+	}
+	return slots, nil
+}
+
+// findFreeSlots returns the start times of slots of the given duration that
+// do not overlap the busy intervals and fit in the working hours.
+func findFreeSlots(busy []*BusyInterval, duration time.Duration, from, to time.Time, workingHours remote.WorkingHours, loc *time.Location) []time.Time {
+	dayStart, dayEnd := parseWorkingHours(workingHours)
+	workingDays := workingHours.DaysOfWeek
+	if len(workingDays) == 0 {
+		workingDays = defaultWorkingDays
+	}
+
+	result := []time.Time{}
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !isWorkingDay(day.Weekday(), workingDays) {
+			continue
+		}
+
+		count := 0
+		end := wallClock(day, dayEnd)
+		for start := wallClock(day, dayStart); !start.Add(duration).After(end); start = start.Add(proposalStep) {
+			if start.Before(from) || start.Add(duration).After(to) {
+				continue
+			}
+			if overlapsBusy(start, start.Add(duration), busy) {
+				continue
+			}
+			result = append(result, start)
+			if len(result) == maxProposals {
+				return result
+			}
+			count++
+			if count == maxProposalsPerDay {
+				break
+			}
+		}
+	}
+	return result
+}
+
+func parseWorkingHours(workingHours remote.WorkingHours) (start, end time.Duration) {
+	start, end = 9*time.Hour, 17*time.Hour
+
+	s, errStart := time.Parse(workingHoursFormat, truncateWorkingHour(workingHours.StartTime))
+	e, errEnd := time.Parse(workingHoursFormat, truncateWorkingHour(workingHours.EndTime))
+	if errStart != nil || errEnd != nil || !e.After(s) {
+		return start, end
+	}
+
+	return time.Duration(s.Hour())*time.Hour + time.Duration(s.Minute())*time.Minute,
+		time.Duration(e.Hour())*time.Hour + time.Duration(e.Minute())*time.Minute
+}
+
+// wallClock returns the time of the day at the given offset from midnight on
+// the clock of its location. Adding the offset to midnight instead is an hour
+// off on the days the clocks change.
+func wallClock(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, day.Location())
+}
+
+// truncateWorkingHour drops the fractional seconds Graph adds to working
+// hours, i.e. 08:00:00.0000000.
+func truncateWorkingHour(value string) string {
+	if i := strings.Index(value, "."); i >= 0 {
+		return value[:i]
+	}
+	return value
+}
+
+func isWorkingDay(day time.Weekday, workingDays []string) bool {
+	for _, d := range workingDays {
+		if strings.EqualFold(d, day.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+func overlapsBusy(start, end time.Time, busy []*BusyInterval) bool {
+	for _, b := range busy {
+		if b.Start.Before(end) && start.Before(b.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// findEventConflicts returns the accepted events of the user that overlap
+// with the event.
+func findEventConflicts(client remote.Client, remoteUserID string, event *remote.Event) ([]*remote.Event, error) {
+	if event.Start == nil || event.End == nil || event.IsAllDay || event.IsCancelled {
+		return nil, nil
+	}
+
+	events, err := client.GetDefaultCalendarView(remoteUserID, event.Start.Time(), event.End.Time())
+	if err != nil {
+		return nil, err
+	}
+	return views.FindConflictsWith(event, events), nil
+}
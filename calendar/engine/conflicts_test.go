@@ -0,0 +1,135 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestFindFreeSlots(t *testing.T) {
+	loc := time.UTC
+	workingHours := remote.WorkingHours{
+		StartTime:  "09:00:00.0000000",
+		EndTime:    "11:00:00.0000000",
+		DaysOfWeek: []string{"wednesday", "thursday"},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		busy     []*BusyInterval
+		from     time.Time
+		to       time.Time
+		expected []time.Time
+	}{
+		{
+			name: "free morning",
+			from: makeTime(0, 0, loc),
+			to:   makeTime(0, 0, loc).AddDate(0, 0, 1),
+			expected: []time.Time{
+				makeTime(9, 0, loc),
+				makeTime(9, 30, loc),
+				makeTime(10, 0, loc),
+			},
+		},
+		{
+			name: "busy intervals are skipped",
+			busy: []*BusyInterval{{Start: makeTime(9, 15, loc), End: makeTime(10, 0, loc)}},
+			from: makeTime(0, 0, loc),
+			to:   makeTime(0, 0, loc).AddDate(0, 0, 1),
+			expected: []time.Time{
+				makeTime(10, 0, loc),
+			},
+		},
+		{
+			name: "slots before the start are skipped",
+			from: makeTime(9, 45, loc),
+			to:   makeTime(0, 0, loc).AddDate(0, 0, 1),
+			expected: []time.Time{
+				makeTime(10, 0, loc),
+			},
+		},
+		{
+			name: "non working days are skipped",
+			from: makeTime(0, 0, loc).AddDate(0, 0, 2),
+			to:   makeTime(0, 0, loc).AddDate(0, 0, 3),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			slots := findFreeSlots(tc.busy, time.Hour, tc.from, tc.to, workingHours, loc)
+			if len(tc.expected) == 0 {
+				require.Empty(t, slots)
+				return
+			}
+			require.Equal(t, tc.expected, slots)
+		})
+	}
+}
+
+func TestFindFreeSlotsOnDSTDay(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	workingHours := remote.WorkingHours{
+		StartTime:  "09:00:00.0000000",
+		EndTime:    "10:00:00.0000000",
+		DaysOfWeek: []string{"sunday"},
+	}
+
+	for _, day := range []time.Time{
+		time.Date(2020, 3, 8, 0, 0, 0, 0, loc),
+		time.Date(2020, 11, 1, 0, 0, 0, 0, loc),
+	} {
+		slots := findFreeSlots(nil, 30*time.Minute, day, day.AddDate(0, 0, 1), workingHours, loc)
+		require.Equal(t, []time.Time{
+			time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, loc),
+			time.Date(day.Year(), day.Month(), day.Day(), 9, 30, 0, 0, loc),
+		}, slots, day.String())
+	}
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	for _, tc := range []struct {
+		start         string
+		end           string
+		expectedStart time.Duration
+		expectedEnd   time.Duration
+	}{
+		{"08:30:00.0000000", "16:00:00.0000000", 8*time.Hour + 30*time.Minute, 16 * time.Hour},
+		{"08:00:00", "18:00:00", 8 * time.Hour, 18 * time.Hour},
+		{"", "", 9 * time.Hour, 17 * time.Hour},
+		{"18:00:00", "08:00:00", 9 * time.Hour, 17 * time.Hour},
+	} {
+		start, end := parseWorkingHours(remote.WorkingHours{StartTime: tc.start, EndTime: tc.end})
+		require.Equal(t, tc.expectedStart, start, tc.start)
+		require.Equal(t, tc.expectedEnd, end, tc.end)
+	}
+}
+
+func TestFindEventConflicts(t *testing.T) {
+	_, _, _, _, _, mockClient, _ := GetMockSetup(t)
+	loc := time.UTC
+
+	invitation := &remote.Event{
+		ID:    "invitation",
+		Start: remote.NewDateTime(makeTime(10, 0, loc), "UTC"),
+		End:   remote.NewDateTime(makeTime(11, 0, loc), "UTC"),
+	}
+	accepted := &remote.Event{
+		ID:             "accepted",
+		Start:          remote.NewDateTime(makeTime(10, 30, loc), "UTC"),
+		End:            remote.NewDateTime(makeTime(11, 30, loc), "UTC"),
+		ResponseStatus: &remote.EventResponseStatus{Response: remote.EventResponseStatusAccepted},
+	}
+
+	mockClient.EXPECT().GetDefaultCalendarView(MockRemoteUserID, gomock.Any(), gomock.Any()).Return([]*remote.Event{invitation, accepted}, nil).Times(1)
+
+	conflicts, err := findEventConflicts(mockClient, MockRemoteUserID, invitation)
+	require.NoError(t, err)
+	require.Equal(t, []*remote.Event{accepted}, conflicts)
+}
@@ -186,7 +186,7 @@ func (m *mscalendar) ProcessAllDailySummary(now time.Time) error {
 
 	if !fetchIndividually {
 		var err error
-		calendarViews, err = m.client.DoBatchViewCalendarRequests(requests)
+		calendarViews, err = m.viewCalendars(requests)
 		if err != nil {
 			return err
 		}
@@ -5,16 +5,19 @@ package engine
 
 import (
 	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
 
 type EventResponder interface {
-	AcceptEvent(user *User, eventID string) error
-	DeclineEvent(user *User, eventID string) error
-	TentativelyAcceptEvent(user *User, eventID string) error
-	RespondToEvent(user *User, eventID, response string) error
+	AcceptEvent(user *User, eventID string, opts *remote.EventResponseOptions) error
+	DeclineEvent(user *User, eventID string, opts *remote.EventResponseOptions) error
+	TentativelyAcceptEvent(user *User, eventID string, opts *remote.EventResponseOptions) error
+	RespondToEvent(user *User, eventID, response string, opts *remote.EventResponseOptions) error
+	SuggestNewTimes(user *User, eventID string) ([]*remote.TimeSlot, error)
 }
 
-func (m *mscalendar) AcceptEvent(user *User, eventID string) error {
+func (m *mscalendar) AcceptEvent(user *User, eventID string, opts *remote.EventResponseOptions) error {
 	err := m.Filter(
 		withClient,
 		withUserExpanded(user),
@@ -23,10 +26,10 @@ func (m *mscalendar) AcceptEvent(user *User, eventID string) error {
 		return err
 	}
 
-	return m.client.AcceptEvent(user.Remote.ID, eventID)
+	return m.client.AcceptEvent(user.Remote.ID, eventID, opts)
 }
 
-func (m *mscalendar) DeclineEvent(user *User, eventID string) error {
+func (m *mscalendar) DeclineEvent(user *User, eventID string, opts *remote.EventResponseOptions) error {
 	err := m.Filter(
 		withClient,
 		withUserExpanded(user),
@@ -35,10 +38,10 @@ func (m *mscalendar) DeclineEvent(user *User, eventID string) error {
 		return err
 	}
 
-	return m.client.DeclineEvent(user.Remote.ID, eventID)
+	return m.client.DeclineEvent(user.Remote.ID, eventID, opts)
 }
 
-func (m *mscalendar) TentativelyAcceptEvent(user *User, eventID string) error {
+func (m *mscalendar) TentativelyAcceptEvent(user *User, eventID string, opts *remote.EventResponseOptions) error {
 	err := m.Filter(
 		withClient,
 		withUserExpanded(user),
@@ -47,10 +50,10 @@ func (m *mscalendar) TentativelyAcceptEvent(user *User, eventID string) error {
 		return err
 	}
 
-	return m.client.TentativelyAcceptEvent(user.Remote.ID, eventID)
+	return m.client.TentativelyAcceptEvent(user.Remote.ID, eventID, opts)
 }
 
-func (m *mscalendar) RespondToEvent(user *User, eventID, response string) error {
+func (m *mscalendar) RespondToEvent(user *User, eventID, response string, opts *remote.EventResponseOptions) error {
 	if response == OptionNotResponded {
 		return errors.New("not responded is not a valid response")
 	}
@@ -65,11 +68,11 @@ func (m *mscalendar) RespondToEvent(user *User, eventID, response string) error
 
 	switch response {
 	case OptionYes:
-		return m.client.AcceptEvent(user.Remote.ID, eventID)
+		return m.client.AcceptEvent(user.Remote.ID, eventID, opts)
 	case OptionNo:
-		return m.client.DeclineEvent(user.Remote.ID, eventID)
+		return m.client.DeclineEvent(user.Remote.ID, eventID, opts)
 	case OptionMaybe:
-		return m.client.TentativelyAcceptEvent(user.Remote.ID, eventID)
+		return m.client.TentativelyAcceptEvent(user.Remote.ID, eventID, opts)
 	default:
 		return errors.New(response + " is not a valid response")
 	}
@@ -37,7 +37,7 @@ func TestAcceptEvent(t *testing.T) {
 			user: GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to accept the event")).Times(1)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(errors.New("unable to accept the event")).Times(1)
 			},
 			assertion: func(err error) {
 				require.Error(t, err)
@@ -49,7 +49,7 @@ func TestAcceptEvent(t *testing.T) {
 			user: GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil).Times(1)
 			},
 			assertion: func(err error) {
 				require.NoError(t, err)
@@ -60,7 +60,7 @@ func TestAcceptEvent(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			err := mscalendar.AcceptEvent(tt.user, MockEventID)
+			err := mscalendar.AcceptEvent(tt.user, MockEventID, nil)
 
 			tt.assertion(err)
 		})
@@ -92,7 +92,7 @@ func TestDeclineEvent(t *testing.T) {
 			user: GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to decline event")).Times(1)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, nil).Return(errors.New("unable to decline event")).Times(1)
 			},
 			assertion: func(err error) {
 				require.Error(t, err)
@@ -104,7 +104,7 @@ func TestDeclineEvent(t *testing.T) {
 			user: GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, nil).Return(nil).Times(1)
 			},
 			assertion: func(err error) {
 				require.NoError(t, err)
@@ -115,7 +115,7 @@ func TestDeclineEvent(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			err := mscalendar.DeclineEvent(tt.user, MockEventID)
+			err := mscalendar.DeclineEvent(tt.user, MockEventID, nil)
 
 			tt.assertion(err)
 		})
@@ -147,7 +147,7 @@ func TestTentativelyAcceptEvent(t *testing.T) {
 			user: GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to tentatively accept the event")).Times(1)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, nil).Return(errors.New("unable to tentatively accept the event")).Times(1)
 			},
 			assertion: func(err error) {
 				require.Error(t, err)
@@ -159,7 +159,7 @@ func TestTentativelyAcceptEvent(t *testing.T) {
 			user: GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil).Times(1)
 			},
 			assertion: func(err error) {
 				require.NoError(t, err)
@@ -170,7 +170,7 @@ func TestTentativelyAcceptEvent(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			err := mscalendar.TentativelyAcceptEvent(tt.user, MockEventID)
+			err := mscalendar.TentativelyAcceptEvent(tt.user, MockEventID, nil)
 
 			tt.assertion(err)
 		})
@@ -224,7 +224,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionYes,
 			user:     GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -236,7 +236,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionYes,
 			user:     GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to accept the event")).Times(1)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(errors.New("unable to accept the event")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -249,7 +249,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionNo,
 			user:     GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, nil).Return(nil).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -261,7 +261,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionNo,
 			user:     GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to decline the event")).Times(1)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, nil).Return(errors.New("unable to decline the event")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -274,7 +274,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionMaybe,
 			user:     GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -286,7 +286,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionMaybe,
 			user:     GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to tentatively accept the event")).Times(1)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, nil).Return(errors.New("unable to tentatively accept the event")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -299,7 +299,7 @@ func TestRespondToEvent(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			err := mscalendar.RespondToEvent(tt.user, MockEventID, tt.response)
+			err := mscalendar.RespondToEvent(tt.user, MockEventID, tt.response, nil)
 
 			tt.assertion(err)
 		})
@@ -0,0 +1,172 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const (
+	ExportDefaultDays = 14
+	ExportMaxDays     = 90
+
+	exportAllDayFormat = "2006-01-02"
+)
+
+// ExportCalendar returns the events of the given number of days, starting
+// today in the time zone of the user mailbox.
+func (m *mscalendar) ExportCalendar(user *User, now time.Time, days int) ([]byte, error) {
+	data, _, _, err := m.exportCalendar(user, now, days)
+	return data, err
+}
+
+func (m *mscalendar) exportCalendar(user *User, now time.Time, days int) (data []byte, from, to time.Time, err error) {
+	err = m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, from, to, err
+	}
+
+	mailbox, err := m.client.GetMailboxSettings(user.Remote.ID)
+	if err != nil {
+		return nil, from, to, errors.Wrap(err, "error getting mailbox settings")
+	}
+
+	loc, err := time.LoadLocation(tz.Go(mailbox.TimeZone))
+	if err != nil {
+		loc = time.UTC
+	}
+
+	now = now.In(loc)
+	from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	to = from.AddDate(0, 0, days)
+
+	events, err := m.client.GetEventsBetweenDates(user.Remote.ID, from, to)
+	if err != nil {
+		return nil, from, to, errors.Wrap(err, "error getting events")
+	}
+
+	cal := &ical.Calendar{
+		ProdID: fmt.Sprintf("-//Mattermost//%s//EN", m.Provider.DisplayName),
+		Method: "PUBLISH",
+	}
+	for _, e := range events {
+		if e.IsCancelled {
+			continue
+		}
+		cal.Events = append(cal.Events, remoteEventToICal(e, loc))
+	}
+
+	data, err = cal.Marshal()
+	return data, from, to, err
+}
+
+func (m *mscalendar) DMCalendarExport(user *User, now time.Time, days int) error {
+	data, from, to, err := m.exportCalendar(user, now, days)
+	if err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf("calendar-%s-%s%s", from.Format(exportAllDayFormat), to.Format(exportAllDayFormat), ical.FileExtension)
+	message := fmt.Sprintf("Here are your events from %s to %s.", from.Format(exportAllDayFormat), to.Format(exportAllDayFormat))
+	_, err = m.Poster.DMWithFile(user.MattermostUserID, message, fileName, data)
+	if err != nil {
+		return errors.Wrap(err, "error sending calendar export")
+	}
+	return nil
+}
+
+func remoteEventToICal(e *remote.Event, loc *time.Location) *ical.Event {
+	evt := &ical.Event{
+		UID:         e.ICalUID,
+		Summary:     e.Subject,
+		Description: e.BodyPreview,
+		URL:         e.Weblink,
+		AllDay:      e.IsAllDay,
+		Status:      ical.StatusConfirmed,
+	}
+	if evt.UID == "" {
+		evt.UID = e.ID
+	}
+	if e.ShowAs == "tentative" {
+		evt.Status = ical.StatusTentative
+	}
+
+	if e.Start != nil {
+		evt.Start = remoteDateTimeToICal(e.Start, e.IsAllDay, loc)
+	}
+	if e.End != nil {
+		evt.End = remoteDateTimeToICal(e.End, e.IsAllDay, loc)
+	}
+
+	if e.Location != nil {
+		evt.Location = e.Location.DisplayName
+	}
+
+	if e.Organizer != nil && e.Organizer.EmailAddress != nil {
+		evt.Organizer = &ical.Person{
+			Name:  e.Organizer.EmailAddress.Name,
+			Email: e.Organizer.EmailAddress.Address,
+		}
+	}
+
+	for _, a := range e.Attendees {
+		if a.EmailAddress == nil || a.EmailAddress.Address == "" {
+			continue
+		}
+		attendee := &ical.Attendee{
+			Person: ical.Person{
+				Name:  a.EmailAddress.Name,
+				Email: a.EmailAddress.Address,
+			},
+			Role:     ical.RoleRequired,
+			PartStat: ical.PartStatNeedsAction,
+		}
+		switch a.Type {
+		case remote.AttendeeTypeOptional:
+			attendee.Role = ical.RoleOptional
+		case remote.AttendeeTypeResource:
+			attendee.Role = ical.RoleNonParticipant
+		}
+		if a.Status != nil {
+			attendee.PartStat = responseToPartStat(a.Status.Response)
+		}
+		evt.Attendees = append(evt.Attendees, attendee)
+	}
+
+	return evt
+}
+
+func remoteDateTimeToICal(dt *remote.DateTime, allDay bool, loc *time.Location) time.Time {
+	if allDay {
+		// All-day events are floating dates, the time zone must be ignored.
+		day, err := time.Parse(exportAllDayFormat, strings.SplitN(dt.DateTime, "T", 2)[0])
+		if err == nil {
+			return day
+		}
+	}
+	return dt.Time().In(loc)
+}
+
+func responseToPartStat(response string) string {
+	switch response {
+	case remote.EventResponseStatusAccepted, "organizer":
+		return ical.PartStatAccepted
+	case remote.EventResponseStatusTentative, "tentativelyAccepted":
+		return ical.PartStatTentative
+	case remote.EventResponseStatusDeclined:
+		return ical.PartStatDeclined
+	}
+	return ical.PartStatNeedsAction
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+func TestExportCalendar(t *testing.T) {
+	mscalendar, _, _, _, _, mockClient, _ := GetMockSetup(t)
+	// Still February 29th in the mailbox time zone
+	now := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	from := time.Date(2024, 2, 29, 0, 0, 0, 0, loc)
+	to := time.Date(2024, 3, 14, 0, 0, 0, 0, loc)
+
+	tests := []struct {
+		name       string
+		setupMock  func()
+		assertions func(t *testing.T, data []byte, err error)
+	}{
+		{
+			name: "error getting events",
+			setupMock: func() {
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "Eastern Standard Time"}, nil).Times(1)
+				mockClient.EXPECT().GetEventsBetweenDates(MockRemoteUserID, from, to).Return(nil, errors.New("some error")).Times(1)
+			},
+			assertions: func(t *testing.T, _ []byte, err error) {
+				require.EqualError(t, err, "error getting events: some error")
+			},
+		},
+		{
+			name: "events are exported in the mailbox time zone",
+			setupMock: func() {
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "Eastern Standard Time"}, nil).Times(1)
+				mockClient.EXPECT().GetEventsBetweenDates(MockRemoteUserID, from, to).Return([]*remote.Event{
+					{
+						ICalUID: "uid_1",
+						Subject: MockEventName,
+						Start:   &remote.DateTime{DateTime: "2024-03-04T15:00:00.0000000", TimeZone: "UTC"},
+						End:     &remote.DateTime{DateTime: "2024-03-04T16:00:00.0000000", TimeZone: "UTC"},
+						Organizer: &remote.Attendee{
+							EmailAddress: &remote.EmailAddress{Address: "organizer@example.com", Name: "Organizer"},
+						},
+						Attendees: []*remote.Attendee{
+							{
+								Type:         remote.AttendeeTypeOptional,
+								EmailAddress: &remote.EmailAddress{Address: "att@example.com"},
+								Status:       &remote.EventResponseStatus{Response: "tentativelyAccepted"},
+							},
+						},
+					},
+					{
+						ICalUID:     "uid_cancelled",
+						IsCancelled: true,
+						Start:       &remote.DateTime{DateTime: "2024-03-04T15:00:00.0000000", TimeZone: "UTC"},
+					},
+				}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, data []byte, err error) {
+				require.NoError(t, err)
+				out := string(data)
+				require.Contains(t, out, "UID:uid_1\r\n")
+				require.NotContains(t, out, "uid_cancelled")
+				require.Contains(t, out, "TZID:America/New_York\r\n")
+				require.Contains(t, out, "DTSTART;TZID=America/New_York:20240304T100000\r\n")
+				require.Contains(t, out, "ORGANIZER;CN=Organizer:mailto:organizer@example.com\r\n")
+				require.Contains(t, out, "ATTENDEE;ROLE=OPT-PARTICIPANT;PARTSTAT=TENTATIVE:mailto:att@example.com\r\n")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			user := GetMockUser(model.NewString(MockRemoteUserID), model.NewString(MockMMModelUserID), MockMMUserID, nil)
+			data, err := mscalendar.ExportCalendar(user, now, 14)
+
+			tt.assertions(t, data, err)
+		})
+	}
+}
+
+func TestRemoteEventToICalAllDay(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	evt := remoteEventToICal(&remote.Event{
+		ID:       "event_id",
+		IsAllDay: true,
+		Start:    &remote.DateTime{DateTime: "2024-03-04T00:00:00.0000000", TimeZone: "UTC"},
+		End:      &remote.DateTime{DateTime: "2024-03-05T00:00:00.0000000", TimeZone: "UTC"},
+	}, loc)
+
+	require.Equal(t, "event_id", evt.UID)
+	require.True(t, evt.AllDay)
+	require.Equal(t, ical.StatusConfirmed, evt.Status)
+	require.Equal(t, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), evt.Start)
+	require.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), evt.End)
+}
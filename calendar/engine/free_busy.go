@@ -0,0 +1,268 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+const (
+	FreeBusyDefaultDays = 14
+	FreeBusyMaxDays     = 60
+	FreeBusyMaxUsers    = 100
+
+	freeBusyTokenLength = 32
+	freeBusyInterval    = 30
+)
+
+type FreeBusy interface {
+	EnableFreeBusyFeed(user *User) (string, error)
+	DisableFreeBusyFeed(user *User) error
+	GetFreeBusyFeedURL(user *User) (string, error)
+	GetFreeBusy(user *User, from, to time.Time) ([]*BusyInterval, error)
+	GetUsersFreeBusy(user *User, mattermostUserIDs []string, from, to time.Time) ([]*UserFreeBusy, error)
+	ExportFreeBusy(user *User, from, to time.Time) ([]byte, error)
+}
+
+// BusyInterval is a time range in which a user is not free. It deliberately
+// carries no detail about the event occupying it. Status is one of the
+// remote.ScheduleStatus* values.
+type BusyInterval struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Status string    `json:"status"`
+}
+
+// UserFreeBusy holds the busy intervals of a Mattermost user. Error is set
+// instead when the schedule of the user could not be read, e.g. because they
+// are not connected.
+type UserFreeBusy struct {
+	MattermostUserID string          `json:"mattermost_user_id"`
+	Busy             []*BusyInterval `json:"busy"`
+	Error            string          `json:"error,omitempty"`
+}
+
+func (m *mscalendar) EnableFreeBusyFeed(user *User) (string, error) {
+	err := m.Filter(withUserExpanded(user))
+	if err != nil {
+		return "", err
+	}
+
+	b := make([]byte, freeBusyTokenLength)
+	if _, err = rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "error generating free/busy token")
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	err = m.Store.StoreFreeBusyToken(user.MattermostUserID, token)
+	if err != nil {
+		return "", errors.Wrap(err, "error storing free/busy token")
+	}
+
+	return m.freeBusyFeedURL(token), nil
+}
+
+func (m *mscalendar) DisableFreeBusyFeed(user *User) error {
+	err := m.Store.DeleteFreeBusyToken(user.MattermostUserID)
+	if err != nil {
+		return errors.Wrap(err, "error deleting free/busy token")
+	}
+	return nil
+}
+
+func (m *mscalendar) GetFreeBusyFeedURL(user *User) (string, error) {
+	token, err := m.Store.LoadFreeBusyToken(user.MattermostUserID)
+	if err != nil {
+		return "", err
+	}
+	return m.freeBusyFeedURL(token), nil
+}
+
+func (m *mscalendar) GetFreeBusy(user *User, from, to time.Time) ([]*BusyInterval, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !to.After(from) {
+		return nil, errors.New("end time must be after start time")
+	}
+
+	requests := []*remote.ScheduleUserInfo{{
+		RemoteUserID: user.Remote.ID,
+		Mail:         user.Remote.Mail,
+	}}
+	schedules, err := m.client.GetSchedule(requests, remote.NewDateTime(from.UTC(), "UTC"), remote.NewDateTime(to.UTC(), "UTC"), freeBusyInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting schedule")
+	}
+
+	result := []*BusyInterval{}
+	for _, s := range schedules {
+		if s.Error != nil {
+			return nil, errors.Errorf("error getting schedule: %s", s.Error.Message)
+		}
+		result = append(result, busyIntervals(s)...)
+	}
+
+	sortBusyIntervals(result)
+	return result, nil
+}
+
+// GetUsersFreeBusy returns the busy intervals of each of the Mattermost users,
+// in the order they are given, reading their schedules on behalf of user with
+// as few requests as possible.
+func (m *mscalendar) GetUsersFreeBusy(user *User, mattermostUserIDs []string, from, to time.Time) ([]*UserFreeBusy, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !to.After(from) {
+		return nil, errors.New("end time must be after start time")
+	}
+	if len(mattermostUserIDs) > FreeBusyMaxUsers {
+		return nil, errors.Errorf("cannot get free/busy information of more than %d users", FreeBusyMaxUsers)
+	}
+
+	result := []*UserFreeBusy{}
+	byMail := map[string][]*UserFreeBusy{}
+	requests := []*remote.ScheduleUserInfo{}
+	for _, mattermostUserID := range mattermostUserIDs {
+		fb := &UserFreeBusy{
+			MattermostUserID: mattermostUserID,
+			Busy:             []*BusyInterval{},
+		}
+		result = append(result, fb)
+
+		storedUser, err := m.Store.LoadUser(mattermostUserID)
+		if err != nil || storedUser.Remote == nil || storedUser.Remote.Mail == "" {
+			fb.Error = "user not connected"
+			continue
+		}
+
+		mail := strings.ToLower(storedUser.Remote.Mail)
+		if _, ok := byMail[mail]; !ok {
+			requests = append(requests, &remote.ScheduleUserInfo{
+				RemoteUserID: user.Remote.ID,
+				Mail:         storedUser.Remote.Mail,
+			})
+		}
+		byMail[mail] = append(byMail[mail], fb)
+	}
+	if len(requests) == 0 {
+		return result, nil
+	}
+
+	schedules, err := m.client.GetSchedule(requests, remote.NewDateTime(from.UTC(), "UTC"), remote.NewDateTime(to.UTC(), "UTC"), freeBusyInterval)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting schedules")
+	}
+
+	found := map[string]bool{}
+	for _, s := range schedules {
+		mail := strings.ToLower(s.ScheduleID)
+		found[mail] = true
+		for _, fb := range byMail[mail] {
+			if s.Error != nil {
+				fb.Error = s.Error.Message
+				continue
+			}
+			fb.Busy = append(fb.Busy, busyIntervals(s)...)
+		}
+	}
+	for mail, fbs := range byMail {
+		for _, fb := range fbs {
+			if !found[mail] {
+				fb.Error = "schedule not available"
+			}
+			sortBusyIntervals(fb.Busy)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *mscalendar) ExportFreeBusy(user *User, from, to time.Time) ([]byte, error) {
+	intervals, err := m.GetFreeBusy(user, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	fb := &ical.FreeBusy{
+		UID:   fmt.Sprintf("freebusy-%s@mattermost", user.MattermostUserID),
+		Start: from,
+		End:   to,
+	}
+	if user.Remote.Mail != "" {
+		fb.Organizer = &ical.Person{Email: user.Remote.Mail}
+	}
+	for _, i := range intervals {
+		fb.Periods = append(fb.Periods, &ical.Period{
+			Start: i.Start,
+			End:   i.End,
+			Type:  freeBusyType(i.Status),
+		})
+	}
+
+	cal := &ical.Calendar{
+		ProdID:   fmt.Sprintf("-//Mattermost//%s//EN", m.Provider.DisplayName),
+		Method:   "PUBLISH",
+		FreeBusy: []*ical.FreeBusy{fb},
+	}
+	return cal.Marshal()
+}
+
+func (m *mscalendar) freeBusyFeedURL(token string) string {
+	return m.Config.PluginURL + config.PathFreeBusy + "/" + token
+}
+
+func busyIntervals(s *remote.ScheduleInformation) []*BusyInterval {
+	result := []*BusyInterval{}
+	for _, item := range s.ScheduleItems {
+		if item.Status == remote.ScheduleStatusFree || item.Start == nil || item.End == nil {
+			continue
+		}
+		result = append(result, &BusyInterval{
+			Start:  item.Start.Time().UTC(),
+			End:    item.End.Time().UTC(),
+			Status: item.Status,
+		})
+	}
+	return result
+}
+
+func sortBusyIntervals(intervals []*BusyInterval) {
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+}
+
+func freeBusyType(status string) string {
+	switch status {
+	case remote.ScheduleStatusTentative:
+		return ical.FreeBusyTypeBusyTentative
+	case remote.ScheduleStatusOof:
+		return ical.FreeBusyTypeBusyUnavailable
+	default:
+		return ical.FreeBusyTypeBusy
+	}
+}
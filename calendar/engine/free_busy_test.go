@@ -0,0 +1,168 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestGetFreeBusy(t *testing.T) {
+	mscalendar, _, _, _, _, mockClient, _ := GetMockSetup(t)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		setupMock  func()
+		assertions func(t *testing.T, result []*BusyInterval, err error)
+	}{
+		{
+			name: "error getting schedule",
+			setupMock: func() {
+				mockClient.EXPECT().GetSchedule(gomock.Len(1), gomock.Any(), gomock.Any(), freeBusyInterval).Return(nil, errors.New("some error")).Times(1)
+			},
+			assertions: func(t *testing.T, _ []*BusyInterval, err error) {
+				require.EqualError(t, err, "error getting schedule: some error")
+			},
+		},
+		{
+			name: "schedule error",
+			setupMock: func() {
+				mockClient.EXPECT().GetSchedule(gomock.Len(1), gomock.Any(), gomock.Any(), freeBusyInterval).Return([]*remote.ScheduleInformation{
+					{Error: &remote.ScheduleInformationError{Message: "mailbox not found"}},
+				}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, _ []*BusyInterval, err error) {
+				require.EqualError(t, err, "error getting schedule: mailbox not found")
+			},
+		},
+		{
+			name: "busy intervals sorted without free time",
+			setupMock: func() {
+				mockClient.EXPECT().GetSchedule(gomock.Len(1), gomock.Any(), gomock.Any(), freeBusyInterval).Return([]*remote.ScheduleInformation{{
+					ScheduleItems: []*remote.ScheduleItem{
+						{
+							Start:   remote.NewDateTime(from.Add(14*time.Hour), "UTC"),
+							End:     remote.NewDateTime(from.Add(15*time.Hour), "UTC"),
+							Status:  remote.ScheduleStatusTentative,
+							Subject: "Secret",
+						},
+						{
+							Start:  remote.NewDateTime(from.Add(11*time.Hour), "UTC"),
+							End:    remote.NewDateTime(from.Add(12*time.Hour), "UTC"),
+							Status: remote.ScheduleStatusFree,
+						},
+						{
+							Start:  remote.NewDateTime(from.Add(9*time.Hour), "UTC"),
+							End:    remote.NewDateTime(from.Add(10*time.Hour), "UTC"),
+							Status: remote.ScheduleStatusBusy,
+						},
+					},
+				}}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, result []*BusyInterval, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*BusyInterval{
+					{Start: from.Add(9 * time.Hour), End: from.Add(10 * time.Hour), Status: remote.ScheduleStatusBusy},
+					{Start: from.Add(14 * time.Hour), End: from.Add(15 * time.Hour), Status: remote.ScheduleStatusTentative},
+				}, result)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			user := GetMockUser(model.NewString(MockRemoteUserID), model.NewString(MockMMModelUserID), MockMMUserID, nil)
+			result, err := mscalendar.GetFreeBusy(user, from, to)
+
+			tt.assertions(t, result, err)
+		})
+	}
+}
+
+func TestGetUsersFreeBusy(t *testing.T) {
+	mscalendar, mockStore, _, _, _, mockClient, _ := GetMockSetup(t)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	user := GetMockUser(model.NewString(MockRemoteUserID), model.NewString(MockMMModelUserID), MockMMUserID, nil)
+
+	mockStore.EXPECT().LoadUser("alice").Return(&store.User{Remote: &remote.User{ID: "alice_remote", Mail: "Alice@example.com"}}, nil).Times(1)
+	mockStore.EXPECT().LoadUser("bob").Return(&store.User{Remote: &remote.User{ID: "bob_remote", Mail: "bob@example.com"}}, nil).Times(1)
+	mockStore.EXPECT().LoadUser("carol").Return(nil, store.ErrNotFound).Times(1)
+	mockStore.EXPECT().LoadUser("dave").Return(&store.User{Remote: &remote.User{ID: "dave_remote", Mail: "dave@example.com"}}, nil).Times(1)
+	mockClient.EXPECT().GetSchedule([]*remote.ScheduleUserInfo{
+		{RemoteUserID: MockRemoteUserID, Mail: "Alice@example.com"},
+		{RemoteUserID: MockRemoteUserID, Mail: "bob@example.com"},
+		{RemoteUserID: MockRemoteUserID, Mail: "dave@example.com"},
+	}, gomock.Any(), gomock.Any(), freeBusyInterval).Return([]*remote.ScheduleInformation{
+		{
+			ScheduleID: "alice@example.com",
+			ScheduleItems: []*remote.ScheduleItem{
+				{
+					Start:  remote.NewDateTime(from.Add(14*time.Hour), "UTC"),
+					End:    remote.NewDateTime(from.Add(15*time.Hour), "UTC"),
+					Status: remote.ScheduleStatusOof,
+				},
+				{
+					Start:  remote.NewDateTime(from.Add(9*time.Hour), "UTC"),
+					End:    remote.NewDateTime(from.Add(10*time.Hour), "UTC"),
+					Status: remote.ScheduleStatusBusy,
+				},
+			},
+		},
+		{
+			ScheduleID: "bob@example.com",
+			Error:      &remote.ScheduleInformationError{Message: "mailbox not found"},
+		},
+	}, nil).Times(1)
+
+	result, err := mscalendar.GetUsersFreeBusy(user, []string{"alice", "bob", "carol", "dave"}, from, to)
+	require.NoError(t, err)
+	require.Equal(t, []*UserFreeBusy{
+		{
+			MattermostUserID: "alice",
+			Busy: []*BusyInterval{
+				{Start: from.Add(9 * time.Hour), End: from.Add(10 * time.Hour), Status: remote.ScheduleStatusBusy},
+				{Start: from.Add(14 * time.Hour), End: from.Add(15 * time.Hour), Status: remote.ScheduleStatusOof},
+			},
+		},
+		{MattermostUserID: "bob", Busy: []*BusyInterval{}, Error: "mailbox not found"},
+		{MattermostUserID: "carol", Busy: []*BusyInterval{}, Error: "user not connected"},
+		{MattermostUserID: "dave", Busy: []*BusyInterval{}, Error: "schedule not available"},
+	}, result)
+
+	t.Run("too many users", func(t *testing.T) {
+		_, err := mscalendar.GetUsersFreeBusy(user, make([]string, FreeBusyMaxUsers+1), from, to)
+		require.EqualError(t, err, "cannot get free/busy information of more than 100 users")
+	})
+}
+
+func TestEnableFreeBusyFeed(t *testing.T) {
+	mscalendar, mockStore, _, _, _, _, _ := GetMockSetup(t)
+	mscalendar.Config.PluginURL = "https://mattermost.example.com/plugins/mscalendar"
+	user := GetMockUser(model.NewString(MockRemoteUserID), model.NewString(MockMMModelUserID), MockMMUserID, nil)
+
+	var storedToken string
+	mockStore.EXPECT().StoreFreeBusyToken(MockMMUserID, gomock.Any()).DoAndReturn(func(_, token string) error {
+		storedToken = token
+		return nil
+	}).Times(1)
+
+	url, err := mscalendar.EnableFreeBusyFeed(user)
+	require.NoError(t, err)
+	require.NotEmpty(t, storedToken)
+	require.Equal(t, "https://mattermost.example.com/plugins/mscalendar/freebusy/"+storedToken, url)
+	require.False(t, strings.ContainsAny(storedToken, "+/="))
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const (
+	FileIDKey = "FileID"
+
+	maxICalFileSize     = 1024 * 1024
+	maxICalImportEvents = 20
+)
+
+type CalendarImport interface {
+	OfferCalendarImport(post *model.Post) error
+	ImportCalendarFile(user *User, fileID string) ([]*remote.Event, error)
+}
+
+// OfferCalendarImport replies to a post containing iCalendar files with an
+// action to add their events to the calendar of the user clicking it.
+func (m *mscalendar) OfferCalendarImport(post *model.Post) error {
+	attachments := []*model.SlackAttachment{}
+	for _, fileID := range post.FileIds {
+		info, err := m.PluginAPI.GetFileInfo(fileID)
+		if err != nil {
+			m.Logger.Warnf("Failed to get file info. fileID=%s err=%v", fileID, err)
+			continue
+		}
+		if !isICalFile(info) {
+			continue
+		}
+
+		attachments = append(attachments, &model.SlackAttachment{
+			Text: fmt.Sprintf("**%s** contains calendar events.", info.Name),
+			Actions: []*model.PostAction{{
+				Name: "Add to my calendar",
+				Integration: &model.PostActionIntegration{
+					URL: m.Config.PluginURL + config.PathPostAction + config.PathImport,
+					Context: map[string]interface{}{
+						FileIDKey: fileID,
+					},
+				},
+			}},
+		})
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	rootID := post.RootId
+	if rootID == "" {
+		rootID = post.Id
+	}
+	offer := &model.Post{
+		ChannelId: post.ChannelId,
+		RootId:    rootID,
+	}
+	model.ParseSlackAttachment(offer, attachments)
+	return m.Poster.CreatePost(offer)
+}
+
+// ImportCalendarFile creates the events of an iCalendar file in the calendar of
+// the user. Attendees are not invited, only the user's calendar is updated.
+// Files with recurring events are rejected.
+func (m *mscalendar) ImportCalendarFile(user *User, fileID string) ([]*remote.Event, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := m.PluginAPI.GetFileInfo(fileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting file info")
+	}
+	if !m.PluginAPI.CanReadChannel(info.ChannelId, user.MattermostUserID) {
+		return nil, errors.New("you don't have access to this file")
+	}
+	if !isICalFile(info) {
+		return nil, errors.New("the file is not an iCalendar file")
+	}
+	if info.Size > maxICalFileSize {
+		return nil, errors.New("the file is too large")
+	}
+
+	data, err := m.PluginAPI.GetFile(fileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting file")
+	}
+
+	mailbox, err := m.client.GetMailboxSettings(user.Remote.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting mailbox settings")
+	}
+	loc, err := time.LoadLocation(tz.Go(mailbox.TimeZone))
+	if err != nil {
+		loc = time.UTC
+	}
+
+	cal, err := ical.ParseInLocation(bytes.NewReader(data), loc)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing calendar file")
+	}
+
+	toImport := []*ical.Event{}
+	for _, evt := range cal.Events {
+		if evt.Status != ical.StatusCancelled {
+			toImport = append(toImport, evt)
+		}
+	}
+	if len(toImport) == 0 {
+		return nil, errors.New("the file doesn't contain any event")
+	}
+	if len(toImport) > maxICalImportEvents {
+		return nil, errors.Errorf("the file contains more than %d events", maxICalImportEvents)
+	}
+
+	for _, evt := range toImport {
+		if evt.Recurring {
+			return nil, errors.Errorf("the event %q repeats, recurring events can't be imported", evt.Summary)
+		}
+	}
+
+	created := []*remote.Event{}
+	for _, evt := range toImport {
+		event, err := m.client.CreateEvent(user.Remote.ID, iCalEventToRemote(evt, mailbox.TimeZone))
+		if err != nil {
+			return created, errors.Wrapf(err, "error creating event %q", evt.Summary)
+		}
+		created = append(created, event)
+	}
+
+	return created, nil
+}
+
+func iCalEventToRemote(evt *ical.Event, mailboxTimeZone string) *remote.Event {
+	event := &remote.Event{
+		Subject:  evt.Summary,
+		IsAllDay: evt.AllDay,
+	}
+
+	if evt.AllDay {
+		// All-day events must start and end at midnight in the calendar's
+		// time zone.
+		event.Start = allDayDateTime(evt.Start, mailboxTimeZone)
+		event.End = allDayDateTime(evt.End, mailboxTimeZone)
+	} else {
+		event.Start = iCalDateTimeToRemote(evt.Start)
+		event.End = iCalDateTimeToRemote(evt.End)
+	}
+
+	if evt.Description != "" {
+		event.Body = &remote.ItemBody{
+			Content:     evt.Description,
+			ContentType: "text/plain",
+		}
+	}
+	if evt.Location != "" {
+		event.Location = &remote.Location{
+			DisplayName: evt.Location,
+		}
+	}
+
+	return event
+}
+
+// iCalDateTimeToRemote keeps the time zone of a date-time when it is a real
+// one. The custom time zones of the file are unknown to the remote calendar,
+// their times are sent in UTC instead.
+func iCalDateTimeToRemote(t time.Time) *remote.DateTime {
+	name := t.Location().String()
+	if _, err := time.LoadLocation(name); err != nil || name == "Local" {
+		return remote.NewDateTime(t.UTC(), "UTC")
+	}
+	return remote.NewDateTime(t, tz.Microsoft(name))
+}
+
+func allDayDateTime(t time.Time, timeZone string) *remote.DateTime {
+	return &remote.DateTime{
+		DateTime: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Format(remote.RFC3339NanoNoTimezone),
+		TimeZone: timeZone,
+	}
+}
+
+func isICalFile(info *model.FileInfo) bool {
+	return strings.EqualFold(info.Extension, strings.TrimPrefix(ical.FileExtension, ".")) ||
+		strings.HasPrefix(info.MimeType, ical.ContentType)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const mockICalFile = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:uid\r\nSUMMARY:Imported\r\nDTSTART;TZID=Europe/Paris:20240315T090000\r\nDTEND;TZID=Europe/Paris:20240315T100000\r\nATTENDEE:mailto:someone@example.com\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+const mockICalFileCustomZone = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+	"BEGIN:VTIMEZONE\r\nTZID:Custom Zone\r\n" +
+	"BEGIN:DAYLIGHT\r\nDTSTART:19810329T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\nEND:DAYLIGHT\r\n" +
+	"BEGIN:STANDARD\r\nDTSTART:19961027T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\nEND:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\nUID:uid\r\nSUMMARY:Imported\r\nDTSTART;TZID=Custom Zone:20240715T090000\r\nDTEND;TZID=Custom Zone:20240715T100000\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+func TestImportCalendarFile(t *testing.T) {
+	mscalendar, _, _, _, mockPluginAPI, mockClient, _ := GetMockSetup(t)
+	fileInfo := &model.FileInfo{Id: "fileID", ChannelId: mockChannelID, Extension: "ics", Size: int64(len(mockICalFile))}
+
+	tests := []struct {
+		name       string
+		setupMock  func()
+		assertions func(t *testing.T, events []*remote.Event, err error)
+	}{
+		{
+			name: "user can't read the channel",
+			setupMock: func() {
+				mockPluginAPI.EXPECT().GetFileInfo("fileID").Return(fileInfo, nil).Times(1)
+				mockPluginAPI.EXPECT().CanReadChannel(mockChannelID, MockMMUserID).Return(false).Times(1)
+			},
+			assertions: func(t *testing.T, _ []*remote.Event, err error) {
+				require.EqualError(t, err, "you don't have access to this file")
+			},
+		},
+		{
+			name: "not an iCalendar file",
+			setupMock: func() {
+				mockPluginAPI.EXPECT().GetFileInfo("fileID").Return(&model.FileInfo{Id: "fileID", ChannelId: mockChannelID, Extension: "png"}, nil).Times(1)
+				mockPluginAPI.EXPECT().CanReadChannel(mockChannelID, MockMMUserID).Return(true).Times(1)
+			},
+			assertions: func(t *testing.T, _ []*remote.Event, err error) {
+				require.EqualError(t, err, "the file is not an iCalendar file")
+			},
+		},
+		{
+			name: "events are created without attendees",
+			setupMock: func() {
+				mockPluginAPI.EXPECT().GetFileInfo("fileID").Return(fileInfo, nil).Times(1)
+				mockPluginAPI.EXPECT().CanReadChannel(mockChannelID, MockMMUserID).Return(true).Times(1)
+				mockPluginAPI.EXPECT().GetFile("fileID").Return([]byte(mockICalFile), nil).Times(1)
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).DoAndReturn(func(_ string, event *remote.Event) (*remote.Event, error) {
+					require.Equal(t, "Imported", event.Subject)
+					require.Empty(t, event.Attendees)
+					require.Equal(t, &remote.DateTime{DateTime: "2024-03-15T09:00:00", TimeZone: "Europe/Paris"}, event.Start)
+					require.Equal(t, &remote.DateTime{DateTime: "2024-03-15T10:00:00", TimeZone: "Europe/Paris"}, event.End)
+					return event, nil
+				}).Times(1)
+			},
+			assertions: func(t *testing.T, events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+			},
+		},
+		{
+			name: "times of custom time zones are sent in UTC",
+			setupMock: func() {
+				mockPluginAPI.EXPECT().GetFileInfo("fileID").Return(fileInfo, nil).Times(1)
+				mockPluginAPI.EXPECT().CanReadChannel(mockChannelID, MockMMUserID).Return(true).Times(1)
+				mockPluginAPI.EXPECT().GetFile("fileID").Return([]byte(mockICalFileCustomZone), nil).Times(1)
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockClient.EXPECT().CreateEvent(MockRemoteUserID, gomock.Any()).DoAndReturn(func(_ string, event *remote.Event) (*remote.Event, error) {
+					require.Equal(t, &remote.DateTime{DateTime: "2024-07-15T07:00:00", TimeZone: "UTC"}, event.Start)
+					require.Equal(t, &remote.DateTime{DateTime: "2024-07-15T08:00:00", TimeZone: "UTC"}, event.End)
+					return event, nil
+				}).Times(1)
+			},
+			assertions: func(t *testing.T, events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+			},
+		},
+		{
+			name: "recurring events are rejected",
+			setupMock: func() {
+				recurring := strings.Replace(mockICalFile, "END:VEVENT", "RRULE:FREQ=WEEKLY;BYDAY=FR\r\nEND:VEVENT", 1)
+				mockPluginAPI.EXPECT().GetFileInfo("fileID").Return(fileInfo, nil).Times(1)
+				mockPluginAPI.EXPECT().CanReadChannel(mockChannelID, MockMMUserID).Return(true).Times(1)
+				mockPluginAPI.EXPECT().GetFile("fileID").Return([]byte(recurring), nil).Times(1)
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockClient.EXPECT().CreateEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			assertions: func(t *testing.T, events []*remote.Event, err error) {
+				require.EqualError(t, err, `the event "Imported" repeats, recurring events can't be imported`)
+				require.Empty(t, events)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			user := GetMockUser(model.NewString(MockRemoteUserID), model.NewString(MockMMModelUserID), MockMMUserID, nil)
+			events, err := mscalendar.ImportCalendarFile(user, "fileID")
+
+			tt.assertions(t, events, err)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+// Invitees are the members of a channel or a group resolved to the email
+// addresses they are invited with.
+type Invitees struct {
+	Attendees []*AttendeeResolution
+}
+
+// Outcomes of inviting a Mattermost user to an event.
+const (
+	// AttendeeInvited means the user was invited with their connected account.
+	AttendeeInvited = "invited"
+	// AttendeeInvitedByMattermostEmail means the user has not connected their
+	// account and was invited with the email of their Mattermost account. It
+	// only happens when the server shows the email addresses.
+	AttendeeInvitedByMattermostEmail = "invited_by_mattermost_email"
+	// AttendeeNotInvited means no email address was found for the user.
+	AttendeeNotInvited = "not_invited"
+)
+
+// AttendeeResolution is the email address a Mattermost user is invited with.
+type AttendeeResolution struct {
+	MattermostUserID string
+	Username         string
+	Email            string
+	Outcome          string
+}
+
+type EventInvitees interface {
+	GetChannelInvitees(user *User, channelID string) (*Invitees, error)
+	GetGroupInvitees(user *User, groupName string) (*Invitees, error)
+	ResolveAttendees(mattermostUserIDs []string) []*AttendeeResolution
+}
+
+// ErrTooManyAttendees is returned when the invitees exceed the configured
+// maximum number of attendees.
+type ErrTooManyAttendees struct {
+	Count int
+	Max   int
+}
+
+func (e *ErrTooManyAttendees) Error() string {
+	return fmt.Sprintf("cannot invite %d attendees, the maximum is %d", e.Count, e.Max)
+}
+
+// CheckAttendeeCount returns ErrTooManyAttendees if count exceeds the
+// configured maximum number of attendees.
+func CheckAttendeeCount(count, max int) error {
+	if count > max {
+		return &ErrTooManyAttendees{Count: count, Max: max}
+	}
+	return nil
+}
+
+func (m *mscalendar) GetChannelInvitees(user *User, channelID string) (*Invitees, error) {
+	if !m.PluginAPI.CanReadChannel(channelID, user.MattermostUserID) {
+		return nil, errors.New("you don't have access to this channel")
+	}
+
+	members, err := m.PluginAPI.GetMattermostUsersInChannel(channelID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get channel members")
+	}
+
+	return m.resolveInvitees(user, members)
+}
+
+func (m *mscalendar) GetGroupInvitees(user *User, groupName string) (*Invitees, error) {
+	group, err := m.PluginAPI.GetMattermostGroupByName(groupName)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("group %q not found", groupName)
+		}
+		return nil, errors.Wrap(err, "failed to get group")
+	}
+
+	members, err := m.PluginAPI.GetMattermostGroupMembers(group.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get group members")
+	}
+
+	return m.resolveInvitees(user, members)
+}
+
+// resolveInvitees maps the members to the email addresses of their connected
+// accounts using the user index, so the store is read once for all of them.
+// The members that have not connected their account are invited with the
+// email of their Mattermost account, as in ResolveAttendees, unless the server
+// hides the emails.
+// Bots, deactivated users and the organizer are skipped.
+func (m *mscalendar) resolveInvitees(user *User, members []*model.User) (*Invitees, error) {
+	userIndex, err := m.Store.LoadUserIndex()
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, errors.Wrap(err, "failed to load connected users")
+	}
+	emails := map[string]string{}
+	for _, u := range userIndex {
+		if u.Email != "" {
+			emails[u.MattermostUserID] = u.Email
+		}
+	}
+
+	invitees := &Invitees{
+		Attendees: []*AttendeeResolution{},
+	}
+	seen := map[string]bool{}
+	for _, member := range members {
+		if member.IsBot || member.DeleteAt != 0 || member.Id == user.MattermostUserID || seen[member.Id] {
+			continue
+		}
+		seen[member.Id] = true
+
+		r := &AttendeeResolution{
+			MattermostUserID: member.Id,
+			Username:         member.Username,
+			Outcome:          AttendeeNotInvited,
+		}
+		if email, ok := emails[member.Id]; ok {
+			r.Email = email
+			r.Outcome = AttendeeInvited
+		} else if member.Email != "" && m.Config.MattermostShowEmailAddress {
+			r.Email = member.Email
+			r.Outcome = AttendeeInvitedByMattermostEmail
+		}
+		invitees.Attendees = append(invitees.Attendees, r)
+	}
+
+	sort.Slice(invitees.Attendees, func(i, j int) bool {
+		return invitees.Attendees[i].Username < invitees.Attendees[j].Username
+	})
+	return invitees, nil
+}
+
+// Emails returns the email addresses of the invited members.
+func (invitees *Invitees) Emails() []string {
+	emails := []string{}
+	seen := map[string]bool{}
+	for _, r := range invitees.Attendees {
+		if r.Outcome == AttendeeNotInvited || seen[strings.ToLower(r.Email)] {
+			continue
+		}
+		seen[strings.ToLower(r.Email)] = true
+		emails = append(emails, r.Email)
+	}
+	return emails
+}
+
+// ResolveAttendees finds the email address to invite each Mattermost user
+// with. The email of the connected account is preferred, and the email of the
+// Mattermost account is used for the users that have not connected theirs, so
+// they still get the invitation. The emails hidden by the server are never
+// used, those users are not invited.
+func (m *mscalendar) ResolveAttendees(mattermostUserIDs []string) []*AttendeeResolution {
+	resolutions := []*AttendeeResolution{}
+	for _, mattermostUserID := range mattermostUserIDs {
+		r := &AttendeeResolution{
+			MattermostUserID: mattermostUserID,
+			Outcome:          AttendeeNotInvited,
+		}
+		resolutions = append(resolutions, r)
+
+		storedUser, err := m.Store.LoadUser(mattermostUserID)
+		if err == nil && storedUser.Remote != nil && storedUser.Remote.Mail != "" {
+			r.Username = storedUser.MattermostUsername
+			r.Email = storedUser.Remote.Mail
+			r.Outcome = AttendeeInvited
+			continue
+		}
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			m.Logger.Warnf("Error loading attendee %s. err=%v", mattermostUserID, err)
+		}
+
+		mattermostUser, err := m.PluginAPI.GetMattermostUser(mattermostUserID)
+		if err != nil {
+			m.Logger.Warnf("Error getting Mattermost user %s. err=%v", mattermostUserID, err)
+			continue
+		}
+		r.Username = mattermostUser.Username
+		if mattermostUser.Email != "" && m.Config.MattermostShowEmailAddress {
+			r.Email = mattermostUser.Email
+			r.Outcome = AttendeeInvitedByMattermostEmail
+		}
+	}
+	return resolutions
+}
+
+// FormatAttendeeOutcomes describes to the organizer how each Mattermost user
+// was invited to the event.
+func FormatAttendeeOutcomes(subject string, resolutions []*AttendeeResolution, showEmails bool) string {
+	out := fmt.Sprintf("Invitations for **%s**:", subject)
+	for _, r := range resolutions {
+		name := r.MattermostUserID
+		if r.Username != "" {
+			name = "@" + r.Username
+		}
+
+		switch r.Outcome {
+		case AttendeeInvited:
+			out += fmt.Sprintf("\n- %s: invited", name)
+		case AttendeeInvitedByMattermostEmail:
+			if !showEmails {
+				out += fmt.Sprintf("\n- %s: invited with the email of their Mattermost account, because they have not connected their account", name)
+				continue
+			}
+			out += fmt.Sprintf("\n- %s: invited at %s, the email of their Mattermost account, because they have not connected their account", name, r.Email)
+		default:
+			out += fmt.Sprintf("\n- %s: not invited, no email address was found", name)
+		}
+	}
+	return out
+}
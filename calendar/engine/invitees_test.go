@@ -0,0 +1,142 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestGetChannelInvitees(t *testing.T) {
+	mscalendar, mockStore, _, _, mockPluginAPI, _, _ := GetMockSetup(t)
+
+	members := []*model.User{
+		{Id: MockMMUserID, Username: "organizer"},
+		{Id: "bot", Username: "bot", IsBot: true},
+		{Id: "deactivated", Username: "deactivated", DeleteAt: 1},
+		{Id: "user2", Username: "zoe"},
+		{Id: "user1", Username: "adam"},
+		{Id: "user3", Username: "bob", Email: "bob@mattermost.com"},
+		{Id: "user5", Username: "dan"},
+		{Id: "user4", Username: "carl"},
+	}
+	userIndex := store.UserIndex{
+		{MattermostUserID: MockMMUserID, Email: "organizer@example.com"},
+		{MattermostUserID: "bot", Email: "bot@example.com"},
+		{MattermostUserID: "deactivated", Email: "deactivated@example.com"},
+		{MattermostUserID: "user2", Email: "zoe@example.com"},
+		{MattermostUserID: "user1", Email: "adam@example.com"},
+		{MattermostUserID: "user4", Email: "Adam@example.com"},
+	}
+
+	for _, tc := range []struct {
+		name           string
+		canRead        bool
+		showEmails     bool
+		expectedError  string
+		expected       *Invitees
+		expectedEmails []string
+	}{
+		{
+			name:          "no access to the channel",
+			expectedError: "you don't have access to this channel",
+		},
+		{
+			name:    "members resolved",
+			canRead: true,
+			expected: &Invitees{
+				Attendees: []*AttendeeResolution{
+					{MattermostUserID: "user1", Username: "adam", Email: "adam@example.com", Outcome: AttendeeInvited},
+					{MattermostUserID: "user3", Username: "bob", Outcome: AttendeeNotInvited},
+					{MattermostUserID: "user4", Username: "carl", Email: "Adam@example.com", Outcome: AttendeeInvited},
+					{MattermostUserID: "user5", Username: "dan", Outcome: AttendeeNotInvited},
+					{MattermostUserID: "user2", Username: "zoe", Email: "zoe@example.com", Outcome: AttendeeInvited},
+				},
+			},
+			expectedEmails: []string{"adam@example.com", "zoe@example.com"},
+		},
+		{
+			name:       "members resolved with the emails shown",
+			canRead:    true,
+			showEmails: true,
+			expected: &Invitees{
+				Attendees: []*AttendeeResolution{
+					{MattermostUserID: "user1", Username: "adam", Email: "adam@example.com", Outcome: AttendeeInvited},
+					{MattermostUserID: "user3", Username: "bob", Email: "bob@mattermost.com", Outcome: AttendeeInvitedByMattermostEmail},
+					{MattermostUserID: "user4", Username: "carl", Email: "Adam@example.com", Outcome: AttendeeInvited},
+					{MattermostUserID: "user5", Username: "dan", Outcome: AttendeeNotInvited},
+					{MattermostUserID: "user2", Username: "zoe", Email: "zoe@example.com", Outcome: AttendeeInvited},
+				},
+			},
+			expectedEmails: []string{"adam@example.com", "bob@mattermost.com", "zoe@example.com"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mscalendar.Config.MattermostShowEmailAddress = tc.showEmails
+			mockPluginAPI.EXPECT().CanReadChannel("channelID", MockMMUserID).Return(tc.canRead).Times(1)
+			if tc.canRead {
+				mockPluginAPI.EXPECT().GetMattermostUsersInChannel("channelID").Return(members, nil).Times(1)
+				mockStore.EXPECT().LoadUserIndex().Return(userIndex, nil).Times(1)
+			}
+
+			invitees, err := mscalendar.GetChannelInvitees(NewUser(MockMMUserID), "channelID")
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, invitees)
+			require.Equal(t, tc.expectedEmails, invitees.Emails())
+		})
+	}
+}
+
+func TestGetGroupInvitees(t *testing.T) {
+	mscalendar, mockStore, _, _, mockPluginAPI, _, _ := GetMockSetup(t)
+
+	t.Run("group not found", func(t *testing.T) {
+		mockPluginAPI.EXPECT().GetMattermostGroupByName("missing").Return(nil, store.ErrNotFound).Times(1)
+
+		_, err := mscalendar.GetGroupInvitees(NewUser(MockMMUserID), "missing")
+		require.EqualError(t, err, `group "missing" not found`)
+	})
+
+	t.Run("members resolved", func(t *testing.T) {
+		mockPluginAPI.EXPECT().GetMattermostGroupByName("developers").Return(&model.Group{Id: "groupID"}, nil).Times(1)
+		mockPluginAPI.EXPECT().GetMattermostGroupMembers("groupID").Return([]*model.User{{Id: "user1", Username: "adam"}}, nil).Times(1)
+		mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{{MattermostUserID: "user1", Email: "adam@example.com"}}, nil).Times(1)
+
+		invitees, err := mscalendar.GetGroupInvitees(NewUser(MockMMUserID), "developers")
+		require.NoError(t, err)
+		require.Equal(t, []string{"adam@example.com"}, invitees.Emails())
+	})
+}
+
+func TestFormatAttendeeOutcomes(t *testing.T) {
+	resolutions := []*AttendeeResolution{
+		{MattermostUserID: "connected", Username: "connected", Email: "connected@example.com", Outcome: AttendeeInvited},
+		{MattermostUserID: "unconnected", Username: "unconnected", Email: "unconnected@mattermost.com", Outcome: AttendeeInvitedByMattermostEmail},
+		{MattermostUserID: "unknown", Outcome: AttendeeNotInvited},
+	}
+
+	t.Run("emails shown", func(t *testing.T) {
+		out := FormatAttendeeOutcomes("Planning", resolutions, true)
+		require.Equal(t, "Invitations for **Planning**:"+
+			"\n- @connected: invited"+
+			"\n- @unconnected: invited at unconnected@mattermost.com, the email of their Mattermost account, because they have not connected their account"+
+			"\n- unknown: not invited, no email address was found", out)
+	})
+
+	t.Run("emails hidden", func(t *testing.T) {
+		out := FormatAttendeeOutcomes("Planning", resolutions, false)
+		require.Equal(t, "Invitations for **Planning**:"+
+			"\n- @connected: invited"+
+			"\n- @unconnected: invited with the email of their Mattermost account, because they have not connected their account"+
+			"\n- unknown: not invited, no email address was found", out)
+	})
+}
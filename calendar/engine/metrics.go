@@ -0,0 +1,68 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"sort"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/metrics"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+type Metrics interface {
+	GetMetrics() ([]*metrics.Family, error)
+}
+
+// GetMetrics returns the metrics read from the store, and those of the remote
+// cache of this node.
+func (m *mscalendar) GetMetrics() ([]*metrics.Family, error) {
+	uindex, err := m.Store.LoadUserIndex()
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+	queued, deadLetters, err := m.Store.CountNotifications()
+	if err != nil {
+		return nil, err
+	}
+
+	cacheLookups := &metrics.Family{
+		Name: "remote_cache_lookups_total",
+		Help: "Lookups in the remote cache, by kind of data and result.",
+		Type: metrics.TypeCounter,
+	}
+	stats := GetRemoteCacheStats()
+	kinds := make([]string, 0, len(stats))
+	for kind := range stats {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		cacheLookups.Samples = append(cacheLookups.Samples,
+			metrics.Sample{Labels: map[string]string{"kind": kind, "result": "hit"}, Value: float64(stats[kind].Hits)},
+			metrics.Sample{Labels: map[string]string{"kind": kind, "result": "miss"}, Value: float64(stats[kind].Misses)},
+		)
+	}
+
+	return []*metrics.Family{
+		{
+			Name:    "connected_users",
+			Help:    "Users connected to their calendar.",
+			Type:    metrics.TypeGauge,
+			Samples: []metrics.Sample{{Value: float64(len(uindex))}},
+		},
+		{
+			Name:    "notification_queue_depth",
+			Help:    "Webhook notifications waiting to be processed.",
+			Type:    metrics.TypeGauge,
+			Samples: []metrics.Sample{{Value: float64(queued)}},
+		},
+		{
+			Name:    "notification_dead_letters",
+			Help:    "Webhook notifications that failed to be processed, kept for the admins.",
+			Type:    metrics.TypeGauge,
+			Samples: []metrics.Sample{{Value: float64(deadLetters)}},
+		},
+		cacheLookups,
+	}, nil
+}
@@ -10,8 +10,10 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	engine "github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	metrics "github.com/mattermost/mattermost-plugin-mscalendar/calendar/metrics"
 	remote "github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	store "github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	model "github.com/mattermost/mattermost/server/public/model"
 )
 
 // MockEngine is a mock of Engine interface.
@@ -38,17 +40,17 @@ func (m *MockEngine) EXPECT() *MockEngineMockRecorder {
 }
 
 // AcceptEvent mocks base method.
-func (m *MockEngine) AcceptEvent(arg0 *engine.User, arg1 string) error {
+func (m *MockEngine) AcceptEvent(arg0 *engine.User, arg1 string, arg2 *remote.EventResponseOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AcceptEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "AcceptEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AcceptEvent indicates an expected call of AcceptEvent.
-func (mr *MockEngineMockRecorder) AcceptEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) AcceptEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptEvent", reflect.TypeOf((*MockEngine)(nil).AcceptEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptEvent", reflect.TypeOf((*MockEngine)(nil).AcceptEvent), arg0, arg1, arg2)
 }
 
 // AfterDisconnect mocks base method.
@@ -79,6 +81,21 @@ func (mr *MockEngineMockRecorder) AfterSuccessfullyConnect(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterSuccessfullyConnect", reflect.TypeOf((*MockEngine)(nil).AfterSuccessfullyConnect), arg0, arg1)
 }
 
+// ClearDeadLetterNotifications mocks base method.
+func (m *MockEngine) ClearDeadLetterNotifications() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearDeadLetterNotifications")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClearDeadLetterNotifications indicates an expected call of ClearDeadLetterNotifications.
+func (mr *MockEngineMockRecorder) ClearDeadLetterNotifications() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearDeadLetterNotifications", reflect.TypeOf((*MockEngine)(nil).ClearDeadLetterNotifications))
+}
+
 // ClearSettingsPosts mocks base method.
 func (m *MockEngine) ClearSettingsPosts(arg0 string) {
 	m.ctrl.T.Helper()
@@ -107,7 +124,7 @@ func (mr *MockEngineMockRecorder) CreateCalendar(arg0, arg1 interface{}) *gomock
 }
 
 // CreateEvent mocks base method.
-func (m *MockEngine) CreateEvent(arg0 *engine.User, arg1 *remote.Event, arg2 []string) (*remote.Event, error) {
+func (m *MockEngine) CreateEvent(arg0 *engine.User, arg1 *remote.Event, arg2 []*engine.AttendeeResolution) (*remote.Event, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*remote.Event)
@@ -136,18 +153,46 @@ func (mr *MockEngineMockRecorder) CreateMyEventSubscription() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMyEventSubscription", reflect.TypeOf((*MockEngine)(nil).CreateMyEventSubscription))
 }
 
+// DMCalendarExport mocks base method.
+func (m *MockEngine) DMCalendarExport(arg0 *engine.User, arg1 time.Time, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DMCalendarExport", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DMCalendarExport indicates an expected call of DMCalendarExport.
+func (mr *MockEngineMockRecorder) DMCalendarExport(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DMCalendarExport", reflect.TypeOf((*MockEngine)(nil).DMCalendarExport), arg0, arg1, arg2)
+}
+
+// DMEventRSVPs mocks base method.
+func (m *MockEngine) DMEventRSVPs(arg0 *engine.User, arg1 *remote.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DMEventRSVPs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DMEventRSVPs indicates an expected call of DMEventRSVPs.
+func (mr *MockEngineMockRecorder) DMEventRSVPs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DMEventRSVPs", reflect.TypeOf((*MockEngine)(nil).DMEventRSVPs), arg0, arg1)
+}
+
 // DeclineEvent mocks base method.
-func (m *MockEngine) DeclineEvent(arg0 *engine.User, arg1 string) error {
+func (m *MockEngine) DeclineEvent(arg0 *engine.User, arg1 string, arg2 *remote.EventResponseOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeclineEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeclineEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeclineEvent indicates an expected call of DeclineEvent.
-func (mr *MockEngineMockRecorder) DeclineEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) DeclineEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEvent", reflect.TypeOf((*MockEngine)(nil).DeclineEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEvent", reflect.TypeOf((*MockEngine)(nil).DeclineEvent), arg0, arg1, arg2)
 }
 
 // DeleteCalendar mocks base method.
@@ -192,6 +237,20 @@ func (mr *MockEngineMockRecorder) DeleteOrphanedSubscription(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrphanedSubscription", reflect.TypeOf((*MockEngine)(nil).DeleteOrphanedSubscription), arg0)
 }
 
+// DisableFreeBusyFeed mocks base method.
+func (m *MockEngine) DisableFreeBusyFeed(arg0 *engine.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableFreeBusyFeed", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableFreeBusyFeed indicates an expected call of DisableFreeBusyFeed.
+func (mr *MockEngineMockRecorder) DisableFreeBusyFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableFreeBusyFeed", reflect.TypeOf((*MockEngine)(nil).DisableFreeBusyFeed), arg0)
+}
+
 // DisconnectUser mocks base method.
 func (m *MockEngine) DisconnectUser(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -206,6 +265,51 @@ func (mr *MockEngineMockRecorder) DisconnectUser(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectUser", reflect.TypeOf((*MockEngine)(nil).DisconnectUser), arg0)
 }
 
+// EnableFreeBusyFeed mocks base method.
+func (m *MockEngine) EnableFreeBusyFeed(arg0 *engine.User) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableFreeBusyFeed", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableFreeBusyFeed indicates an expected call of EnableFreeBusyFeed.
+func (mr *MockEngineMockRecorder) EnableFreeBusyFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableFreeBusyFeed", reflect.TypeOf((*MockEngine)(nil).EnableFreeBusyFeed), arg0)
+}
+
+// ExportCalendar mocks base method.
+func (m *MockEngine) ExportCalendar(arg0 *engine.User, arg1 time.Time, arg2 int) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCalendar", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportCalendar indicates an expected call of ExportCalendar.
+func (mr *MockEngineMockRecorder) ExportCalendar(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCalendar", reflect.TypeOf((*MockEngine)(nil).ExportCalendar), arg0, arg1, arg2)
+}
+
+// ExportFreeBusy mocks base method.
+func (m *MockEngine) ExportFreeBusy(arg0 *engine.User, arg1, arg2 time.Time) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportFreeBusy", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportFreeBusy indicates an expected call of ExportFreeBusy.
+func (mr *MockEngineMockRecorder) ExportFreeBusy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportFreeBusy", reflect.TypeOf((*MockEngine)(nil).ExportFreeBusy), arg0, arg1, arg2)
+}
+
 // FindMeetingTimes mocks base method.
 func (m *MockEngine) FindMeetingTimes(arg0 *engine.User, arg1 *remote.FindMeetingTimesParameters) (*remote.MeetingTimeSuggestionResults, error) {
 	m.ctrl.T.Helper()
@@ -235,6 +339,21 @@ func (mr *MockEngineMockRecorder) GetActingUser() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActingUser", reflect.TypeOf((*MockEngine)(nil).GetActingUser))
 }
 
+// GetAgendaForUser mocks base method.
+func (m *MockEngine) GetAgendaForUser(arg0 *engine.User, arg1, arg2 time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgendaForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgendaForUser indicates an expected call of GetAgendaForUser.
+func (mr *MockEngineMockRecorder) GetAgendaForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgendaForUser", reflect.TypeOf((*MockEngine)(nil).GetAgendaForUser), arg0, arg1, arg2)
+}
+
 // GetCalendarViews mocks base method.
 func (m *MockEngine) GetCalendarViews(arg0 []*store.User) ([]*remote.ViewCalendarResponse, error) {
 	m.ctrl.T.Helper()
@@ -265,6 +384,21 @@ func (mr *MockEngineMockRecorder) GetCalendars(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendars", reflect.TypeOf((*MockEngine)(nil).GetCalendars), arg0)
 }
 
+// GetChannelInvitees mocks base method.
+func (m *MockEngine) GetChannelInvitees(arg0 *engine.User, arg1 string) (*engine.Invitees, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelInvitees", arg0, arg1)
+	ret0, _ := ret[0].(*engine.Invitees)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChannelInvitees indicates an expected call of GetChannelInvitees.
+func (mr *MockEngineMockRecorder) GetChannelInvitees(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelInvitees", reflect.TypeOf((*MockEngine)(nil).GetChannelInvitees), arg0, arg1)
+}
+
 // GetDailySummarySettingsForUser mocks base method.
 func (m *MockEngine) GetDailySummarySettingsForUser(arg0 *engine.User) (*store.DailySummaryUserSettings, error) {
 	m.ctrl.T.Helper()
@@ -295,6 +429,96 @@ func (mr *MockEngineMockRecorder) GetDaySummaryForUser(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDaySummaryForUser", reflect.TypeOf((*MockEngine)(nil).GetDaySummaryForUser), arg0, arg1)
 }
 
+// GetFreeBusy mocks base method.
+func (m *MockEngine) GetFreeBusy(arg0 *engine.User, arg1, arg2 time.Time) ([]*engine.BusyInterval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFreeBusy", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*engine.BusyInterval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFreeBusy indicates an expected call of GetFreeBusy.
+func (mr *MockEngineMockRecorder) GetFreeBusy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreeBusy", reflect.TypeOf((*MockEngine)(nil).GetFreeBusy), arg0, arg1, arg2)
+}
+
+// GetFreeBusyFeedURL mocks base method.
+func (m *MockEngine) GetFreeBusyFeedURL(arg0 *engine.User) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFreeBusyFeedURL", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFreeBusyFeedURL indicates an expected call of GetFreeBusyFeedURL.
+func (mr *MockEngineMockRecorder) GetFreeBusyFeedURL(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreeBusyFeedURL", reflect.TypeOf((*MockEngine)(nil).GetFreeBusyFeedURL), arg0)
+}
+
+// GetGroupInvitees mocks base method.
+func (m *MockEngine) GetGroupInvitees(arg0 *engine.User, arg1 string) (*engine.Invitees, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupInvitees", arg0, arg1)
+	ret0, _ := ret[0].(*engine.Invitees)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupInvitees indicates an expected call of GetGroupInvitees.
+func (mr *MockEngineMockRecorder) GetGroupInvitees(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupInvitees", reflect.TypeOf((*MockEngine)(nil).GetGroupInvitees), arg0, arg1)
+}
+
+// GetMetrics mocks base method.
+func (m *MockEngine) GetMetrics() ([]*metrics.Family, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetrics")
+	ret0, _ := ret[0].([]*metrics.Family)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetrics indicates an expected call of GetMetrics.
+func (mr *MockEngineMockRecorder) GetMetrics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockEngine)(nil).GetMetrics))
+}
+
+// GetNotificationQueueStatus mocks base method.
+func (m *MockEngine) GetNotificationQueueStatus() (*engine.NotificationQueueStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationQueueStatus")
+	ret0, _ := ret[0].(*engine.NotificationQueueStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationQueueStatus indicates an expected call of GetNotificationQueueStatus.
+func (mr *MockEngineMockRecorder) GetNotificationQueueStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationQueueStatus", reflect.TypeOf((*MockEngine)(nil).GetNotificationQueueStatus))
+}
+
+// GetOrganizedEvents mocks base method.
+func (m *MockEngine) GetOrganizedEvents(arg0 *engine.User, arg1 string) ([]*remote.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizedEvents", arg0, arg1)
+	ret0, _ := ret[0].([]*remote.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizedEvents indicates an expected call of GetOrganizedEvents.
+func (mr *MockEngineMockRecorder) GetOrganizedEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizedEvents", reflect.TypeOf((*MockEngine)(nil).GetOrganizedEvents), arg0, arg1)
+}
+
 // GetRemoteUser mocks base method.
 func (m *MockEngine) GetRemoteUser(arg0 string) (*remote.User, error) {
 	m.ctrl.T.Helper()
@@ -310,6 +534,21 @@ func (mr *MockEngineMockRecorder) GetRemoteUser(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteUser", reflect.TypeOf((*MockEngine)(nil).GetRemoteUser), arg0)
 }
 
+// GetRoomsAvailability mocks base method.
+func (m *MockEngine) GetRoomsAvailability(arg0 *engine.User, arg1 string, arg2, arg3 time.Time) ([]*engine.RoomAvailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoomsAvailability", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*engine.RoomAvailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoomsAvailability indicates an expected call of GetRoomsAvailability.
+func (mr *MockEngineMockRecorder) GetRoomsAvailability(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoomsAvailability", reflect.TypeOf((*MockEngine)(nil).GetRoomsAvailability), arg0, arg1, arg2, arg3)
+}
+
 // GetTimezone mocks base method.
 func (m *MockEngine) GetTimezone(arg0 *engine.User) (string, error) {
 	m.ctrl.T.Helper()
@@ -340,6 +579,51 @@ func (mr *MockEngineMockRecorder) GetUserSettings(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSettings", reflect.TypeOf((*MockEngine)(nil).GetUserSettings), arg0)
 }
 
+// GetUsersFreeBusy mocks base method.
+func (m *MockEngine) GetUsersFreeBusy(arg0 *engine.User, arg1 []string, arg2, arg3 time.Time) ([]*engine.UserFreeBusy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersFreeBusy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*engine.UserFreeBusy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersFreeBusy indicates an expected call of GetUsersFreeBusy.
+func (mr *MockEngineMockRecorder) GetUsersFreeBusy(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersFreeBusy", reflect.TypeOf((*MockEngine)(nil).GetUsersFreeBusy), arg0, arg1, arg2, arg3)
+}
+
+// GetWeeklySummarySettingsForUser mocks base method.
+func (m *MockEngine) GetWeeklySummarySettingsForUser(arg0 *engine.User) (*store.WeeklySummaryUserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWeeklySummarySettingsForUser", arg0)
+	ret0, _ := ret[0].(*store.WeeklySummaryUserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWeeklySummarySettingsForUser indicates an expected call of GetWeeklySummarySettingsForUser.
+func (mr *MockEngineMockRecorder) GetWeeklySummarySettingsForUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWeeklySummarySettingsForUser", reflect.TypeOf((*MockEngine)(nil).GetWeeklySummarySettingsForUser), arg0)
+}
+
+// ImportCalendarFile mocks base method.
+func (m *MockEngine) ImportCalendarFile(arg0 *engine.User, arg1 string) ([]*remote.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCalendarFile", arg0, arg1)
+	ret0, _ := ret[0].([]*remote.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCalendarFile indicates an expected call of ImportCalendarFile.
+func (mr *MockEngineMockRecorder) ImportCalendarFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCalendarFile", reflect.TypeOf((*MockEngine)(nil).ImportCalendarFile), arg0, arg1)
+}
+
 // IsAuthorizedAdmin mocks base method.
 func (m *MockEngine) IsAuthorizedAdmin(arg0 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -385,6 +669,36 @@ func (mr *MockEngineMockRecorder) LoadMyEventSubscription() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadMyEventSubscription", reflect.TypeOf((*MockEngine)(nil).LoadMyEventSubscription))
 }
 
+// NudgeNonResponders mocks base method.
+func (m *MockEngine) NudgeNonResponders(arg0 *engine.User, arg1 string) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NudgeNonResponders", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// NudgeNonResponders indicates an expected call of NudgeNonResponders.
+func (mr *MockEngineMockRecorder) NudgeNonResponders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NudgeNonResponders", reflect.TypeOf((*MockEngine)(nil).NudgeNonResponders), arg0, arg1)
+}
+
+// OfferCalendarImport mocks base method.
+func (m *MockEngine) OfferCalendarImport(arg0 *model.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OfferCalendarImport", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OfferCalendarImport indicates an expected call of OfferCalendarImport.
+func (mr *MockEngineMockRecorder) OfferCalendarImport(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferCalendarImport", reflect.TypeOf((*MockEngine)(nil).OfferCalendarImport), arg0)
+}
+
 // PrintSettings mocks base method.
 func (m *MockEngine) PrintSettings(arg0 string) {
 	m.ctrl.T.Helper()
@@ -411,6 +725,63 @@ func (mr *MockEngineMockRecorder) ProcessAllDailySummary(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessAllDailySummary", reflect.TypeOf((*MockEngine)(nil).ProcessAllDailySummary), arg0)
 }
 
+// ProcessAllNotificationBatches mocks base method.
+func (m *MockEngine) ProcessAllNotificationBatches(arg0 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessAllNotificationBatches", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessAllNotificationBatches indicates an expected call of ProcessAllNotificationBatches.
+func (mr *MockEngineMockRecorder) ProcessAllNotificationBatches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessAllNotificationBatches", reflect.TypeOf((*MockEngine)(nil).ProcessAllNotificationBatches), arg0)
+}
+
+// ProcessAllRSVPDigests mocks base method.
+func (m *MockEngine) ProcessAllRSVPDigests(arg0 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessAllRSVPDigests", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessAllRSVPDigests indicates an expected call of ProcessAllRSVPDigests.
+func (mr *MockEngineMockRecorder) ProcessAllRSVPDigests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessAllRSVPDigests", reflect.TypeOf((*MockEngine)(nil).ProcessAllRSVPDigests), arg0)
+}
+
+// ProcessAllWeeklySummary mocks base method.
+func (m *MockEngine) ProcessAllWeeklySummary(arg0 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessAllWeeklySummary", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessAllWeeklySummary indicates an expected call of ProcessAllWeeklySummary.
+func (mr *MockEngineMockRecorder) ProcessAllWeeklySummary(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessAllWeeklySummary", reflect.TypeOf((*MockEngine)(nil).ProcessAllWeeklySummary), arg0)
+}
+
+// ReconcileSubscriptions mocks base method.
+func (m *MockEngine) ReconcileSubscriptions() (*engine.SubscriptionReconcileSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileSubscriptions")
+	ret0, _ := ret[0].(*engine.SubscriptionReconcileSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileSubscriptions indicates an expected call of ReconcileSubscriptions.
+func (mr *MockEngineMockRecorder) ReconcileSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileSubscriptions", reflect.TypeOf((*MockEngine)(nil).ReconcileSubscriptions))
+}
+
 // RenewMyEventSubscription mocks base method.
 func (m *MockEngine) RenewMyEventSubscription() (*store.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -426,18 +797,47 @@ func (mr *MockEngineMockRecorder) RenewMyEventSubscription() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewMyEventSubscription", reflect.TypeOf((*MockEngine)(nil).RenewMyEventSubscription))
 }
 
+// ResolveAttendees mocks base method.
+func (m *MockEngine) ResolveAttendees(arg0 []string) []*engine.AttendeeResolution {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAttendees", arg0)
+	ret0, _ := ret[0].([]*engine.AttendeeResolution)
+	return ret0
+}
+
+// ResolveAttendees indicates an expected call of ResolveAttendees.
+func (mr *MockEngineMockRecorder) ResolveAttendees(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAttendees", reflect.TypeOf((*MockEngine)(nil).ResolveAttendees), arg0)
+}
+
 // RespondToEvent mocks base method.
-func (m *MockEngine) RespondToEvent(arg0 *engine.User, arg1, arg2 string) error {
+func (m *MockEngine) RespondToEvent(arg0 *engine.User, arg1, arg2 string, arg3 *remote.EventResponseOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RespondToEvent", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "RespondToEvent", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RespondToEvent indicates an expected call of RespondToEvent.
-func (mr *MockEngineMockRecorder) RespondToEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) RespondToEvent(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToEvent", reflect.TypeOf((*MockEngine)(nil).RespondToEvent), arg0, arg1, arg2, arg3)
+}
+
+// RetryDeadLetterNotifications mocks base method.
+func (m *MockEngine) RetryDeadLetterNotifications() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDeadLetterNotifications")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryDeadLetterNotifications indicates an expected call of RetryDeadLetterNotifications.
+func (mr *MockEngineMockRecorder) RetryDeadLetterNotifications() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToEvent", reflect.TypeOf((*MockEngine)(nil).RespondToEvent), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDeadLetterNotifications", reflect.TypeOf((*MockEngine)(nil).RetryDeadLetterNotifications))
 }
 
 // SetDailySummaryEnabled mocks base method.
@@ -470,6 +870,96 @@ func (mr *MockEngineMockRecorder) SetDailySummaryPostTime(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDailySummaryPostTime", reflect.TypeOf((*MockEngine)(nil).SetDailySummaryPostTime), arg0, arg1)
 }
 
+// SetNotificationBatchMinutes mocks base method.
+func (m *MockEngine) SetNotificationBatchMinutes(arg0 *engine.User, arg1 int) (*store.NotificationBatchUserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotificationBatchMinutes", arg0, arg1)
+	ret0, _ := ret[0].(*store.NotificationBatchUserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNotificationBatchMinutes indicates an expected call of SetNotificationBatchMinutes.
+func (mr *MockEngineMockRecorder) SetNotificationBatchMinutes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotificationBatchMinutes", reflect.TypeOf((*MockEngine)(nil).SetNotificationBatchMinutes), arg0, arg1)
+}
+
+// SetQuietHours mocks base method.
+func (m *MockEngine) SetQuietHours(arg0 *engine.User, arg1, arg2 string) (*store.NotificationBatchUserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetQuietHours", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*store.NotificationBatchUserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetQuietHours indicates an expected call of SetQuietHours.
+func (mr *MockEngineMockRecorder) SetQuietHours(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQuietHours", reflect.TypeOf((*MockEngine)(nil).SetQuietHours), arg0, arg1, arg2)
+}
+
+// SetRSVPDigestMinutes mocks base method.
+func (m *MockEngine) SetRSVPDigestMinutes(arg0 *engine.User, arg1 int) (*store.RSVPDigestUserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRSVPDigestMinutes", arg0, arg1)
+	ret0, _ := ret[0].(*store.RSVPDigestUserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRSVPDigestMinutes indicates an expected call of SetRSVPDigestMinutes.
+func (mr *MockEngineMockRecorder) SetRSVPDigestMinutes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRSVPDigestMinutes", reflect.TypeOf((*MockEngine)(nil).SetRSVPDigestMinutes), arg0, arg1)
+}
+
+// SetWeeklySummaryEnabled mocks base method.
+func (m *MockEngine) SetWeeklySummaryEnabled(arg0 *engine.User, arg1 bool) (*store.WeeklySummaryUserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWeeklySummaryEnabled", arg0, arg1)
+	ret0, _ := ret[0].(*store.WeeklySummaryUserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetWeeklySummaryEnabled indicates an expected call of SetWeeklySummaryEnabled.
+func (mr *MockEngineMockRecorder) SetWeeklySummaryEnabled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWeeklySummaryEnabled", reflect.TypeOf((*MockEngine)(nil).SetWeeklySummaryEnabled), arg0, arg1)
+}
+
+// SetWeeklySummaryPostTime mocks base method.
+func (m *MockEngine) SetWeeklySummaryPostTime(arg0 *engine.User, arg1, arg2 string) (*store.WeeklySummaryUserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWeeklySummaryPostTime", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*store.WeeklySummaryUserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetWeeklySummaryPostTime indicates an expected call of SetWeeklySummaryPostTime.
+func (mr *MockEngineMockRecorder) SetWeeklySummaryPostTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWeeklySummaryPostTime", reflect.TypeOf((*MockEngine)(nil).SetWeeklySummaryPostTime), arg0, arg1, arg2)
+}
+
+// SuggestNewTimes mocks base method.
+func (m *MockEngine) SuggestNewTimes(arg0 *engine.User, arg1 string) ([]*remote.TimeSlot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestNewTimes", arg0, arg1)
+	ret0, _ := ret[0].([]*remote.TimeSlot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestNewTimes indicates an expected call of SuggestNewTimes.
+func (mr *MockEngineMockRecorder) SuggestNewTimes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestNewTimes", reflect.TypeOf((*MockEngine)(nil).SuggestNewTimes), arg0, arg1)
+}
+
 // Sync mocks base method.
 func (m *MockEngine) Sync(arg0 string) (string, *engine.StatusSyncJobSummary, error) {
 	m.ctrl.T.Helper()
@@ -503,17 +993,17 @@ func (mr *MockEngineMockRecorder) SyncAll() *gomock.Call {
 }
 
 // TentativelyAcceptEvent mocks base method.
-func (m *MockEngine) TentativelyAcceptEvent(arg0 *engine.User, arg1 string) error {
+func (m *MockEngine) TentativelyAcceptEvent(arg0 *engine.User, arg1 string, arg2 *remote.EventResponseOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TentativelyAcceptEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "TentativelyAcceptEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TentativelyAcceptEvent indicates an expected call of TentativelyAcceptEvent.
-func (mr *MockEngineMockRecorder) TentativelyAcceptEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) TentativelyAcceptEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TentativelyAcceptEvent", reflect.TypeOf((*MockEngine)(nil).TentativelyAcceptEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TentativelyAcceptEvent", reflect.TypeOf((*MockEngine)(nil).TentativelyAcceptEvent), arg0, arg1, arg2)
 }
 
 // ViewCalendar mocks base method.
@@ -48,6 +48,95 @@ func (mr *MockPluginAPIMockRecorder) CanLinkEventToChannel(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanLinkEventToChannel", reflect.TypeOf((*MockPluginAPI)(nil).CanLinkEventToChannel), arg0, arg1)
 }
 
+// CanReadChannel mocks base method.
+func (m *MockPluginAPI) CanReadChannel(arg0, arg1 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanReadChannel", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CanReadChannel indicates an expected call of CanReadChannel.
+func (mr *MockPluginAPIMockRecorder) CanReadChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanReadChannel", reflect.TypeOf((*MockPluginAPI)(nil).CanReadChannel), arg0, arg1)
+}
+
+// GetDirectChannel mocks base method.
+func (m *MockPluginAPI) GetDirectChannel(arg0, arg1 string) (*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDirectChannel", arg0, arg1)
+	ret0, _ := ret[0].(*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDirectChannel indicates an expected call of GetDirectChannel.
+func (mr *MockPluginAPIMockRecorder) GetDirectChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDirectChannel", reflect.TypeOf((*MockPluginAPI)(nil).GetDirectChannel), arg0, arg1)
+}
+
+// GetFile mocks base method.
+func (m *MockPluginAPI) GetFile(arg0 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFile", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockPluginAPIMockRecorder) GetFile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockPluginAPI)(nil).GetFile), arg0)
+}
+
+// GetFileInfo mocks base method.
+func (m *MockPluginAPI) GetFileInfo(arg0 string) (*model.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileInfo", arg0)
+	ret0, _ := ret[0].(*model.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileInfo indicates an expected call of GetFileInfo.
+func (mr *MockPluginAPIMockRecorder) GetFileInfo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfo", reflect.TypeOf((*MockPluginAPI)(nil).GetFileInfo), arg0)
+}
+
+// GetMattermostGroupByName mocks base method.
+func (m *MockPluginAPI) GetMattermostGroupByName(arg0 string) (*model.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMattermostGroupByName", arg0)
+	ret0, _ := ret[0].(*model.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMattermostGroupByName indicates an expected call of GetMattermostGroupByName.
+func (mr *MockPluginAPIMockRecorder) GetMattermostGroupByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMattermostGroupByName", reflect.TypeOf((*MockPluginAPI)(nil).GetMattermostGroupByName), arg0)
+}
+
+// GetMattermostGroupMembers mocks base method.
+func (m *MockPluginAPI) GetMattermostGroupMembers(arg0 string) ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMattermostGroupMembers", arg0)
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMattermostGroupMembers indicates an expected call of GetMattermostGroupMembers.
+func (mr *MockPluginAPIMockRecorder) GetMattermostGroupMembers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMattermostGroupMembers", reflect.TypeOf((*MockPluginAPI)(nil).GetMattermostGroupMembers), arg0)
+}
+
 // GetMattermostUser mocks base method.
 func (m *MockPluginAPI) GetMattermostUser(arg0 string) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -123,6 +212,21 @@ func (mr *MockPluginAPIMockRecorder) GetMattermostUserTeams(arg0 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMattermostUserTeams", reflect.TypeOf((*MockPluginAPI)(nil).GetMattermostUserTeams), arg0)
 }
 
+// GetMattermostUsersInChannel mocks base method.
+func (m *MockPluginAPI) GetMattermostUsersInChannel(arg0 string) ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMattermostUsersInChannel", arg0)
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMattermostUsersInChannel indicates an expected call of GetMattermostUsersInChannel.
+func (mr *MockPluginAPIMockRecorder) GetMattermostUsersInChannel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMattermostUsersInChannel", reflect.TypeOf((*MockPluginAPI)(nil).GetMattermostUsersInChannel), arg0)
+}
+
 // GetPost mocks base method.
 func (m *MockPluginAPI) GetPost(arg0 string) (*model.Post, error) {
 	m.ctrl.T.Helper()
@@ -153,6 +257,20 @@ func (mr *MockPluginAPIMockRecorder) IsSysAdmin(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSysAdmin", reflect.TypeOf((*MockPluginAPI)(nil).IsSysAdmin), arg0)
 }
 
+// OpenInteractiveDialog mocks base method.
+func (m *MockPluginAPI) OpenInteractiveDialog(arg0 model.OpenDialogRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenInteractiveDialog", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OpenInteractiveDialog indicates an expected call of OpenInteractiveDialog.
+func (mr *MockPluginAPIMockRecorder) OpenInteractiveDialog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenInteractiveDialog", reflect.TypeOf((*MockPluginAPI)(nil).OpenInteractiveDialog), arg0)
+}
+
 // PublishWebsocketEvent mocks base method.
 func (m *MockPluginAPI) PublishWebsocketEvent(arg0, arg1 string, arg2 map[string]interface{}) {
 	m.ctrl.T.Helper()
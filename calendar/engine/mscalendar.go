@@ -23,6 +23,15 @@ type Engine interface {
 	Welcomer
 	Settings
 	DailySummary
+	WeeklySummary
+	Rooms
+	CalendarImport
+	FreeBusy
+	RSVP
+	EventInvitees
+	NotificationBatching
+	NotificationQueue
+	Metrics
 }
 
 // Dependencies contains all API dependencies
@@ -52,6 +61,14 @@ type PluginAPI interface {
 	SearchLinkableChannelForUser(teamID, mattermostUserID, search string) ([]*model.Channel, error)
 	GetMattermostUserTeams(mattermostUserID string) ([]*model.Team, error)
 	PublishWebsocketEvent(mattermostUserID, event string, payload map[string]any)
+	CanReadChannel(channelID, userID string) bool
+	GetDirectChannel(userID, otherUserID string) (*model.Channel, error)
+	GetFileInfo(fileID string) (*model.FileInfo, error)
+	GetFile(fileID string) ([]byte, error)
+	OpenInteractiveDialog(dialog model.OpenDialogRequest) error
+	GetMattermostUsersInChannel(channelID string) ([]*model.User, error)
+	GetMattermostGroupByName(name string) (*model.Group, error)
+	GetMattermostGroupMembers(groupID string) ([]*model.User, error)
 }
 
 type Env struct {
@@ -5,18 +5,33 @@ package engine
 
 import (
 	"context"
-	"fmt"
+	"crypto/subtle"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost/server/public/model"
 
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/metrics"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
 )
 
-const maxQueueSize = 1024
+const (
+	// notificationClaimDuration is how long a node has to process a
+	// notification before other nodes can claim it.
+	notificationClaimDuration = 2 * time.Minute
+	notificationPollInterval  = 10 * time.Second
+	maxNotificationAttempts   = 5
+	notificationRetryDelay    = 30 * time.Second
+	// notificationDeliveryTTL is how long the deliveries are remembered to
+	// drop the repeated ones, the remote retries for up to 4 hours.
+	notificationDeliveryTTL = 4 * time.Hour
+)
 
 const (
 	FieldSubject        = "Subject"
@@ -28,6 +43,7 @@ const (
 	FieldAttendees      = "Attendees"
 	FieldOrganizer      = "Organizer"
 	FieldResponseStatus = "ResponseStatus"
+	FieldConflicts      = "Conflicts"
 )
 
 const (
@@ -44,7 +60,35 @@ const (
 	ResponseNone  = "notResponded"
 )
 
-var importantNotificationChanges = []string{FieldSubject, FieldWhen}
+// fieldChanges maps the notification fields to the kind of change users can
+// choose to be notified of.
+// The notifications failing with these errors are not about a subscription of
+// the plugin in use, they are dropped rather than kept for the admins.
+var (
+	errUnknownSubscription  = errors.New("unknown subscription")
+	errOrphanedSubscription = errors.New("subscription is orphaned")
+	errUnauthorizedWebhook  = errors.New("unauthorized webhook")
+)
+
+var fieldChanges = map[string]string{
+	FieldSubject:     store.EventChangeSubject,
+	FieldWhen:        store.EventChangeTime,
+	FieldDuration:    store.EventChangeTime,
+	FieldLocation:    store.EventChangeLocation,
+	FieldAttendees:   store.EventChangeAttendees,
+	FieldBodyPreview: store.EventChangeDescription,
+	FieldImportance:  store.EventChangeImportance,
+}
+
+var changeFieldOrder = []string{
+	FieldSubject,
+	FieldWhen,
+	FieldDuration,
+	FieldLocation,
+	FieldAttendees,
+	FieldBodyPreview,
+	FieldImportance,
+}
 
 var notificationFieldOrder = []string{
 	FieldWhen,
@@ -61,116 +105,300 @@ type NotificationProcessor interface {
 
 type notificationProcessor struct {
 	Env
-	envChan chan Env
+	envLock sync.RWMutex
 
-	queue chan *remote.Notification
-	quit  chan bool
+	wake chan struct{}
+	quit chan bool
 }
 
 func NewNotificationProcessor(env Env) NotificationProcessor {
 	processor := &notificationProcessor{
-		Env:     env,
-		envChan: make(chan (Env)),
-		queue:   make(chan (*remote.Notification), maxQueueSize),
-		quit:    make(chan (bool)),
+		Env:  env,
+		wake: make(chan struct{}, 1),
+		quit: make(chan bool),
 	}
 	go processor.work()
 	return processor
 }
 
+// Enqueue persists the notifications, so they are processed even if the
+// plugin restarts, by this node or by any other node of the cluster. The
+// notifications of unknown subscriptions, or with a client state that does
+// not match, are dropped so that forged webhooks do not fill the queue.
 func (processor *notificationProcessor) Enqueue(notifications ...*remote.Notification) error {
-	for _, n := range notifications {
-		select {
-		case processor.queue <- n:
-		default:
-			return fmt.Errorf("webhook notification: queue full, dropped notification")
+	processor.envLock.RLock()
+	notifications = processor.dropUnauthorized(notifications)
+	notifications = processor.dropRepeatedDeliveries(notifications)
+	err := processor.Store.PushNotifications(notifications, time.Now())
+	if err != nil {
+		for _, n := range notifications {
+			if n.DeliveryID == "" {
+				continue
+			}
+			unmarkErr := processor.Store.UnmarkNotificationDelivered(n.DeliveryID)
+			if unmarkErr != nil {
+				processor.Logger.Warnf("webhook notification: failed to unmark delivery: `%v`.", unmarkErr)
+			}
 		}
 	}
+	processor.envLock.RUnlock()
+	if errors.Is(err, store.ErrNotificationQueueFull) {
+		for range notifications {
+			metrics.IncNotificationsDropped(metrics.DropQueueFull)
+		}
+	}
+	if err != nil {
+		return errors.Wrap(err, "webhook notification: failed to queue notifications")
+	}
+
+	select {
+	case processor.wake <- struct{}{}:
+	default:
+	}
 	return nil
 }
 
+// dropUnauthorized returns the notifications of known subscriptions with
+// their client state. If a subscription cannot be loaded, its notifications
+// are kept, they are checked again when processed.
+func (processor *notificationProcessor) dropUnauthorized(notifications []*remote.Notification) []*remote.Notification {
+	subs := map[string]*store.Subscription{}
+	kept := []*remote.Notification{}
+	for _, n := range notifications {
+		sub, loaded := subs[n.SubscriptionID]
+		if !loaded {
+			var err error
+			sub, err = processor.Store.LoadSubscription(n.SubscriptionID)
+			if err != nil && !errors.Is(err, store.ErrNotFound) {
+				processor.Logger.Warnf("webhook notification: failed to load subscription: `%v`.", err)
+				kept = append(kept, n)
+				continue
+			}
+			subs[n.SubscriptionID] = sub
+		}
+
+		reason := ""
+		switch {
+		case sub == nil || sub.Remote == nil:
+			reason = "unknown subscription"
+		case sub.Remote.ClientState != "" && subtle.ConstantTimeCompare([]byte(sub.Remote.ClientState), []byte(n.ClientState)) != 1:
+			reason = "invalid client state"
+		}
+		if reason != "" {
+			metrics.IncNotificationsDropped(metrics.DropRejected)
+			processor.Logger.With(bot.LogContext{
+				"audit":          "webhook_rejected",
+				"reason":         reason,
+				"subscriptionID": n.SubscriptionID,
+			}).Warnf("webhook notification: rejected webhook: %s.", reason)
+			continue
+		}
+		kept = append(kept, n)
+	}
+	return kept
+}
+
+// dropRepeatedDeliveries returns the notifications not received before. If
+// the deliveries cannot be checked, the notifications are kept.
+func (processor *notificationProcessor) dropRepeatedDeliveries(notifications []*remote.Notification) []*remote.Notification {
+	kept := []*remote.Notification{}
+	for _, n := range notifications {
+		if n.DeliveryID == "" {
+			kept = append(kept, n)
+			continue
+		}
+		isNew, err := processor.Store.MarkNotificationDelivered(n.DeliveryID, notificationDeliveryTTL)
+		if err != nil {
+			processor.Logger.Warnf("webhook notification: failed to check delivery: `%v`.", err)
+			kept = append(kept, n)
+			continue
+		}
+		if !isNew {
+			metrics.IncNotificationsDropped(metrics.DropRepeated)
+			processor.Logger.With(bot.LogContext{
+				"subscriptionID": n.SubscriptionID,
+				"deliveryID":     n.DeliveryID,
+			}).Debugf("webhook notification: dropped repeated delivery.")
+			continue
+		}
+		kept = append(kept, n)
+	}
+	return kept
+}
+
 func (processor *notificationProcessor) Configure(env Env) {
-	processor.envChan <- env
+	processor.envLock.Lock()
+	defer processor.envLock.Unlock()
+	processor.Env = env
 }
 
 func (processor *notificationProcessor) Quit() {
 	processor.quit <- true
 }
 
+// work processes the queue when notifications are enqueued on this node, and
+// polls it for the notifications to retry and those enqueued on other nodes.
 func (processor *notificationProcessor) work() {
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
-		case n := <-processor.queue:
-			err := processor.processNotification(n)
-			if err != nil {
-				processor.Logger.With(bot.LogContext{
-					"subscriptionID": n.SubscriptionID,
-				}).Infof("webhook notification: failed: `%v`.", err)
-			}
-
-		case env := <-processor.envChan:
-			processor.Env = env
-
+		case <-processor.wake:
+		case <-ticker.C:
 		case <-processor.quit:
 			return
 		}
+
+		for processor.processNextNotification() {
+		}
+	}
+}
+
+// processNextNotification claims a notification from the queue and processes
+// it. It returns false once no notification is ready.
+func (processor *notificationProcessor) processNextNotification() bool {
+	processor.envLock.RLock()
+	defer processor.envLock.RUnlock()
+
+	q, err := processor.Store.ClaimNotification(time.Now(), notificationClaimDuration)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			processor.Logger.Warnf("webhook notification: failed to claim notification: `%v`.", err)
+		}
+		return false
+	}
+
+	processor.processQueuedNotification(q)
+	return true
+}
+
+// processQueuedNotification retries the notification later if the remote
+// failed temporarily, or moves it to the dead-letter list for the admins once
+// it cannot succeed. The notifications of unknown or orphaned subscriptions,
+// and the unauthorized ones, are dropped: retrying them would not help.
+func (processor *notificationProcessor) processQueuedNotification(q *store.QueuedNotification) {
+	logger := processor.Logger.With(bot.LogContext{
+		"subscriptionID": q.Notification.SubscriptionID,
+		"attempts":       q.Attempts,
+	})
+
+	err := processor.processNotification(q.Notification)
+	if err == nil {
+		err = processor.Store.CompleteNotification(q.ID)
+		if err != nil {
+			logger.Warnf("webhook notification: failed to remove notification from queue: `%v`.", err)
+		}
+		return
+	}
+
+	if remote.IsTransientError(err) && q.Attempts < maxNotificationAttempts {
+		nextAttempt := time.Now().Add(notificationRetryDelay << (q.Attempts - 1))
+		logger.Infof("webhook notification: failed, retrying at %s: `%v`.", nextAttempt.Format(time.RFC3339), err)
+		err = processor.Store.RetryNotification(q.ID, nextAttempt, err.Error())
+		if err != nil {
+			logger.Warnf("webhook notification: failed to retry notification: `%v`.", err)
+		}
+		return
+	}
+
+	if errors.Is(err, errUnknownSubscription) || errors.Is(err, errOrphanedSubscription) || errors.Is(err, errUnauthorizedWebhook) {
+		logger.Infof("webhook notification: dropped: `%v`.", err)
+		metrics.IncNotificationsDropped(metrics.DropRejected)
+		err = processor.Store.CompleteNotification(q.ID)
+		if err != nil {
+			logger.Warnf("webhook notification: failed to remove notification from queue: `%v`.", err)
+		}
+		return
+	}
+
+	logger.Infof("webhook notification: failed: `%v`.", err)
+	metrics.IncNotificationsDropped(metrics.DropDeadLetter)
+	err = processor.Store.DeadLetterNotification(q.ID, err.Error())
+	if err != nil {
+		logger.Warnf("webhook notification: failed to move notification to the dead-letter list: `%v`.", err)
 	}
 }
 
 func (processor *notificationProcessor) processNotification(n *remote.Notification) error {
 	sub, err := processor.Store.LoadSubscription(n.SubscriptionID)
+	if errors.Is(err, store.ErrNotFound) {
+		return errUnknownSubscription
+	}
 	if err != nil {
 		return err
 	}
 	creator, err := processor.Store.LoadUser(sub.MattermostCreatorID)
+	if errors.Is(err, store.ErrNotFound) {
+		return errOrphanedSubscription
+	}
 	if err != nil {
 		return err
 	}
-	if sub.Remote.ID != creator.Settings.EventSubscriptionID {
-		return errors.New("subscription is orphaned")
+	replaced := sub.Remote.ID != creator.Settings.EventSubscriptionID
+	if replaced && !isReplacedSubscriptionAccepted(sub, creator, time.Now()) {
+		return errOrphanedSubscription
 	}
-	if sub.Remote.ClientState != "" && sub.Remote.ClientState != n.ClientState {
-		return errors.New("unauthorized webhook")
+	if sub.Remote.ClientState != "" && subtle.ConstantTimeCompare([]byte(sub.Remote.ClientState), []byte(n.ClientState)) != 1 {
+		processor.Logger.With(bot.LogContext{
+			"audit":            "webhook_rejected",
+			"subscriptionID":   n.SubscriptionID,
+			"mattermostUserID": creator.MattermostUserID,
+		}).Warnf("webhook notification: rejected webhook with an invalid client state.")
+		return errUnauthorizedWebhook
 	}
 
 	n.Subscription = sub.Remote
 	n.SubscriptionCreator = creator.Remote
 
-	client := processor.Remote.MakeUserClient(context.Background(), creator.OAuth2Token, sub.MattermostCreatorID, processor.Poster, processor.Store)
-
-	if n.RecommendRenew {
-		var renewed *remote.Subscription
-		renewed, err = client.RenewSubscription(processor.Config.GetNotificationURL(), sub.Remote.CreatorID, n.Subscription)
-		if err != nil {
-			return err
+	client := NewCachedClient(processor.Env, processor.Remote.MakeUserClient(context.Background(), creator.OAuth2Token, sub.MattermostCreatorID, processor.Poster, processor.Store))
+	if n.LifecycleEvent != "" {
+		if replaced {
+			// The new subscription of the creator is kept working instead.
+			return nil
 		}
+		return processor.processLifecycleNotification(client, creator, sub, n)
+	}
 
-		storedSub := &store.Subscription{
-			Remote:              renewed,
-			MattermostCreatorID: creator.MattermostUserID,
-			PluginVersion:       processor.Config.PluginVersion,
-		}
-		err = processor.Store.StoreUserSubscription(creator, storedSub)
+	// The events of the creator changed, so the cached ones are stale.
+	processor.invalidateRemoteCache(creator)
+
+	if n.RecommendRenew && !replaced {
+		err = processor.renewSubscription(client, creator, sub)
 		if err != nil {
 			return err
 		}
-		processor.Logger.With(bot.LogContext{
-			"MattermostUserID": creator.MattermostUserID,
-			"SubscriptionID":   n.SubscriptionID,
-		}).Debugf("webhook notification: renewed user subscription.")
 	}
 
 	if n.IsBare {
+		n.DecryptionKey = processor.notificationDecryptionKey(n)
 		n, err = client.GetNotificationData(n)
 		if err != nil {
 			return err
 		}
 	}
 
-	var sa *model.SlackAttachment
-	prior, err := processor.Store.LoadUserEvent(creator.MattermostUserID, n.Event.ICalUID)
-	if err != nil && err != store.ErrNotFound {
-		return err
+	var prior *store.Event
+	if n.ChangeType == remote.ChangeTypeDeleted {
+		prior, err = processor.Store.LoadUserEventByRemoteID(creator.MattermostUserID, n.Event.ID)
+		if errors.Is(err, store.ErrNotFound) {
+			processor.Logger.With(bot.LogContext{
+				"MattermostUserID": creator.MattermostUserID,
+				"EventID":          n.Event.ID,
+			}).Debugf("webhook notification: deleted event not found.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Deleted events only have their ID, the rest is known from the
+		// stored event.
+		n.Event = prior.Remote
+	} else {
+		prior, err = processor.Store.LoadUserEvent(creator.MattermostUserID, n.Event.ICalUID)
+		if err != nil && err != store.ErrNotFound {
+			return err
+		}
 	}
 
 	mailSettings, err := client.GetMailboxSettings(sub.Remote.CreatorID)
@@ -178,40 +406,179 @@ func (processor *notificationProcessor) processNotification(n *remote.Notificati
 		return err
 	}
 	timezone := mailSettings.TimeZone
+	settings := creator.Settings.GetEventNotifications()
+
+	if n.ChangeType == remote.ChangeTypeDeleted || (n.Event.IsCancelled && (prior == nil || !prior.Remote.IsCancelled)) {
+		return processor.processCancelledEvent(creator, n, prior, timezone, settings)
+	}
 
+	var sa *model.SlackAttachment
+	notify := true
 	if prior != nil {
-		var changed bool
-		changed, sa = processor.updatedEventSlackAttachment(n, prior.Remote, timezone)
-		if !changed {
+		notify, sa = processor.updatedEventSlackAttachment(n, prior.Remote, timezone, settings.Changes)
+		if !notify {
 			processor.Logger.With(bot.LogContext{
 				"MattermostUserID": creator.MattermostUserID,
 				"SubscriptionID":   n.SubscriptionID,
 				"ChangeType":       n.ChangeType,
 				"EventID":          n.Event.ID,
 				"EventICalUID":     n.Event.ICalUID,
-			}).Debugf("webhook notification: no changes detected in event.")
-			return nil
+			}).Debugf("webhook notification: no changes to notify in event.")
 		}
 	} else {
 		sa = processor.newEventSlackAttachment(n, timezone)
 		prior = &store.Event{}
+
+		if n.Event.ResponseRequested && !n.Event.IsOrganizer {
+			conflicts, conflictsErr := findEventConflicts(client, sub.Remote.CreatorID, n.Event)
+			if conflictsErr != nil {
+				processor.Logger.With(bot.LogContext{
+					"MattermostUserID": creator.MattermostUserID,
+					"EventID":          n.Event.ID,
+					"err":              conflictsErr.Error(),
+				}).Warnf("webhook notification: failed to check event conflicts.")
+			}
+			if len(conflicts) > 0 {
+				processor.addConflictsToSlackAttachment(sa, n.Event.ID, conflicts, timezone)
+			}
+		}
 	}
 
-	_, err = processor.Poster.DMWithAttachments(creator.MattermostUserID, sa)
-	if err != nil {
-		return err
+	if notify && n.Event.IsOrganizer && settings.MuteOwnEvents {
+		notify = false
+		processor.Logger.With(bot.LogContext{
+			"MattermostUserID": creator.MattermostUserID,
+			"EventID":          n.Event.ID,
+		}).Debugf("webhook notification: notifications muted for own event.")
+	}
+
+	if notify {
+		err = processor.deliverNotification(creator, sa, timezone)
+		if err != nil {
+			return err
+		}
 	}
 
+	// The event is stored even if no notification was sent, so the next
+	// changes are compared with the latest version.
 	prior.Remote = n.Event
 	err = processor.Store.StoreUserEvent(creator.MattermostUserID, prior)
 	if err != nil {
 		return err
 	}
 
-	processor.Logger.With(bot.LogContext{
+	if notify {
+		processor.Logger.With(bot.LogContext{
+			"MattermostUserID": creator.MattermostUserID,
+			"SubscriptionID":   n.SubscriptionID,
+		}).Debugf("Notified: %s.", sa.Title)
+	}
+
+	return nil
+}
+
+// processCancelledEvent notifies the user of an event that was cancelled or
+// deleted, and cleans up what the plugin keeps for it. Events deleted after
+// being cancelled were already notified.
+func (processor *notificationProcessor) processCancelledEvent(creator *store.User, n *remote.Notification, prior *store.Event, timezone string, settings *store.EventNotificationUserSettings) error {
+	deleted := n.ChangeType == remote.ChangeTypeDeleted
+	event := n.Event
+	if prior != nil {
+		// Show the time the event was supposed to take place
+		original := *prior.Remote
+		original.IsCancelled = original.IsCancelled || n.Event.IsCancelled
+		event = &original
+	}
+	logger := processor.Logger.With(bot.LogContext{
 		"MattermostUserID": creator.MattermostUserID,
 		"SubscriptionID":   n.SubscriptionID,
-	}).Debugf("Notified: %s.", sa.Title)
+		"ChangeType":       n.ChangeType,
+		"EventID":          event.ID,
+		"EventICalUID":     event.ICalUID,
+	})
+
+	notify := !(deleted && prior.Remote.IsCancelled)
+	if notify && !slices.Contains(settings.Changes, store.EventChangeCancellation) {
+		notify = false
+		logger.Debugf("webhook notification: cancellation notifications disabled.")
+	}
+	if notify && event.IsOrganizer && settings.MuteOwnEvents {
+		notify = false
+		logger.Debugf("webhook notification: notifications muted for own event.")
+	}
+
+	if notify {
+		sa := processor.cancelledEventSlackAttachment(event, deleted, timezone)
+		err := processor.deliverNotification(creator, sa, timezone)
+		if err != nil {
+			return err
+		}
+		logger.Debugf("Notified: %s.", sa.Title)
+	}
+
+	err := processor.cleanUpCancelledEvent(creator, event, deleted)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		// Keep the cancelled event, so later changes to it are not notified
+		// as a new event.
+		if prior == nil {
+			prior = &store.Event{}
+		}
+		prior.Remote = n.Event
+		err = processor.Store.StoreUserEvent(creator.MattermostUserID, prior)
+		if err != nil {
+			return err
+		}
+	}
+
+	if isActiveEvent(creator, event.ICalUID) {
+		_, _, err = New(processor.Env, creator.MattermostUserID).Sync(creator.MattermostUserID)
+		if err != nil {
+			logger.Warnf("webhook notification: failed to sync the status after the event was cancelled. err=%v", err)
+		}
+	}
+
+	return nil
+}
+
+// cleanUpCancelledEvent removes the stored event and the channels linked to
+// it. The metadata is shared by all the users, so it is only removed if the
+// event is gone for everyone.
+func (processor *notificationProcessor) cleanUpCancelledEvent(creator *store.User, event *remote.Event, deleted bool) error {
+	if deleted {
+		err := processor.Store.DeleteUserEvent(creator.MattermostUserID, event.ICalUID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete stored event")
+		}
+	}
+
+	if event.IsCancelled || event.IsOrganizer {
+		err := processor.Store.DeleteEventMetadata(event.ICalUID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return errors.Wrap(err, "failed to delete event metadata")
+		}
+	}
+
+	if _, ok := creator.ChannelEvents[event.ICalUID]; ok {
+		err := processor.Store.DeleteUserLinkedEvent(creator.MattermostUserID, event.ICalUID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete linked event")
+		}
+	}
 
 	return nil
 }
+
+// isActiveEvent returns true if the user's status is currently set because
+// of the event.
+func isActiveEvent(user *store.User, iCalUID string) bool {
+	for _, h := range user.ActiveEvents {
+		if strings.HasPrefix(h, iCalUID+" ") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const NotificationBatchMaxMinutes = 24 * 60
+
+type NotificationBatching interface {
+	SetQuietHours(user *User, start, end string) (*store.NotificationBatchUserSettings, error)
+	SetNotificationBatchMinutes(user *User, minutes int) (*store.NotificationBatchUserSettings, error)
+	ProcessAllNotificationBatches(now time.Time) error
+}
+
+// SetQuietHours holds the event notifications between start and end, in the
+// Kitchen format. Quiet hours are turned off if start is empty.
+func (m *mscalendar) SetQuietHours(user *User, start, end string) (*store.NotificationBatchUserSettings, error) {
+	if start != "" {
+		startTime, err := time.Parse(time.Kitchen, start)
+		if err != nil {
+			return nil, errors.New("Invalid time value: " + start)
+		}
+		endTime, err := time.Parse(time.Kitchen, end)
+		if err != nil {
+			return nil, errors.New("Invalid time value: " + end)
+		}
+		if startTime.Equal(endTime) {
+			return nil, errors.New("quiet hours must start and end at different times")
+		}
+	}
+
+	err := m.Filter(withUserExpanded(user))
+	if err != nil {
+		return nil, err
+	}
+
+	timezone, err := m.GetTimezone(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Settings.NotificationBatch == nil {
+		user.Settings.NotificationBatch = &store.NotificationBatchUserSettings{}
+	}
+	batch := user.Settings.NotificationBatch
+	batch.QuietHoursStart = start
+	batch.QuietHoursEnd = end
+	batch.Timezone = timezone
+
+	err = m.Store.StoreUser(user.User)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// SetNotificationBatchMinutes delivers the event notifications together every
+// number of minutes. Batching is turned off if minutes is 0.
+func (m *mscalendar) SetNotificationBatchMinutes(user *User, minutes int) (*store.NotificationBatchUserSettings, error) {
+	if minutes < 0 || minutes > NotificationBatchMaxMinutes {
+		return nil, fmt.Errorf("notifications must be delivered every 0 to %d minutes", NotificationBatchMaxMinutes)
+	}
+	if minutes%int(StatusSyncJobInterval/time.Minute) != 0 {
+		return nil, fmt.Errorf("minutes must be a multiple of %d", StatusSyncJobInterval/time.Minute)
+	}
+
+	err := m.Filter(withUserExpanded(user))
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Settings.NotificationBatch == nil {
+		user.Settings.NotificationBatch = &store.NotificationBatchUserSettings{}
+	}
+	batch := user.Settings.NotificationBatch
+	batch.BatchMinutes = minutes
+
+	err = m.Store.StoreUser(user.User)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// ProcessAllNotificationBatches delivers the notifications held for the users
+// whose quiet hours are over or whose batching period has passed. It runs as
+// part of the status sync job.
+func (m *mscalendar) ProcessAllNotificationBatches(now time.Time) error {
+	userIndex, err := m.Store.LoadUserIndex()
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	count := 0
+	for _, u := range userIndex {
+		storeUser, err := m.Store.LoadUser(u.MattermostUserID)
+		if err != nil {
+			m.Logger.Warnf("Error loading user %s for notification batch. err=%v", u.MattermostUserID, err)
+			continue
+		}
+
+		// Users that never held notifications have no batch to deliver
+		settings := storeUser.Settings.NotificationBatch
+		if settings == nil {
+			continue
+		}
+
+		batch, err := m.Store.LoadNotificationBatch(storeUser.MattermostUserID)
+		if err != nil {
+			if !errors.Is(err, store.ErrNotFound) {
+				m.Logger.Warnf("Error loading notification batch for user %s. err=%v", storeUser.MattermostUserID, err)
+			}
+			continue
+		}
+		timezone := m.notificationBatchTimezone(storeUser)
+		if !isNotificationBatchDue(settings, batch, timezone, now) {
+			continue
+		}
+
+		err = m.deliverNotificationBatch(storeUser.MattermostUserID, timezone)
+		if err != nil {
+			m.Logger.Warnf("Error delivering notification batch to user %s. err=%v", storeUser.MattermostUserID, err)
+			continue
+		}
+		count++
+	}
+
+	m.Logger.Debugf("Delivered %d notification batches", count)
+	return nil
+}
+
+// notificationBatchTimezone reads the current time zone of the user, so that
+// the quiet hours follow them when they travel. The time zone known when the
+// quiet hours were set is used if it cannot be read.
+func (m *mscalendar) notificationBatchTimezone(storeUser *store.User) string {
+	engine, err := m.FilterCopy(withActingUser(storeUser.MattermostUserID))
+	if err == nil {
+		var timezone string
+		timezone, err = engine.GetTimezone(NewUser(storeUser.MattermostUserID))
+		if err == nil {
+			return timezone
+		}
+	}
+	m.Logger.Warnf("Error getting timezone of user %s for notification batch. err=%v", storeUser.MattermostUserID, err)
+	return storeUser.Settings.NotificationBatch.Timezone
+}
+
+// deliverNotificationBatch posts the held notifications as one digest. They
+// are held again if the post fails.
+func (m *mscalendar) deliverNotificationBatch(mattermostUserID, timezone string) error {
+	batch, err := m.Store.TakeNotificationBatch(mattermostUserID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	_, err = m.Poster.DMWithMessageAndAttachments(mattermostUserID, formatNotificationBatch(batch, timezone), batch.Attachments...)
+	if err != nil {
+		now := time.Unix(batch.Since, 0)
+		for _, sa := range batch.Attachments {
+			if addErr := m.Store.AddToNotificationBatch(mattermostUserID, sa, now); addErr != nil {
+				return errors.Wrap(addErr, "failed to hold notifications again")
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func formatNotificationBatch(batch *store.NotificationBatch, timezone string) string {
+	since := time.Unix(batch.Since, 0).In(userLocation(timezone)).Format(time.Kitchen)
+	message := fmt.Sprintf("#### Calendar notifications\nYou have %d event notifications since %s.", len(batch.Attachments), since)
+	if batch.Dropped > 0 {
+		message += fmt.Sprintf(" %d older notifications were dropped.", batch.Dropped)
+	}
+	return message
+}
+
+// holdNotification returns true if the notification must be added to the batch
+// of the user instead of being delivered right away.
+func holdNotification(settings *store.NotificationBatchUserSettings, timezone string, now time.Time) bool {
+	if settings == nil {
+		return false
+	}
+	return settings.BatchMinutes > 0 || isQuietTime(settings, timezone, now)
+}
+
+// isNotificationBatchDue returns true once the quiet hours are over and, if
+// batching is on, the batching period since the first held notification has
+// passed.
+func isNotificationBatchDue(settings *store.NotificationBatchUserSettings, batch *store.NotificationBatch, timezone string, now time.Time) bool {
+	if isQuietTime(settings, timezone, now) {
+		return false
+	}
+	if settings.BatchMinutes == 0 {
+		return true
+	}
+	return !now.Before(time.Unix(batch.Since, 0).Add(time.Duration(settings.BatchMinutes) * time.Minute))
+}
+
+// isQuietTime returns true if now is within the quiet hours of the user, in
+// their current time zone. Quiet hours may span midnight, i.e. from 10:00PM
+// to 7:00AM.
+func isQuietTime(settings *store.NotificationBatchUserSettings, timezone string, now time.Time) bool {
+	if settings.QuietHoursStart == "" {
+		return false
+	}
+	start, err := time.Parse(time.Kitchen, settings.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(time.Kitchen, settings.QuietHoursEnd)
+	if err != nil {
+		return false
+	}
+
+	local := now.In(userLocation(timezone))
+	current := local.Hour()*60 + local.Minute()
+	startMinutes := start.Hour()*60 + start.Minute()
+	endMinutes := end.Hour()*60 + end.Minute()
+
+	if startMinutes < endMinutes {
+		return current >= startMinutes && current < endMinutes
+	}
+	return current >= startMinutes || current < endMinutes
+}
+
+func userLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(tz.Go(timezone))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// deliverNotification sends the notification to the user, or holds it in the
+// batch of the user during their quiet hours or batching period. The timezone
+// is the current one of the user.
+func (processor *notificationProcessor) deliverNotification(creator *store.User, sa *model.SlackAttachment, timezone string) error {
+	now := time.Now()
+	if holdNotification(creator.Settings.NotificationBatch, timezone, now) {
+		return processor.Store.AddToNotificationBatch(creator.MattermostUserID, sa, now)
+	}
+
+	_, err := processor.Poster.DMWithAttachments(creator.MattermostUserID, sa)
+	return err
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestIsQuietTime(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		start    string
+		end      string
+		now      time.Time
+		expected bool
+	}{
+		{name: "quiet hours off", now: time.Date(2020, 2, 12, 23, 0, 0, 0, time.UTC)},
+		{name: "during the day", start: "12:00PM", end: "2:00PM", now: time.Date(2020, 2, 12, 13, 0, 0, 0, time.UTC), expected: true},
+		{name: "at the end", start: "12:00PM", end: "2:00PM", now: time.Date(2020, 2, 12, 14, 0, 0, 0, time.UTC)},
+		{name: "before midnight", start: "10:00PM", end: "7:00AM", now: time.Date(2020, 2, 12, 23, 0, 0, 0, time.UTC), expected: true},
+		{name: "after midnight", start: "10:00PM", end: "7:00AM", now: time.Date(2020, 2, 12, 6, 59, 0, 0, time.UTC), expected: true},
+		{name: "outside overnight hours", start: "10:00PM", end: "7:00AM", now: time.Date(2020, 2, 12, 12, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := &store.NotificationBatchUserSettings{QuietHoursStart: tc.start, QuietHoursEnd: tc.end}
+			require.Equal(t, tc.expected, isQuietTime(settings, "UTC", tc.now))
+		})
+	}
+
+	t.Run("in the current timezone of the user", func(t *testing.T) {
+		// The quiet hours were set in another time zone
+		settings := &store.NotificationBatchUserSettings{QuietHoursStart: "10:00PM", QuietHoursEnd: "7:00AM", Timezone: "Tokyo Standard Time"}
+		require.True(t, isQuietTime(settings, "Eastern Standard Time", time.Date(2020, 2, 12, 4, 0, 0, 0, time.UTC)))
+		require.False(t, isQuietTime(settings, "Eastern Standard Time", time.Date(2020, 2, 12, 14, 0, 0, 0, time.UTC)))
+	})
+}
+
+func TestIsNotificationBatchDue(t *testing.T) {
+	since := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+	batch := &store.NotificationBatch{Since: since.Unix()}
+
+	for _, tc := range []struct {
+		name     string
+		settings *store.NotificationBatchUserSettings
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "during quiet hours",
+			settings: &store.NotificationBatchUserSettings{QuietHoursStart: "9:00AM", QuietHoursEnd: "11:00AM", Timezone: "UTC"},
+			now:      since.Add(30 * time.Minute),
+		},
+		{
+			name:     "quiet hours over",
+			settings: &store.NotificationBatchUserSettings{QuietHoursStart: "9:00AM", QuietHoursEnd: "11:00AM", Timezone: "UTC"},
+			now:      since.Add(time.Hour),
+			expected: true,
+		},
+		{
+			name:     "batching period not passed",
+			settings: &store.NotificationBatchUserSettings{BatchMinutes: 30},
+			now:      since.Add(25 * time.Minute),
+		},
+		{
+			name:     "batching period passed",
+			settings: &store.NotificationBatchUserSettings{BatchMinutes: 30},
+			now:      since.Add(30 * time.Minute),
+			expected: true,
+		},
+		{
+			name:     "batching turned off",
+			settings: &store.NotificationBatchUserSettings{},
+			now:      since,
+			expected: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, isNotificationBatchDue(tc.settings, batch, "UTC", tc.now))
+		})
+	}
+}
+
+func TestSetNotificationBatchMinutes(t *testing.T) {
+	mscalendar, mockStore, _, _, mockPluginAPI, _, _ := GetMockSetup(t)
+
+	for _, tc := range []struct {
+		name          string
+		minutes       int
+		expectedError string
+		expected      *store.NotificationBatchUserSettings
+	}{
+		{name: "too many minutes", minutes: NotificationBatchMaxMinutes + 5, expectedError: "notifications must be delivered every 0 to 1440 minutes"},
+		{name: "not a multiple of the job interval", minutes: 7, expectedError: "minutes must be a multiple of 5"},
+		{name: "enabled", minutes: 30, expected: &store.NotificationBatchUserSettings{BatchMinutes: 30}},
+		{name: "disabled", minutes: 0, expected: &store.NotificationBatchUserSettings{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectedError == "" {
+				mockStore.EXPECT().LoadUser(MockMMUserID).Return(&store.User{MattermostUserID: MockMMUserID, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil).Times(1)
+				mockStore.EXPECT().StoreUser(gomock.Any()).Return(nil).Times(1)
+			}
+
+			settings, err := mscalendar.SetNotificationBatchMinutes(NewUser(MockMMUserID), tc.minutes)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, settings)
+		})
+	}
+}
+
+func TestSetQuietHours(t *testing.T) {
+	mscalendar, mockStore, _, mockRemote, mockPluginAPI, mockClient, _ := GetMockSetup(t)
+
+	for _, tc := range []struct {
+		name          string
+		start         string
+		end           string
+		expectedError string
+		expected      *store.NotificationBatchUserSettings
+	}{
+		{name: "invalid start", start: "22:00", end: "7:00AM", expectedError: "Invalid time value: 22:00"},
+		{name: "same start and end", start: "7:00AM", end: "7:00AM", expectedError: "quiet hours must start and end at different times"},
+		{name: "enabled", start: "10:00PM", end: "7:00AM", expected: &store.NotificationBatchUserSettings{QuietHoursStart: "10:00PM", QuietHoursEnd: "7:00AM", Timezone: "Eastern Standard Time"}},
+		{name: "disabled", expected: &store.NotificationBatchUserSettings{Timezone: "Eastern Standard Time"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectedError == "" {
+				mockStore.EXPECT().LoadUser(MockMMUserID).Return(&store.User{MattermostUserID: MockMMUserID, Remote: &remote.User{ID: MockRemoteUserID}}, nil).AnyTimes()
+				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient).Times(1)
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "Eastern Standard Time"}, nil).Times(1)
+				mockStore.EXPECT().StoreUser(gomock.Any()).Return(nil).Times(1)
+			}
+
+			settings, err := mscalendar.SetQuietHours(NewUser(MockMMUserID), tc.start, tc.end)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, settings)
+		})
+	}
+}
+
+func TestProcessAllNotificationBatches(t *testing.T) {
+	mscalendar, mockStore, mockPoster, mockRemote, mockPluginAPI, mockClient, mockLogger := GetMockSetup(t)
+	mscalendar.actingUser = NewUser("")
+	now := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+
+	newUser := func(id string, settings *store.NotificationBatchUserSettings) *store.User {
+		return &store.User{
+			MattermostUserID: id,
+			Remote:           &remote.User{ID: id + "_remote"},
+			Settings:         store.Settings{NotificationBatch: settings},
+		}
+	}
+	attachments := []*model.SlackAttachment{{Title: "(new) Planning"}, {Title: "(updated) Retro"}}
+	batch := &store.NotificationBatch{Attachments: attachments, Since: now.Add(-time.Hour).Unix()}
+
+	mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{
+		{MattermostUserID: "dueID"},
+		{MattermostUserID: "quietID"},
+		{MattermostUserID: "failingID"},
+		{MattermostUserID: "noSettingsID"},
+	}, nil).Times(1)
+	mockStore.EXPECT().LoadUser("dueID").Return(newUser("dueID", &store.NotificationBatchUserSettings{BatchMinutes: 30, Timezone: "UTC"}), nil).AnyTimes()
+	// The quiet hours were set in Tokyo, where they are over, but the user
+	// is now in UTC where they are not.
+	mockStore.EXPECT().LoadUser("quietID").Return(newUser("quietID", &store.NotificationBatchUserSettings{QuietHoursStart: "9:00AM", QuietHoursEnd: "11:00AM", Timezone: "Tokyo Standard Time"}), nil).AnyTimes()
+	mockStore.EXPECT().LoadUser("failingID").Return(newUser("failingID", &store.NotificationBatchUserSettings{}), nil).AnyTimes()
+	mockStore.EXPECT().LoadUser("noSettingsID").Return(newUser("noSettingsID", nil), nil).Times(1)
+	mockPluginAPI.EXPECT().GetMattermostUser(gomock.Any()).Return(&model.User{}, nil).AnyTimes()
+	mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient).AnyTimes()
+	mockClient.EXPECT().GetMailboxSettings("dueID_remote").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+	mockClient.EXPECT().GetMailboxSettings("quietID_remote").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+	mockClient.EXPECT().GetMailboxSettings("failingID_remote").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+
+	mockStore.EXPECT().LoadNotificationBatch("dueID").Return(batch, nil).Times(1)
+	mockStore.EXPECT().LoadNotificationBatch("quietID").Return(batch, nil).Times(1)
+	mockStore.EXPECT().LoadNotificationBatch("failingID").Return(batch, nil).Times(1)
+
+	mockStore.EXPECT().TakeNotificationBatch("dueID").Return(batch, nil).Times(1)
+	mockPoster.EXPECT().DMWithMessageAndAttachments("dueID", "#### Calendar notifications\nYou have 2 event notifications since 9:00AM.", attachments[0], attachments[1]).Return("postID", nil).Times(1)
+
+	// Notifications are held again if they cannot be delivered
+	mockStore.EXPECT().TakeNotificationBatch("failingID").Return(batch, nil).Times(1)
+	mockPoster.EXPECT().DMWithMessageAndAttachments("failingID", gomock.Any(), gomock.Any(), gomock.Any()).Return("", errors.New("failed")).Times(1)
+	mockStore.EXPECT().AddToNotificationBatch("failingID", attachments[0], time.Unix(batch.Since, 0)).Return(nil).Times(1)
+	mockStore.EXPECT().AddToNotificationBatch("failingID", attachments[1], time.Unix(batch.Since, 0)).Return(nil).Times(1)
+	mockLogger.EXPECT().Warnf("Error delivering notification batch to user %s. err=%v", "failingID", gomock.Any()).Times(1)
+
+	mockLogger.EXPECT().Debugf("Delivered %d notification batches", 1).Times(1)
+
+	err := mscalendar.ProcessAllNotificationBatches(now)
+	require.NoError(t, err)
+}
+
+func TestNotificationBatchTimezone(t *testing.T) {
+	mscalendar, mockStore, _, mockRemote, mockPluginAPI, mockClient, mockLogger := GetMockSetup(t)
+	mscalendar.actingUser = NewUser("")
+	storeUser := &store.User{
+		MattermostUserID: MockMMUserID,
+		Remote:           &remote.User{ID: MockRemoteUserID},
+		Settings:         store.Settings{NotificationBatch: &store.NotificationBatchUserSettings{Timezone: "Tokyo Standard Time"}},
+	}
+	mockStore.EXPECT().LoadUser(MockMMUserID).Return(storeUser, nil).AnyTimes()
+	mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil).AnyTimes()
+	mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient).AnyTimes()
+
+	t.Run("current timezone", func(t *testing.T) {
+		mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "Eastern Standard Time"}, nil).Times(1)
+
+		require.Equal(t, "Eastern Standard Time", mscalendar.notificationBatchTimezone(storeUser))
+	})
+
+	t.Run("stored timezone if the current one cannot be read", func(t *testing.T) {
+		mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(nil, errors.New("some error")).Times(1)
+		mockLogger.EXPECT().Warnf("Error getting timezone of user %s for notification batch. err=%v", MockMMUserID, gomock.Any()).Times(1)
+
+		require.Equal(t, "Tokyo Standard Time", mscalendar.notificationBatchTimezone(storeUser))
+	})
+}
+
+func TestDeliverNotification(t *testing.T) {
+	_, mockStore, mockPoster, _, _, _, _ := GetMockSetup(t)
+	processor := &notificationProcessor{
+		Env: Env{Dependencies: &Dependencies{Store: mockStore, Poster: mockPoster}},
+	}
+	sa := &model.SlackAttachment{Title: "(new) Planning"}
+
+	t.Run("delivered right away", func(t *testing.T) {
+		mockPoster.EXPECT().DMWithAttachments(MockMMUserID, sa).Return("postID", nil).Times(1)
+
+		err := processor.deliverNotification(&store.User{MattermostUserID: MockMMUserID}, sa, "UTC")
+		require.NoError(t, err)
+	})
+
+	t.Run("held while batching", func(t *testing.T) {
+		mockStore.EXPECT().AddToNotificationBatch(MockMMUserID, sa, gomock.Any()).Return(nil).Times(1)
+
+		err := processor.deliverNotification(&store.User{
+			MattermostUserID: MockMMUserID,
+			Settings:         store.Settings{NotificationBatch: &store.NotificationBatchUserSettings{BatchMinutes: 30}},
+		}, sa, "UTC")
+		require.NoError(t, err)
+	})
+}
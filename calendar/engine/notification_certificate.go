@@ -0,0 +1,136 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+const (
+	notificationCertificateValidity = 30 * 24 * time.Hour
+	// The certificate is replaced before it expires, so that the
+	// subscriptions reconciled meanwhile are created with the new one.
+	notificationCertificateRotateBefore = 7 * 24 * time.Hour
+)
+
+// isRichNotificationsEnabled also requires the encryption key, without which
+// the private keys of the certificates cannot be stored, so that the
+// subscriptions are still created without the events.
+func isRichNotificationsEnabled(env Env) bool {
+	return env.Config != nil && env.Config.EnableRichNotifications && env.Config.EncryptionKey != ""
+}
+
+// notificationEncryption returns the certificate the new subscriptions are to
+// be created with, rotating it when close to expiration. It returns nil when
+// rich notifications are disabled or the certificate is unavailable, in which
+// case the subscriptions only notify of the changes.
+func (m *mscalendar) notificationEncryption() *remote.NotificationEncryption {
+	if !isRichNotificationsEnabled(m.Env) {
+		return nil
+	}
+
+	certificate, err := m.currentNotificationCertificate(time.Now())
+	if err != nil {
+		m.Logger.Warnf("Failed to load the notification certificate, events will not be included in the notifications. err=%v", err)
+		return nil
+	}
+
+	return &remote.NotificationEncryption{
+		CertificateID: certificate.ID,
+		Certificate:   certificate.Certificate,
+	}
+}
+
+func (m *mscalendar) currentNotificationCertificate(now time.Time) (*store.NotificationCertificate, error) {
+	certificates, err := m.Store.LoadNotificationCertificates()
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
+	}
+	if certificates == nil {
+		certificates = &store.NotificationCertificates{}
+	}
+
+	current := certificates.Current
+	if current != nil && now.Add(notificationCertificateRotateBefore).Before(time.Unix(current.ExpiresAt, 0)) {
+		return current, nil
+	}
+
+	certificate, err := generateNotificationCertificate(now)
+	if err != nil {
+		return nil, err
+	}
+	err = m.Store.StoreNotificationCertificates(&store.NotificationCertificates{
+		Current:  certificate,
+		Previous: current,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// notificationDecryptionKey returns the private key of the certificate the
+// notification is encrypted with, or nil if it is not known.
+func (processor *notificationProcessor) notificationDecryptionKey(n *remote.Notification) []byte {
+	if n.EncryptionCertificateID == "" || !isRichNotificationsEnabled(processor.Env) {
+		return nil
+	}
+
+	certificates, err := processor.Store.LoadNotificationCertificates()
+	if err != nil {
+		processor.Logger.Warnf("Failed to load the notification certificates. err=%v", err)
+		return nil
+	}
+	certificate := certificates.Get(n.EncryptionCertificateID)
+	if certificate == nil {
+		return nil
+	}
+	return certificate.PrivateKey
+}
+
+func generateNotificationCertificate(now time.Time) (*store.NotificationCertificate, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate notification certificate key")
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate notification certificate serial number")
+	}
+	expiresAt := now.Add(notificationCertificateValidity)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "Mattermost Microsoft Calendar notifications"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     expiresAt,
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create notification certificate")
+	}
+	privateKey, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal notification certificate key")
+	}
+
+	return &store.NotificationCertificate{
+		ID:          model.NewId(),
+		Certificate: certificate,
+		PrivateKey:  privateKey,
+		ExpiresAt:   expiresAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestCurrentNotificationCertificate(t *testing.T) {
+	now := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+	valid := &store.NotificationCertificate{ID: "valid", ExpiresAt: now.Add(20 * 24 * time.Hour).Unix()}
+	expiring := &store.NotificationCertificate{ID: "expiring", ExpiresAt: now.Add(2 * 24 * time.Hour).Unix()}
+
+	for _, tc := range []struct {
+		name             string
+		stored           *store.NotificationCertificates
+		loadErr          error
+		expectedID       string
+		expectedPrevious *store.NotificationCertificate
+	}{
+		{
+			name:       "valid certificate is kept",
+			stored:     &store.NotificationCertificates{Current: valid},
+			expectedID: "valid",
+		},
+		{
+			name:             "expiring certificate is rotated",
+			stored:           &store.NotificationCertificates{Current: expiring},
+			expectedPrevious: expiring,
+		},
+		{
+			name:    "certificate is created",
+			loadErr: store.ErrNotFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mscalendar, mockStore, _, _, _, _, _ := GetMockSetup(t)
+
+			mockStore.EXPECT().LoadNotificationCertificates().Return(tc.stored, tc.loadErr).Times(1)
+			var stored *store.NotificationCertificates
+			if tc.expectedID == "" {
+				mockStore.EXPECT().StoreNotificationCertificates(gomock.Any()).DoAndReturn(func(c *store.NotificationCertificates) error {
+					stored = c
+					return nil
+				}).Times(1)
+			}
+
+			certificate, err := mscalendar.currentNotificationCertificate(now)
+			require.NoError(t, err)
+			if tc.expectedID != "" {
+				require.Equal(t, tc.expectedID, certificate.ID)
+				return
+			}
+
+			require.Equal(t, certificate, stored.Current)
+			require.Equal(t, tc.expectedPrevious, stored.Previous)
+			require.Equal(t, now.Add(notificationCertificateValidity).Unix(), certificate.ExpiresAt)
+			parsed, err := x509.ParseCertificate(certificate.Certificate)
+			require.NoError(t, err)
+			require.Equal(t, now.Add(notificationCertificateValidity), parsed.NotAfter)
+			_, err = x509.ParsePKCS8PrivateKey(certificate.PrivateKey)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNotificationEncryptionRequiresEncryptionKey(t *testing.T) {
+	mscalendar, mockStore, _, _, _, _, _ := GetMockSetup(t)
+	mscalendar.Config.EnableRichNotifications = true
+	mscalendar.Config.EncryptionKey = ""
+	mockStore.EXPECT().LoadNotificationCertificates().Times(0)
+
+	require.Nil(t, mscalendar.notificationEncryption())
+}
@@ -16,13 +16,13 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 )
 
-func (processor *notificationProcessor) newSlackAttachment(n *remote.Notification) *model.SlackAttachment {
-	title := views.EnsureSubject(n.Event.Subject)
-	titleLink := n.Event.Weblink
-	text := n.Event.BodyPreview
+func (processor *notificationProcessor) newSlackAttachment(event *remote.Event) *model.SlackAttachment {
+	title := views.EnsureSubject(event.Subject)
+	titleLink := event.Weblink
+	text := event.BodyPreview
 	return &model.SlackAttachment{
-		AuthorName: n.Event.Organizer.EmailAddress.Name,
-		AuthorLink: "mailto:" + n.Event.Organizer.EmailAddress.Address,
+		AuthorName: event.Organizer.EmailAddress.Name,
+		AuthorLink: "mailto:" + event.Organizer.EmailAddress.Address,
 		TitleLink:  titleLink,
 		Title:      title,
 		Text:       text,
@@ -31,7 +31,7 @@ func (processor *notificationProcessor) newSlackAttachment(n *remote.Notificatio
 }
 
 func (processor *notificationProcessor) newEventSlackAttachment(n *remote.Notification, timezone string) *model.SlackAttachment {
-	sa := processor.newSlackAttachment(n)
+	sa := processor.newSlackAttachment(n.Event)
 	sa.Title = "(new) " + sa.Title
 
 	fields := eventToFields(n.Event, timezone)
@@ -46,13 +46,20 @@ func (processor *notificationProcessor) newEventSlackAttachment(n *remote.Notifi
 	}
 
 	if n.Event.ResponseRequested && !n.Event.IsOrganizer {
-		sa.Actions = NewPostActionForEventResponse(n.Event.ID, n.Event.ResponseStatus.Response, processor.actionURL(config.PathRespond))
+		sa.Actions = processor.newInvitationActions(n.Event)
 	}
 	return sa
 }
 
-func (processor *notificationProcessor) updatedEventSlackAttachment(n *remote.Notification, prior *remote.Event, timezone string) (bool, *model.SlackAttachment) {
-	sa := processor.newSlackAttachment(n)
+// updatedEventSlackAttachment returns the changes to the event, if any of them
+// is of a kind the user wants to be notified of.
+func (processor *notificationProcessor) updatedEventSlackAttachment(n *remote.Notification, prior *remote.Event, timezone string, changes []string) (bool, *model.SlackAttachment) {
+	notify := map[string]bool{}
+	for _, c := range changes {
+		notify[c] = true
+	}
+
+	sa := processor.newSlackAttachment(n.Event)
 	sa.Title = "(updated) " + sa.Title
 
 	newFields := eventToFields(n.Event, timezone)
@@ -62,67 +69,141 @@ func (processor *notificationProcessor) updatedEventSlackAttachment(n *remote.No
 		return false, nil
 	}
 
-	var allChanges []string
-	allChanges = append(allChanges, added...)
-	allChanges = append(allChanges, updated...)
-	allChanges = append(allChanges, deleted...)
+	changedFields := map[string]bool{}
+	for _, k := range append(append(added, updated...), deleted...) {
+		changedFields[k] = true
+	}
 
-	hasImportantChanges := false
-	for _, k := range allChanges {
-		if isImportantChange(k) {
-			hasImportantChanges = true
-			break
+	for _, k := range changeFieldOrder {
+		if !changedFields[k] || !notify[fieldChanges[k]] {
+			continue
+		}
+
+		var value string
+		switch {
+		case k == FieldAttendees:
+			value = attendeesDiff(prior, n.Event)
+		case newFields[k] == nil:
+			value = fmt.Sprintf("~~%s~~", views.MarkdownToHTMLEntities(strings.Join(priorFields[k].Strings(), ", ")))
+		case priorFields[k] == nil:
+			value = views.MarkdownToHTMLEntities(strings.Join(newFields[k].Strings(), ", "))
+		default:
+			value = fmt.Sprintf("~~%s~~ \u2192 %s", views.MarkdownToHTMLEntities(strings.Join(priorFields[k].Strings(), ", ")), views.MarkdownToHTMLEntities(strings.Join(newFields[k].Strings(), ", ")))
+		}
+		if value == "" {
+			continue
 		}
+
+		sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
+			Title: k,
+			Value: value,
+			Short: k != FieldAttendees && k != FieldBodyPreview,
+		})
 	}
 
-	if !hasImportantChanges {
+	if len(sa.Fields) == 0 {
 		return false, nil
 	}
 
-	for _, k := range added {
-		if !isImportantChange(k) {
-			continue
-		}
+	if n.Event.ResponseRequested && !n.Event.IsOrganizer && !n.Event.IsCancelled {
+		sa.Actions = processor.newInvitationActions(n.Event)
+	}
+	return true, sa
+}
+
+// cancelledEventSlackAttachment shows when the event was supposed to take
+// place, so the user knows which time was freed. Events deleted from the
+// calendar of an attendee without being cancelled are shown as removed.
+func (processor *notificationProcessor) cancelledEventSlackAttachment(event *remote.Event, deleted bool, timezone string) *model.SlackAttachment {
+	sa := processor.newSlackAttachment(event)
+	if deleted && !event.IsCancelled && !event.IsOrganizer {
+		sa.Title = "(removed) " + sa.Title
+	} else {
+		sa.Title = "(cancelled) " + sa.Title
+	}
+
+	fields := eventToFields(event, timezone)
+	for _, k := range []string{FieldWhen, FieldLocation} {
 		sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
 			Title: k,
-			Value: views.MarkdownToHTMLEntities(strings.Join(newFields[k].Strings(), ", ")),
+			Value: strings.Join(fields[k].Strings(), ", "),
 			Short: true,
 		})
 	}
-	for _, k := range updated {
-		if !isImportantChange(k) {
+	return sa
+}
+
+// attendeesDiff describes the attendees added to and removed from the event.
+// Changes to the responses of the attendees are ignored.
+func attendeesDiff(prior, event *remote.Event) string {
+	priorAttendees := map[string]*remote.Attendee{}
+	for _, a := range prior.Attendees {
+		if a.EmailAddress != nil {
+			priorAttendees[strings.ToLower(a.EmailAddress.Address)] = a
+		}
+	}
+
+	added := []string{}
+	for _, a := range event.Attendees {
+		if a.EmailAddress == nil {
 			continue
 		}
-		sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
-			Title: k,
-			Value: fmt.Sprintf("~~%s~~ \u2192 %s", views.MarkdownToHTMLEntities(strings.Join(priorFields[k].Strings(), ", ")), views.MarkdownToHTMLEntities(strings.Join(newFields[k].Strings(), ", "))),
-			Short: true,
-		})
+		key := strings.ToLower(a.EmailAddress.Address)
+		if _, ok := priorAttendees[key]; ok {
+			delete(priorAttendees, key)
+			continue
+		}
+		added = append(added, formatAttendee(a))
 	}
-	for _, k := range deleted {
-		if !isImportantChange(k) {
+
+	removed := []string{}
+	for _, a := range prior.Attendees {
+		if a.EmailAddress == nil {
 			continue
 		}
-		sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
-			Title: k,
-			Value: fmt.Sprintf("~~%s~~", views.MarkdownToHTMLEntities(strings.Join(priorFields[k].Strings(), ", "))),
-			Short: true,
-		})
+		if _, ok := priorAttendees[strings.ToLower(a.EmailAddress.Address)]; ok {
+			removed = append(removed, "~~"+formatAttendee(a)+"~~")
+		}
 	}
 
-	if n.Event.ResponseRequested && !n.Event.IsOrganizer && !n.Event.IsCancelled {
-		sa.Actions = NewPostActionForEventResponse(n.Event.ID, n.Event.ResponseStatus.Response, processor.actionURL(config.PathRespond))
+	lines := []string{}
+	if len(added) > 0 {
+		lines = append(lines, "Added: "+strings.Join(added, ", "))
 	}
-	return true, sa
+	if len(removed) > 0 {
+		lines = append(lines, "Removed: "+strings.Join(removed, ", "))
+	}
+	return strings.Join(lines, "\n")
 }
 
-func isImportantChange(fieldName string) bool {
-	for _, ic := range importantNotificationChanges {
-		if ic == fieldName {
-			return true
-		}
+func formatAttendee(a *remote.Attendee) string {
+	return fmt.Sprintf("[%s](mailto:%s)", a.EmailAddress.Name, a.EmailAddress.Address)
+}
+
+func (processor *notificationProcessor) addConflictsToSlackAttachment(sa *model.SlackAttachment, eventID string, conflicts []*remote.Event, timezone string) {
+	lines := []string{}
+	for _, c := range conflicts {
+		lines = append(lines, fmt.Sprintf("[%s](%s) (%s - %s)",
+			views.MarkdownToHTMLEntities(views.EnsureSubject(c.Subject)),
+			c.Weblink,
+			c.Start.In(timezone).Time().Format(time.Kitchen),
+			c.End.In(timezone).Time().Format(time.Kitchen)))
 	}
-	return false
+
+	sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
+		Title: FieldConflicts,
+		Value: ":warning: This event overlaps with " + strings.Join(lines, ", "),
+		Short: false,
+	})
+	sa.Actions = append(sa.Actions, NewPostActionForDeclineWithNote(eventID, processor.actionURL(config.PathDeclineWithNote)))
+}
+
+func (processor *notificationProcessor) newInvitationActions(event *remote.Event) []*model.PostAction {
+	actions := NewPostActionForEventResponse(event.ID, event.ResponseStatus.Response, processor.actionURL(config.PathRespond))
+	return append(actions,
+		NewPostActionForRespondWithComment(event.ID, processor.actionURL(config.PathRespondWithComment)),
+		NewPostActionForProposeNewTime(event.ID, processor.actionURL(config.PathProposeNewTime)),
+	)
 }
 
 func (processor *notificationProcessor) actionURL(action string) string {
@@ -159,6 +240,42 @@ func NewPostActionForEventResponse(eventID, response, url string) []*model.PostA
 	return []*model.PostAction{pa}
 }
 
+func NewPostActionForDeclineWithNote(eventID, url string) *model.PostAction {
+	return &model.PostAction{
+		Name: "Decline with note",
+		Integration: &model.PostActionIntegration{
+			URL: url,
+			Context: map[string]interface{}{
+				config.EventIDKey: eventID,
+			},
+		},
+	}
+}
+
+func NewPostActionForRespondWithComment(eventID, url string) *model.PostAction {
+	return &model.PostAction{
+		Name: "Respond with comment",
+		Integration: &model.PostActionIntegration{
+			URL: url,
+			Context: map[string]interface{}{
+				config.EventIDKey: eventID,
+			},
+		},
+	}
+}
+
+func NewPostActionForProposeNewTime(eventID, url string) *model.PostAction {
+	return &model.PostAction{
+		Name: "Propose new time",
+		Integration: &model.PostActionIntegration{
+			URL: url,
+			Context: map[string]interface{}{
+				config.EventIDKey: eventID,
+			},
+		},
+	}
+}
+
 func eventToFields(e *remote.Event, timezone string) fields.Fields {
 	date := func(dtStart, dtEnd *remote.DateTime) (time.Time, time.Time, string) {
 		if dtStart == nil || dtEnd == nil {
@@ -213,9 +330,7 @@ func eventToFields(e *remote.Event, timezone string) fields.Fields {
 
 	attendees := []fields.Value{}
 	for _, a := range e.Attendees {
-		attendees = append(attendees, fields.NewStringValue(
-			fmt.Sprintf("[%s](mailto:%s)",
-				a.EmailAddress.Name, a.EmailAddress.Address)))
+		attendees = append(attendees, fields.NewStringValue(formatAttendee(a)))
 	}
 
 	if len(attendees) == 0 {
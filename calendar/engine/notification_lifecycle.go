@@ -0,0 +1,156 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+// missedNotificationsResyncWindow is how far ahead the events are synced
+// again when notifications were missed.
+const missedNotificationsResyncWindow = 14 * 24 * time.Hour
+
+// processLifecycleNotification keeps the subscription of the creator working:
+// it is renewed when the remote asks for it, created again when the remote
+// removed it, and the upcoming events are synced again when notifications
+// were missed.
+func (processor *notificationProcessor) processLifecycleNotification(client remote.Client, creator *store.User, sub *store.Subscription, n *remote.Notification) error {
+	logger := processor.Logger.With(bot.LogContext{
+		"MattermostUserID": creator.MattermostUserID,
+		"SubscriptionID":   n.SubscriptionID,
+		"LifecycleEvent":   n.LifecycleEvent,
+	})
+
+	switch n.LifecycleEvent {
+	case remote.LifecycleReauthorizationRequired:
+		return processor.renewSubscription(client, creator, sub)
+
+	case remote.LifecycleMissed:
+		processor.invalidateRemoteCache(creator)
+		if processor.Config != nil && processor.Config.EnableCalendarCache {
+			err := processor.Store.DeleteCalendarCache(creator.Remote.ID)
+			if err != nil && err != store.ErrNotFound {
+				return err
+			}
+		}
+		queued, err := processor.resyncEvents(client, creator, sub, n, time.Now())
+		if err != nil {
+			return err
+		}
+		logger.With(bot.LogContext{"Queued": queued}).Infof("webhook notification: notifications were missed, upcoming events queued to be synced again.")
+		return nil
+
+	case remote.LifecycleSubscriptionRemoved:
+		err := processor.Store.DeleteUserSubscription(creator, sub.Remote.ID)
+		if err != nil {
+			return err
+		}
+		m := &mscalendar{
+			Env: processor.Env,
+			actingUser: &User{
+				User:             creator,
+				MattermostUserID: creator.MattermostUserID,
+			},
+			client: client,
+		}
+		_, err = m.CreateMyEventSubscription()
+		if err != nil {
+			return err
+		}
+		logger.Debugf("webhook notification: created the removed user subscription again.")
+		return nil
+	}
+
+	logger.Debugf("webhook notification: unknown lifecycle event.")
+	return nil
+}
+
+// resyncEvents queues the upcoming events of the creator as updated, so that
+// the changes missed are notified like any other: each event is compared with
+// the one stored when it was last notified. The events created before the
+// subscription and never notified are left out.
+func (processor *notificationProcessor) resyncEvents(client remote.Client, creator *store.User, sub *store.Subscription, n *remote.Notification, now time.Time) (int, error) {
+	// The cached events may be missing the changes
+	if cached, ok := client.(*cachedClient); ok {
+		client = cached.Client
+	}
+	events, err := client.GetDefaultCalendarView(creator.Remote.ID, now, now.Add(missedNotificationsResyncWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	notifications := []*remote.Notification{}
+	for _, e := range events {
+		if sub.CreatedAt != 0 && e.ICalUID != "" {
+			created, parseErr := time.Parse(time.RFC3339Nano, e.CreatedDateTime)
+			if parseErr == nil && created.Unix() < sub.CreatedAt {
+				_, err = processor.Store.LoadUserEvent(creator.MattermostUserID, e.ICalUID)
+				if errors.Is(err, store.ErrNotFound) {
+					continue
+				}
+				if err != nil {
+					return 0, err
+				}
+			}
+		}
+
+		notifications = append(notifications, &remote.Notification{
+			SubscriptionID: n.SubscriptionID,
+			ClientState:    n.ClientState,
+			ChangeType:     remote.ChangeTypeUpdated,
+			Event:          e,
+		})
+	}
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+
+	err = processor.Store.PushNotifications(notifications, now)
+	if err != nil {
+		return 0, err
+	}
+	return len(notifications), nil
+}
+
+func (processor *notificationProcessor) renewSubscription(client remote.Client, creator *store.User, sub *store.Subscription) error {
+	renewed, err := client.RenewSubscription(processor.Config.GetNotificationURL(), sub.Remote.CreatorID, sub.Remote)
+	if err != nil {
+		return err
+	}
+
+	storedSub := &store.Subscription{
+		Remote:              renewed,
+		MattermostCreatorID: creator.MattermostUserID,
+		PluginVersion:       processor.Config.PluginVersion,
+		CreatedAt:           sub.CreatedAt,
+	}
+	err = processor.Store.StoreUserSubscription(creator, storedSub)
+	if err != nil {
+		return err
+	}
+	processor.Logger.With(bot.LogContext{
+		"MattermostUserID": creator.MattermostUserID,
+		"SubscriptionID":   sub.Remote.ID,
+	}).Debugf("webhook notification: renewed user subscription.")
+	return nil
+}
+
+func (processor *notificationProcessor) invalidateRemoteCache(creator *store.User) {
+	if processor.Config == nil || !processor.Config.EnableCalendarCache {
+		return
+	}
+	err := processor.Store.InvalidateRemoteCache(creator.Remote.ID, store.RemoteCacheEvents)
+	if err != nil {
+		processor.Logger.With(bot.LogContext{
+			"MattermostUserID": creator.MattermostUserID,
+			"err":              err.Error(),
+		}).Warnf("webhook notification: failed to invalidate the remote cache.")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
+)
+
+func TestProcessLifecycleNotification(t *testing.T) {
+	const mattermostUserID = "creator_mm_id"
+	const notificationURL = "https://mattermost.example.com/plugins/mscalendar" + config.FullPathEventNotification
+
+	for _, tc := range []struct {
+		name           string
+		lifecycleEvent string
+		cacheEnabled   bool
+		clientState    string
+		setupMock      func(*mock_store.MockStore, *mock_remote.MockClient, *store.User, *store.Subscription)
+		expectedErr    string
+	}{
+		{
+			name:           "reauthorization required",
+			lifecycleEvent: remote.LifecycleReauthorizationRequired,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, user *store.User, sub *store.Subscription) {
+				renewed := &remote.Subscription{ID: sub.Remote.ID, ExpirationDateTime: "2020-02-14T10:00:00Z"}
+				c.EXPECT().RenewSubscription(notificationURL, sub.Remote.CreatorID, sub.Remote).Return(renewed, nil).Times(1)
+				s.EXPECT().StoreUserSubscription(user, &store.Subscription{
+					Remote:              renewed,
+					MattermostCreatorID: mattermostUserID,
+					PluginVersion:       "x.x.x",
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:           "missed notifications",
+			lifecycleEvent: remote.LifecycleMissed,
+			cacheEnabled:   true,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, user *store.User, sub *store.Subscription) {
+				s.EXPECT().InvalidateRemoteCache(user.Remote.ID, store.RemoteCacheEvents).Return(nil).Times(1)
+				s.EXPECT().DeleteCalendarCache(user.Remote.ID).Return(nil).Times(1)
+				c.EXPECT().GetDefaultCalendarView(user.Remote.ID, gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+			},
+		},
+		{
+			name:           "missed notifications without cache",
+			lifecycleEvent: remote.LifecycleMissed,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, user *store.User, sub *store.Subscription) {
+				sub.CreatedAt = time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC).Unix()
+				c.EXPECT().GetDefaultCalendarView(user.Remote.ID, gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, start, end time.Time) ([]*remote.Event, error) {
+					require.Equal(t, missedNotificationsResyncWindow, end.Sub(start))
+					return []*remote.Event{
+						{ID: "created_after", ICalUID: "created_after", CreatedDateTime: "2020-02-12T11:00:00.0000000Z"},
+						{ID: "notified_before", ICalUID: "notified_before", CreatedDateTime: "2020-02-11T10:00:00.0000000Z"},
+						{ID: "never_notified", ICalUID: "never_notified", CreatedDateTime: "2020-02-11T10:00:00.0000000Z"},
+					}, nil
+				}).Times(1)
+				s.EXPECT().LoadUserEvent(user.MattermostUserID, "notified_before").Return(&store.Event{}, nil).Times(1)
+				s.EXPECT().LoadUserEvent(user.MattermostUserID, "never_notified").Return(nil, store.ErrNotFound).Times(1)
+				s.EXPECT().PushNotifications(gomock.Any(), gomock.Any()).DoAndReturn(func(notifications []*remote.Notification, _ time.Time) error {
+					require.Len(t, notifications, 2)
+					for i, id := range []string{"created_after", "notified_before"} {
+						require.Equal(t, id, notifications[i].Event.ID)
+						require.Equal(t, remote.ChangeTypeUpdated, notifications[i].ChangeType)
+						require.Equal(t, sub.Remote.ID, notifications[i].SubscriptionID)
+						require.Equal(t, sub.Remote.ClientState, notifications[i].ClientState)
+						require.False(t, notifications[i].IsBare)
+					}
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:           "subscription removed",
+			lifecycleEvent: remote.LifecycleSubscriptionRemoved,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, user *store.User, sub *store.Subscription) {
+				created := &remote.Subscription{ID: "new_subscription_id"}
+				s.EXPECT().DeleteUserSubscription(user, sub.Remote.ID).Return(nil).Times(1)
+				c.EXPECT().CreateMySubscription(notificationURL, user.Remote.ID, nil).Return(created, nil).Times(1)
+				s.EXPECT().StoreUserSubscription(user, gomock.Any()).DoAndReturn(func(_ *store.User, stored *store.Subscription) error {
+					require.Equal(t, created, stored.Remote)
+					require.Equal(t, mattermostUserID, stored.MattermostCreatorID)
+					require.NotZero(t, stored.CreatedAt)
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:           "unauthorized lifecycle notification",
+			lifecycleEvent: remote.LifecycleSubscriptionRemoved,
+			clientState:    "wrong_client_state",
+			setupMock:      func(*mock_store.MockStore, *mock_remote.MockClient, *store.User, *store.Subscription) {},
+			expectedErr:    "unauthorized webhook",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockPoster := mock_bot.NewMockPoster(ctrl)
+			mockRemote := mock_remote.NewMockRemote(ctrl)
+			mockClient := mock_remote.NewMockClient(ctrl)
+
+			processor := &notificationProcessor{
+				Env: Env{
+					Config: &config.Config{
+						PluginVersion: "x.x.x",
+						PluginURL:     "https://mattermost.example.com/plugins/mscalendar",
+						StoredConfig:  config.StoredConfig{EnableCalendarCache: tc.cacheEnabled},
+					},
+					Dependencies: &Dependencies{
+						Store:  mockStore,
+						Logger: &bot.NilLogger{},
+						Poster: mockPoster,
+						Remote: mockRemote,
+					},
+				},
+			}
+
+			subscription := newTestSubscription()
+			user := newTestUser()
+			user.MattermostUserID = mattermostUserID
+			user.Settings.EventSubscriptionID = subscription.Remote.ID
+
+			mockStore.EXPECT().LoadSubscription(subscription.Remote.ID).Return(subscription, nil)
+			mockStore.EXPECT().LoadUser(mattermostUserID).Return(user, nil)
+			clientState := subscription.Remote.ClientState
+			if tc.clientState != "" {
+				clientState = tc.clientState
+			} else {
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), user.OAuth2Token, mattermostUserID, mockPoster, mockStore).Return(mockClient)
+			}
+			tc.setupMock(mockStore, mockClient, user, subscription)
+
+			err := processor.processNotification(&remote.Notification{
+				SubscriptionID: subscription.Remote.ID,
+				LifecycleEvent: tc.lifecycleEvent,
+				ClientState:    clientState,
+			})
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
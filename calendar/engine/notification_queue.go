@@ -0,0 +1,76 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+type NotificationQueue interface {
+	GetNotificationQueueStatus() (*NotificationQueueStatus, error)
+	RetryDeadLetterNotifications() (int, error)
+	ClearDeadLetterNotifications() (int, error)
+}
+
+// NotificationQueueStatus shows the admins the webhook notifications waiting
+// to be processed and those that failed.
+type NotificationQueueStatus struct {
+	Pending     []*store.QueuedNotification
+	DeadLetters []*store.QueuedNotification
+}
+
+func (m *mscalendar) GetNotificationQueueStatus() (*NotificationQueueStatus, error) {
+	pending, err := m.Store.LoadNotificationQueue()
+	if err != nil {
+		return nil, err
+	}
+	deadLetters, err := m.Store.LoadDeadLetterNotifications()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotificationQueueStatus{
+		Pending:     pending,
+		DeadLetters: deadLetters,
+	}, nil
+}
+
+// RetryDeadLetterNotifications moves the failed notifications back to the
+// queue, to be processed again from their first attempt. They are removed
+// from the dead-letter list only once queued, so none is lost if the queue is
+// full.
+func (m *mscalendar) RetryDeadLetterNotifications() (int, error) {
+	deadLetters, err := m.Store.LoadDeadLetterNotifications()
+	if err != nil {
+		return 0, err
+	}
+
+	ids := []string{}
+	notifications := []*remote.Notification{}
+	for _, q := range deadLetters {
+		ids = append(ids, q.ID)
+		notifications = append(notifications, q.Notification)
+	}
+
+	err = m.Store.PushNotifications(notifications, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	err = m.Store.DeleteDeadLetterNotifications(ids)
+	if err != nil {
+		return 0, err
+	}
+	return len(notifications), nil
+}
+
+func (m *mscalendar) ClearDeadLetterNotifications() (int, error) {
+	deadLetters, err := m.Store.TakeDeadLetterNotifications()
+	if err != nil {
+		return 0, err
+	}
+	return len(deadLetters), nil
+}
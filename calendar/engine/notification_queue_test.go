@@ -0,0 +1,45 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestRetryDeadLetterNotifications(t *testing.T) {
+	deadLetters := []*store.QueuedNotification{
+		{ID: "first", Notification: &remote.Notification{SubscriptionID: "sub1"}},
+		{ID: "second", Notification: &remote.Notification{SubscriptionID: "sub2"}},
+	}
+	notifications := []*remote.Notification{deadLetters[0].Notification, deadLetters[1].Notification}
+
+	t.Run("queued again", func(t *testing.T) {
+		mscalendar, mockStore, _, _, _, _, _ := GetMockSetup(t)
+		gomock.InOrder(
+			mockStore.EXPECT().LoadDeadLetterNotifications().Return(deadLetters, nil).Times(1),
+			mockStore.EXPECT().PushNotifications(notifications, gomock.Any()).Return(nil).Times(1),
+			mockStore.EXPECT().DeleteDeadLetterNotifications([]string{"first", "second"}).Return(nil).Times(1),
+		)
+
+		n, err := mscalendar.RetryDeadLetterNotifications()
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+	})
+
+	t.Run("kept when the queue is full", func(t *testing.T) {
+		mscalendar, mockStore, _, _, _, _, _ := GetMockSetup(t)
+		mockStore.EXPECT().LoadDeadLetterNotifications().Return(deadLetters, nil).Times(1)
+		mockStore.EXPECT().PushNotifications(notifications, gomock.Any()).Return(store.ErrNotificationQueueFull).Times(1)
+		mockStore.EXPECT().DeleteDeadLetterNotifications(gomock.Any()).Times(0)
+
+		_, err := mscalendar.RetryDeadLetterNotifications()
+		require.Equal(t, store.ErrNotificationQueueFull, err)
+	})
+}
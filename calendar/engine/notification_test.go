@@ -7,10 +7,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
@@ -199,20 +202,592 @@ func TestProcessNotification(t *testing.T) {
 	}
 }
 
-func TestProcessNotificationOverflow(t *testing.T) {
-	t.Run("overflow", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
+func TestEnqueueNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-		processor := &notificationProcessor{
-			queue: make(chan (*remote.Notification), maxQueueSize),
-		}
+	mockStore := mock_store.NewMockStore(ctrl)
+	processor := &notificationProcessor{
+		Env:  Env{Dependencies: &Dependencies{Store: mockStore}},
+		wake: make(chan struct{}, 1),
+	}
+	notifications := []*remote.Notification{{SubscriptionID: "remote_subscription_id", ClientState: "stored_client_state"}}
+	mockStore.EXPECT().LoadSubscription("remote_subscription_id").Return(newTestSubscription(), nil).AnyTimes()
 
-		for i := 0; i < maxQueueSize; i++ {
-			err := processor.Enqueue(&remote.Notification{})
-			require.NoError(t, err)
-		}
-		err := processor.Enqueue(&remote.Notification{})
-		require.Error(t, err)
+	t.Run("queued", func(t *testing.T) {
+		mockStore.EXPECT().PushNotifications(notifications, gomock.Any()).Return(nil).Times(2)
+
+		require.NoError(t, processor.Enqueue(notifications...))
+		// The worker is woken up once for all the pending notifications
+		require.NoError(t, processor.Enqueue(notifications...))
+		require.Len(t, processor.wake, 1)
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		mockStore.EXPECT().PushNotifications(notifications, gomock.Any()).Return(store.ErrNotificationQueueFull).Times(1)
+
+		err := processor.Enqueue(notifications...)
+		require.ErrorIs(t, err, store.ErrNotificationQueueFull)
+	})
+}
+
+func TestEnqueueUnauthorizedNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_store.NewMockStore(ctrl)
+	processor := &notificationProcessor{
+		Env:  Env{Dependencies: &Dependencies{Store: mockStore, Logger: &bot.NilLogger{}}},
+		wake: make(chan struct{}, 1),
+	}
+	valid := &remote.Notification{SubscriptionID: "remote_subscription_id", ClientState: "stored_client_state"}
+	forged := &remote.Notification{SubscriptionID: "remote_subscription_id", ClientState: "forged_client_state"}
+	unknown := &remote.Notification{SubscriptionID: "unknown_subscription_id", ClientState: "stored_client_state"}
+	unchecked := &remote.Notification{SubscriptionID: "unavailable_subscription_id"}
+
+	mockStore.EXPECT().LoadSubscription("remote_subscription_id").Return(newTestSubscription(), nil).Times(1)
+	mockStore.EXPECT().LoadSubscription("unknown_subscription_id").Return(nil, store.ErrNotFound).Times(1)
+	mockStore.EXPECT().LoadSubscription("unavailable_subscription_id").Return(nil, errors.New("KV store unavailable")).Times(1)
+	mockStore.EXPECT().PushNotifications([]*remote.Notification{valid, unchecked}, gomock.Any()).Return(nil).Times(1)
+
+	require.NoError(t, processor.Enqueue(valid, forged, unknown, unchecked))
+}
+
+func TestEnqueueRepeatedDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_store.NewMockStore(ctrl)
+	processor := &notificationProcessor{
+		Env:  Env{Dependencies: &Dependencies{Store: mockStore, Logger: &bot.NilLogger{}}},
+		wake: make(chan struct{}, 1),
+	}
+	first := &remote.Notification{SubscriptionID: "remote_subscription_id", ClientState: "stored_client_state", DeliveryID: "first_delivery"}
+	repeated := &remote.Notification{SubscriptionID: "remote_subscription_id", ClientState: "stored_client_state", DeliveryID: "repeated_delivery"}
+	mockStore.EXPECT().LoadSubscription("remote_subscription_id").Return(newTestSubscription(), nil).AnyTimes()
+
+	t.Run("repeated delivery is dropped", func(t *testing.T) {
+		mockStore.EXPECT().MarkNotificationDelivered("first_delivery", notificationDeliveryTTL).Return(true, nil).Times(1)
+		mockStore.EXPECT().MarkNotificationDelivered("repeated_delivery", notificationDeliveryTTL).Return(false, nil).Times(1)
+		mockStore.EXPECT().PushNotifications([]*remote.Notification{first}, gomock.Any()).Return(nil).Times(1)
+
+		require.NoError(t, processor.Enqueue(first, repeated))
+	})
+
+	t.Run("delivery is kept if it cannot be checked", func(t *testing.T) {
+		mockStore.EXPECT().MarkNotificationDelivered("first_delivery", notificationDeliveryTTL).Return(false, errors.New("KV store unavailable")).Times(1)
+		mockStore.EXPECT().PushNotifications([]*remote.Notification{first}, gomock.Any()).Return(nil).Times(1)
+
+		require.NoError(t, processor.Enqueue(first))
 	})
+
+	t.Run("delivery is unmarked if it cannot be queued", func(t *testing.T) {
+		mockStore.EXPECT().MarkNotificationDelivered("first_delivery", notificationDeliveryTTL).Return(true, nil).Times(1)
+		mockStore.EXPECT().PushNotifications([]*remote.Notification{first}, gomock.Any()).Return(store.ErrNotificationQueueFull).Times(1)
+		mockStore.EXPECT().UnmarkNotificationDelivered("first_delivery").Return(nil).Times(1)
+
+		err := processor.Enqueue(first)
+		require.ErrorIs(t, err, store.ErrNotificationQueueFull)
+	})
+}
+
+func TestProcessQueuedNotification(t *testing.T) {
+	transientErr := &remote.TransientError{Err: errors.New("service unavailable")}
+
+	for _, tc := range []struct {
+		name          string
+		attempts      int
+		err           error
+		expectRetry   bool
+		expectFailure bool
+		expectDrop    bool
+	}{
+		{
+			name:     "processed",
+			attempts: 1,
+		},
+		{
+			name:        "transient error",
+			attempts:    1,
+			err:         transientErr,
+			expectRetry: true,
+		},
+		{
+			name:          "transient error after the last attempt",
+			attempts:      maxNotificationAttempts,
+			err:           transientErr,
+			expectFailure: true,
+		},
+		{
+			name:          "permanent error",
+			attempts:      1,
+			err:           errors.New("subscription not found"),
+			expectFailure: true,
+		},
+		{
+			name:       "unknown subscription",
+			attempts:   1,
+			err:        store.ErrNotFound,
+			expectDrop: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockRemote := mock_remote.NewMockRemote(ctrl)
+			processor := &notificationProcessor{
+				Env: Env{Dependencies: &Dependencies{Store: mockStore, Remote: mockRemote, Logger: &bot.NilLogger{}}},
+			}
+
+			subscription := newTestSubscription()
+			q := &store.QueuedNotification{
+				ID: "queued_id",
+				Notification: &remote.Notification{
+					SubscriptionID: subscription.Remote.ID,
+					ClientState:    subscription.Remote.ClientState,
+					ChangeType:     remote.ChangeTypeDeleted,
+					Event:          &remote.Event{ID: "remote_event_id_1"},
+				},
+				Attempts: tc.attempts,
+			}
+
+			if tc.err != nil {
+				mockStore.EXPECT().LoadSubscription(subscription.Remote.ID).Return(nil, tc.err).Times(1)
+			} else {
+				// A deleted event that was never stored has nothing to notify
+				user := newTestUser()
+				user.Settings.EventSubscriptionID = subscription.Remote.ID
+				mockStore.EXPECT().LoadSubscription(subscription.Remote.ID).Return(subscription, nil).Times(1)
+				mockStore.EXPECT().LoadUser(subscription.MattermostCreatorID).Return(user, nil).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), user.OAuth2Token, subscription.MattermostCreatorID, gomock.Any(), mockStore).Return(mock_remote.NewMockClient(ctrl)).Times(1)
+				mockStore.EXPECT().LoadUserEventByRemoteID(user.MattermostUserID, "remote_event_id_1").Return(nil, store.ErrNotFound).Times(1)
+			}
+
+			switch {
+			case tc.expectRetry:
+				before := time.Now()
+				mockStore.EXPECT().RetryNotification("queued_id", gomock.Any(), tc.err.Error()).DoAndReturn(
+					func(_ string, nextAttempt time.Time, _ string) error {
+						require.False(t, nextAttempt.Before(before.Add(notificationRetryDelay)))
+						return nil
+					}).Times(1)
+			case tc.expectFailure:
+				mockStore.EXPECT().DeadLetterNotification("queued_id", tc.err.Error()).Return(nil).Times(1)
+			case tc.expectDrop:
+				mockStore.EXPECT().CompleteNotification("queued_id").Return(nil).Times(1)
+				mockStore.EXPECT().DeadLetterNotification(gomock.Any(), gomock.Any()).Times(0)
+			default:
+				mockStore.EXPECT().CompleteNotification("queued_id").Return(nil).Times(1)
+			}
+
+			processor.processQueuedNotification(q)
+		})
+	}
+}
+
+func TestProcessReplacedSubscriptionNotification(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		previousID     string
+		replacedAgo    time.Duration
+		lifecycleEvent string
+		expectedError  string
+	}{
+		{
+			name:        "accepted during the grace period",
+			previousID:  "remote_subscription_id",
+			replacedAgo: time.Hour,
+		},
+		{
+			name:          "orphaned after the grace period",
+			previousID:    "remote_subscription_id",
+			replacedAgo:   replacedSubscriptionGracePeriod + time.Hour,
+			expectedError: "subscription is orphaned",
+		},
+		{
+			name:          "orphaned if not the previous subscription",
+			previousID:    "other_subscription_id",
+			replacedAgo:   time.Hour,
+			expectedError: "subscription is orphaned",
+		},
+		{
+			name:           "lifecycle notifications ignored",
+			previousID:     "remote_subscription_id",
+			replacedAgo:    time.Hour,
+			lifecycleEvent: remote.LifecycleSubscriptionRemoved,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockRemote := mock_remote.NewMockRemote(ctrl)
+			processor := &notificationProcessor{
+				Env: Env{Dependencies: &Dependencies{Store: mockStore, Remote: mockRemote, Logger: &bot.NilLogger{}}},
+			}
+
+			subscription := newTestSubscription()
+			subscription.ReplacedAt = time.Now().Add(-tc.replacedAgo).Unix()
+			user := newTestUser()
+			user.Settings.PreviousEventSubscriptionID = tc.previousID
+			mockStore.EXPECT().LoadSubscription(subscription.Remote.ID).Return(subscription, nil).Times(1)
+			mockStore.EXPECT().LoadUser(subscription.MattermostCreatorID).Return(user, nil).Times(1)
+			if tc.expectedError == "" {
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), user.OAuth2Token, subscription.MattermostCreatorID, gomock.Any(), mockStore).Return(mock_remote.NewMockClient(ctrl)).Times(1)
+			}
+			if tc.expectedError == "" && tc.lifecycleEvent == "" {
+				mockStore.EXPECT().LoadUserEventByRemoteID(user.MattermostUserID, "remote_event_id_1").Return(nil, store.ErrNotFound).Times(1)
+			}
+
+			err := processor.processNotification(&remote.Notification{
+				SubscriptionID: subscription.Remote.ID,
+				ClientState:    subscription.Remote.ClientState,
+				ChangeType:     remote.ChangeTypeDeleted,
+				LifecycleEvent: tc.lifecycleEvent,
+				Event:          &remote.Event{ID: "remote_event_id_1"},
+			})
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpdatedEventSlackAttachment(t *testing.T) {
+	processor := &notificationProcessor{
+		Env: Env{Config: &config.Config{PluginURLPath: "/plugins/mscalendar"}},
+	}
+
+	attendee := func(name string) *remote.Attendee {
+		return &remote.Attendee{EmailAddress: &remote.EmailAddress{Name: name, Address: name + "@example.com"}}
+	}
+	allChanges := store.EventChangeOptions
+
+	for _, tc := range []struct {
+		name           string
+		update         func(e *remote.Event)
+		changes        []string
+		expectedNotify bool
+		expectedTitle  string
+		expectedFields map[string]string
+	}{
+		{
+			name:    "no changes",
+			update:  func(_ *remote.Event) {},
+			changes: allChanges,
+		},
+		{
+			name:           "subject changed",
+			update:         func(e *remote.Event) { e.Subject = "Retro" },
+			changes:        allChanges,
+			expectedNotify: true,
+			expectedTitle:  "(updated) Retro",
+			expectedFields: map[string]string{FieldSubject: "~~Planning~~ → Retro"},
+		},
+		{
+			name:    "location change filtered",
+			update:  func(e *remote.Event) { e.Location.DisplayName = "Room 2" },
+			changes: []string{store.EventChangeSubject, store.EventChangeTime},
+		},
+		{
+			name: "only the selected changes are shown",
+			update: func(e *remote.Event) {
+				e.Location.DisplayName = "Room 2"
+				e.Importance = "high"
+			},
+			changes:        []string{store.EventChangeLocation},
+			expectedNotify: true,
+			expectedTitle:  "(updated) Planning",
+			expectedFields: map[string]string{FieldLocation: "~~Room 1~~ → Room 2"},
+		},
+		{
+			name: "attendees added and removed",
+			update: func(e *remote.Event) {
+				e.Attendees = []*remote.Attendee{attendee("bob"), attendee("carol")}
+			},
+			changes:        allChanges,
+			expectedNotify: true,
+			expectedTitle:  "(updated) Planning",
+			expectedFields: map[string]string{FieldAttendees: "Added: [carol](mailto:carol@example.com)\nRemoved: ~~[alice](mailto:alice@example.com)~~"},
+		},
+		{
+			name: "attendee response changed",
+			update: func(e *remote.Event) {
+				e.Attendees = []*remote.Attendee{attendee("alice"), attendee("bob")}
+				e.Attendees[0].Status = &remote.EventResponseStatus{Response: ResponseYes}
+			},
+			changes: allChanges,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			prior := newTestEvent("1", "Room 1", "Planning")
+			prior.Attendees = []*remote.Attendee{attendee("alice"), attendee("bob")}
+			event := newTestEvent("1", "Room 1", "Planning")
+			event.Attendees = []*remote.Attendee{attendee("alice"), attendee("bob")}
+			tc.update(event)
+
+			notify, sa := processor.updatedEventSlackAttachment(&remote.Notification{Event: event}, prior, "UTC", tc.changes)
+			require.Equal(t, tc.expectedNotify, notify)
+			if !tc.expectedNotify {
+				return
+			}
+
+			require.Equal(t, tc.expectedTitle, sa.Title)
+			saFields := map[string]string{}
+			for _, f := range sa.Fields {
+				saFields[f.Title] = f.Value.(string)
+			}
+			require.Equal(t, tc.expectedFields, saFields)
+		})
+	}
+}
+
+func TestCancelledEventSlackAttachment(t *testing.T) {
+	processor := &notificationProcessor{
+		Env: Env{Config: &config.Config{PluginURLPath: "/plugins/mscalendar"}},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		update        func(e *remote.Event)
+		deleted       bool
+		expectedTitle string
+	}{
+		{
+			name:          "cancelled",
+			update:        func(e *remote.Event) { e.IsCancelled = true },
+			expectedTitle: "(cancelled) Planning",
+		},
+		{
+			name:          "deleted after being cancelled",
+			update:        func(e *remote.Event) { e.IsCancelled = true },
+			deleted:       true,
+			expectedTitle: "(cancelled) Planning",
+		},
+		{
+			name:          "deleted by the organizer",
+			update:        func(e *remote.Event) { e.IsOrganizer = true },
+			deleted:       true,
+			expectedTitle: "(cancelled) Planning",
+		},
+		{
+			name:          "removed from the calendar of an attendee",
+			update:        func(_ *remote.Event) {},
+			deleted:       true,
+			expectedTitle: "(removed) Planning",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			event := newTestEvent("1", "Room 1", "Planning")
+			event.Start = remote.NewDateTime(time.Date(2030, 3, 4, 10, 0, 0, 0, time.UTC), "UTC")
+			event.End = remote.NewDateTime(time.Date(2030, 3, 4, 11, 0, 0, 0, time.UTC), "UTC")
+			tc.update(event)
+
+			sa := processor.cancelledEventSlackAttachment(event, tc.deleted, "UTC")
+			require.Equal(t, tc.expectedTitle, sa.Title)
+			require.Empty(t, sa.Actions)
+
+			saFields := map[string]string{}
+			for _, f := range sa.Fields {
+				saFields[f.Title] = f.Value.(string)
+			}
+			require.Equal(t, map[string]string{
+				FieldWhen:     "Monday, March 04, 2030 · (10:00AM - 11:00AM)",
+				FieldLocation: "Room 1",
+			}, saFields)
+		})
+	}
+}
+
+func TestProcessNotificationChangeTypes(t *testing.T) {
+	const mattermostUserID = "creator_mm_id"
+
+	newEvent := func(update func(e *remote.Event)) *remote.Event {
+		e := newTestEvent("1", "Room 1", "Planning")
+		e.ResponseRequested = false
+		update(e)
+		return e
+	}
+	noUpdate := func(_ *remote.Event) {}
+	cancel := func(e *remote.Event) { e.IsCancelled = true }
+
+	for _, tc := range []struct {
+		name          string
+		changeType    string
+		event         *remote.Event
+		prior         *remote.Event
+		changes       []string
+		linked        bool
+		expectedTitle string
+		expectStore   bool
+		expectDelete  bool
+		expectMetaDel bool
+	}{
+		{
+			name:          "created",
+			changeType:    remote.ChangeTypeCreated,
+			event:         newEvent(noUpdate),
+			expectedTitle: "(new) Planning",
+			expectStore:   true,
+		},
+		{
+			name:          "updated",
+			changeType:    remote.ChangeTypeUpdated,
+			event:         newEvent(func(e *remote.Event) { e.Subject = "Retro" }),
+			prior:         newEvent(noUpdate),
+			expectedTitle: "(updated) Retro",
+			expectStore:   true,
+		},
+		{
+			name:          "cancelled",
+			changeType:    remote.ChangeTypeUpdated,
+			event:         newEvent(cancel),
+			prior:         newEvent(noUpdate),
+			linked:        true,
+			expectedTitle: "(cancelled) Planning",
+			expectStore:   true,
+			expectMetaDel: true,
+		},
+		{
+			name:          "cancellation filtered",
+			changeType:    remote.ChangeTypeUpdated,
+			event:         newEvent(cancel),
+			prior:         newEvent(noUpdate),
+			changes:       []string{store.EventChangeSubject},
+			expectStore:   true,
+			expectMetaDel: true,
+		},
+		{
+			name:          "deleted",
+			changeType:    remote.ChangeTypeDeleted,
+			event:         &remote.Event{ID: "remote_event_id_1"},
+			prior:         newEvent(noUpdate),
+			expectedTitle: "(removed) Planning",
+			expectDelete:  true,
+		},
+		{
+			name:          "deleted by the organizer",
+			changeType:    remote.ChangeTypeDeleted,
+			event:         &remote.Event{ID: "remote_event_id_1"},
+			prior:         newEvent(func(e *remote.Event) { e.IsOrganizer = true }),
+			linked:        true,
+			expectedTitle: "(cancelled) Planning",
+			expectDelete:  true,
+			expectMetaDel: true,
+		},
+		{
+			name:          "deleted after being cancelled",
+			changeType:    remote.ChangeTypeDeleted,
+			event:         &remote.Event{ID: "remote_event_id_1"},
+			prior:         newEvent(cancel),
+			expectDelete:  true,
+			expectMetaDel: true,
+		},
+		{
+			name:       "deleted event not found",
+			changeType: remote.ChangeTypeDeleted,
+			event:      &remote.Event{ID: "remote_event_id_1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockPoster := mock_bot.NewMockPoster(ctrl)
+			mockRemote := mock_remote.NewMockRemote(ctrl)
+			mockClient := mock_remote.NewMockClient(ctrl)
+
+			processor := &notificationProcessor{
+				Env: Env{
+					Config: &config.Config{PluginVersion: "x.x.x"},
+					Dependencies: &Dependencies{
+						Store:  mockStore,
+						Logger: &bot.NilLogger{},
+						Poster: mockPoster,
+						Remote: mockRemote,
+					},
+				},
+			}
+
+			subscription := newTestSubscription()
+			user := newTestUser()
+			user.MattermostUserID = mattermostUserID
+			user.Settings.EventSubscriptionID = subscription.Remote.ID
+			if tc.changes != nil {
+				user.Settings.EventNotifications = &store.EventNotificationUserSettings{Changes: tc.changes}
+			}
+			if tc.linked {
+				user.ChannelEvents = store.ChannelEventLink{"remote_event_uid_1": "channel_id"}
+			}
+
+			mockStore.EXPECT().LoadSubscription(subscription.Remote.ID).Return(subscription, nil)
+			mockStore.EXPECT().LoadUser(mattermostUserID).Return(user, nil)
+			mockRemote.EXPECT().MakeUserClient(gomock.Any(), user.OAuth2Token, mattermostUserID, mockPoster, mockStore).Return(mockClient)
+
+			var prior *store.Event
+			if tc.prior != nil {
+				prior = &store.Event{Remote: tc.prior}
+			}
+			if tc.changeType == remote.ChangeTypeDeleted {
+				if prior == nil {
+					mockStore.EXPECT().LoadUserEventByRemoteID(mattermostUserID, "remote_event_id_1").Return(nil, store.ErrNotFound)
+				} else {
+					mockStore.EXPECT().LoadUserEventByRemoteID(mattermostUserID, "remote_event_id_1").Return(prior, nil)
+				}
+			} else {
+				if prior == nil {
+					mockStore.EXPECT().LoadUserEvent(mattermostUserID, "remote_event_uid_1").Return(nil, store.ErrNotFound)
+				} else {
+					mockStore.EXPECT().LoadUserEvent(mattermostUserID, "remote_event_uid_1").Return(prior, nil)
+				}
+			}
+
+			if tc.changeType != remote.ChangeTypeDeleted || prior != nil {
+				mockClient.EXPECT().GetMailboxSettings(subscription.Remote.CreatorID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil)
+			}
+
+			var title string
+			if tc.expectedTitle != "" {
+				mockPoster.EXPECT().DMWithAttachments(mattermostUserID, gomock.Any()).DoAndReturn(
+					func(_ string, attachments ...*model.SlackAttachment) (string, error) {
+						title = attachments[0].Title
+						return "post_id", nil
+					})
+			}
+			if tc.expectStore {
+				mockStore.EXPECT().StoreUserEvent(mattermostUserID, gomock.Any()).Return(nil)
+			}
+			if tc.expectDelete {
+				mockStore.EXPECT().DeleteUserEvent(mattermostUserID, "remote_event_uid_1").Return(nil)
+			}
+			if tc.expectMetaDel {
+				mockStore.EXPECT().DeleteEventMetadata("remote_event_uid_1").Return(nil)
+			}
+			if tc.linked {
+				mockStore.EXPECT().DeleteUserLinkedEvent(mattermostUserID, "remote_event_uid_1").Return(nil)
+			}
+
+			err := processor.processNotification(&remote.Notification{
+				SubscriptionID: subscription.Remote.ID,
+				ChangeType:     tc.changeType,
+				ClientState:    subscription.Remote.ClientState,
+				Event:          tc.event,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedTitle, title)
+		})
+	}
+}
+
+func TestIsActiveEvent(t *testing.T) {
+	user := &store.User{ActiveEvents: []string{"event_uid_1 2030-03-04T10:00:00Z"}}
+	require.True(t, isActiveEvent(user, "event_uid_1"))
+	require.False(t, isActiveEvent(user, "event_uid"))
+	require.False(t, isActiveEvent(&store.User{}, "event_uid_1"))
 }
@@ -13,7 +13,9 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/oauth2connect"
 )
 
@@ -23,6 +25,8 @@ const (
 	RemoteUserAlreadyConnected         = "%s account `%s` is already mapped to Mattermost account `%s`. Please run `/%s disconnect`, while logged in as the Mattermost account"
 	RemoteUserAlreadyConnectedDisabled = "%s account `%s` is already mapped to a Mattermost account, but the account is deactivated. Please enable it and run `/%s disconnect`,  while logged in as the other Mattermost account, and try again"
 	RemoteUserAlreadyConnectedNotFound = "%s account `%s` is already mapped to a Mattermost account, but the Mattermost user could not be found"
+	SubscribeToEventsFailed            = "We could not subscribe you to notifications of your events. You can enable them from `/%s settings`."
+	UnsubscribeFromEventsFailed        = "We could not unsubscribe you from notifications of your events. You can disable them from `/%s settings`."
 )
 
 type oauth2App struct {
@@ -137,7 +141,33 @@ func (app *oauth2App) CompleteOAuth2(authedUserID, code, state string) error {
 		return err
 	}
 
+	if app.Provider.Features.EventNotifications {
+		app.subscribeToEvents(u, client)
+	}
+
 	app.Welcomer.AfterSuccessfullyConnect(mattermostUserID, me.Mail)
 
 	return nil
 }
+
+// subscribeToEvents creates the event subscription of a newly connected user,
+// so that they are notified of their invitations. Failing to do so does not
+// prevent the connection, the user is told to subscribe from the settings.
+func (app *oauth2App) subscribeToEvents(u *store.User, client remote.Client) {
+	m := &mscalendar{
+		Env: app.Env,
+		actingUser: &User{
+			User:             u,
+			MattermostUserID: u.MattermostUserID,
+		},
+		client: client,
+	}
+	_, err := m.CreateMyEventSubscription()
+	if err != nil {
+		app.Logger.With(bot.LogContext{
+			"mattermostUserID": u.MattermostUserID,
+			"err":              err.Error(),
+		}).Warnf("Error subscribing to events after connecting.")
+		_, _ = app.Poster.DM(u.MattermostUserID, SubscribeToEventsFailed, config.Provider.CommandTrigger)
+	}
+}
@@ -88,6 +88,67 @@ func TestCompleteOAuth2Happy(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCompleteOAuth2SubscribesToEvents(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		status    int
+		setupMock func(*mock_store.MockStore, *mock_bot.MockPoster)
+	}{
+		{
+			name:   "subscribed",
+			status: http.StatusCreated,
+			setupMock: func(ss *mock_store.MockStore, _ *mock_bot.MockPoster) {
+				ss.EXPECT().StoreUserSubscription(gomock.Any(), gomock.Any()).DoAndReturn(func(u *store.User, sub *store.Subscription) error {
+					require.Equal(t, fakeID, u.MattermostUserID)
+					require.Equal(t, "subscription-id", sub.Remote.ID)
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:   "error subscribing does not fail the connection",
+			status: http.StatusForbidden,
+			setupMock: func(_ *mock_store.MockStore, poster *mock_bot.MockPoster) {
+				poster.EXPECT().DM(fakeID, SubscribeToEventsFailed, gomock.Any()).Return("", nil).Times(1)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			statusOKGraphAPIResponder()
+			httpmock.RegisterResponder("POST", "https://graph.microsoft.com/v1.0/subscriptions",
+				httpmock.NewStringResponder(tc.status, `{"id": "subscription-id"}`))
+
+			app, env := newOAuth2TestApp(ctrl)
+			env.Config.Provider.Features.EventNotifications = true
+			ss := env.Dependencies.Store.(*mock_store.MockStore)
+			aa := env.PluginAPI.(*mock_plugin_api.MockPluginAPI)
+			poster := env.Poster.(*mock_bot.MockPoster)
+			welcomer := env.Dependencies.Welcomer.(*mock_welcomer.MockWelcomer)
+
+			ss.EXPECT().VerifyOAuth2State(gomock.Any()).Return(nil).Times(1)
+			ss.EXPECT().RefreshAndStoreToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(&oauth2.Token{
+				AccessToken: "creator_oauth_token",
+			}, nil).AnyTimes()
+			ss.EXPECT().LoadMattermostUserID(fakeRemoteID).Return("", errors.New("connected user not found")).Times(1)
+			aa.EXPECT().GetMattermostUser(fakeID).Return(&model.User{Id: fakeID, Username: "fake_username"}, nil)
+			ss.EXPECT().StoreUser(gomock.Any()).Return(nil).Times(1)
+			ss.EXPECT().StoreUserInIndex(gomock.Any()).Return(nil).Times(1)
+			ss.EXPECT().CheckUserConnected(fakeID).Return(true).AnyTimes()
+			ss.EXPECT().DisconnectUserFromStoreIfNecessary(gomock.Any(), fakeID).AnyTimes()
+			tc.setupMock(ss, poster)
+			welcomer.EXPECT().AfterSuccessfullyConnect(fakeID, "mail-value").Return(nil).Times(1)
+
+			err := app.CompleteOAuth2(fakeID, fakeCode, "state_"+fakeID)
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestInitOAuth2(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,223 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const (
+	// The events are cached by windows of whole hours, so that views of the
+	// same hours computed at different times share the entry.
+	remoteCacheEventsBucket = time.Hour
+	remoteCacheEventsTTL    = 5 * time.Minute
+	remoteCacheMailboxTTL   = time.Hour
+	remoteCacheCalendarsTTL = time.Hour
+)
+
+// RemoteCacheCounts is the number of lookups in the remote cache of a kind of
+// data that were found, or not.
+type RemoteCacheCounts struct {
+	Hits   int64
+	Misses int64
+}
+
+var remoteCacheStats = struct {
+	sync.Mutex
+	counts map[string]*RemoteCacheCounts
+}{
+	counts: map[string]*RemoteCacheCounts{},
+}
+
+// GetRemoteCacheStats returns the lookups in the remote cache since the
+// plugin started, by kind of data.
+func GetRemoteCacheStats() map[string]RemoteCacheCounts {
+	remoteCacheStats.Lock()
+	defer remoteCacheStats.Unlock()
+	stats := map[string]RemoteCacheCounts{}
+	for kind, counts := range remoteCacheStats.counts {
+		stats[kind] = *counts
+	}
+	return stats
+}
+
+func countRemoteCacheLookup(kind string, hit bool) {
+	remoteCacheStats.Lock()
+	defer remoteCacheStats.Unlock()
+	counts, ok := remoteCacheStats.counts[kind]
+	if !ok {
+		counts = &RemoteCacheCounts{}
+		remoteCacheStats.counts[kind] = counts
+	}
+	if hit {
+		counts.Hits++
+	} else {
+		counts.Misses++
+	}
+}
+
+// cachedClient reads the events, mailbox settings and calendars of the users
+// from the remote cache when present, and invalidates them when they are
+// changed through it.
+type cachedClient struct {
+	remote.Client
+	store  store.RemoteCacheStore
+	logger bot.Logger
+}
+
+// NewCachedClient returns a client caching the responses of the remote when
+// the calendar cache is enabled, or the client itself otherwise.
+func NewCachedClient(env Env, client remote.Client) remote.Client {
+	if env.Config == nil || !env.Config.EnableCalendarCache {
+		return client
+	}
+	if _, ok := client.(*cachedClient); ok {
+		return client
+	}
+	return &cachedClient{
+		Client: client,
+		store:  env.Store,
+		logger: env.Logger,
+	}
+}
+
+func (c *cachedClient) GetMailboxSettings(remoteUserID string) (*remote.MailboxSettings, error) {
+	settings := &remote.MailboxSettings{}
+	generation, hit := c.load(remoteUserID, store.RemoteCacheMailbox, "", settings)
+	if hit {
+		return settings, nil
+	}
+
+	settings, err := c.Client.GetMailboxSettings(remoteUserID)
+	if err != nil {
+		return nil, err
+	}
+	c.save(remoteUserID, store.RemoteCacheMailbox, generation, "", settings, remoteCacheMailboxTTL)
+	return settings, nil
+}
+
+func (c *cachedClient) GetCalendars(remoteUserID string) ([]*remote.Calendar, error) {
+	calendars := []*remote.Calendar{}
+	generation, hit := c.load(remoteUserID, store.RemoteCacheCalendars, "", &calendars)
+	if hit {
+		return calendars, nil
+	}
+
+	calendars, err := c.Client.GetCalendars(remoteUserID)
+	if err != nil {
+		return nil, err
+	}
+	c.save(remoteUserID, store.RemoteCacheCalendars, generation, "", calendars, remoteCacheCalendarsTTL)
+	return calendars, nil
+}
+
+func (c *cachedClient) GetDefaultCalendarView(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	return c.getEvents(remoteUserID, start, end, c.Client.GetDefaultCalendarView)
+}
+
+func (c *cachedClient) GetEventsBetweenDates(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	return c.getEvents(remoteUserID, start, end, c.Client.GetEventsBetweenDates)
+}
+
+func (c *cachedClient) getEvents(remoteUserID string, start, end time.Time, get func(string, time.Time, time.Time) ([]*remote.Event, error)) ([]*remote.Event, error) {
+	bucketStart := start.Truncate(remoteCacheEventsBucket)
+	bucketEnd := end.Truncate(remoteCacheEventsBucket)
+	if bucketEnd.Before(end) {
+		bucketEnd = bucketEnd.Add(remoteCacheEventsBucket)
+	}
+	key := fmt.Sprintf("%d-%d", bucketStart.Unix(), bucketEnd.Unix())
+
+	events := []*remote.Event{}
+	generation, hit := c.load(remoteUserID, store.RemoteCacheEvents, key, &events)
+	if !hit {
+		var err error
+		events, err = get(remoteUserID, bucketStart, bucketEnd)
+		if err != nil {
+			return nil, err
+		}
+		c.save(remoteUserID, store.RemoteCacheEvents, generation, key, events, remoteCacheEventsTTL)
+	}
+	return eventsBetween(events, start, end), nil
+}
+
+// eventsBetween returns the events overlapping the window, out of those of
+// the hours around it.
+func eventsBetween(events []*remote.Event, start, end time.Time) []*remote.Event {
+	result := []*remote.Event{}
+	for _, e := range events {
+		if e.End != nil && !e.End.Time().IsZero() && !e.End.Time().After(start) {
+			continue
+		}
+		if e.Start != nil && !e.Start.Time().IsZero() && !e.Start.Time().Before(end) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func (c *cachedClient) CreateEvent(remoteUserID string, event *remote.Event) (*remote.Event, error) {
+	defer c.invalidate(remoteUserID, store.RemoteCacheEvents)
+	return c.Client.CreateEvent(remoteUserID, event)
+}
+
+func (c *cachedClient) AcceptEvent(remoteUserID, eventID string, opts *remote.EventResponseOptions) error {
+	defer c.invalidate(remoteUserID, store.RemoteCacheEvents)
+	return c.Client.AcceptEvent(remoteUserID, eventID, opts)
+}
+
+func (c *cachedClient) DeclineEvent(remoteUserID, eventID string, opts *remote.EventResponseOptions) error {
+	defer c.invalidate(remoteUserID, store.RemoteCacheEvents)
+	return c.Client.DeclineEvent(remoteUserID, eventID, opts)
+}
+
+func (c *cachedClient) TentativelyAcceptEvent(remoteUserID, eventID string, opts *remote.EventResponseOptions) error {
+	defer c.invalidate(remoteUserID, store.RemoteCacheEvents)
+	return c.Client.TentativelyAcceptEvent(remoteUserID, eventID, opts)
+}
+
+func (c *cachedClient) CreateCalendar(remoteUserID string, calendar *remote.Calendar) (*remote.Calendar, error) {
+	defer c.invalidate(remoteUserID, store.RemoteCacheCalendars)
+	return c.Client.CreateCalendar(remoteUserID, calendar)
+}
+
+func (c *cachedClient) DeleteCalendar(remoteUserID, calendarID string) error {
+	defer c.invalidate(remoteUserID, store.RemoteCacheEvents)
+	defer c.invalidate(remoteUserID, store.RemoteCacheCalendars)
+	return c.Client.DeleteCalendar(remoteUserID, calendarID)
+}
+
+// load returns the generation of the kind, to save the data fetched after a
+// miss in, and whether the entry was found.
+func (c *cachedClient) load(remoteUserID, kind, key string, v interface{}) (string, bool) {
+	generation, err := c.store.LoadRemoteCache(remoteUserID, kind, key, v)
+	if err != nil && err != store.ErrNotFound {
+		c.logger.With(bot.LogContext{"remote_id": remoteUserID, "kind": kind, "err": err}).Debugf("error loading from the remote cache")
+	}
+	countRemoteCacheLookup(kind, err == nil)
+	return generation, err == nil
+}
+
+func (c *cachedClient) save(remoteUserID, kind, generation, key string, v interface{}, ttl time.Duration) {
+	if generation == "" {
+		return
+	}
+	err := c.store.StoreRemoteCache(remoteUserID, kind, generation, key, v, ttl)
+	if err != nil {
+		c.logger.With(bot.LogContext{"remote_id": remoteUserID, "kind": kind, "err": err}).Warnf("error storing in the remote cache")
+	}
+}
+
+func (c *cachedClient) invalidate(remoteUserID, kind string) {
+	err := c.store.InvalidateRemoteCache(remoteUserID, kind)
+	if err != nil {
+		c.logger.With(bot.LogContext{"remote_id": remoteUserID, "kind": kind, "err": err}).Warnf("error invalidating the remote cache")
+	}
+}
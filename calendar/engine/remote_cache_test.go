@@ -0,0 +1,137 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestCachedClient(t *testing.T) {
+	start := time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	key := fmt.Sprintf("%d-%d", start.Unix(), end.Unix())
+	events := []*remote.Event{{ID: "event_id", Subject: "Meeting"}}
+	at := func(hour, minute int) *remote.DateTime {
+		return remote.NewDateTime(start.Add(time.Duration(hour)*time.Hour+time.Duration(minute)*time.Minute), "UTC")
+	}
+	morning := &remote.Event{ID: "morning", Start: at(9, 0), End: at(10, 0)}
+	noon := &remote.Event{ID: "noon", Start: at(12, 0), End: at(13, 0)}
+
+	for _, tc := range []struct {
+		name      string
+		disabled  bool
+		setupMock func(*mockCalendarCache)
+		run       func(*testing.T, remote.Client)
+	}{
+		{
+			name:     "cache disabled",
+			disabled: true,
+			setupMock: func(m *mockCalendarCache) {
+				m.client.EXPECT().GetDefaultCalendarView(MockRemoteUserID, start, end).Return(events, nil).Times(1)
+			},
+			run: func(t *testing.T, c remote.Client) {
+				got, err := c.GetDefaultCalendarView(MockRemoteUserID, start, end)
+				require.NoError(t, err)
+				require.Equal(t, events, got)
+			},
+		},
+		{
+			name: "miss",
+			setupMock: func(m *mockCalendarCache) {
+				m.store.EXPECT().LoadRemoteCache(MockRemoteUserID, store.RemoteCacheEvents, key, gomock.Any()).Return("1", store.ErrNotFound).Times(1)
+				m.client.EXPECT().GetDefaultCalendarView(MockRemoteUserID, start, end).Return(events, nil).Times(1)
+				m.store.EXPECT().StoreRemoteCache(MockRemoteUserID, store.RemoteCacheEvents, "1", key, events, remoteCacheEventsTTL).Return(nil).Times(1)
+			},
+			run: func(t *testing.T, c remote.Client) {
+				got, err := c.GetDefaultCalendarView(MockRemoteUserID, start, end)
+				require.NoError(t, err)
+				require.Equal(t, events, got)
+			},
+		},
+		{
+			name: "hit",
+			setupMock: func(m *mockCalendarCache) {
+				m.store.EXPECT().LoadRemoteCache(MockRemoteUserID, store.RemoteCacheMailbox, "", gomock.Any()).DoAndReturn(func(_, _, _ string, v interface{}) (string, error) {
+					v.(*remote.MailboxSettings).TimeZone = "Pacific Standard Time"
+					return "1", nil
+				}).Times(1)
+			},
+			run: func(t *testing.T, c remote.Client) {
+				settings, err := c.GetMailboxSettings(MockRemoteUserID)
+				require.NoError(t, err)
+				require.Equal(t, "Pacific Standard Time", settings.TimeZone)
+			},
+		},
+		{
+			name: "error is not cached",
+			setupMock: func(m *mockCalendarCache) {
+				m.store.EXPECT().LoadRemoteCache(MockRemoteUserID, store.RemoteCacheCalendars, "", gomock.Any()).Return("1", store.ErrNotFound).Times(1)
+				m.client.EXPECT().GetCalendars(MockRemoteUserID).Return(nil, fmt.Errorf("some error")).Times(1)
+			},
+			run: func(t *testing.T, c remote.Client) {
+				_, err := c.GetCalendars(MockRemoteUserID)
+				require.EqualError(t, err, "some error")
+			},
+		},
+		{
+			name: "windows share the entry of their hours",
+			setupMock: func(m *mockCalendarCache) {
+				hoursKey := fmt.Sprintf("%d-%d", start.Add(9*time.Hour).Unix(), start.Add(13*time.Hour).Unix())
+				m.store.EXPECT().LoadRemoteCache(MockRemoteUserID, store.RemoteCacheEvents, hoursKey, gomock.Any()).DoAndReturn(func(_, _, _ string, v interface{}) (string, error) {
+					*v.(*[]*remote.Event) = []*remote.Event{morning, noon}
+					return "1", nil
+				}).Times(1)
+			},
+			run: func(t *testing.T, c remote.Client) {
+				got, err := c.GetDefaultCalendarView(MockRemoteUserID, start.Add(9*time.Hour+30*time.Minute), start.Add(12*time.Hour+15*time.Minute))
+				require.NoError(t, err)
+				require.Equal(t, []*remote.Event{morning, noon}, got)
+			},
+		},
+		{
+			name: "events outside of the window are left out",
+			setupMock: func(m *mockCalendarCache) {
+				hoursKey := fmt.Sprintf("%d-%d", start.Add(10*time.Hour).Unix(), start.Add(12*time.Hour).Unix())
+				m.store.EXPECT().LoadRemoteCache(MockRemoteUserID, store.RemoteCacheEvents, hoursKey, gomock.Any()).Return("1", store.ErrNotFound).Times(1)
+				m.client.EXPECT().GetDefaultCalendarView(MockRemoteUserID, start.Add(10*time.Hour), start.Add(12*time.Hour)).Return([]*remote.Event{morning, noon}, nil).Times(1)
+				m.store.EXPECT().StoreRemoteCache(MockRemoteUserID, store.RemoteCacheEvents, "1", hoursKey, []*remote.Event{morning, noon}, remoteCacheEventsTTL).Return(nil).Times(1)
+			},
+			run: func(t *testing.T, c remote.Client) {
+				got, err := c.GetDefaultCalendarView(MockRemoteUserID, start.Add(10*time.Hour), start.Add(11*time.Hour+30*time.Minute))
+				require.NoError(t, err)
+				require.Equal(t, []*remote.Event{}, got)
+			},
+		},
+		{
+			name: "changes invalidate the cache",
+			setupMock: func(m *mockCalendarCache) {
+				m.client.EXPECT().AcceptEvent(MockRemoteUserID, "event_id", nil).Return(nil).Times(1)
+				m.store.EXPECT().InvalidateRemoteCache(MockRemoteUserID, store.RemoteCacheEvents).Return(nil).Times(1)
+				m.client.EXPECT().DeleteCalendar(MockRemoteUserID, "calendar_id").Return(nil).Times(1)
+				m.store.EXPECT().InvalidateRemoteCache(MockRemoteUserID, store.RemoteCacheCalendars).Return(nil).Times(1)
+				m.store.EXPECT().InvalidateRemoteCache(MockRemoteUserID, store.RemoteCacheEvents).Return(nil).Times(1)
+			},
+			run: func(t *testing.T, c remote.Client) {
+				require.NoError(t, c.AcceptEvent(MockRemoteUserID, "event_id", nil))
+				require.NoError(t, c.DeleteCalendar(MockRemoteUserID, "calendar_id"))
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mscalendar, mockStore, _, _, _, mockClient, _ := GetMockSetup(t)
+			mscalendar.Config.EnableCalendarCache = !tc.disabled
+			tc.setupMock(&mockCalendarCache{store: mockStore, client: mockClient})
+
+			tc.run(t, NewCachedClient(mscalendar.Env, mockClient))
+		})
+	}
+}
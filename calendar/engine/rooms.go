@@ -0,0 +1,122 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const (
+	minRoomAvailabilityInterval = 6
+	maxRoomAvailabilityInterval = 1440
+)
+
+type Rooms interface {
+	GetRoomsAvailability(user *User, building string, start, end time.Time) ([]*RoomAvailability, error)
+}
+
+// RoomAvailability holds the free/busy status of a room for a time window.
+// Status is one of the remote.ScheduleStatus* values.
+type RoomAvailability struct {
+	Room   *remote.Room
+	Status string
+}
+
+func (m *mscalendar) GetRoomsAvailability(user *User, building string, start, end time.Time) ([]*RoomAvailability, error) {
+	if !m.Config.EnableRoomFinder {
+		return nil, errors.New("the room finder is not enabled, ask a system admin to enable it")
+	}
+
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !end.After(start) {
+		return nil, errors.New("end time must be after start time")
+	}
+
+	allRooms, err := m.client.FindRooms()
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding rooms")
+	}
+
+	rooms := []*remote.Room{}
+	requests := []*remote.ScheduleUserInfo{}
+	for _, room := range allRooms {
+		if room.EmailAddress == "" {
+			continue
+		}
+		if building != "" && !strings.EqualFold(room.Building, building) {
+			continue
+		}
+		rooms = append(rooms, room)
+		requests = append(requests, &remote.ScheduleUserInfo{
+			RemoteUserID: user.Remote.ID,
+			Mail:         room.EmailAddress,
+		})
+	}
+	if len(rooms) == 0 {
+		return []*RoomAvailability{}, nil
+	}
+
+	interval := int(end.Sub(start).Minutes())
+	if interval < minRoomAvailabilityInterval {
+		interval = minRoomAvailabilityInterval
+	}
+	if interval > maxRoomAvailabilityInterval {
+		interval = maxRoomAvailabilityInterval
+	}
+
+	schedules, err := m.client.GetSchedule(requests, remote.NewDateTime(start.UTC(), "UTC"), remote.NewDateTime(end.UTC(), "UTC"), interval)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting room schedules")
+	}
+
+	statusByMail := map[string]string{}
+	for _, s := range schedules {
+		statusByMail[strings.ToLower(s.ScheduleID)] = getScheduleStatus(s)
+	}
+
+	result := []*RoomAvailability{}
+	for _, room := range rooms {
+		status, ok := statusByMail[strings.ToLower(room.EmailAddress)]
+		if !ok {
+			status = remote.ScheduleStatusUnknown
+		}
+		result = append(result, &RoomAvailability{
+			Room:   room,
+			Status: status,
+		})
+	}
+
+	return result, nil
+}
+
+// getScheduleStatus returns the most restrictive status found in the
+// availability view of the schedule.
+func getScheduleStatus(s *remote.ScheduleInformation) string {
+	if s.Error != nil || s.AvailabilityView == "" {
+		return remote.ScheduleStatusUnknown
+	}
+
+	status := remote.ScheduleStatusFree
+	for _, c := range s.AvailabilityView {
+		switch c {
+		case remote.AvailabilityViewBusy, remote.AvailabilityViewOutOfOffice, remote.AvailabilityViewWorkingElsewhere:
+			return remote.ScheduleStatusBusy
+		case remote.AvailabilityViewTentative:
+			status = remote.ScheduleStatusTentative
+		}
+	}
+	return status
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestGetRoomsAvailability(t *testing.T) {
+	mscalendar, _, _, _, _, mockClient, _ := GetMockSetup(t)
+	mscalendar.Config.EnableRoomFinder = true
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+
+	rooms := []*remote.Room{
+		{DisplayName: "Room A", EmailAddress: "room.a@example.com", Building: "HQ"},
+		{DisplayName: "Room B", EmailAddress: "room.b@example.com", Building: "HQ"},
+		{DisplayName: "Room C", EmailAddress: "room.c@example.com", Building: "Annex"},
+		{DisplayName: "Room D", EmailAddress: "room.d@example.com", Building: "HQ"},
+	}
+
+	tests := []struct {
+		name       string
+		building   string
+		setupMock  func()
+		assertions func(t *testing.T, result []*RoomAvailability, err error)
+	}{
+		{
+			name: "room finder not enabled",
+			setupMock: func() {
+				mscalendar.Config.EnableRoomFinder = false
+			},
+			assertions: func(t *testing.T, _ []*RoomAvailability, err error) {
+				mscalendar.Config.EnableRoomFinder = true
+				require.EqualError(t, err, "the room finder is not enabled, ask a system admin to enable it")
+			},
+		},
+		{
+			name: "error finding rooms",
+			setupMock: func() {
+				mockClient.EXPECT().FindRooms().Return(nil, errors.New("some error")).Times(1)
+			},
+			assertions: func(t *testing.T, _ []*RoomAvailability, err error) {
+				require.EqualError(t, err, "error finding rooms: some error")
+			},
+		},
+		{
+			name:     "no rooms in building",
+			building: "Nowhere",
+			setupMock: func() {
+				mockClient.EXPECT().FindRooms().Return(rooms, nil).Times(1)
+			},
+			assertions: func(t *testing.T, result []*RoomAvailability, err error) {
+				require.NoError(t, err)
+				require.Empty(t, result)
+			},
+		},
+		{
+			name:     "rooms filtered by building with their status",
+			building: "hq",
+			setupMock: func() {
+				mockClient.EXPECT().FindRooms().Return(rooms, nil).Times(1)
+				mockClient.EXPECT().GetSchedule(gomock.Len(3), gomock.Any(), gomock.Any(), 30).Return([]*remote.ScheduleInformation{
+					{ScheduleID: "Room.A@example.com", AvailabilityView: "0"},
+					{ScheduleID: "room.b@example.com", AvailabilityView: "2"},
+				}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, result []*RoomAvailability, err error) {
+				require.NoError(t, err)
+				require.Len(t, result, 3)
+				require.Equal(t, "Room A", result[0].Room.DisplayName)
+				require.Equal(t, remote.ScheduleStatusFree, result[0].Status)
+				require.Equal(t, "Room B", result[1].Room.DisplayName)
+				require.Equal(t, remote.ScheduleStatusBusy, result[1].Status)
+				require.Equal(t, "Room D", result[2].Room.DisplayName)
+				require.Equal(t, remote.ScheduleStatusUnknown, result[2].Status)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			user := GetMockUser(model.NewString(MockRemoteUserID), model.NewString(MockMMModelUserID), MockMMUserID, nil)
+			result, err := mscalendar.GetRoomsAvailability(user, tt.building, start, end)
+
+			tt.assertions(t, result, err)
+		})
+	}
+}
+
+func TestGetScheduleStatus(t *testing.T) {
+	for _, tc := range []struct {
+		schedule *remote.ScheduleInformation
+		expected string
+	}{
+		{&remote.ScheduleInformation{AvailabilityView: "000"}, remote.ScheduleStatusFree},
+		{&remote.ScheduleInformation{AvailabilityView: "010"}, remote.ScheduleStatusTentative},
+		{&remote.ScheduleInformation{AvailabilityView: "012"}, remote.ScheduleStatusBusy},
+		{&remote.ScheduleInformation{AvailabilityView: "3"}, remote.ScheduleStatusBusy},
+		{&remote.ScheduleInformation{}, remote.ScheduleStatusUnknown},
+		{&remote.ScheduleInformation{AvailabilityView: "0", Error: &remote.ScheduleInformationError{}}, remote.ScheduleStatusUnknown},
+	} {
+		require.Equal(t, tc.expected, getScheduleStatus(tc.schedule), string(tc.schedule.AvailabilityView))
+	}
+}
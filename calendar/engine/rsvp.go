@@ -0,0 +1,391 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const (
+	RSVPSearchDays           = 14
+	RSVPDigestDefaultMinutes = 60
+	RSVPDigestMaxMinutes     = 24 * 60
+
+	// rsvpDigestMaxCatchUp is how far back the digests missed by the skipped
+	// runs are sent, so that they are not all sent at once after a long
+	// downtime or when the digest is enabled again.
+	rsvpDigestMaxCatchUp = time.Hour
+)
+
+const (
+	rsvpGroupAccepted   = "Accepted"
+	rsvpGroupTentative  = "Tentative"
+	rsvpGroupDeclined   = "Declined"
+	rsvpGroupNoResponse = "No response"
+)
+
+type RSVP interface {
+	GetOrganizedEvents(user *User, query string) ([]*remote.Event, error)
+	DMEventRSVPs(user *User, event *remote.Event) error
+	NudgeNonResponders(user *User, eventID string) (nudged int, notConnected int, err error)
+	SetRSVPDigestMinutes(user *User, minutes int) (*store.RSVPDigestUserSettings, error)
+	ProcessAllRSVPDigests(now time.Time) error
+}
+
+// GetOrganizedEvents returns the upcoming events organized by the user whose
+// ID matches the query or whose subject contains it. All upcoming organized
+// events are returned when the query is empty.
+func (m *mscalendar) GetOrganizedEvents(user *User, query string) ([]*remote.Event, error) {
+	now := time.Now()
+	events, err := m.ViewCalendar(user, now, now.Add(RSVPSearchDays*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	// Event IDs are case-sensitive, unlike the subject search
+	query = strings.TrimSpace(query)
+	subjectQuery := strings.ToLower(query)
+	result := []*remote.Event{}
+	for _, e := range events {
+		if !e.IsOrganizer || e.IsCancelled || len(rsvpAttendees(e)) == 0 {
+			continue
+		}
+		if query == "" || e.ID == query || strings.Contains(strings.ToLower(e.Subject), subjectQuery) {
+			result = append(result, e)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Time().Before(result[j].Start.Time())
+	})
+	return result, nil
+}
+
+func (m *mscalendar) DMEventRSVPs(user *User, event *remote.Event) error {
+	timezone, err := m.GetTimezone(user)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.Poster.DMWithAttachments(user.MattermostUserID, m.renderRSVPDigest(event, timezone))
+	return err
+}
+
+// NudgeNonResponders sends the event with RSVP actions to the attendees that
+// have not responded yet and are connected to Mattermost.
+func (m *mscalendar) NudgeNonResponders(user *User, eventID string) (int, int, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	event, err := m.client.GetEvent(user.Remote.ID, eventID)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get event")
+	}
+	if !event.IsOrganizer {
+		return 0, 0, errors.New("only the organizer can nudge attendees")
+	}
+
+	nonResponders := []*remote.Attendee{}
+	for _, group := range RSVPGroups(event) {
+		if group.Title == rsvpGroupNoResponse {
+			nonResponders = group.Attendees
+		}
+	}
+	if len(nonResponders) == 0 {
+		return 0, 0, nil
+	}
+
+	userIndex, err := m.Store.LoadUserIndex()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to load connected users")
+	}
+	usersByEmail := map[string]*store.UserShort{}
+	for _, u := range userIndex {
+		if u.Email != "" {
+			usersByEmail[strings.ToLower(u.Email)] = u
+		}
+	}
+
+	nudged, notConnected := 0, 0
+	for _, a := range nonResponders {
+		if a.EmailAddress == nil {
+			notConnected++
+			continue
+		}
+		attendee, ok := usersByEmail[strings.ToLower(a.EmailAddress.Address)]
+		if !ok || attendee.MattermostUserID == user.MattermostUserID {
+			notConnected++
+			continue
+		}
+
+		err = m.nudgeAttendee(attendee.MattermostUserID, user, event)
+		if err != nil {
+			m.Logger.With(bot.LogContext{
+				"mm_user_id": attendee.MattermostUserID,
+				"event_id":   eventID,
+				"err":        err,
+			}).Warnf("Failed to nudge attendee")
+			continue
+		}
+		nudged++
+	}
+
+	return nudged, notConnected, nil
+}
+
+// nudgeAttendee DMs the attendee their copy of the event, found by iCalendar
+// UID, with the RSVP actions.
+func (m *mscalendar) nudgeAttendee(mattermostUserID string, organizer *User, event *remote.Event) error {
+	engine, err := m.FilterCopy(withActingUser(mattermostUserID))
+	if err != nil {
+		return err
+	}
+
+	attendee := NewUser(mattermostUserID)
+	events, err := engine.ViewCalendar(attendee, event.Start.Time(), event.End.Time())
+	if err != nil {
+		return err
+	}
+
+	var attendeeEvent *remote.Event
+	for _, e := range events {
+		if e.ICalUID == event.ICalUID {
+			attendeeEvent = e
+			break
+		}
+	}
+	if attendeeEvent == nil {
+		return errors.New("event not found in the attendee calendar")
+	}
+
+	timezone, err := engine.GetTimezone(attendee)
+	if err != nil {
+		return err
+	}
+
+	sa, err := views.RenderEventAsAttachment(attendeeEvent, timezone, views.ShowTimezoneOption(timezone))
+	if err != nil {
+		return err
+	}
+	sa.Pretext = fmt.Sprintf("%s is waiting for your response to this event.", organizer.String())
+	response := ""
+	if attendeeEvent.ResponseStatus != nil {
+		response = attendeeEvent.ResponseStatus.Response
+	}
+	sa.Actions = NewPostActionForEventResponse(attendeeEvent.ID, response, m.actionURL(config.PathRespond))
+
+	_, err = m.Poster.DMWithAttachments(mattermostUserID, sa)
+	return err
+}
+
+func (m *mscalendar) SetRSVPDigestMinutes(user *User, minutes int) (*store.RSVPDigestUserSettings, error) {
+	if minutes < 0 || minutes > RSVPDigestMaxMinutes {
+		return nil, fmt.Errorf("the digest must be sent between 0 and %d minutes before the meeting", RSVPDigestMaxMinutes)
+	}
+	if minutes%int(StatusSyncJobInterval/time.Minute) != 0 {
+		return nil, fmt.Errorf("minutes must be a multiple of %d", StatusSyncJobInterval/time.Minute)
+	}
+
+	err := m.Filter(withUserExpanded(user))
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Settings.RSVPDigest == nil {
+		user.Settings.RSVPDigest = &store.RSVPDigestUserSettings{MinutesBefore: RSVPDigestDefaultMinutes}
+	}
+	digest := user.Settings.RSVPDigest
+	digest.Enable = minutes > 0
+	if minutes > 0 {
+		digest.MinutesBefore = minutes
+	}
+
+	err = m.Store.StoreUser(user.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if digest.Enable {
+		err = m.Store.AddToUserList(store.RSVPDigestUserList, user.MattermostUserID)
+	} else {
+		err = m.Store.RemoveFromUserList(store.RSVPDigestUserList, user.MattermostUserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// ProcessAllRSVPDigests DMs the organizers that enabled the RSVP digest the
+// responses of the meetings they organize which start in the configured time.
+// It runs as part of the status sync job. The time up to which the digests
+// were sent is kept for each organizer, so that the meetings of a skipped run
+// are caught up with and those of a repeated run are not sent again.
+func (m *mscalendar) ProcessAllRSVPDigests(now time.Time) error {
+	mattermostUserIDs, err := m.Store.LoadUserList(store.RSVPDigestUserList)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, mattermostUserID := range mattermostUserIDs {
+		sent, err := m.processRSVPDigest(mattermostUserID, now)
+		if err != nil {
+			m.Logger.With(bot.LogContext{"mm_user_id": mattermostUserID, "err": err}).Warnf("Error sending RSVP digest")
+		}
+		count += sent
+	}
+
+	m.Logger.Debugf("Sent %d RSVP digests", count)
+	return nil
+}
+
+func (m *mscalendar) processRSVPDigest(mattermostUserID string, now time.Time) (int, error) {
+	storeUser, err := m.Store.LoadUser(mattermostUserID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return 0, errors.Wrap(err, "error loading user")
+	}
+	if storeUser == nil || storeUser.Settings.RSVPDigest == nil || !storeUser.Settings.RSVPDigest.Enable {
+		// The user disconnected or disabled the digest meanwhile
+		return 0, m.Store.RemoveFromUserList(store.RSVPDigestUserList, mattermostUserID)
+	}
+	digest := storeUser.Settings.RSVPDigest
+
+	from := now.Add(time.Duration(digest.MinutesBefore) * time.Minute)
+	to := from.Add(StatusSyncJobInterval)
+	sentUntil, err := m.Store.LoadRSVPDigestSentUntil(mattermostUserID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return 0, errors.Wrap(err, "error loading RSVP digest state")
+	}
+	if err == nil {
+		if !sentUntil.Before(to) {
+			return 0, nil
+		}
+		from = sentUntil
+		if earliest := to.Add(-rsvpDigestMaxCatchUp); from.Before(earliest) {
+			from = earliest
+		}
+		if from.Before(now) {
+			from = now
+		}
+	}
+
+	engine, err := m.FilterCopy(withActingUser(mattermostUserID))
+	if err != nil {
+		return 0, errors.Wrap(err, "error creating user engine")
+	}
+
+	user := newUserFromStoredUser(storeUser)
+	events, err := engine.ViewCalendar(user, from, to)
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting events")
+	}
+
+	count := 0
+	timezone := ""
+	for _, e := range events {
+		start := e.Start.Time()
+		if !e.IsOrganizer || e.IsCancelled || start.Before(from) || !start.Before(to) || len(rsvpAttendees(e)) == 0 {
+			continue
+		}
+
+		if timezone == "" {
+			timezone, err = engine.GetTimezone(user)
+			if err != nil {
+				return count, errors.Wrap(err, "error getting timezone")
+			}
+		}
+
+		_, err = m.Poster.DMWithAttachments(mattermostUserID, engine.renderRSVPDigest(e, timezone))
+		if err != nil {
+			m.Logger.Warnf("Error sending RSVP digest to user %s. err=%v", mattermostUserID, err)
+			continue
+		}
+		count++
+	}
+
+	return count, m.Store.StoreRSVPDigestSentUntil(mattermostUserID, to)
+}
+
+func (m *mscalendar) renderRSVPDigest(event *remote.Event, timezone string) *model.SlackAttachment {
+	groups := RSVPGroups(event)
+
+	var nudge *model.PostAction
+	for _, group := range groups {
+		if group.Title == rsvpGroupNoResponse && len(group.Attendees) > 0 {
+			nudge = &model.PostAction{
+				Name: "Nudge non-responders",
+				Integration: &model.PostActionIntegration{
+					URL: m.actionURL(config.PathNudge),
+					Context: map[string]interface{}{
+						config.EventIDKey: event.ID,
+					},
+				},
+			}
+		}
+	}
+
+	return views.RenderRSVPDigest(event, timezone, groups, nudge)
+}
+
+func (m *mscalendar) actionURL(action string) string {
+	return fmt.Sprintf("%s%s%s", m.Config.PluginURLPath, config.PathPostAction, action)
+}
+
+// RSVPGroups returns the attendees of the event grouped by their response.
+// Resources, like meeting rooms, are not included.
+func RSVPGroups(event *remote.Event) []*views.RSVPGroup {
+	accepted := &views.RSVPGroup{Title: rsvpGroupAccepted}
+	tentative := &views.RSVPGroup{Title: rsvpGroupTentative}
+	declined := &views.RSVPGroup{Title: rsvpGroupDeclined}
+	noResponse := &views.RSVPGroup{Title: rsvpGroupNoResponse}
+
+	for _, a := range rsvpAttendees(event) {
+		response := ""
+		if a.Status != nil {
+			response = a.Status.Response
+		}
+
+		switch response {
+		case ResponseYes:
+			accepted.Attendees = append(accepted.Attendees, a)
+		case ResponseMaybe:
+			tentative.Attendees = append(tentative.Attendees, a)
+		case ResponseNo:
+			declined.Attendees = append(declined.Attendees, a)
+		default:
+			noResponse.Attendees = append(noResponse.Attendees, a)
+		}
+	}
+
+	return []*views.RSVPGroup{accepted, tentative, declined, noResponse}
+}
+
+func rsvpAttendees(event *remote.Event) []*remote.Attendee {
+	attendees := []*remote.Attendee{}
+	for _, a := range event.Attendees {
+		if a.Type == remote.AttendeeTypeResource {
+			continue
+		}
+		attendees = append(attendees, a)
+	}
+	return attendees
+}
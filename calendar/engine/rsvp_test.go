@@ -0,0 +1,293 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func rsvpTestAttendee(email, response string) *remote.Attendee {
+	return &remote.Attendee{
+		EmailAddress: &remote.EmailAddress{Address: email},
+		Status:       &remote.EventResponseStatus{Response: response},
+		Type:         remote.AttendeeTypeRequired,
+	}
+}
+
+func TestRSVPGroups(t *testing.T) {
+	event := &remote.Event{
+		Attendees: []*remote.Attendee{
+			rsvpTestAttendee("a@example.com", ResponseYes),
+			rsvpTestAttendee("b@example.com", ResponseMaybe),
+			rsvpTestAttendee("c@example.com", ResponseNo),
+			rsvpTestAttendee("d@example.com", ResponseNone),
+			rsvpTestAttendee("e@example.com", "none"),
+			{EmailAddress: &remote.EmailAddress{Address: "room@example.com"}, Type: remote.AttendeeTypeResource},
+		},
+	}
+
+	groups := RSVPGroups(event)
+	require.Len(t, groups, 4)
+	for i, expected := range []struct {
+		title string
+		count int
+	}{
+		{rsvpGroupAccepted, 1},
+		{rsvpGroupTentative, 1},
+		{rsvpGroupDeclined, 1},
+		{rsvpGroupNoResponse, 2},
+	} {
+		require.Equal(t, expected.title, groups[i].Title)
+		require.Len(t, groups[i].Attendees, expected.count, expected.title)
+	}
+}
+
+func TestGetOrganizedEvents(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	newEvent := func(id, subject string, start time.Time) *remote.Event {
+		return &remote.Event{
+			ID:          id,
+			Subject:     subject,
+			IsOrganizer: true,
+			Start:       remote.NewDateTime(start, "UTC"),
+			End:         remote.NewDateTime(start.Add(time.Hour), "UTC"),
+			Attendees:   []*remote.Attendee{rsvpTestAttendee("a@example.com", ResponseNone)},
+		}
+	}
+	events := []*remote.Event{
+		newEvent("AAMkAGI2=", "Retro", start.Add(time.Hour)),
+		newEvent("aamkagi2=", "Planning", start),
+		newEvent("AAMkAGI3=", "Weekly planning", start.Add(2*time.Hour)),
+	}
+
+	for _, tc := range []struct {
+		name        string
+		query       string
+		expectedIDs []string
+	}{
+		{name: "all", query: "", expectedIDs: []string{"aamkagi2=", "AAMkAGI2=", "AAMkAGI3="}},
+		{name: "by ID", query: " AAMkAGI2= ", expectedIDs: []string{"AAMkAGI2="}},
+		{name: "by subject, ignoring case", query: "PLANNING", expectedIDs: []string{"aamkagi2=", "AAMkAGI3="}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mscalendar, mockStore, _, mockRemote, mockPluginAPI, mockClient, _ := GetMockSetup(t)
+			mockStore.EXPECT().LoadUser(MockMMUserID).Return(&store.User{MattermostUserID: MockMMUserID, Remote: &remote.User{ID: MockRemoteUserID}}, nil).AnyTimes()
+			mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil).AnyTimes()
+			mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient).AnyTimes()
+			mockClient.EXPECT().GetDefaultCalendarView(MockRemoteUserID, gomock.Any(), gomock.Any()).Return(events, nil).Times(1)
+
+			result, err := mscalendar.GetOrganizedEvents(NewUser(MockMMUserID), tc.query)
+			require.NoError(t, err)
+			ids := []string{}
+			for _, e := range result {
+				ids = append(ids, e.ID)
+			}
+			require.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+}
+
+func TestSetRSVPDigestMinutes(t *testing.T) {
+	mscalendar, mockStore, _, _, mockPluginAPI, _, _ := GetMockSetup(t)
+
+	for _, tc := range []struct {
+		name          string
+		minutes       int
+		expectedError string
+		expected      *store.RSVPDigestUserSettings
+	}{
+		{name: "too many minutes", minutes: RSVPDigestMaxMinutes + 5, expectedError: "the digest must be sent between 0 and 1440 minutes before the meeting"},
+		{name: "not a multiple of the job interval", minutes: 7, expectedError: "minutes must be a multiple of 5"},
+		{name: "enabled", minutes: 30, expected: &store.RSVPDigestUserSettings{MinutesBefore: 30, Enable: true}},
+		{name: "disabled", minutes: 0, expected: &store.RSVPDigestUserSettings{MinutesBefore: RSVPDigestDefaultMinutes}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectedError == "" {
+				mockStore.EXPECT().LoadUser(MockMMUserID).Return(&store.User{MattermostUserID: MockMMUserID, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil).Times(1)
+				mockStore.EXPECT().StoreUser(gomock.Any()).Return(nil).Times(1)
+				if tc.expected.Enable {
+					mockStore.EXPECT().AddToUserList(store.RSVPDigestUserList, MockMMUserID).Return(nil).Times(1)
+				} else {
+					mockStore.EXPECT().RemoveFromUserList(store.RSVPDigestUserList, MockMMUserID).Return(nil).Times(1)
+				}
+			}
+
+			digest, err := mscalendar.SetRSVPDigestMinutes(NewUser(MockMMUserID), tc.minutes)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, digest)
+		})
+	}
+}
+
+func TestNudgeNonResponders(t *testing.T) {
+	mscalendar, mockStore, mockPoster, mockRemote, mockPluginAPI, mockClient, _ := GetMockSetup(t)
+	mscalendar.actingUser = NewUser("")
+	start := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+
+	event := &remote.Event{
+		ID:          MockEventID,
+		ICalUID:     "icaluid",
+		IsOrganizer: true,
+		Start:       remote.NewDateTime(start, "UTC"),
+		End:         remote.NewDateTime(start.Add(time.Hour), "UTC"),
+		Attendees: []*remote.Attendee{
+			rsvpTestAttendee("accepted@example.com", ResponseYes),
+			rsvpTestAttendee("Connected@example.com", ResponseNone),
+			rsvpTestAttendee("external@example.com", ResponseNone),
+		},
+	}
+	attendeeEvent := &remote.Event{
+		ID:             "attendeeEventID",
+		ICalUID:        "icaluid",
+		Subject:        "Planning",
+		Start:          event.Start,
+		End:            event.End,
+		Organizer:      &remote.Attendee{EmailAddress: &remote.EmailAddress{Address: "organizer@example.com"}},
+		Location:       &remote.Location{},
+		ResponseStatus: &remote.EventResponseStatus{Response: remote.EventResponseStatusNotAnswered},
+	}
+
+	mockStore.EXPECT().LoadUser(MockMMUserID).Return(&store.User{MattermostUserID: MockMMUserID, Remote: &remote.User{ID: MockRemoteUserID}}, nil).AnyTimes()
+	mockStore.EXPECT().LoadUser("attendeeID").Return(&store.User{MattermostUserID: "attendeeID", Remote: &remote.User{ID: "attendeeRemoteID"}}, nil).AnyTimes()
+	mockPluginAPI.EXPECT().GetMattermostUser(gomock.Any()).Return(&model.User{Id: MockMMUserID, Username: "organizer"}, nil).AnyTimes()
+	mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient).AnyTimes()
+	mockClient.EXPECT().GetEvent(MockRemoteUserID, MockEventID).Return(event, nil).Times(1)
+	mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{
+		{MattermostUserID: MockMMUserID, Email: "organizer@example.com"},
+		{MattermostUserID: "attendeeID", Email: "connected@example.com"},
+	}, nil).Times(1)
+	mockClient.EXPECT().GetDefaultCalendarView("attendeeRemoteID", gomock.Any(), gomock.Any()).Return([]*remote.Event{attendeeEvent}, nil).Times(1)
+	mockClient.EXPECT().GetMailboxSettings("attendeeRemoteID").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+	mockPoster.EXPECT().DMWithAttachments("attendeeID", gomock.Any()).DoAndReturn(func(_ string, attachments ...*model.SlackAttachment) (string, error) {
+		require.Len(t, attachments, 1)
+		require.Equal(t, "@organizer is waiting for your response to this event.", attachments[0].Pretext)
+		require.Equal(t, "attendeeEventID", attachments[0].Actions[0].Integration.Context[config.EventIDKey])
+		return "postID", nil
+	}).Times(1)
+
+	nudged, notConnected, err := mscalendar.NudgeNonResponders(NewUser(MockMMUserID), MockEventID)
+	require.NoError(t, err)
+	require.Equal(t, 1, nudged)
+	require.Equal(t, 1, notConnected)
+}
+
+func TestProcessAllRSVPDigests(t *testing.T) {
+	now := time.Date(2020, 2, 12, 9, 0, 0, 0, time.UTC)
+	from := now.Add(time.Hour)
+	to := from.Add(StatusSyncJobInterval)
+
+	newEvent := func(id string, start time.Time, isOrganizer bool) *remote.Event {
+		return &remote.Event{
+			ID:          id,
+			Subject:     id,
+			IsOrganizer: isOrganizer,
+			Start:       remote.NewDateTime(start, "UTC"),
+			End:         remote.NewDateTime(start.Add(time.Hour), "UTC"),
+			Attendees:   []*remote.Attendee{rsvpTestAttendee("a@example.com", ResponseNone)},
+		}
+	}
+	events := []*remote.Event{
+		newEvent("in window", from, true),
+		newEvent("not organizer", from, false),
+		newEvent("missed run", from.Add(-StatusSyncJobInterval), true),
+		newEvent("next run", to, true),
+	}
+
+	for _, tc := range []struct {
+		name          string
+		sentUntil     time.Time
+		expectedFrom  time.Time
+		expectedPosts []string
+	}{
+		{
+			name:          "first run",
+			expectedFrom:  from,
+			expectedPosts: []string{"in window"},
+		},
+		{
+			name:          "after the previous run",
+			sentUntil:     from,
+			expectedFrom:  from,
+			expectedPosts: []string{"in window"},
+		},
+		{
+			name:          "after a missed run",
+			sentUntil:     from.Add(-StatusSyncJobInterval),
+			expectedFrom:  from.Add(-StatusSyncJobInterval),
+			expectedPosts: []string{"in window", "missed run"},
+		},
+		{
+			name:          "after a long downtime",
+			sentUntil:     now.Add(-24 * time.Hour),
+			expectedFrom:  to.Add(-rsvpDigestMaxCatchUp),
+			expectedPosts: []string{"in window", "missed run"},
+		},
+		{
+			name:      "repeated run",
+			sentUntil: to,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mscalendar, mockStore, mockPoster, mockRemote, mockPluginAPI, mockClient, mockLogger := GetMockSetup(t)
+			mscalendar.actingUser = NewUser("")
+
+			storeUser := &store.User{
+				MattermostUserID: MockMMUserID,
+				Remote:           &remote.User{ID: MockRemoteUserID},
+				Settings: store.Settings{
+					RSVPDigest: &store.RSVPDigestUserSettings{MinutesBefore: 60, Enable: true},
+				},
+			}
+			mockStore.EXPECT().LoadUserList(store.RSVPDigestUserList).Return([]string{MockMMUserID, "disabledID", "disconnectedID"}, nil).Times(1)
+			mockStore.EXPECT().LoadUser(MockMMUserID).Return(storeUser, nil).AnyTimes()
+			mockStore.EXPECT().LoadUser("disabledID").Return(&store.User{MattermostUserID: "disabledID"}, nil).Times(1)
+			mockStore.EXPECT().RemoveFromUserList(store.RSVPDigestUserList, "disabledID").Return(nil).Times(1)
+			mockStore.EXPECT().LoadUser("disconnectedID").Return(nil, store.ErrNotFound).Times(1)
+			mockStore.EXPECT().RemoveFromUserList(store.RSVPDigestUserList, "disconnectedID").Return(nil).Times(1)
+
+			if tc.sentUntil.IsZero() {
+				mockStore.EXPECT().LoadRSVPDigestSentUntil(MockMMUserID).Return(time.Time{}, store.ErrNotFound).Times(1)
+			} else {
+				mockStore.EXPECT().LoadRSVPDigestSentUntil(MockMMUserID).Return(tc.sentUntil, nil).Times(1)
+			}
+
+			if tc.expectedFrom.IsZero() {
+				mockLogger.EXPECT().Debugf("Sent %d RSVP digests", 0).Times(1)
+			} else {
+				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil).AnyTimes()
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient).AnyTimes()
+				mockClient.EXPECT().GetDefaultCalendarView(MockRemoteUserID, tc.expectedFrom, to).Return(events, nil).Times(1)
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				posts := []string{}
+				mockPoster.EXPECT().DMWithAttachments(MockMMUserID, gomock.Any()).DoAndReturn(func(_ string, attachments ...*model.SlackAttachment) (string, error) {
+					require.Equal(t, "Nudge non-responders", attachments[0].Actions[0].Name)
+					posts = append(posts, attachments[0].Title)
+					return "postID", nil
+				}).Times(len(tc.expectedPosts))
+				mockStore.EXPECT().StoreRSVPDigestSentUntil(MockMMUserID, to).Return(nil).Times(1)
+				mockLogger.EXPECT().Debugf("Sent %d RSVP digests", len(tc.expectedPosts)).Times(1)
+				defer func() {
+					require.Equal(t, tc.expectedPosts, posts)
+				}()
+			}
+
+			err := mscalendar.ProcessAllRSVPDigests(now)
+			require.NoError(t, err)
+		})
+	}
+}
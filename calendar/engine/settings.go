@@ -59,8 +59,25 @@ func NewSettingsPanel(bot bot.Bot, panelStore settingspanel.PanelStore, settingS
 		settingStore,
 	))
 	if providerFeatures.EventNotifications {
-		settings = append(settings, NewNotificationsSetting(getCal))
+		notificationsSetting := NewNotificationsSetting(getCal)
+		settings = append(settings, notificationsSetting)
+		settings = append(settings, settingspanel.NewMultiOptionSetting(
+			store.EventChangesSettingID,
+			"Event changes",
+			"Which changes to your events do you want to be notified of?",
+			notificationsSetting.GetID(),
+			store.EventChangeOptions,
+			settingStore,
+		))
+		settings = append(settings, settingspanel.NewBoolSetting(
+			store.MuteOwnEventsSettingID,
+			"Mute My Events",
+			"Do you want to stop receiving notifications for the events you organize?",
+			notificationsSetting.GetID(),
+			settingStore,
+		))
 	}
+	settings = append(settings, NewFreeBusySetting(getCal))
 	settings = append(settings, NewDailySummarySetting(
 		settingStore,
 		func(userID string) (string, error) { return getCal(userID).GetTimezone(NewUser(userID)) },
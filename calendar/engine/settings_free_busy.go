@@ -0,0 +1,136 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/settingspanel"
+)
+
+type freeBusySetting struct {
+	getCal      func(string) Engine
+	title       string
+	description string
+	id          string
+	dependsOn   string
+}
+
+func NewFreeBusySetting(getCal func(string) Engine) settingspanel.Setting {
+	return &freeBusySetting{
+		title:       "Share free/busy feed",
+		description: "Do you want a secret link that shows when you are busy, without any event details? Anyone with the link can see it. Select No to revoke the link.",
+		id:          "free_busy_feed_setting",
+		dependsOn:   "",
+		getCal:      getCal,
+	}
+}
+
+func (s *freeBusySetting) Set(userID string, value interface{}) error {
+	cal := s.getCal(userID)
+	user := NewUser(userID)
+
+	if value == "true" {
+		_, err := cal.GetFreeBusyFeedURL(user)
+		if err != nil {
+			_, err = cal.EnableFreeBusyFeed(user)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return cal.DisableFreeBusyFeed(user)
+}
+
+func (s *freeBusySetting) Get(userID string) (interface{}, error) {
+	_, err := s.getCal(userID).GetFreeBusyFeedURL(NewUser(userID))
+	if err == nil {
+		return "true", nil
+	}
+
+	return "false", nil
+}
+
+func (s *freeBusySetting) GetID() string {
+	return s.id
+}
+
+func (s *freeBusySetting) GetTitle() string {
+	return s.title
+}
+
+func (s *freeBusySetting) GetDescription() string {
+	return s.description
+}
+
+func (s *freeBusySetting) GetDependency() string {
+	return s.dependsOn
+}
+
+func (s *freeBusySetting) getActionStyle(actionValue, currentValue string) string {
+	if actionValue == currentValue {
+		return "primary"
+	}
+	return "default"
+}
+
+func (s *freeBusySetting) GetSlackAttachments(userID, settingHandler string, disabled bool) (*model.SlackAttachment, error) {
+	title := fmt.Sprintf("Setting: %s", s.title)
+	currentValueMessage := "Disabled"
+
+	actions := []*model.PostAction{}
+	if !disabled {
+		currentTextValue := "No"
+		currentValue := "false"
+		feedURL, err := s.getCal(userID).GetFreeBusyFeedURL(NewUser(userID))
+		if err == nil {
+			currentTextValue = fmt.Sprintf("Yes\n**Link:** %s", feedURL)
+			currentValue = "true"
+		}
+		currentValueMessage = fmt.Sprintf("**Current value:** %s", currentTextValue)
+
+		actionTrue := model.PostAction{
+			Name:  "Yes",
+			Style: s.getActionStyle("true", currentValue),
+			Integration: &model.PostActionIntegration{
+				URL: settingHandler,
+				Context: map[string]interface{}{
+					settingspanel.ContextIDKey:          s.id,
+					settingspanel.ContextButtonValueKey: "true",
+				},
+			},
+		}
+
+		actionFalse := model.PostAction{
+			Name:  "No",
+			Style: s.getActionStyle("false", currentValue),
+			Integration: &model.PostActionIntegration{
+				URL: settingHandler,
+				Context: map[string]interface{}{
+					settingspanel.ContextIDKey:          s.id,
+					settingspanel.ContextButtonValueKey: "false",
+				},
+			},
+		}
+		actions = []*model.PostAction{&actionTrue, &actionFalse}
+	}
+
+	text := fmt.Sprintf("%s\n%s", s.description, currentValueMessage)
+	sa := model.SlackAttachment{
+		Title:    title,
+		Text:     text,
+		Actions:  actions,
+		Fallback: fmt.Sprintf("%s: %s", title, text),
+	}
+
+	return &sa, nil
+}
+
+func (s *freeBusySetting) IsDisabled(foreignValue interface{}) bool {
+	return foreignValue == "false"
+}
@@ -21,7 +21,7 @@ type notificationSetting struct {
 
 func NewNotificationsSetting(getCal func(string) Engine) settingspanel.Setting {
 	return &notificationSetting{
-		title:       "Receive notifications of new events",
+		title:       "Event notifications",
 		description: "Do you want to subscribe to new events and receive a message when they are created?",
 		id:          "new_or_updated_event_setting",
 		dependsOn:   "",
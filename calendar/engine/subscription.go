@@ -6,6 +6,7 @@ package engine
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -20,6 +21,7 @@ type Subscriptions interface {
 	DeleteMyEventSubscription() error
 	ListRemoteSubscriptions() ([]*remote.Subscription, error)
 	LoadMyEventSubscription() (*store.Subscription, error)
+	ReconcileSubscriptions() (*SubscriptionReconcileSummary, error)
 }
 
 func (m *mscalendar) CreateMyEventSubscription() (*store.Subscription, error) {
@@ -28,7 +30,7 @@ func (m *mscalendar) CreateMyEventSubscription() (*store.Subscription, error) {
 		return nil, fmt.Errorf("error withClient in CreateMyEventSubscription: %w", err)
 	}
 
-	sub, err := m.client.CreateMySubscription(m.Config.GetNotificationURL(), m.actingUser.Remote.ID)
+	sub, err := m.client.CreateMySubscription(m.Config.GetNotificationURL(), m.actingUser.Remote.ID, m.notificationEncryption())
 	if err != nil {
 		return nil, err
 	}
@@ -37,6 +39,7 @@ func (m *mscalendar) CreateMyEventSubscription() (*store.Subscription, error) {
 		Remote:              sub,
 		MattermostCreatorID: m.actingUser.MattermostUserID,
 		PluginVersion:       m.Config.PluginVersion,
+		CreatedAt:           time.Now().Unix(),
 	}
 	err = m.Store.StoreUserSubscription(m.actingUser.User, storedSub)
 	if err != nil {
@@ -108,6 +111,10 @@ func (m *mscalendar) RenewMyEventSubscription() (*store.Subscription, error) {
 		return nil, err
 	}
 	storedSub.Remote = renewed
+	if storedSub.CreatedAt == 0 {
+		// The client state of the older subscriptions is rotated from now on.
+		storedSub.CreatedAt = time.Now().Unix()
+	}
 
 	err = m.Store.StoreUserSubscription(m.actingUser.User, storedSub)
 	if err != nil {
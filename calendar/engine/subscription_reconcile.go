@@ -0,0 +1,283 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const (
+	// subscriptionRenewWindow is how long before their expiration the
+	// subscriptions are renewed. It is longer than the interval of the
+	// reconciliation job, so that a subscription never expires between runs,
+	// and shorter than the lifetime of the subscriptions, so that they are not
+	// renewed on every run.
+	subscriptionRenewWindow = 36 * time.Hour
+
+	// subscriptionReconcileWorkers is the number of users reconciled at once.
+	subscriptionReconcileWorkers = 5
+
+	// clientStateRotationInterval is how long the subscriptions keep their
+	// client state, the secret the webhooks are validated with. The remote
+	// does not update it, so the subscriptions are replaced.
+	clientStateRotationInterval = 7 * 24 * time.Hour
+
+	// replacedSubscriptionGracePeriod is how long the notifications of a
+	// replaced subscription are still accepted, with its client state, so the
+	// ones queued or retried by the remote are not dropped as orphaned.
+	replacedSubscriptionGracePeriod = notificationDeliveryTTL
+)
+
+type SubscriptionReconcileSummary struct {
+	UsersProcessed int
+	Renewed        int
+	Recreated      int
+	Rotated        int
+	Upgraded       int
+	OrphansDeleted int
+	Failed         int
+}
+
+// needsAttention returns true if the admins are to be told about the run:
+// the renewals, rotations and recreations are routine and only logged.
+func (s *SubscriptionReconcileSummary) needsAttention() bool {
+	return s.Upgraded > 0 || s.OrphansDeleted > 0 || s.Failed > 0
+}
+
+func (s *SubscriptionReconcileSummary) String() string {
+	return fmt.Sprintf("Subscription reconciliation finished.\n* Users processed: %d\n* Subscriptions renewed: %d\n* Missing subscriptions recreated: %d\n* Client states rotated: %d\n* Subscriptions upgraded with lifecycle notifications: %d\n* Orphaned subscriptions deleted: %d\n* Users with errors: %d",
+		s.UsersProcessed, s.Renewed, s.Recreated, s.Rotated, s.Upgraded, s.OrphansDeleted, s.Failed)
+}
+
+// ReconcileSubscriptions compares the remote subscriptions of every connected
+// user with the stored ones. Missing subscriptions are created again, orphaned
+// ones deleted, the ones close to their expiration renewed, and the ones with
+// an old client state, or created before the lifecycle notifications were
+// subscribed to, replaced. The admins are sent the summary when orphaned
+// subscriptions were deleted, subscriptions upgraded, or users failed.
+func (m *mscalendar) ReconcileSubscriptions() (*SubscriptionReconcileSummary, error) {
+	uindex, err := m.Store.LoadUserIndex()
+	if err != nil {
+		if err == store.ErrNotFound {
+			return &SubscriptionReconcileSummary{}, nil
+		}
+		return nil, err
+	}
+
+	summary := &SubscriptionReconcileSummary{}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	users := make(chan *store.UserShort)
+	for i := 0; i < subscriptionReconcileWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range users {
+				result, err := m.reconcileUserSubscriptions(u.MattermostUserID, time.Now())
+				if err != nil {
+					m.Logger.With(bot.LogContext{
+						"mattermostUserID": u.MattermostUserID,
+						"err":              err.Error(),
+					}).Warnf("Error reconciling subscriptions.")
+				}
+
+				lock.Lock()
+				summary.UsersProcessed++
+				summary.Renewed += result.Renewed
+				summary.Recreated += result.Recreated
+				summary.Rotated += result.Rotated
+				summary.Upgraded += result.Upgraded
+				summary.OrphansDeleted += result.OrphansDeleted
+				if err != nil {
+					summary.Failed++
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, u := range uindex {
+		users <- u
+	}
+	close(users)
+	wg.Wait()
+
+	if summary.needsAttention() {
+		err = m.Poster.DMAdmins("%s", summary.String())
+		if err != nil {
+			m.Logger.Warnf("Error sending the subscriptions reconciliation summary to the admins. err=%v", err)
+		}
+	}
+	return summary, nil
+}
+
+func (m *mscalendar) reconcileUserSubscriptions(mattermostUserID string, now time.Time) (*SubscriptionReconcileSummary, error) {
+	result := &SubscriptionReconcileSummary{}
+	asUser := New(m.Env, mattermostUserID).(*mscalendar)
+	err := asUser.Filter(withClient)
+	if err != nil {
+		return result, err
+	}
+
+	subs, err := asUser.client.ListSubscriptions()
+	if err != nil {
+		return result, err
+	}
+
+	err = asUser.deletePreviousSubscription(now.Add(-replacedSubscriptionGracePeriod))
+	if err != nil {
+		return result, err
+	}
+
+	subscriptionID := asUser.actingUser.Settings.EventSubscriptionID
+	var current *remote.Subscription
+	for _, sub := range subs {
+		if sub.ID == subscriptionID {
+			current = sub
+			continue
+		}
+		// Subscriptions of other installations of the app are left alone.
+		if sub.NotificationURL != m.Config.GetNotificationURL() {
+			continue
+		}
+		err = asUser.DeleteOrphanedSubscription(&store.Subscription{Remote: sub})
+		if err != nil {
+			return result, err
+		}
+		result.OrphansDeleted++
+	}
+
+	if subscriptionID == "" {
+		return result, nil
+	}
+
+	stored, err := m.Store.LoadSubscription(subscriptionID)
+	if err != nil && err != store.ErrNotFound {
+		return result, err
+	}
+	if current == nil || err == store.ErrNotFound {
+		err = m.Store.DeleteUserSubscription(asUser.actingUser.User, subscriptionID)
+		if err != nil {
+			return result, err
+		}
+		_, err = asUser.CreateMyEventSubscription()
+		if err != nil {
+			return result, err
+		}
+		result.Recreated++
+		return result, nil
+	}
+
+	if isClientStateDue(stored, now) {
+		err = asUser.replaceSubscription(current, stored, now)
+		if err != nil {
+			return result, err
+		}
+		result.Rotated++
+		return result, nil
+	}
+
+	// The lifecycle notification URL cannot be updated, the subscriptions
+	// created before it was set are replaced.
+	if current.LifecycleNotificationURL == "" {
+		err = asUser.replaceSubscription(current, stored, now)
+		if err != nil {
+			return result, err
+		}
+		result.Upgraded++
+		return result, nil
+	}
+
+	expires, err := time.Parse(time.RFC3339, current.ExpirationDateTime)
+	if err == nil && expires.Sub(now) > subscriptionRenewWindow {
+		return result, nil
+	}
+	_, err = asUser.RenewMyEventSubscription()
+	if err != nil {
+		return result, err
+	}
+	result.Renewed++
+	return result, nil
+}
+
+// replaceSubscription creates a new subscription for the acting user, and
+// deletes the current one. The notifications of the replaced subscription are
+// still accepted for replacedSubscriptionGracePeriod.
+func (m *mscalendar) replaceSubscription(current *remote.Subscription, stored *store.Subscription, now time.Time) error {
+	err := m.deletePreviousSubscription(now)
+	if err != nil {
+		return err
+	}
+
+	m.actingUser.Settings.PreviousEventSubscriptionID = current.ID
+	_, err = m.CreateMyEventSubscription()
+	if err != nil {
+		m.actingUser.Settings.PreviousEventSubscriptionID = ""
+		return err
+	}
+	stored.ReplacedAt = now.Unix()
+	err = m.Store.StoreSubscription(stored)
+	if err != nil {
+		return err
+	}
+
+	err = m.client.DeleteSubscription(current)
+	if err != nil {
+		// Deleted as an orphan by the next reconciliation.
+		m.Logger.With(bot.LogContext{
+			"mattermostUserID": m.actingUser.MattermostUserID,
+			"subscriptionID":   current.ID,
+			"err":              err.Error(),
+		}).Warnf("Error deleting the replaced subscription.")
+	}
+	return nil
+}
+
+// deletePreviousSubscription deletes the subscription the acting user replaced
+// last if it was replaced before the given time.
+func (m *mscalendar) deletePreviousSubscription(replacedBefore time.Time) error {
+	previousID := m.actingUser.Settings.PreviousEventSubscriptionID
+	if previousID == "" {
+		return nil
+	}
+
+	previous, err := m.Store.LoadSubscription(previousID)
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	if err == nil {
+		if time.Unix(previous.ReplacedAt, 0).After(replacedBefore) {
+			return nil
+		}
+		err = m.Store.DeleteUserSubscription(nil, previousID)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.actingUser.Settings.PreviousEventSubscriptionID = ""
+	return m.Store.StoreUser(m.actingUser.User)
+}
+
+// isReplacedSubscriptionAccepted returns true if the subscription is the one
+// the user replaced last, less than replacedSubscriptionGracePeriod ago.
+func isReplacedSubscriptionAccepted(sub *store.Subscription, user *store.User, now time.Time) bool {
+	return sub.ReplacedAt != 0 &&
+		sub.Remote.ID == user.Settings.PreviousEventSubscriptionID &&
+		now.Sub(time.Unix(sub.ReplacedAt, 0)) < replacedSubscriptionGracePeriod
+}
+
+// isClientStateDue returns true if the client state of the subscription is
+// missing, or was created long enough ago to be replaced.
+func isClientStateDue(sub *store.Subscription, now time.Time) bool {
+	if sub.Remote.ClientState == "" {
+		return true
+	}
+	return sub.CreatedAt != 0 && now.Sub(time.Unix(sub.CreatedAt, 0)) >= clientStateRotationInterval
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
+)
+
+func TestReconcileUserSubscriptions(t *testing.T) {
+	now := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+	notificationURL := "https://mattermost.example.com/plugins/mscalendar" + config.FullPathEventNotification
+	newSub := func(id string, expires time.Time) *remote.Subscription {
+		return &remote.Subscription{
+			ID:                       id,
+			NotificationURL:          notificationURL,
+			LifecycleNotificationURL: "https://mattermost.example.com/plugins/mscalendar" + config.FullPathLifecycleNotification,
+			ExpirationDateTime:       expires.Format(time.RFC3339),
+		}
+	}
+	newStoredSub := func(createdAt time.Time, clientState string) *store.Subscription {
+		sub := GetMockSubscription()
+		sub.Remote.ClientState = clientState
+		sub.CreatedAt = createdAt.Unix()
+		return sub
+	}
+
+	for _, tc := range []struct {
+		name                   string
+		subscriptionID         string
+		previousSubscriptionID string
+		setupMock              func(*mock_store.MockStore, *mock_remote.MockClient, *mock_bot.MockLogger)
+		expected               *SubscriptionReconcileSummary
+		expectedErr            string
+	}{
+		{
+			name:           "subscription far from expiration",
+			subscriptionID: MockEventSubscriptionID,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{newSub(MockEventSubscriptionID, now.Add(60*time.Hour))}, nil).Times(1)
+				s.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(newStoredSub(now.Add(-24*time.Hour), "client_state"), nil).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{},
+		},
+		{
+			name:           "subscription close to expiration is renewed",
+			subscriptionID: MockEventSubscriptionID,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{newSub(MockEventSubscriptionID, now.Add(24*time.Hour))}, nil).Times(1)
+				s.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(newStoredSub(now.Add(-24*time.Hour), "client_state"), nil).Times(3)
+				c.EXPECT().RenewSubscription(notificationURL, MockRemoteUserID, gomock.Any()).Return(&remote.Subscription{ID: MockEventSubscriptionID}, nil).Times(1)
+				s.EXPECT().StoreUserSubscription(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{Renewed: 1},
+		},
+		{
+			name:           "missing subscription is recreated",
+			subscriptionID: MockEventSubscriptionID,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{}, nil).Times(1)
+				s.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(newStoredSub(now.Add(-24*time.Hour), "client_state"), nil).Times(1)
+				s.EXPECT().DeleteUserSubscription(gomock.Any(), MockEventSubscriptionID).Return(nil).Times(1)
+				c.EXPECT().CreateMySubscription(notificationURL, MockRemoteUserID, nil).Return(&remote.Subscription{ID: "newSubscriptionID"}, nil).Times(1)
+				s.EXPECT().StoreUserSubscription(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{Recreated: 1},
+		},
+		{
+			name:           "old client state is rotated",
+			subscriptionID: MockEventSubscriptionID,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				current := newSub(MockEventSubscriptionID, now.Add(60*time.Hour))
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{current}, nil).Times(1)
+				s.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(newStoredSub(now.Add(-8*24*time.Hour), "client_state"), nil).Times(1)
+				c.EXPECT().CreateMySubscription(notificationURL, MockRemoteUserID, nil).Return(&remote.Subscription{ID: "newSubscriptionID"}, nil).Times(1)
+				s.EXPECT().StoreUserSubscription(gomock.Any(), gomock.Any()).DoAndReturn(func(user *store.User, _ *store.Subscription) error {
+					require.Equal(t, MockEventSubscriptionID, user.Settings.PreviousEventSubscriptionID)
+					return nil
+				}).Times(1)
+				c.EXPECT().DeleteSubscription(current).Return(nil).Times(1)
+				s.EXPECT().StoreSubscription(gomock.Any()).DoAndReturn(func(sub *store.Subscription) error {
+					require.Equal(t, now.Unix(), sub.ReplacedAt)
+					return nil
+				}).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{Rotated: 1},
+		},
+		{
+			name:           "missing client state is rotated",
+			subscriptionID: MockEventSubscriptionID,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, logger *mock_bot.MockLogger) {
+				current := newSub(MockEventSubscriptionID, now.Add(60*time.Hour))
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{current}, nil).Times(1)
+				s.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(newStoredSub(time.Unix(0, 0), ""), nil).Times(1)
+				c.EXPECT().CreateMySubscription(notificationURL, MockRemoteUserID, nil).Return(&remote.Subscription{ID: "newSubscriptionID"}, nil).Times(1)
+				s.EXPECT().StoreUserSubscription(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				c.EXPECT().DeleteSubscription(current).Return(errors.New("error deleting the subscription")).Times(1)
+				logger.EXPECT().With(gomock.Any()).Return(logger).Times(1)
+				logger.EXPECT().Warnf("Error deleting the replaced subscription.").Times(1)
+				s.EXPECT().StoreSubscription(gomock.Any()).DoAndReturn(func(sub *store.Subscription) error {
+					require.Equal(t, now.Unix(), sub.ReplacedAt)
+					return nil
+				}).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{Rotated: 1},
+		},
+		{
+			name:           "subscription without lifecycle notifications is upgraded",
+			subscriptionID: MockEventSubscriptionID,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				current := newSub(MockEventSubscriptionID, now.Add(60*time.Hour))
+				current.LifecycleNotificationURL = ""
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{current}, nil).Times(1)
+				s.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(newStoredSub(now.Add(-24*time.Hour), "client_state"), nil).Times(1)
+				c.EXPECT().CreateMySubscription(notificationURL, MockRemoteUserID, nil).Return(&remote.Subscription{ID: "newSubscriptionID"}, nil).Times(1)
+				s.EXPECT().StoreUserSubscription(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				c.EXPECT().DeleteSubscription(current).Return(nil).Times(1)
+				s.EXPECT().StoreSubscription(gomock.Any()).DoAndReturn(func(sub *store.Subscription) error {
+					require.Equal(t, now.Unix(), sub.ReplacedAt)
+					return nil
+				}).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{Upgraded: 1},
+		},
+		{
+			name:           "orphaned subscriptions are deleted",
+			subscriptionID: MockEventSubscriptionID,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				orphan := newSub("orphanedSubscriptionID", now.Add(60*time.Hour))
+				other := newSub("otherInstallationSubscriptionID", now.Add(60*time.Hour))
+				other.NotificationURL = "https://other.example.com/plugins/mscalendar" + config.FullPathEventNotification
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{orphan, other, newSub(MockEventSubscriptionID, now.Add(60*time.Hour))}, nil).Times(1)
+				c.EXPECT().DeleteSubscription(orphan).Return(nil).Times(1)
+				s.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(newStoredSub(now.Add(-24*time.Hour), "client_state"), nil).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{OrphansDeleted: 1},
+		},
+		{
+			name:                   "replaced subscription is kept for the grace period",
+			subscriptionID:         MockEventSubscriptionID,
+			previousSubscriptionID: "previousSubscriptionID",
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{newSub(MockEventSubscriptionID, now.Add(60*time.Hour))}, nil).Times(1)
+				s.EXPECT().LoadSubscription("previousSubscriptionID").Return(&store.Subscription{ReplacedAt: now.Add(-time.Hour).Unix()}, nil).Times(1)
+				s.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(newStoredSub(now.Add(-24*time.Hour), "client_state"), nil).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{},
+		},
+		{
+			name:                   "replaced subscription is deleted after the grace period",
+			subscriptionID:         MockEventSubscriptionID,
+			previousSubscriptionID: "previousSubscriptionID",
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{newSub(MockEventSubscriptionID, now.Add(60*time.Hour))}, nil).Times(1)
+				s.EXPECT().LoadSubscription("previousSubscriptionID").Return(&store.Subscription{ReplacedAt: now.Add(-5 * time.Hour).Unix()}, nil).Times(1)
+				s.EXPECT().DeleteUserSubscription(nil, "previousSubscriptionID").Return(nil).Times(1)
+				s.EXPECT().StoreUser(gomock.Any()).DoAndReturn(func(user *store.User) error {
+					require.Empty(t, user.Settings.PreviousEventSubscriptionID)
+					return nil
+				}).Times(1)
+				s.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(newStoredSub(now.Add(-24*time.Hour), "client_state"), nil).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{},
+		},
+		{
+			name: "user without subscription",
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				c.EXPECT().ListSubscriptions().Return([]*remote.Subscription{}, nil).Times(1)
+			},
+			expected: &SubscriptionReconcileSummary{},
+		},
+		{
+			name:           "error listing the subscriptions",
+			subscriptionID: MockEventSubscriptionID,
+			setupMock: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockLogger) {
+				c.EXPECT().ListSubscriptions().Return(nil, errors.New("error listing the subscriptions")).Times(1)
+			},
+			expected:    &SubscriptionReconcileSummary{},
+			expectedErr: "error listing the subscriptions",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mscalendar, mockStore, _, mockRemote, mockPluginAPI, mockClient, mockLogger := GetMockSetup(t)
+			mscalendar.Config.PluginURL = "https://mattermost.example.com/plugins/mscalendar"
+
+			user := GetMockUser(model.NewString(MockRemoteUserID), nil, MockMMUserID, &store.Settings{EventSubscriptionID: tc.subscriptionID, PreviousEventSubscriptionID: tc.previousSubscriptionID})
+			mockStore.EXPECT().LoadUser(MockMMUserID).Return(user.User, nil).Times(1)
+			mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil).Times(1)
+			mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), MockMMUserID, gomock.Any(), gomock.Any()).Return(mockClient).Times(1)
+			tc.setupMock(mockStore, mockClient, mockLogger)
+
+			result, err := mscalendar.reconcileUserSubscriptions(MockMMUserID, now)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestSubscriptionReconcileSummaryNeedsAttention(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		summary  *SubscriptionReconcileSummary
+		expected bool
+	}{
+		{"nothing changed", &SubscriptionReconcileSummary{UsersProcessed: 3}, false},
+		{"routine changes", &SubscriptionReconcileSummary{UsersProcessed: 3, Renewed: 2, Rotated: 1, Recreated: 1}, false},
+		{"orphans deleted", &SubscriptionReconcileSummary{OrphansDeleted: 1}, true},
+		{"subscriptions upgraded", &SubscriptionReconcileSummary{Upgraded: 1}, true},
+		{"users failed", &SubscriptionReconcileSummary{Failed: 1}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.summary.needsAttention())
+		})
+	}
+}
@@ -41,7 +41,7 @@ func TestCreateMyEventSubscription(t *testing.T) {
 			setupMock: func() {
 				mscalendar.client = mockClient
 				mscalendar.actingUser = GetMockUser(model.NewString(MockActingUserRemoteID), nil, MockActingUserID, nil)
-				mockClient.EXPECT().CreateMySubscription(gomock.Any(), MockActingUserRemoteID).Return(nil, errors.New("error creating the subscription"))
+				mockClient.EXPECT().CreateMySubscription(gomock.Any(), MockActingUserRemoteID, nil).Return(nil, errors.New("error creating the subscription"))
 			},
 			assertion: func(sub *store.Subscription, err error) {
 				require.EqualError(t, err, "error creating the subscription")
@@ -52,11 +52,13 @@ func TestCreateMyEventSubscription(t *testing.T) {
 			setupMock: func() {
 				mscalendar.client = mockClient
 				mscalendar.actingUser = GetMockUser(model.NewString(MockActingUserRemoteID), nil, MockActingUserID, nil)
-				mockClient.EXPECT().CreateMySubscription(gomock.Any(), MockActingUserRemoteID).Return(&remote.Subscription{}, nil)
-				mockStore.EXPECT().StoreUserSubscription(mscalendar.actingUser.User, expectedSub)
+				mockClient.EXPECT().CreateMySubscription(gomock.Any(), MockActingUserRemoteID, nil).Return(&remote.Subscription{}, nil)
+				mockStore.EXPECT().StoreUserSubscription(mscalendar.actingUser.User, gomock.Any())
 			},
 			assertion: func(sub *store.Subscription, err error) {
 				require.NoError(t, err)
+				require.NotZero(t, sub.CreatedAt)
+				expectedSub.CreatedAt = sub.CreatedAt
 				require.Equal(t, expectedSub, sub)
 			},
 		},
@@ -260,7 +262,23 @@ func TestRenewMyEventSubscription(t *testing.T) {
 			},
 			assertion: func(subs *store.Subscription, err error) {
 				require.NoError(t, err)
-				require.Equal(t, &store.Subscription{Remote: &remote.Subscription{}}, subs)
+				require.Equal(t, &remote.Subscription{}, subs.Remote)
+				require.NotZero(t, subs.CreatedAt, "the creation time of older subscriptions is set")
+			},
+		},
+		{
+			name: "renewal keeps the creation time",
+			setupMock: func() {
+				mscalendar.client = mockClient
+				mscalendar.actingUser = GetMockUser(model.NewString(MockActingUserRemoteID), nil, MockActingUserID, nil)
+				mscalendar.actingUser.Settings.EventSubscriptionID = MockEventSubscriptionID
+				mockStore.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(&store.Subscription{Remote: &remote.Subscription{}, CreatedAt: 1000}, nil).Times(2)
+				mockClient.EXPECT().RenewSubscription(gomock.Any(), MockActingUserRemoteID, &remote.Subscription{}).Return(&remote.Subscription{}, nil).Times(1)
+				mockStore.EXPECT().StoreUserSubscription(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			assertion: func(subs *store.Subscription, err error) {
+				require.NoError(t, err)
+				require.Equal(t, &store.Subscription{Remote: &remote.Subscription{}, CreatedAt: 1000}, subs)
 			},
 		},
 	}
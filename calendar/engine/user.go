@@ -174,6 +174,14 @@ func (m *mscalendar) DisconnectUser(mattermostUserID string) error {
 		}
 	}
 
+	// The remote subscription replaced last is already deleted.
+	if previousID := storedUser.Settings.PreviousEventSubscriptionID; previousID != "" {
+		err = m.Store.DeleteUserSubscription(nil, previousID)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to delete subscription %s", previousID)
+		}
+	}
+
 	err = m.Store.DeleteUser(mattermostUserID)
 	if err != nil {
 		return err
@@ -184,6 +192,18 @@ func (m *mscalendar) DisconnectUser(mattermostUserID string) error {
 		return err
 	}
 
+	err = m.Store.DeleteFreeBusyToken(mattermostUserID)
+	if err != nil {
+		return err
+	}
+
+	if storedUser.Remote != nil {
+		err = m.Store.DeleteCalendarCache(storedUser.Remote.ID)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
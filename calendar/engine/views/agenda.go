@@ -0,0 +1,109 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package views
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+// RenderAgendaView renders the events between from and to grouped per day,
+// with the meeting time of each day and of the whole range. Overlapping
+// events are marked and listed at the end.
+func RenderAgendaView(events []*remote.Event, timeZone string, from, to time.Time) (string, error) {
+	title := fmt.Sprintf("Agenda from %s to %s", from.Format("Monday January 02"), to.Add(-time.Nanosecond).Format("Monday January 02, 2006"))
+
+	active := []*remote.Event{}
+	for _, e := range events {
+		if !e.IsCancelled {
+			active = append(active, e)
+		}
+	}
+	if len(active) == 0 {
+		return title + "\n\nYou have no events in this period.", nil
+	}
+
+	if timeZone != "" {
+		for _, e := range active {
+			e.Start = e.Start.In(timeZone)
+			e.End = e.End.In(timeZone)
+		}
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].Start.Time().Before(active[j].Start.Time())
+	})
+
+	conflicts := FindConflicts(active)
+	conflicting := conflictingSet(conflicts)
+
+	resp := title + "\nTimes are shown in " + active[0].Start.TimeZone
+	var total time.Duration
+	for _, group := range groupEventsByDate(active) {
+		busy := busyDuration(group)
+		total += busy
+		resp += fmt.Sprintf("\n#### %s\n%d event(s), %s in meetings\n\n", group[0].Start.Time().Format("Monday January 02"), len(group), renderDuration(busy))
+		resp += renderTableHeader()
+		for _, e := range group {
+			eventString, err := renderEvent(e, true, timeZone)
+			if err != nil {
+				return "", err
+			}
+			if conflicting[e] {
+				eventString = markConflictRow(eventString)
+			}
+			resp += fmt.Sprintf("\n%s", eventString)
+		}
+	}
+
+	resp += fmt.Sprintf("\n\n**Total meeting time:** %s", renderDuration(total))
+	if len(conflicts) > 0 {
+		resp += fmt.Sprintf("\n**Conflicts:** %d", len(conflicts))
+		for _, c := range conflicts {
+			resp += fmt.Sprintf("\n- %s: %s overlaps with %s",
+				c[1].Start.Time().Format("Monday 3:04PM"),
+				MarkdownToHTMLEntities(EnsureSubject(c[0].Subject)),
+				MarkdownToHTMLEntities(EnsureSubject(c[1].Subject)))
+		}
+	}
+
+	return resp, nil
+}
+
+// busyDuration returns the time covered by the events of a day, counting
+// overlapping events once. Events must be sorted by start time.
+func busyDuration(events []*remote.Event) time.Duration {
+	var total time.Duration
+	var end time.Time
+	for _, e := range events {
+		if !blocksTime(e) {
+			continue
+		}
+		start, eEnd := e.Start.Time(), e.End.Time()
+		if start.Before(end) {
+			start = end
+		}
+		if eEnd.After(start) {
+			total += eEnd.Sub(start)
+			end = eEnd
+		}
+	}
+	return total
+}
+
+func renderDuration(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+}
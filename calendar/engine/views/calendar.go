@@ -58,6 +58,9 @@ func RenderCalendarView(events []*remote.Event, timeZone string) (string, error)
 		return events[i].Start.Time().Before(events[j].Start.Time())
 	})
 
+	conflicts := FindConflicts(events)
+	conflicting := conflictingSet(conflicts)
+
 	resp := "Times are shown in " + events[0].Start.TimeZone
 	for _, group := range groupEventsByDate(events) {
 		resp += "\n" + group[0].Start.Time().Format("Monday January 02, 2006") + "\n\n"
@@ -67,10 +70,17 @@ func RenderCalendarView(events []*remote.Event, timeZone string) (string, error)
 			if err != nil {
 				return "", err
 			}
+			if conflicting[e] {
+				eventString = markConflictRow(eventString)
+			}
 			resp += fmt.Sprintf("\n%s", eventString)
 		}
 	}
 
+	if len(conflicts) > 0 {
+		resp += fmt.Sprintf("\n\n%sYou have %d conflicting event(s).", conflictMarker, len(conflicts))
+	}
+
 	return resp, nil
 }
 
@@ -88,6 +98,11 @@ func RenderDaySummary(events []*remote.Event, timezone string) (string, []*model
 
 	message := fmt.Sprintf("Agenda for %s.\nTimes are shown in %s", events[0].Start.Time().Format("Monday, 02 January"), events[0].Start.TimeZone)
 
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Time().Before(events[j].Start.Time())
+	})
+	conflicting := conflictingSet(FindConflicts(events))
+
 	var attachments []*model.SlackAttachment
 	for _, event := range events {
 		var actions []*model.PostAction
@@ -100,6 +115,13 @@ func RenderDaySummary(events []*remote.Event, timezone string) (string, []*model
 				Short: true,
 			})
 		}
+		if conflicting[event] {
+			fields = append(fields, &model.SlackAttachmentField{
+				Title: "Conflict",
+				Value: conflictMarker + "Overlaps with another event",
+				Short: true,
+			})
+		}
 
 		attachments = append(attachments, &model.SlackAttachment{
 			Title: event.Subject,
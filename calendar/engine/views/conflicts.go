@@ -0,0 +1,85 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package views
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const conflictMarker = ":warning: "
+
+// FindConflicts returns the pairs of events whose times overlap. Only events
+// the user attends (accepted, tentative or organized) that block time can
+// conflict. Events must be sorted by start time.
+func FindConflicts(events []*remote.Event) [][2]*remote.Event {
+	conflicts := [][2]*remote.Event{}
+	for i, a := range events {
+		if !blocksTime(a) {
+			continue
+		}
+		aEnd := a.End.Time()
+		for _, b := range events[i+1:] {
+			if !b.Start.Time().Before(aEnd) {
+				break
+			}
+			if !blocksTime(b) {
+				continue
+			}
+			conflicts = append(conflicts, [2]*remote.Event{a, b})
+		}
+	}
+	return conflicts
+}
+
+// FindConflictsWith returns the accepted or organized events that overlap
+// with event. It is used for invitations, where a tentative event already in
+// the calendar is not considered a commitment.
+func FindConflictsWith(event *remote.Event, events []*remote.Event) []*remote.Event {
+	conflicts := []*remote.Event{}
+	if event.Start == nil || event.End == nil || event.IsAllDay || event.IsCancelled {
+		return conflicts
+	}
+
+	start, end := event.Start.Time(), event.End.Time()
+	for _, e := range events {
+		if e.ID == event.ID || (e.ICalUID != "" && e.ICalUID == event.ICalUID) {
+			continue
+		}
+		if !blocksTime(e) || !isAccepted(e) {
+			continue
+		}
+		if e.Start.Time().Before(end) && start.Before(e.End.Time()) {
+			conflicts = append(conflicts, e)
+		}
+	}
+	return conflicts
+}
+
+func blocksTime(e *remote.Event) bool {
+	if e.Start == nil || e.End == nil || e.IsAllDay || e.IsCancelled || e.ShowAs == remote.ScheduleStatusFree {
+		return false
+	}
+	return e.ResponseStatus == nil || e.ResponseStatus.Response != remote.EventResponseStatusDeclined
+}
+
+func isAccepted(e *remote.Event) bool {
+	return e.IsOrganizer || e.ResponseStatus == nil || e.ResponseStatus.Response == remote.EventResponseStatusAccepted
+}
+
+func conflictingSet(conflicts [][2]*remote.Event) map[*remote.Event]bool {
+	set := map[*remote.Event]bool{}
+	for _, c := range conflicts {
+		set[c[0]] = true
+		set[c[1]] = true
+	}
+	return set
+}
+
+// markConflictRow prefixes the first cell of a table row with the conflict
+// marker.
+func markConflictRow(row string) string {
+	return "| " + conflictMarker + strings.TrimPrefix(row, "| ")
+}
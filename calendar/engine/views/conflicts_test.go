@@ -0,0 +1,97 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func conflictTestEvent(id string, startHour, endHour int, response string) *remote.Event {
+	day := time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC)
+	return &remote.Event{
+		ID:             id,
+		Subject:        id,
+		Start:          remote.NewDateTime(day.Add(time.Duration(startHour)*time.Hour), "UTC"),
+		End:            remote.NewDateTime(day.Add(time.Duration(endHour)*time.Hour), "UTC"),
+		ResponseStatus: &remote.EventResponseStatus{Response: response},
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	a := conflictTestEvent("a", 9, 11, remote.EventResponseStatusAccepted)
+	b := conflictTestEvent("b", 10, 12, remote.EventResponseStatusTentative)
+	c := conflictTestEvent("c", 11, 12, remote.EventResponseStatusAccepted)
+	declined := conflictTestEvent("declined", 9, 10, remote.EventResponseStatusDeclined)
+	free := conflictTestEvent("free", 9, 10, remote.EventResponseStatusAccepted)
+	free.ShowAs = remote.ScheduleStatusFree
+	later := conflictTestEvent("later", 13, 14, remote.EventResponseStatusAccepted)
+
+	for _, tc := range []struct {
+		name     string
+		events   []*remote.Event
+		expected [][2]*remote.Event
+	}{
+		{
+			name:     "no events",
+			expected: [][2]*remote.Event{},
+		},
+		{
+			name:     "back to back events do not conflict",
+			events:   []*remote.Event{a, c, later},
+			expected: [][2]*remote.Event{},
+		},
+		{
+			name:     "overlapping events",
+			events:   []*remote.Event{a, b, c},
+			expected: [][2]*remote.Event{{a, b}, {b, c}},
+		},
+		{
+			name:     "declined and free events are ignored",
+			events:   []*remote.Event{declined, free, a},
+			expected: [][2]*remote.Event{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, FindConflicts(tc.events))
+		})
+	}
+}
+
+func TestFindConflictsWith(t *testing.T) {
+	invitation := conflictTestEvent("invitation", 10, 11, remote.EventResponseStatusNotAnswered)
+	accepted := conflictTestEvent("accepted", 9, 11, remote.EventResponseStatusAccepted)
+	tentative := conflictTestEvent("tentative", 10, 11, remote.EventResponseStatusTentative)
+	organized := conflictTestEvent("organized", 10, 12, remote.EventResponseStatusNotAnswered)
+	organized.IsOrganizer = true
+	before := conflictTestEvent("before", 9, 10, remote.EventResponseStatusAccepted)
+
+	conflicts := FindConflictsWith(invitation, []*remote.Event{invitation, accepted, tentative, organized, before})
+	require.Equal(t, []*remote.Event{accepted, organized}, conflicts)
+
+	allDay := conflictTestEvent("all-day", 0, 24, remote.EventResponseStatusNotAnswered)
+	allDay.IsAllDay = true
+	require.Empty(t, FindConflictsWith(allDay, []*remote.Event{accepted}))
+}
+
+func TestRenderCalendarViewConflicts(t *testing.T) {
+	a := conflictTestEvent("a", 9, 11, remote.EventResponseStatusAccepted)
+	b := conflictTestEvent("b", 10, 12, remote.EventResponseStatusAccepted)
+	later := conflictTestEvent("later", 13, 14, remote.EventResponseStatusAccepted)
+
+	out, err := RenderCalendarView([]*remote.Event{a, b, later}, "UTC")
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(out, conflictMarker+"9:00AM")+strings.Count(out, conflictMarker+"10:00AM"))
+	require.NotContains(t, out, conflictMarker+"1:00PM")
+	require.Contains(t, out, "You have 1 conflicting event(s).")
+
+	out, err = RenderCalendarView([]*remote.Event{a, later}, "UTC")
+	require.NoError(t, err)
+	require.NotContains(t, out, conflictMarker)
+}
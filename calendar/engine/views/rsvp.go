@@ -0,0 +1,95 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+// RSVPGroup is the set of attendees of an event that gave the same response.
+type RSVPGroup struct {
+	Title     string
+	Attendees []*remote.Attendee
+}
+
+// RenderRSVPDigest renders the attendees of an event grouped by response. The
+// nudge action is only added when it is not nil.
+func RenderRSVPDigest(event *remote.Event, timezone string, groups []*RSVPGroup, nudge *model.PostAction) *model.SlackAttachment {
+	sa := &model.SlackAttachment{
+		Title:     EnsureSubject(event.Subject),
+		TitleLink: event.Weblink,
+		Text:      fmt.Sprintf("%s\n%s", renderEventTime(event, timezone), renderRSVPCounts(groups)),
+		Fallback:  fmt.Sprintf("Responses for %s: %s", EnsureSubject(event.Subject), renderRSVPCounts(groups)),
+	}
+
+	for _, group := range groups {
+		value := "None"
+		if len(group.Attendees) > 0 {
+			names := []string{}
+			for _, a := range group.Attendees {
+				names = append(names, renderAttendee(a))
+			}
+			value = strings.Join(names, ", ")
+		}
+		sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
+			Title: fmt.Sprintf("%s (%d)", group.Title, len(group.Attendees)),
+			Value: value,
+			Short: false,
+		})
+	}
+
+	if nudge != nil {
+		sa.Actions = []*model.PostAction{nudge}
+	}
+
+	return sa
+}
+
+// RenderRSVPList renders one line per event with the number of attendees for
+// each response.
+func RenderRSVPList(events []*remote.Event, timezone string, groups [][]*RSVPGroup) string {
+	if len(events) == 0 {
+		return "You are not organizing any upcoming meetings."
+	}
+
+	lines := []string{"Upcoming meetings you organize:"}
+	for i, e := range events {
+		lines = append(lines, fmt.Sprintf("- [%s](%s) %s: %s",
+			MarkdownToHTMLEntities(EnsureSubject(e.Subject)),
+			e.Weblink,
+			renderEventTime(e, timezone),
+			renderRSVPCounts(groups[i])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderEventTime(event *remote.Event, timezone string) string {
+	start := event.Start.In(timezone).Time()
+	end := event.End.In(timezone).Time()
+	return fmt.Sprintf("%s - %s", start.Format("Mon Jan 2, 3:04PM"), end.Format("3:04PM"))
+}
+
+func renderRSVPCounts(groups []*RSVPGroup) string {
+	counts := []string{}
+	for _, group := range groups {
+		counts = append(counts, fmt.Sprintf("%d %s", len(group.Attendees), strings.ToLower(group.Title)))
+	}
+	return strings.Join(counts, ", ")
+}
+
+func renderAttendee(a *remote.Attendee) string {
+	if a.EmailAddress == nil {
+		return "Unknown"
+	}
+	name := a.EmailAddress.Name
+	if name == "" {
+		name = a.EmailAddress.Address
+	}
+	return fmt.Sprintf("[%s](mailto:%s)", MarkdownToHTMLEntities(name), a.EmailAddress.Address)
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const (
+	WeeklySummaryDays = 7
+	AgendaMaxDays     = 62
+)
+
+type WeeklySummary interface {
+	GetAgendaForUser(user *User, from, to time.Time) (string, error)
+	GetWeeklySummarySettingsForUser(user *User) (*store.WeeklySummaryUserSettings, error)
+	SetWeeklySummaryPostTime(user *User, day, timeStr string) (*store.WeeklySummaryUserSettings, error)
+	SetWeeklySummaryEnabled(user *User, enable bool) (*store.WeeklySummaryUserSettings, error)
+	ProcessAllWeeklySummary(now time.Time) error
+}
+
+// GetAgendaForUser renders the user's events between from and to, grouped
+// per day, with meeting totals and conflicts.
+func (m *mscalendar) GetAgendaForUser(user *User, from, to time.Time) (string, error) {
+	if !to.After(from) {
+		return "", errors.New("end date must be after start date")
+	}
+	if to.Sub(from) > AgendaMaxDays*24*time.Hour {
+		return "", fmt.Errorf("the agenda cannot cover more than %d days", AgendaMaxDays)
+	}
+
+	timezone, err := m.GetTimezone(user)
+	if err != nil {
+		return "", err
+	}
+
+	events, err := m.ViewCalendar(user, from, to)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get calendar events")
+	}
+
+	messageString, err := views.RenderAgendaView(m.excludeDeclinedEvents(events), timezone, from, to)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to render agenda")
+	}
+
+	return messageString, nil
+}
+
+func (m *mscalendar) GetWeeklySummarySettingsForUser(user *User) (*store.WeeklySummaryUserSettings, error) {
+	err := m.Filter(withUserExpanded(user))
+	if err != nil {
+		return nil, err
+	}
+
+	return user.Settings.WeeklySummary, nil
+}
+
+func (m *mscalendar) SetWeeklySummaryPostTime(user *User, day, timeStr string) (*store.WeeklySummaryUserSettings, error) {
+	err := m.Filter(withUserExpanded(user))
+	if err != nil {
+		return nil, err
+	}
+
+	weekday, err := ParseWeekday(day)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := time.Parse(time.Kitchen, timeStr)
+	if err != nil {
+		return nil, errors.New("Invalid time value: " + timeStr)
+	}
+
+	if t.Minute()%int(DailySummaryJobInterval/time.Minute) != 0 {
+		return nil, fmt.Errorf("time must be a multiple of %d minutes", DailySummaryJobInterval/time.Minute)
+	}
+
+	timezone, err := m.GetTimezone(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Settings.WeeklySummary == nil {
+		user.Settings.WeeklySummary = store.DefaultWeeklySummaryUserSettings()
+	}
+
+	wsum := user.Settings.WeeklySummary
+	wsum.Day = weekday.String()
+	wsum.PostTime = timeStr
+	wsum.Timezone = timezone
+
+	err = m.Store.StoreUser(user.User)
+	if err != nil {
+		return nil, err
+	}
+	return wsum, nil
+}
+
+func (m *mscalendar) SetWeeklySummaryEnabled(user *User, enable bool) (*store.WeeklySummaryUserSettings, error) {
+	err := m.Filter(withUserExpanded(user))
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Settings.WeeklySummary == nil {
+		user.Settings.WeeklySummary = store.DefaultWeeklySummaryUserSettings()
+	}
+
+	wsum := user.Settings.WeeklySummary
+	wsum.Enable = enable
+
+	err = m.Store.StoreUser(user.User)
+	if err != nil {
+		return nil, err
+	}
+	return wsum, nil
+}
+
+// ProcessAllWeeklySummary posts the weekly summary to the users whose
+// configured day and time match now. It runs as part of the daily summary
+// job, so it shares its interval.
+func (m *mscalendar) ProcessAllWeeklySummary(now time.Time) error {
+	userIndex, err := m.Store.LoadUserIndex()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, user := range userIndex {
+		storeUser, storeErr := m.Store.LoadUser(user.MattermostUserID)
+		if storeErr != nil {
+			m.Logger.Warnf("Error loading user %s for weekly summary. err=%v", user.MattermostUserID, storeErr)
+			continue
+		}
+
+		wsum := storeUser.Settings.WeeklySummary
+		shouldPost, shouldPostErr := shouldPostWeeklySummary(wsum, now)
+		if shouldPostErr != nil {
+			m.Logger.With(bot.LogContext{"mm_user_id": storeUser.MattermostUserID, "now": now.String(), "err": shouldPostErr}).Warnf("Error checking weekly summary should be posted")
+			continue
+		}
+		if !shouldPost {
+			continue
+		}
+
+		engine, err := m.FilterCopy(withActingUser(storeUser.MattermostUserID))
+		if err != nil {
+			m.Logger.Errorf("Error creating user engine %s. err=%v", storeUser.MattermostUserID, err)
+			continue
+		}
+
+		from, to := getWeekHoursForTimezone(now, wsum.Timezone)
+		postStr, err := engine.GetAgendaForUser(NewUser(storeUser.MattermostUserID), from, to)
+		if err != nil {
+			m.Logger.With(bot.LogContext{
+				"mm_user_id": storeUser.MattermostUserID,
+				"now":        now.String(),
+				"err":        err,
+			}).Errorf("Error rendering weekly summary for user")
+			continue
+		}
+
+		_, err = m.Poster.DM(storeUser.MattermostUserID, "### Your week ahead\n%s", postStr)
+		if err != nil {
+			m.Logger.With(bot.LogContext{
+				"mm_user_id": storeUser.MattermostUserID,
+				"now":        now.String(),
+				"err":        err,
+			}).Errorf("Error posting weekly summary for user")
+			continue
+		}
+
+		wsum.LastPostTime = time.Now().Format(time.RFC3339)
+		err = m.Store.StoreUser(storeUser)
+		if err != nil {
+			m.Logger.Warnf("Error storing weekly summary LastPostTime for user %s. err=%v", storeUser.MattermostUserID, err)
+		}
+		count++
+	}
+
+	m.Logger.Infof("Processed weekly summary for %d users", count)
+	return nil
+}
+
+// ParseWeekday parses a full or abbreviated weekday name, case insensitive.
+func ParseWeekday(day string) (time.Weekday, error) {
+	lower := strings.ToLower(day)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if lower == name || (len(lower) >= 3 && strings.HasPrefix(name, lower)) {
+			return d, nil
+		}
+	}
+	return time.Sunday, errors.New("Invalid day value: " + day)
+}
+
+func shouldPostWeeklySummary(wsum *store.WeeklySummaryUserSettings, now time.Time) (bool, error) {
+	if wsum == nil || !wsum.Enable {
+		return false, nil
+	}
+
+	lastPostStr := wsum.LastPostTime
+	if lastPostStr != "" {
+		lastPost, err := time.Parse(time.RFC3339, lastPostStr)
+		if err != nil {
+			return false, errors.New("Failed to parse last post time: " + lastPostStr)
+		}
+		since := now.Sub(lastPost)
+		if since < dailySummaryTimeWindow {
+			return false, nil
+		}
+	}
+
+	weekday, err := ParseWeekday(wsum.Day)
+	if err != nil {
+		return false, err
+	}
+
+	timezone := tz.Go(wsum.Timezone)
+	if timezone == "" {
+		return false, errors.New("invalid timezone")
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return false, err
+	}
+	t, err := time.ParseInLocation(time.Kitchen, wsum.PostTime, loc)
+	if err != nil {
+		return false, err
+	}
+
+	now = now.In(loc)
+	if now.Weekday() != weekday {
+		return false, nil
+	}
+
+	t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	diff := now.Sub(t)
+	if diff >= 0 {
+		return diff < dailySummaryTimeWindow, nil
+	}
+	return -diff < dailySummaryTimeWindow, nil
+}
+
+func getWeekHoursForTimezone(now time.Time, timezone string) (start, end time.Time) {
+	start, _ = getTodayHoursForTimezone(now, timezone)
+	end = start.AddDate(0, 0, WeeklySummaryDays)
+	return start, end
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestGetAgendaForUser(t *testing.T) {
+	mscalendar, _, _, _, _, mockClient, _ := GetMockSetup(t)
+	user := GetMockUser(model.NewString(MockRemoteUserID), model.NewString(MockMMModelUserID), MockMMUserID, nil)
+	from := time.Date(2020, 2, 10, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, WeeklySummaryDays)
+
+	t.Run("range too long", func(t *testing.T) {
+		_, err := mscalendar.GetAgendaForUser(user, from, from.AddDate(0, 0, AgendaMaxDays+1))
+		require.EqualError(t, err, "the agenda cannot cover more than 62 days")
+	})
+
+	t.Run("events grouped per day with totals and conflicts", func(t *testing.T) {
+		mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+		mockClient.EXPECT().GetDefaultCalendarView(MockRemoteUserID, from, to).Return([]*remote.Event{
+			{
+				Subject: "Standup",
+				Start:   remote.NewDateTime(from.Add(9*time.Hour), "UTC"),
+				End:     remote.NewDateTime(from.Add(10*time.Hour), "UTC"),
+			},
+			{
+				Subject: "Review",
+				Start:   remote.NewDateTime(from.Add(9*time.Hour+30*time.Minute), "UTC"),
+				End:     remote.NewDateTime(from.Add(11*time.Hour), "UTC"),
+			},
+			{
+				Subject: "Declined",
+				Start:   remote.NewDateTime(from.Add(10*time.Hour), "UTC"),
+				End:     remote.NewDateTime(from.Add(12*time.Hour), "UTC"),
+				ResponseStatus: &remote.EventResponseStatus{
+					Response: remote.EventResponseStatusDeclined,
+				},
+			},
+			{
+				Subject: "Planning",
+				Start:   remote.NewDateTime(from.Add(26*time.Hour), "UTC"),
+				End:     remote.NewDateTime(from.Add(26*time.Hour+45*time.Minute), "UTC"),
+			},
+		}, nil).Times(1)
+
+		result, err := mscalendar.GetAgendaForUser(user, from, to)
+		require.NoError(t, err)
+		require.Equal(t, `Agenda from Monday February 10 to Sunday February 16, 2020
+Times are shown in UTC
+#### Monday February 10
+2 event(s), 2h in meetings
+
+| Time | Subject |
+| :-- | :-- |
+| :warning: 9:00AM - 10:00AM | [Standup]() |
+| :warning: 9:30AM - 11:00AM | [Review]() |
+#### Tuesday February 11
+1 event(s), 45m in meetings
+
+| Time | Subject |
+| :-- | :-- |
+| 2:00AM - 2:45AM | [Planning]() |
+
+**Total meeting time:** 2h 45m
+**Conflicts:** 1
+- Monday 9:30AM: Standup overlaps with Review`, result)
+	})
+}
+
+func TestShouldPostWeeklySummary(t *testing.T) {
+	loc, err := time.LoadLocation("EST")
+	require.Nil(t, err)
+	moment := makeTime(9, 0, loc) // Wednesday 9:00AM
+
+	tests := []struct {
+		name        string
+		wsum        *store.WeeklySummaryUserSettings
+		shouldRun   bool
+		shouldError bool
+	}{
+		{
+			name: "Not configured",
+		},
+		{
+			name: "Disabled",
+			wsum: &store.WeeklySummaryUserSettings{Day: "Wednesday", PostTime: "9:00AM", Timezone: "Eastern Standard Time"},
+		},
+		{
+			name: "Right time, wrong day",
+			wsum: &store.WeeklySummaryUserSettings{Enable: true, Day: "Monday", PostTime: "9:00AM", Timezone: "Eastern Standard Time"},
+		},
+		{
+			name: "Right day, wrong time",
+			wsum: &store.WeeklySummaryUserSettings{Enable: true, Day: "Wednesday", PostTime: "8:00AM", Timezone: "Eastern Standard Time"},
+		},
+		{
+			name:      "Right day and time",
+			wsum:      &store.WeeklySummaryUserSettings{Enable: true, Day: "Wednesday", PostTime: "9:00AM", Timezone: "Eastern Standard Time"},
+			shouldRun: true,
+		},
+		{
+			name:      "Different timezone, right day and time",
+			wsum:      &store.WeeklySummaryUserSettings{Enable: true, Day: "Wednesday", PostTime: "7:00AM", Timezone: "Mountain Standard Time"},
+			shouldRun: true,
+		},
+		{
+			name: "Already posted",
+			wsum: &store.WeeklySummaryUserSettings{Enable: true, Day: "Wednesday", PostTime: "9:00AM", Timezone: "Eastern Standard Time", LastPostTime: moment.Add(-time.Minute).Format(time.RFC3339)},
+		},
+		{
+			name:        "Invalid day",
+			wsum:        &store.WeeklySummaryUserSettings{Enable: true, Day: "Someday", PostTime: "9:00AM", Timezone: "Eastern Standard Time"},
+			shouldError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			shouldRun, err := shouldPostWeeklySummary(tc.wsum, moment)
+			require.Equal(t, tc.shouldRun, shouldRun)
+			if tc.shouldError {
+				require.NotNil(t, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestProcessAllWeeklySummary(t *testing.T) {
+	mscalendar, mockStore, mockPoster, mockRemote, mockPluginAPI, mockClient, mockLogger := GetMockSetup(t)
+	loc, err := time.LoadLocation("EST")
+	require.Nil(t, err)
+	moment := makeTime(9, 0, loc) // Wednesday 9:00AM
+	mscalendar.actingUser = NewUser("")
+
+	storeUser := &store.User{
+		MattermostUserID: MockMMUserID,
+		Remote:           &remote.User{ID: MockRemoteUserID},
+		Settings: store.Settings{
+			WeeklySummary: &store.WeeklySummaryUserSettings{Enable: true, Day: "Wednesday", PostTime: "9:00AM", Timezone: "Eastern Standard Time"},
+		},
+	}
+	mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{{MattermostUserID: MockMMUserID}}, nil).Times(1)
+	mockStore.EXPECT().LoadUser(MockMMUserID).Return(storeUser, nil).AnyTimes()
+	mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil).AnyTimes()
+	mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient).AnyTimes()
+	mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "Eastern Standard Time"}, nil).Times(1)
+	mockClient.EXPECT().GetDefaultCalendarView(MockRemoteUserID, gomock.Any(), gomock.Any()).Return([]*remote.Event{}, nil).Times(1)
+	mockPoster.EXPECT().DM(MockMMUserID, "### Your week ahead\n%s", gomock.Any()).Return("postID", nil).Times(1)
+	mockStore.EXPECT().StoreUser(storeUser).Return(nil).Times(1)
+	mockLogger.EXPECT().Infof("Processed weekly summary for %d users", 1).Times(1)
+
+	err = mscalendar.ProcessAllWeeklySummary(moment)
+	require.NoError(t, err)
+	require.NotEmpty(t, storeUser.Settings.WeeklySummary.LastPostTime)
+}
+
+func TestProcessAllWeeklySummaryPostError(t *testing.T) {
+	mscalendar, mockStore, mockPoster, mockRemote, mockPluginAPI, mockClient, mockLogger := GetMockSetup(t)
+	loc, err := time.LoadLocation("EST")
+	require.Nil(t, err)
+	moment := makeTime(9, 0, loc) // Wednesday 9:00AM
+	mscalendar.actingUser = NewUser("")
+
+	storeUser := &store.User{
+		MattermostUserID: MockMMUserID,
+		Remote:           &remote.User{ID: MockRemoteUserID},
+		Settings: store.Settings{
+			WeeklySummary: &store.WeeklySummaryUserSettings{Enable: true, Day: "Wednesday", PostTime: "9:00AM", Timezone: "Eastern Standard Time"},
+		},
+	}
+	mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{{MattermostUserID: MockMMUserID}}, nil).Times(1)
+	mockStore.EXPECT().LoadUser(MockMMUserID).Return(storeUser, nil).AnyTimes()
+	mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil).AnyTimes()
+	mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient).AnyTimes()
+	mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "Eastern Standard Time"}, nil).Times(1)
+	mockClient.EXPECT().GetDefaultCalendarView(MockRemoteUserID, gomock.Any(), gomock.Any()).Return([]*remote.Event{}, nil).Times(1)
+	mockPoster.EXPECT().DM(MockMMUserID, "### Your week ahead\n%s", gomock.Any()).Return("", errors.New("some error")).Times(1)
+	mockLogger.EXPECT().With(gomock.Any()).Return(mockLogger).Times(1)
+	mockLogger.EXPECT().Errorf("Error posting weekly summary for user").Times(1)
+	mockStore.EXPECT().StoreUser(gomock.Any()).Times(0)
+	mockLogger.EXPECT().Infof("Processed weekly summary for %d users", 0).Times(1)
+
+	err = mscalendar.ProcessAllWeeklySummary(moment)
+	require.NoError(t, err)
+	require.Empty(t, storeUser.Settings.WeeklySummary.LastPostTime)
+}
+
+func TestParseWeekday(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected time.Weekday
+		valid    bool
+	}{
+		{"Monday", time.Monday, true},
+		{"friday", time.Friday, true},
+		{"Sat", time.Saturday, true},
+		{"su", time.Sunday, false},
+		{"Funday", time.Sunday, false},
+	} {
+		day, err := ParseWeekday(tc.value)
+		if tc.valid {
+			require.NoError(t, err, tc.value)
+			require.Equal(t, tc.expected, day)
+		} else {
+			require.Error(t, err, tc.value)
+		}
+	}
+}
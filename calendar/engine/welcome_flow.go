@@ -95,7 +95,7 @@ func (wf *WelcomeFlow) makeSteps() {
 	if wf.providerFeatures.EventNotifications {
 		steps = append(steps, &flow.SimpleStep{
 			Title:                "Subscribe to events",
-			Message:              "Do you want to receive notifications when you are invited to an event?",
+			Message:              "You are subscribed to notifications when you are invited to an event. Do you want to keep receiving them?",
 			PropertyName:         store.SubscribePropertyName,
 			TrueButtonMessage:    "Yes - I would like to receive notifications for new events",
 			FalseButtonMessage:   "No - Do not notify me of new events",
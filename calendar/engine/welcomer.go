@@ -161,17 +161,25 @@ func (bot *mscBot) cleanWelcomePost(mattermostUserID string) error {
 
 func (bot *mscBot) SetProperty(userID, propertyName string, value interface{}) error {
 	if propertyName == store.SubscribePropertyName {
-		if boolValue, _ := value.(bool); boolValue {
-			m := New(bot.Env, userID)
-			_, err := m.LoadMyEventSubscription()
-			if err == nil { // Subscription found
-				return nil
-			}
-
+		boolValue, _ := value.(bool)
+		m := New(bot.Env, userID)
+		_, err := m.LoadMyEventSubscription()
+		subscribed := err == nil
+		var failedMessage string
+		switch {
+		case boolValue && !subscribed:
 			_, err = m.CreateMyEventSubscription()
-			if err != nil {
-				return err
-			}
+			failedMessage = SubscribeToEventsFailed
+		case !boolValue && subscribed:
+			err = m.DeleteMyEventSubscription()
+			failedMessage = UnsubscribeFromEventsFailed
+		default:
+			return nil
+		}
+		if err != nil {
+			// The welcome flow goes on, the user can try again from the settings.
+			bot.Errorf("Error changing the event subscription of user %s. err=%v", userID, err)
+			_, _ = bot.DM(userID, failedMessage, config.Provider.CommandTrigger)
 		}
 		return nil
 	}
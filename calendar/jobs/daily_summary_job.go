@@ -23,14 +23,20 @@ func NewDailySummaryJob() RegisteredJob {
 	}
 }
 
-// runDailySummaryJob delivers the daily calendar summary to all users who have their settings configured to receive it now
+// runDailySummaryJob delivers the daily and weekly calendar summaries to all users who have their settings configured to receive them now
 func runDailySummaryJob(env engine.Env) {
 	env.Logger.Debugf("Daily summary job beginning")
 
-	err := engine.New(env, "").ProcessAllDailySummary(time.Now())
+	now := time.Now()
+	err := engine.New(env, "").ProcessAllDailySummary(now)
 	if err != nil {
 		env.Logger.Errorf("Error during daily summary job. err=%v", err)
 	}
 
+	err = engine.New(env, "").ProcessAllWeeklySummary(now)
+	if err != nil {
+		env.Logger.Errorf("Error during weekly summary job. err=%v", err)
+	}
+
 	env.Logger.Debugf("Daily summary job finished")
 }
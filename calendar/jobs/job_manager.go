@@ -13,6 +13,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/metrics"
 )
 
 type JobManager struct {
@@ -80,7 +81,11 @@ func (jm *JobManager) Close() error {
 
 // activateJob creates an ActiveJob, starts it, and stores it in the job manager.
 func (jm *JobManager) activateJob(job RegisteredJob) error {
-	scheduled, err := scheduleFunc(jm.papi, job.id, cluster.MakeWaitForRoundedInterval(job.interval), func() { job.work(jm.getEnv()) })
+	scheduled, err := scheduleFunc(jm.papi, job.id, cluster.MakeWaitForRoundedInterval(job.interval), func() {
+		start := time.Now()
+		job.work(jm.getEnv())
+		metrics.ObserveJobRun(job.id, time.Since(start))
+	})
 	if err != nil {
 		return err
 	}
@@ -3,7 +3,11 @@
 
 package jobs
 
-import "github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+)
 
 // Unique id for the status sync job
 const statusSyncJobID = "status_sync"
@@ -26,5 +30,15 @@ func runSyncJob(env engine.Env) {
 		env.Logger.Errorf("Error during user status sync job. err=%v", err)
 	}
 
+	err = engine.New(env, "").ProcessAllRSVPDigests(time.Now())
+	if err != nil {
+		env.Logger.Errorf("Error during RSVP digest processing. err=%v", err)
+	}
+
+	err = engine.New(env, "").ProcessAllNotificationBatches(time.Now())
+	if err != nil {
+		env.Logger.Errorf("Error during notification batch processing. err=%v", err)
+	}
+
 	env.Logger.Debugf("User status sync job finished.\nSummary\nNumber of users processed:- %d\nNumber of users had their status changed:- %d\nNumber of users had errors:- %d", syncJobSummary.NumberOfUsersProcessed, syncJobSummary.NumberOfUsersStatusChanged, syncJobSummary.NumberOfUsersFailedStatusChanged)
 }
@@ -0,0 +1,37 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package jobs
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+)
+
+// Unique id for the subscription reconciliation job. It keeps the id of the
+// renew job it replaced, so that the schedule carries over.
+const subscriptionReconcileJobID = "renew"
+
+// NewSubscriptionReconcileJob creates a RegisteredJob with the parameters specific to the SubscriptionReconcileJob
+func NewSubscriptionReconcileJob() RegisteredJob {
+	return RegisteredJob{
+		id:       subscriptionReconcileJobID,
+		interval: 24 * time.Hour,
+		work:     runSubscriptionReconcileJob,
+	}
+}
+
+// runSubscriptionReconcileJob reconciles the event subscriptions of all the
+// connected users with the remote ones.
+func runSubscriptionReconcileJob(env engine.Env) {
+	env.Logger.Debugf("Subscription reconciliation job beginning")
+
+	summary, err := engine.New(env, "").ReconcileSubscriptions()
+	if err != nil {
+		env.Logger.Errorf("Error during subscription reconciliation job. err=%v", err)
+		return
+	}
+
+	env.Logger.Debugf("Subscription reconciliation job finished.\n%s", summary.String())
+}
@@ -0,0 +1,303 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package metrics keeps the metrics of the plugin internals since it started,
+// and writes them in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const namespace = "mscalendar_"
+
+// Metric types
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// Reasons the webhook notifications are dropped for
+const (
+	DropRejected   = "rejected"
+	DropRepeated   = "repeated"
+	DropQueueFull  = "queue_full"
+	DropDeadLetter = "dead_letter"
+)
+
+var (
+	graphRequestBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	jobRunBuckets       = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800}
+)
+
+var (
+	graphRequests        = newCounter("graph_requests_total", "Requests to Microsoft Graph, by endpoint and status.", "method", "endpoint", "status")
+	graphRequestDuration = newHistogram("graph_request_duration_seconds", "Latency of the requests to Microsoft Graph, by endpoint.", graphRequestBuckets, "method", "endpoint")
+	notificationsDropped = newCounter("notifications_dropped_total", "Webhook notifications dropped, by reason.", "reason")
+	jobRunDuration       = newHistogram("job_run_duration_seconds", "Duration of the runs of the scheduled jobs.", jobRunBuckets, "job")
+	tokenRefreshFailures = newCounter("token_refresh_failures_total", "Failures to refresh the OAuth2 token of a user.")
+	registeredCounters   = []*counter{graphRequests, notificationsDropped, tokenRefreshFailures}
+	registeredHistograms = []*histogram{graphRequestDuration, jobRunDuration}
+)
+
+// ObserveGraphRequest counts a request to the remote. The status is the HTTP
+// status code, or 0 if no response was received.
+func ObserveGraphRequest(method, endpoint string, status int, duration time.Duration) {
+	statusLabel := "error"
+	if status != 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+	graphRequests.inc(method, endpoint, statusLabel)
+	graphRequestDuration.observe(duration.Seconds(), method, endpoint)
+}
+
+// IncNotificationsDropped counts a webhook notification that is not processed,
+// for one of the Drop reasons.
+func IncNotificationsDropped(reason string) {
+	notificationsDropped.inc(reason)
+}
+
+func ObserveJobRun(job string, duration time.Duration) {
+	jobRunDuration.observe(duration.Seconds(), job)
+}
+
+func IncTokenRefreshFailures() {
+	tokenRefreshFailures.inc()
+}
+
+// Family is a metric computed when the metrics are written, like the size of
+// the notification queue.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Write writes the metrics kept since the plugin started, and the families,
+// sorted by name.
+func Write(w io.Writer, families ...*Family) error {
+	type entry struct {
+		name  string
+		write func(*strings.Builder)
+	}
+	entries := []entry{}
+	for _, c := range registeredCounters {
+		entries = append(entries, entry{c.name, c.write})
+	}
+	for _, h := range registeredHistograms {
+		entries = append(entries, entry{h.name, h.write})
+	}
+	for _, f := range families {
+		entries = append(entries, entry{namespace + f.Name, f.write})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	b := &strings.Builder{}
+	for _, e := range entries {
+		e.write(b)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (f *Family) write(b *strings.Builder) {
+	name := namespace + f.Name
+	writeHeader(b, name, f.Help, f.Type)
+	for _, s := range f.Samples {
+		names := make([]string, 0, len(s.Labels))
+		for k := range s.Labels {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		values := make([]string, 0, len(names))
+		for _, k := range names {
+			values = append(values, s.Labels[k])
+		}
+		writeSample(b, name, names, values, s.Value)
+	}
+}
+
+// Reset clears the metrics kept since the plugin started, for the tests.
+func Reset() {
+	for _, c := range registeredCounters {
+		c.reset()
+	}
+	for _, h := range registeredHistograms {
+		h.reset()
+	}
+}
+
+type counter struct {
+	name       string
+	help       string
+	labelNames []string
+
+	lock   sync.Mutex
+	values map[string]float64
+}
+
+func newCounter(name, help string, labelNames ...string) *counter {
+	return &counter{
+		name:       namespace + name,
+		help:       help,
+		labelNames: labelNames,
+		values:     map[string]float64{},
+	}
+}
+
+func (c *counter) inc(labelValues ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.values[labelsKey(labelValues)]++
+}
+
+func (c *counter) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.values = map[string]float64{}
+}
+
+func (c *counter) write(b *strings.Builder) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	writeHeader(b, c.name, c.help, TypeCounter)
+	if len(c.labelNames) == 0 {
+		writeSample(b, c.name, nil, nil, c.values[""])
+		return
+	}
+	for _, key := range sortedKeys(c.values) {
+		writeSample(b, c.name, c.labelNames, splitLabelsKey(key), c.values[key])
+	}
+}
+
+type histogramValue struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+type histogram struct {
+	name       string
+	help       string
+	labelNames []string
+	buckets    []float64
+
+	lock   sync.Mutex
+	values map[string]*histogramValue
+}
+
+func newHistogram(name, help string, buckets []float64, labelNames ...string) *histogram {
+	return &histogram{
+		name:       namespace + name,
+		help:       help,
+		labelNames: labelNames,
+		buckets:    buckets,
+		values:     map[string]*histogramValue{},
+	}
+}
+
+func (h *histogram) observe(v float64, labelValues ...string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	key := labelsKey(labelValues)
+	value, ok := h.values[key]
+	if !ok {
+		value = &histogramValue{buckets: make([]uint64, len(h.buckets))}
+		h.values[key] = value
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			value.buckets[i]++
+		}
+	}
+	value.count++
+	value.sum += v
+}
+
+func (h *histogram) reset() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.values = map[string]*histogramValue{}
+}
+
+func (h *histogram) write(b *strings.Builder) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	writeHeader(b, h.name, h.help, TypeHistogram)
+	bucketLabelNames := append(append([]string{}, h.labelNames...), "le")
+	for _, key := range sortedKeys(h.values) {
+		value := h.values[key]
+		labelValues := splitLabelsKey(key)
+		for i, upper := range h.buckets {
+			bucketLabelValues := append(append([]string{}, labelValues...), formatFloat(upper))
+			writeSample(b, h.name+"_bucket", bucketLabelNames, bucketLabelValues, float64(value.buckets[i]))
+		}
+		writeSample(b, h.name+"_bucket", bucketLabelNames, append(append([]string{}, labelValues...), "+Inf"), float64(value.count))
+		writeSample(b, h.name+"_sum", h.labelNames, labelValues, value.sum)
+		writeSample(b, h.name+"_count", h.labelNames, labelValues, float64(value.count))
+	}
+}
+
+// The label values are kept joined, with a separator that cannot be part of
+// the values.
+const labelsKeySeparator = "\xff"
+
+func labelsKey(labelValues []string) string {
+	return strings.Join(labelValues, labelsKeySeparator)
+}
+
+func splitLabelsKey(key string) []string {
+	return strings.Split(key, labelsKeySeparator)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeHeader(b *strings.Builder, name, help, metricType string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func writeSample(b *strings.Builder, name string, labelNames, labelValues []string, value float64) {
+	b.WriteString(name)
+	if len(labelNames) > 0 {
+		b.WriteByte('{')
+		for i, labelName := range labelNames {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(b, `%s="%s"`, labelName, labelValueEscaper.Replace(labelValues[i]))
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
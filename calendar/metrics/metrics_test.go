@@ -0,0 +1,64 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	ObserveGraphRequest("GET", "/v1.0/me/events", 200, 300*time.Millisecond)
+	ObserveGraphRequest("GET", "/v1.0/me/events", 429, 50*time.Millisecond)
+	ObserveGraphRequest("POST", "/v1.0/subscriptions", 0, 2*time.Second)
+	IncNotificationsDropped(DropRepeated)
+	IncTokenRefreshFailures()
+	ObserveJobRun("status_sync", 20*time.Second)
+
+	b := &strings.Builder{}
+	err := Write(b, &Family{
+		Name:    "connected_users",
+		Help:    "Users connected to their calendar.",
+		Type:    TypeGauge,
+		Samples: []Sample{{Value: 3}},
+	}, &Family{
+		Name: "remote_cache_lookups_total",
+		Help: "Lookups in the remote cache.",
+		Type: TypeCounter,
+		Samples: []Sample{
+			{Labels: map[string]string{"result": "hit", "kind": "events"}, Value: 5},
+		},
+	})
+	require.NoError(t, err)
+	out := b.String()
+
+	for _, expected := range []string{
+		"# HELP mscalendar_connected_users Users connected to their calendar.\n# TYPE mscalendar_connected_users gauge\nmscalendar_connected_users 3\n",
+		`mscalendar_graph_requests_total{method="GET",endpoint="/v1.0/me/events",status="200"} 1` + "\n",
+		`mscalendar_graph_requests_total{method="GET",endpoint="/v1.0/me/events",status="429"} 1` + "\n",
+		`mscalendar_graph_requests_total{method="POST",endpoint="/v1.0/subscriptions",status="error"} 1` + "\n",
+		`mscalendar_graph_request_duration_seconds_bucket{method="GET",endpoint="/v1.0/me/events",le="0.05"} 1` + "\n",
+		`mscalendar_graph_request_duration_seconds_bucket{method="GET",endpoint="/v1.0/me/events",le="0.25"} 1` + "\n",
+		`mscalendar_graph_request_duration_seconds_bucket{method="GET",endpoint="/v1.0/me/events",le="0.5"} 2` + "\n",
+		`mscalendar_graph_request_duration_seconds_bucket{method="GET",endpoint="/v1.0/me/events",le="+Inf"} 2` + "\n",
+		`mscalendar_graph_request_duration_seconds_sum{method="GET",endpoint="/v1.0/me/events"} 0.35` + "\n",
+		`mscalendar_graph_request_duration_seconds_count{method="GET",endpoint="/v1.0/me/events"} 2` + "\n",
+		`mscalendar_job_run_duration_seconds_count{job="status_sync"} 1` + "\n",
+		`mscalendar_notifications_dropped_total{reason="repeated"} 1` + "\n",
+		`mscalendar_remote_cache_lookups_total{kind="events",result="hit"} 5` + "\n",
+		"mscalendar_token_refresh_failures_total 1\n",
+	} {
+		require.Contains(t, out, expected)
+	}
+
+	// Families are sorted by name
+	require.Less(t, strings.Index(out, "mscalendar_connected_users"), strings.Index(out, "mscalendar_graph_requests_total"))
+	require.Less(t, strings.Index(out, "mscalendar_graph_requests_total"), strings.Index(out, "mscalendar_token_refresh_failures_total"))
+}
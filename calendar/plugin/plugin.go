@@ -116,6 +116,7 @@ func (p *Plugin) OnActivate() error {
 			),
 		)
 		e.bot = e.bot.WithConfig(stored.Config)
+		e.Config.BotUserID = e.bot.MattermostUserID()
 		e.Dependencies.Store = store.NewPluginStore(p.API, e.bot, e.bot, e.Dependencies.Tracker, e.Provider.Features.EncryptedStore, []byte(e.EncryptionKey))
 	})
 
@@ -154,10 +155,19 @@ func (p *Plugin) OnConfigurationChange() (err error) {
 		return errors.WithMessage(err, "failed to load plugin configuration")
 	}
 
-	mattermostSiteURL := p.API.GetConfig().ServiceSettings.SiteURL
+	if stored.EncryptionKey == "" {
+		stored.EncryptionKey, err = p.generateEncryptionKey()
+		if err != nil {
+			return err
+		}
+	}
+
+	mattermostConfig := p.API.GetConfig()
+	mattermostSiteURL := mattermostConfig.ServiceSettings.SiteURL
 	if mattermostSiteURL == nil {
 		return errors.New("plugin requires Mattermost Site URL to be set")
 	}
+	showEmailAddress := mattermostConfig.PrivacySettings.ShowEmailAddress != nil && *mattermostConfig.PrivacySettings.ShowEmailAddress
 	mattermostURL, err := url.Parse(*mattermostSiteURL)
 	if err != nil {
 		return err
@@ -171,6 +181,7 @@ func (p *Plugin) OnConfigurationChange() (err error) {
 		e.StoredConfig = stored
 		e.Config.MattermostSiteURL = *mattermostSiteURL
 		e.Config.MattermostSiteHostname = mattermostURL.Hostname()
+		e.Config.MattermostShowEmailAddress = showEmailAddress
 		e.Config.PluginURL = pluginURL
 		e.Config.PluginURLPath = pluginURLPath
 
@@ -235,13 +246,30 @@ func (p *Plugin) OnConfigurationChange() (err error) {
 			e.jobManager = jobs.NewJobManager(p.API, e.Env)
 			e.jobManager.AddJob(jobs.NewStatusSyncJob())
 			e.jobManager.AddJob(jobs.NewDailySummaryJob())
-			e.jobManager.AddJob(jobs.NewRenewJob())
+			e.jobManager.AddJob(jobs.NewSubscriptionReconcileJob())
 		}
 	})
 
 	return nil
 }
 
+// generateEncryptionKey generates the encryption key of the installs where it
+// was not set yet, as the free/busy feed tokens and the notification
+// certificates are always encrypted.
+func (p *Plugin) generateEncryptionKey() (string, error) {
+	key := model.NewRandomString(32)
+
+	pluginConfig := p.API.GetPluginConfig()
+	if pluginConfig == nil {
+		pluginConfig = map[string]interface{}{}
+	}
+	pluginConfig["encryptionkey"] = key
+	if appErr := p.API.SavePluginConfig(pluginConfig); appErr != nil {
+		return "", errors.WithMessage(appErr, "failed to save the generated encryption key, generate it in the plugin settings")
+	}
+	return key, nil
+}
+
 func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*model.CommandResponse, *model.AppError) {
 	env := p.getEnv()
 	if env.configError != nil {
@@ -282,6 +310,22 @@ func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*mo
 	return response, nil
 }
 
+func (p *Plugin) MessageHasBeenPosted(_ *plugin.Context, post *model.Post) {
+	if len(post.FileIds) == 0 || post.IsSystemMessage() {
+		return
+	}
+
+	env := p.getEnv()
+	if env.configError != nil || env.bot == nil || post.UserId == env.bot.MattermostUserID() {
+		return
+	}
+
+	err := engine.New(env.Env, post.UserId).OfferCalendarImport(post)
+	if err != nil {
+		env.Logger.Warnf("Failed to offer calendar import. postID=%s err=%v", post.Id, err)
+	}
+}
+
 func (p *Plugin) ServeHTTP(_ *plugin.Context, w http.ResponseWriter, req *http.Request) {
 	env := p.getEnv()
 	if env.configError != nil {
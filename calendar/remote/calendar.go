@@ -26,3 +26,26 @@ type ViewCalendarResponse struct {
 	RemoteUserID string
 	Events       []*Event
 }
+
+// EventsDeltaParams requests the changes to the events of a user between
+// StartTime and EndTime since the DeltaLink returned by a previous request.
+// All the events of the range are returned if DeltaLink is empty.
+type EventsDeltaParams struct {
+	StartTime    time.Time
+	EndTime      time.Time
+	RemoteUserID string
+	DeltaLink    string
+}
+
+// EventsDeltaResponse holds the events created or updated since the previous
+// request, and the IDs of the ones removed. Expired is set when the
+// DeltaLink can no longer be used, and all the events need to be requested
+// again.
+type EventsDeltaResponse struct {
+	Error        *APIError
+	RemoteUserID string
+	Events       []*Event
+	RemovedIDs   []string
+	DeltaLink    string
+	Expired      bool
+}
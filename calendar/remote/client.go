@@ -26,19 +26,22 @@ type Calendars interface {
 	GetCalendars(remoteUserID string) ([]*Calendar, error)
 	GetDefaultCalendarView(remoteUserID string, startTime, endTime time.Time) ([]*Event, error)
 	DoBatchViewCalendarRequests([]*ViewCalendarParams) ([]*ViewCalendarResponse, error)
+	DoBatchEventsDeltaRequests([]*EventsDeltaParams) ([]*EventsDeltaResponse, error)
 	GetMailboxSettings(remoteUserID string) (*MailboxSettings, error)
+	GetSchedule(requests []*ScheduleUserInfo, startTime, endTime *DateTime, availabilityViewInterval int) ([]*ScheduleInformation, error)
+	FindRooms() ([]*Room, error)
 }
 
 type Events interface {
 	CreateEvent(remoteUserID string, calendarEvent *Event) (*Event, error)
-	AcceptEvent(remoteUserID, eventID string) error
-	DeclineEvent(remoteUserID, eventID string) error
-	TentativelyAcceptEvent(remoteUserID, eventID string) error
+	AcceptEvent(remoteUserID, eventID string, opts *EventResponseOptions) error
+	DeclineEvent(remoteUserID, eventID string, opts *EventResponseOptions) error
+	TentativelyAcceptEvent(remoteUserID, eventID string, opts *EventResponseOptions) error
 	GetEventsBetweenDates(remoteUserID string, start, end time.Time) ([]*Event, error)
 }
 
 type Subscriptions interface {
-	CreateMySubscription(notificationURL, remoteUserID string) (*Subscription, error)
+	CreateMySubscription(notificationURL, remoteUserID string, encryption *NotificationEncryption) (*Subscription, error)
 	DeleteSubscription(sub *Subscription) error
 	GetNotificationData(*Notification) (*Notification, error)
 	ListSubscriptions() ([]*Subscription, error)
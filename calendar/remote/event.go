@@ -10,6 +10,16 @@ const (
 	EventResponseStatusDeclined    = "declined"
 )
 
+const (
+	AttendeeTypeRequired = "required"
+	AttendeeTypeOptional = "optional"
+	AttendeeTypeResource = "resource"
+)
+
+const (
+	LocationTypeConferenceRoom = "conferenceRoom"
+)
+
 type Event struct {
 	Start                      *DateTime            `json:"start,omitempty"`
 	Location                   *Location            `json:"location,omitempty"`
@@ -24,6 +34,7 @@ type Event struct {
 	BodyPreview                string               `json:"bodyPreview,omitempty"`
 	ShowAs                     string               `json:"showAs,omitempty"`
 	Weblink                    string               `json:"weblink,omitempty"`
+	CreatedDateTime            string               `json:"createdDateTime,omitempty"`
 	ID                         string               `json:"id,omitempty"`
 	Attendees                  []*Attendee          `json:"attendees,omitempty"`
 	ReminderMinutesBeforeStart int                  `json:"reminderMinutesBeforeStart,omitempty"`
@@ -33,6 +44,15 @@ type Event struct {
 	ResponseRequested          bool                 `json:"responseRequested,omitempty"`
 }
 
+// EventResponseOptions are the optional parts of a response to an
+// invitation. ProposedNewTime is only honored when declining or tentatively
+// accepting. The organizer is notified unless SendResponse is false.
+type EventResponseOptions struct {
+	SendResponse    *bool
+	ProposedNewTime *TimeSlot
+	Comment         string
+}
+
 type ItemBody struct {
 	Content     string `json:"content,omitempty"`
 	ContentType string `json:"contentType,omitempty"`
@@ -44,10 +64,11 @@ type EventResponseStatus struct {
 }
 
 type Location struct {
-	DisplayName  string       `json:"displayName,omitempty"`
-	Address      *Address     `json:"address"`
-	Coordinates  *Coordinates `json:"coordinates"`
-	LocationType string       `json:"locationType"`
+	DisplayName          string       `json:"displayName,omitempty"`
+	Address              *Address     `json:"address"`
+	Coordinates          *Coordinates `json:"coordinates"`
+	LocationType         string       `json:"locationType"`
+	LocationEmailAddress string       `json:"locationEmailAddress,omitempty"`
 }
 
 type Address struct {
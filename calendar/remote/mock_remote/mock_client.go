@@ -37,17 +37,17 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 }
 
 // AcceptEvent mocks base method.
-func (m *MockClient) AcceptEvent(arg0, arg1 string) error {
+func (m *MockClient) AcceptEvent(arg0, arg1 string, arg2 *remote.EventResponseOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AcceptEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "AcceptEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AcceptEvent indicates an expected call of AcceptEvent.
-func (mr *MockClientMockRecorder) AcceptEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) AcceptEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptEvent", reflect.TypeOf((*MockClient)(nil).AcceptEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptEvent", reflect.TypeOf((*MockClient)(nil).AcceptEvent), arg0, arg1, arg2)
 }
 
 // CallFormPost mocks base method.
@@ -111,32 +111,32 @@ func (mr *MockClientMockRecorder) CreateEvent(arg0, arg1 interface{}) *gomock.Ca
 }
 
 // CreateMySubscription mocks base method.
-func (m *MockClient) CreateMySubscription(arg0, arg1 string) (*remote.Subscription, error) {
+func (m *MockClient) CreateMySubscription(arg0, arg1 string, arg2 *remote.NotificationEncryption) (*remote.Subscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMySubscription", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateMySubscription", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*remote.Subscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateMySubscription indicates an expected call of CreateMySubscription.
-func (mr *MockClientMockRecorder) CreateMySubscription(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateMySubscription(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMySubscription", reflect.TypeOf((*MockClient)(nil).CreateMySubscription), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMySubscription", reflect.TypeOf((*MockClient)(nil).CreateMySubscription), arg0, arg1, arg2)
 }
 
 // DeclineEvent mocks base method.
-func (m *MockClient) DeclineEvent(arg0, arg1 string) error {
+func (m *MockClient) DeclineEvent(arg0, arg1 string, arg2 *remote.EventResponseOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeclineEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeclineEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeclineEvent indicates an expected call of DeclineEvent.
-func (mr *MockClientMockRecorder) DeclineEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) DeclineEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEvent", reflect.TypeOf((*MockClient)(nil).DeclineEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEvent", reflect.TypeOf((*MockClient)(nil).DeclineEvent), arg0, arg1, arg2)
 }
 
 // DeleteCalendar mocks base method.
@@ -167,6 +167,21 @@ func (mr *MockClientMockRecorder) DeleteSubscription(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockClient)(nil).DeleteSubscription), arg0)
 }
 
+// DoBatchEventsDeltaRequests mocks base method.
+func (m *MockClient) DoBatchEventsDeltaRequests(arg0 []*remote.EventsDeltaParams) ([]*remote.EventsDeltaResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoBatchEventsDeltaRequests", arg0)
+	ret0, _ := ret[0].([]*remote.EventsDeltaResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DoBatchEventsDeltaRequests indicates an expected call of DoBatchEventsDeltaRequests.
+func (mr *MockClientMockRecorder) DoBatchEventsDeltaRequests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoBatchEventsDeltaRequests", reflect.TypeOf((*MockClient)(nil).DoBatchEventsDeltaRequests), arg0)
+}
+
 // DoBatchViewCalendarRequests mocks base method.
 func (m *MockClient) DoBatchViewCalendarRequests(arg0 []*remote.ViewCalendarParams) ([]*remote.ViewCalendarResponse, error) {
 	m.ctrl.T.Helper()
@@ -197,6 +212,21 @@ func (mr *MockClientMockRecorder) FindMeetingTimes(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMeetingTimes", reflect.TypeOf((*MockClient)(nil).FindMeetingTimes), arg0, arg1)
 }
 
+// FindRooms mocks base method.
+func (m *MockClient) FindRooms() ([]*remote.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRooms")
+	ret0, _ := ret[0].([]*remote.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRooms indicates an expected call of FindRooms.
+func (mr *MockClientMockRecorder) FindRooms() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRooms", reflect.TypeOf((*MockClient)(nil).FindRooms))
+}
+
 // GetCalendars mocks base method.
 func (m *MockClient) GetCalendars(arg0 string) ([]*remote.Calendar, error) {
 	m.ctrl.T.Helper()
@@ -302,6 +332,21 @@ func (mr *MockClientMockRecorder) GetNotificationData(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationData", reflect.TypeOf((*MockClient)(nil).GetNotificationData), arg0)
 }
 
+// GetSchedule mocks base method.
+func (m *MockClient) GetSchedule(arg0 []*remote.ScheduleUserInfo, arg1, arg2 *remote.DateTime, arg3 int) ([]*remote.ScheduleInformation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*remote.ScheduleInformation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockClientMockRecorder) GetSchedule(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockClient)(nil).GetSchedule), arg0, arg1, arg2, arg3)
+}
+
 // GetSuperuserToken mocks base method.
 func (m *MockClient) GetSuperuserToken() (string, error) {
 	m.ctrl.T.Helper()
@@ -348,15 +393,15 @@ func (mr *MockClientMockRecorder) RenewSubscription(arg0, arg1, arg2 interface{}
 }
 
 // TentativelyAcceptEvent mocks base method.
-func (m *MockClient) TentativelyAcceptEvent(arg0, arg1 string) error {
+func (m *MockClient) TentativelyAcceptEvent(arg0, arg1 string, arg2 *remote.EventResponseOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TentativelyAcceptEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "TentativelyAcceptEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TentativelyAcceptEvent indicates an expected call of TentativelyAcceptEvent.
-func (mr *MockClientMockRecorder) TentativelyAcceptEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) TentativelyAcceptEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TentativelyAcceptEvent", reflect.TypeOf((*MockClient)(nil).TentativelyAcceptEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TentativelyAcceptEvent", reflect.TypeOf((*MockClient)(nil).TentativelyAcceptEvent), arg0, arg1, arg2)
 }
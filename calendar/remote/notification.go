@@ -3,8 +3,29 @@
 
 package remote
 
+// Notification change types
+const (
+	ChangeTypeCreated = "created"
+	ChangeTypeUpdated = "updated"
+	ChangeTypeDeleted = "deleted"
+)
+
+// Lifecycle events of the subscriptions
+const (
+	// LifecycleReauthorizationRequired is sent when the subscription is to be
+	// renewed before the remote stops sending its notifications.
+	LifecycleReauthorizationRequired = "reauthorizationRequired"
+	// LifecycleMissed is sent when some notifications could not be delivered.
+	LifecycleMissed = "missed"
+	// LifecycleSubscriptionRemoved is sent when the remote removed the
+	// subscription.
+	LifecycleSubscriptionRemoved = "subscriptionRemoved"
+)
+
 type Notification struct {
-	Webhook interface{}
+	// Webhook is not persisted with the notification, remotes decode it again
+	// from WebhookRawData.
+	Webhook interface{} `json:"-"`
 
 	// Notification data
 	Subscription        *Subscription
@@ -15,14 +36,31 @@ type Notification struct {
 	// persistent secret.
 	ClientState string
 
-	// Notification type
+	// Notification type, one of the ChangeType constants. The Event of a
+	// deleted notification only has its ID set.
 	ChangeType string
 
 	// The (remote) subscription ID the notification is for
 	SubscriptionID string
 
-	// Remote-specific data: full raw JSON of the webhook, and the decoded
-	// backend-specific struct.
+	// Set for the lifecycle notifications of the subscription, to one of the
+	// Lifecycle constants. They carry no event.
+	LifecycleEvent string
+
+	// Set if the webhook includes the event, encrypted with the certificate
+	// of the ID. The handler is to set DecryptionKey to the private key of the
+	// certificate before calling GetNotificationData(), which then does not
+	// need to fetch the event.
+	EncryptionCertificateID string
+	DecryptionKey           []byte `json:"-"`
+
+	// DeliveryID is the same for the deliveries of the same webhook, that the
+	// remote repeats when it is unsure the previous ones were received. The
+	// handler is to process only one of them.
+	DeliveryID string
+
+	// Remote-specific data: raw JSON of the webhook the notification was
+	// decoded from.
 	WebhookRawData []byte
 
 	// Set if subscription renewal is recommended. The date/time logic is
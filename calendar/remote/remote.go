@@ -6,6 +6,7 @@ package remote
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
 
 	"golang.org/x/oauth2"
@@ -17,12 +18,16 @@ import (
 var (
 	ErrSuperUserClientNotSupported = errors.New("superuser client is not supported")
 	ErrNotImplemented              = errors.New("not implemented")
+	ErrRoomsNotPermitted           = errors.New("the permission to read the rooms was not granted, ask a tenant admin to consent to Place.Read.All and connect your account again")
 )
 
 type Remote interface {
 	MakeUserClient(context.Context, *oauth2.Token, string, bot.Poster, UserTokenHelpers) Client
 	MakeSuperuserClient(ctx context.Context) (Client, error)
 	NewOAuth2Config() *oauth2.Config
+	// HandleWebhook responds to the requests it validates or rejects, and
+	// returns nil for them. Otherwise it returns the notifications without
+	// responding, the caller is to accept them once they are persisted.
 	HandleWebhook(http.ResponseWriter, *http.Request) []*Notification
 	CheckConfiguration(configuration config.StoredConfig) error
 }
@@ -33,3 +38,27 @@ type APIError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
+
+// TransientError wraps the errors of the remote that may not happen again if
+// the request is retried, like throttling or server errors.
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransientError returns true if retrying the failed request may succeed.
+func IsTransientError(err error) bool {
+	var transientErr *TransientError
+	if errors.As(err, &transientErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
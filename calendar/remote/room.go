@@ -0,0 +1,14 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package remote
+
+// Room is a meeting room resource as returned by the places API.
+type Room struct {
+	ID           string `json:"id,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	Building     string `json:"building,omitempty"`
+	FloorLabel   string `json:"floorLabel,omitempty"`
+	Capacity     int    `json:"capacity,omitempty"`
+}
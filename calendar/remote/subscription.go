@@ -4,13 +4,25 @@
 package remote
 
 type Subscription struct {
-	ID                 string `json:"id"`
-	ResourceID         string `json:"resourceId,omitempty"`
-	Resource           string `json:"resource,omitempty"`
-	ApplicationID      string `json:"applicationId,omitempty"`
-	ChangeType         string `json:"changeType,omitempty"`
-	ClientState        string `json:"clientState,omitempty"`
-	NotificationURL    string `json:"notificationUrl,omitempty"`
-	ExpirationDateTime string `json:"expirationDateTime,omitempty"`
-	CreatorID          string `json:"creatorId,omitempty"`
+	ID                       string `json:"id"`
+	ResourceID               string `json:"resourceId,omitempty"`
+	Resource                 string `json:"resource,omitempty"`
+	ApplicationID            string `json:"applicationId,omitempty"`
+	ChangeType               string `json:"changeType,omitempty"`
+	ClientState              string `json:"clientState,omitempty"`
+	NotificationURL          string `json:"notificationUrl,omitempty"`
+	LifecycleNotificationURL string `json:"lifecycleNotificationUrl,omitempty"`
+	ExpirationDateTime       string `json:"expirationDateTime,omitempty"`
+	CreatorID                string `json:"creatorId,omitempty"`
+	IncludeResourceData      bool   `json:"includeResourceData,omitempty"`
+	EncryptionCertificate    string `json:"encryptionCertificate,omitempty"`
+	EncryptionCertificateID  string `json:"encryptionCertificateId,omitempty"`
+}
+
+// NotificationEncryption is the certificate the remote is to encrypt the
+// content of the notifications with, so that they include the changed event.
+type NotificationEncryption struct {
+	CertificateID string
+	// Certificate is DER encoded.
+	Certificate []byte
 }
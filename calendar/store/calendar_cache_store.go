@@ -0,0 +1,57 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+// CalendarCache holds the events of a remote user between Start and End, as
+// of the last sync. DeltaLink requests the changes since then.
+type CalendarCache struct {
+	Events    map[string]*remote.Event `json:"events"`
+	DeltaLink string                   `json:"delta_link"`
+	Start     int64                    `json:"start"`
+	End       int64                    `json:"end"`
+	SyncedAt  int64                    `json:"synced_at"`
+}
+
+// CalendarCacheStore keeps a copy of the calendar of each remote user, so the
+// sync jobs only request the changes to it.
+type CalendarCacheStore interface {
+	LoadCalendarCache(remoteUserID string) (*CalendarCache, error)
+	StoreCalendarCache(remoteUserID string, cache *CalendarCache) error
+	DeleteCalendarCache(remoteUserID string) error
+}
+
+func (s *pluginStore) LoadCalendarCache(remoteUserID string) (*CalendarCache, error) {
+	cache := &CalendarCache{}
+	err := kvstore.LoadJSON(s.calendarCacheKV, remoteUserID, cache)
+	if err != nil {
+		return nil, err
+	}
+	if cache.Events == nil {
+		cache.Events = map[string]*remote.Event{}
+	}
+	return cache, nil
+}
+
+func (s *pluginStore) StoreCalendarCache(remoteUserID string, cache *CalendarCache) error {
+	err := kvstore.StoreJSON(s.calendarCacheKV, remoteUserID, cache)
+	if err != nil {
+		return errors.Wrap(err, "failed to store calendar cache")
+	}
+	return nil
+}
+
+func (s *pluginStore) DeleteCalendarCache(remoteUserID string) error {
+	err := s.calendarCacheKV.Delete(remoteUserID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete calendar cache")
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/testutil"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/tracker/mock_tracker"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
+)
+
+func TestCalendarCacheEncrypted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAPI := &testutil.MockPluginAPI{}
+	store := NewPluginStore(mockAPI, mock_bot.NewMockLogger(ctrl), mock_bot.NewMockPoster(ctrl), mock_tracker.NewMockTracker(ctrl), true, []byte("0123456789abcdef0123456789abcdef"))
+
+	var storedKey string
+	var storedValue []byte
+	mockAPI.On("KVSet", MockString, MockByteValue).Run(func(args mock.Arguments) {
+		storedKey = args.String(0)
+		storedValue = args.Get(1).([]byte)
+	}).Return(nil).Times(1)
+
+	err := store.StoreCalendarCache(MockRemoteUserID, &CalendarCache{
+		Events:    map[string]*remote.Event{"event_id": {ID: "event_id", Subject: "Secret meeting"}},
+		DeltaLink: "https://graph.microsoft.com/v1.0/deltaLink",
+		Start:     100,
+		End:       200,
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(storedKey, CalendarCachePrefix))
+	require.NotContains(t, string(storedValue), "Secret meeting")
+
+	mockAPI.On("KVGet", storedKey).Return(storedValue, nil)
+	cache, err := store.LoadCalendarCache(MockRemoteUserID)
+	require.NoError(t, err)
+	require.Equal(t, "Secret meeting", cache.Events["event_id"].Subject)
+	require.Equal(t, "https://graph.microsoft.com/v1.0/deltaLink", cache.DeltaLink)
+	require.Equal(t, int64(100), cache.Start)
+	require.Equal(t, int64(200), cache.End)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestLoadCalendarCacheNotFound(t *testing.T) {
+	mockAPI, store, _, _, _ := GetMockSetup(t)
+	mockAPI.On("KVGet", MockString).Return(nil, nil)
+
+	_, err := store.LoadCalendarCache(MockRemoteUserID)
+	require.Equal(t, ErrNotFound, err)
+}
@@ -38,12 +38,16 @@ type EventStore interface {
 	DeleteLinkedChannelFromEvent(eventID, channelID string) error
 
 	LoadUserEvent(mattermostUserID, eventID string) (*Event, error)
+	LoadUserEventByRemoteID(mattermostUserID, remoteEventID string) (*Event, error)
 	StoreUserEvent(mattermostUserID string, event *Event) error
 	DeleteUserEvent(mattermostUserID, eventID string) error
 }
 
 func eventKey(mattermostUserID, eventID string) string { return mattermostUserID + "_" + eventID }
 func eventMetaKey(eventID string) string               { return "metadata_" + eventID }
+func eventRemoteIDKey(mattermostUserID, remoteEventID string) string {
+	return mattermostUserID + "_remote_" + remoteEventID
+}
 
 func (s *pluginStore) LoadUserEvent(mattermostUserID, eventID string) (*Event, error) {
 	event := Event{}
@@ -54,6 +58,17 @@ func (s *pluginStore) LoadUserEvent(mattermostUserID, eventID string) (*Event, e
 	return &event, nil
 }
 
+// LoadUserEventByRemoteID loads the event by its remote ID instead of its
+// iCalendar UID, for the notifications of deleted events which only have the
+// former.
+func (s *pluginStore) LoadUserEventByRemoteID(mattermostUserID, remoteEventID string) (*Event, error) {
+	eventID, err := s.eventKV.Load(eventRemoteIDKey(mattermostUserID, remoteEventID))
+	if err != nil {
+		return nil, err
+	}
+	return s.LoadUserEvent(mattermostUserID, string(eventID))
+}
+
 func (s *pluginStore) AddLinkedChannelToEvent(eventID, channelID string) error {
 	eventMeta, err := s.LoadEventMetadata(eventID)
 	if err != nil && !errors.Is(err, ErrNotFound) {
@@ -123,6 +138,12 @@ func (s *pluginStore) StoreUserEvent(mattermostUserID string, event *Event) erro
 	if err != nil {
 		return err
 	}
+	if event.Remote.ID != "" {
+		err = s.eventKV.StoreTTL(eventRemoteIDKey(mattermostUserID, event.Remote.ID), []byte(event.Remote.ICalUID), ttl)
+		if err != nil {
+			return err
+		}
+	}
 
 	s.Logger.With(bot.LogContext{
 		"mattermostUserID": mattermostUserID,
@@ -134,10 +155,21 @@ func (s *pluginStore) StoreUserEvent(mattermostUserID string, event *Event) erro
 }
 
 func (s *pluginStore) DeleteUserEvent(mattermostUserID, eventID string) error {
-	err := s.eventKV.Delete(eventKey(mattermostUserID, eventID))
+	event, err := s.LoadUserEvent(mattermostUserID, eventID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	err = s.eventKV.Delete(eventKey(mattermostUserID, eventID))
 	if err != nil {
 		return err
 	}
+	if event != nil && event.Remote != nil && event.Remote.ID != "" {
+		err = s.eventKV.Delete(eventRemoteIDKey(mattermostUserID, event.Remote.ID))
+		if err != nil {
+			return err
+		}
+	}
 
 	s.Logger.With(bot.LogContext{
 		"mattermostUserID": mattermostUserID,
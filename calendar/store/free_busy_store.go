@@ -0,0 +1,83 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+const (
+	freeBusyUserKeyPrefix  = "user:"
+	freeBusyTokenKeyPrefix = "token:"
+)
+
+// FreeBusyStore keeps the secret tokens of the public free/busy feeds. Each
+// token is stored twice, keyed by its owner and by itself, so that the feed
+// endpoint can find the user a request belongs to.
+type FreeBusyStore interface {
+	LoadFreeBusyToken(mattermostUserID string) (string, error)
+	LoadFreeBusyTokenUser(token string) (string, error)
+	StoreFreeBusyToken(mattermostUserID, token string) error
+	DeleteFreeBusyToken(mattermostUserID string) error
+}
+
+func (s *pluginStore) LoadFreeBusyToken(mattermostUserID string) (string, error) {
+	var token string
+	err := kvstore.LoadJSON(s.freeBusyKV, freeBusyUserKeyPrefix+mattermostUserID, &token)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *pluginStore) LoadFreeBusyTokenUser(token string) (string, error) {
+	if token == "" {
+		return "", ErrNotFound
+	}
+	var mattermostUserID string
+	err := kvstore.LoadJSON(s.freeBusyKV, freeBusyTokenKeyPrefix+token, &mattermostUserID)
+	if err != nil {
+		return "", err
+	}
+	return mattermostUserID, nil
+}
+
+func (s *pluginStore) StoreFreeBusyToken(mattermostUserID, token string) error {
+	err := s.DeleteFreeBusyToken(mattermostUserID)
+	if err != nil {
+		return err
+	}
+
+	err = kvstore.StoreJSON(s.freeBusyKV, freeBusyTokenKeyPrefix+token, mattermostUserID)
+	if err != nil {
+		return errors.Wrap(err, "failed to store free/busy token")
+	}
+	err = kvstore.StoreJSON(s.freeBusyKV, freeBusyUserKeyPrefix+mattermostUserID, token)
+	if err != nil {
+		return errors.Wrap(err, "failed to store free/busy token")
+	}
+	return nil
+}
+
+func (s *pluginStore) DeleteFreeBusyToken(mattermostUserID string) error {
+	token, err := s.LoadFreeBusyToken(mattermostUserID)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.freeBusyKV.Delete(freeBusyTokenKeyPrefix + token)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete free/busy token")
+	}
+	err = s.freeBusyKV.Delete(freeBusyUserKeyPrefix + mattermostUserID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete free/busy token")
+	}
+	return nil
+}
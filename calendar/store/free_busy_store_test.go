@@ -0,0 +1,66 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/testutil"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/tracker/mock_tracker"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
+)
+
+func TestStoreFreeBusyToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAPI := &testutil.MockPluginAPI{}
+	store := NewPluginStore(mockAPI, mock_bot.NewMockLogger(ctrl), mock_bot.NewMockPoster(ctrl), mock_tracker.NewMockTracker(ctrl), false, []byte("0123456789abcdef0123456789abcdef"))
+
+	stored := map[string][]byte{}
+	mockAPI.On("KVGet", MockString).Return(nil, nil)
+	mockAPI.On("KVSet", MockString, MockByteValue).Run(func(args mock.Arguments) {
+		stored[args.String(0)] = args.Get(1).([]byte)
+	}).Return(nil)
+
+	err := store.StoreFreeBusyToken(MockMMUserID, "mockToken")
+	require.NoError(t, err)
+
+	require.Len(t, stored, 2)
+	for key, value := range stored {
+		require.True(t, strings.HasPrefix(key, FreeBusyKeyPrefix))
+		require.NotContains(t, string(value), "mockToken")
+		require.NotContains(t, string(value), MockMMUserID)
+	}
+	mockAPI.AssertExpectations(t)
+}
+
+func TestStoreFreeBusyTokenWithoutEncryptionKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAPI := &testutil.MockPluginAPI{}
+	store := NewPluginStore(mockAPI, mock_bot.NewMockLogger(ctrl), mock_bot.NewMockPoster(ctrl), mock_tracker.NewMockTracker(ctrl), false, []byte(""))
+
+	mockAPI.On("KVGet", MockString).Return(nil, nil)
+
+	err := store.StoreFreeBusyToken(MockMMUserID, "mockToken")
+	require.ErrorIs(t, err, ErrEncryptionKeyNotSet)
+	mockAPI.AssertNotCalled(t, "KVSet", MockString, MockByteValue)
+}
+
+func TestLoadFreeBusyTokenUser(t *testing.T) {
+	mockAPI, store, _, _, _ := GetMockSetup(t)
+
+	_, err := store.LoadFreeBusyTokenUser("")
+	require.Equal(t, ErrNotFound, err)
+
+	mockAPI.On("KVGet", MockString).Return(nil, nil)
+	_, err = store.LoadFreeBusyTokenUser("unknownToken")
+	require.Equal(t, ErrNotFound, err)
+	mockAPI.AssertExpectations(t)
+}
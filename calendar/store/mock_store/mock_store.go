@@ -6,9 +6,12 @@ package mock_store
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	remote "github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	store "github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	model "github.com/mattermost/mattermost/server/public/model"
 	oauth2 "golang.org/x/oauth2"
 )
 
@@ -49,6 +52,121 @@ func (mr *MockStoreMockRecorder) AddLinkedChannelToEvent(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLinkedChannelToEvent", reflect.TypeOf((*MockStore)(nil).AddLinkedChannelToEvent), arg0, arg1)
 }
 
+// AddToNotificationBatch mocks base method.
+func (m *MockStore) AddToNotificationBatch(arg0 string, arg1 *model.SlackAttachment, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToNotificationBatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToNotificationBatch indicates an expected call of AddToNotificationBatch.
+func (mr *MockStoreMockRecorder) AddToNotificationBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToNotificationBatch", reflect.TypeOf((*MockStore)(nil).AddToNotificationBatch), arg0, arg1, arg2)
+}
+
+// AddToUserList mocks base method.
+func (m *MockStore) AddToUserList(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToUserList", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToUserList indicates an expected call of AddToUserList.
+func (mr *MockStoreMockRecorder) AddToUserList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToUserList", reflect.TypeOf((*MockStore)(nil).AddToUserList), arg0, arg1)
+}
+
+// CheckUserConnected mocks base method.
+func (m *MockStore) CheckUserConnected(arg0 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckUserConnected", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CheckUserConnected indicates an expected call of CheckUserConnected.
+func (mr *MockStoreMockRecorder) CheckUserConnected(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserConnected", reflect.TypeOf((*MockStore)(nil).CheckUserConnected), arg0)
+}
+
+// ClaimNotification mocks base method.
+func (m *MockStore) ClaimNotification(arg0 time.Time, arg1 time.Duration) (*store.QueuedNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNotification", arg0, arg1)
+	ret0, _ := ret[0].(*store.QueuedNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNotification indicates an expected call of ClaimNotification.
+func (mr *MockStoreMockRecorder) ClaimNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNotification", reflect.TypeOf((*MockStore)(nil).ClaimNotification), arg0, arg1)
+}
+
+// CompleteNotification mocks base method.
+func (m *MockStore) CompleteNotification(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteNotification", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteNotification indicates an expected call of CompleteNotification.
+func (mr *MockStoreMockRecorder) CompleteNotification(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteNotification", reflect.TypeOf((*MockStore)(nil).CompleteNotification), arg0)
+}
+
+// CountNotifications mocks base method.
+func (m *MockStore) CountNotifications() (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNotifications")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountNotifications indicates an expected call of CountNotifications.
+func (mr *MockStoreMockRecorder) CountNotifications() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNotifications", reflect.TypeOf((*MockStore)(nil).CountNotifications))
+}
+
+// DeadLetterNotification mocks base method.
+func (m *MockStore) DeadLetterNotification(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterNotification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetterNotification indicates an expected call of DeadLetterNotification.
+func (mr *MockStoreMockRecorder) DeadLetterNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterNotification", reflect.TypeOf((*MockStore)(nil).DeadLetterNotification), arg0, arg1)
+}
+
+// DeleteCalendarCache mocks base method.
+func (m *MockStore) DeleteCalendarCache(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendarCache", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCalendarCache indicates an expected call of DeleteCalendarCache.
+func (mr *MockStoreMockRecorder) DeleteCalendarCache(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendarCache", reflect.TypeOf((*MockStore)(nil).DeleteCalendarCache), arg0)
+}
+
 // DeleteCurrentStep mocks base method.
 func (m *MockStore) DeleteCurrentStep(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -63,6 +181,20 @@ func (mr *MockStoreMockRecorder) DeleteCurrentStep(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCurrentStep", reflect.TypeOf((*MockStore)(nil).DeleteCurrentStep), arg0)
 }
 
+// DeleteDeadLetterNotifications mocks base method.
+func (m *MockStore) DeleteDeadLetterNotifications(arg0 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetterNotifications", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetterNotifications indicates an expected call of DeleteDeadLetterNotifications.
+func (mr *MockStoreMockRecorder) DeleteDeadLetterNotifications(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetterNotifications", reflect.TypeOf((*MockStore)(nil).DeleteDeadLetterNotifications), arg0)
+}
+
 // DeleteEventMetadata mocks base method.
 func (m *MockStore) DeleteEventMetadata(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -77,6 +209,20 @@ func (mr *MockStoreMockRecorder) DeleteEventMetadata(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventMetadata", reflect.TypeOf((*MockStore)(nil).DeleteEventMetadata), arg0)
 }
 
+// DeleteFreeBusyToken mocks base method.
+func (m *MockStore) DeleteFreeBusyToken(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFreeBusyToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFreeBusyToken indicates an expected call of DeleteFreeBusyToken.
+func (mr *MockStoreMockRecorder) DeleteFreeBusyToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFreeBusyToken", reflect.TypeOf((*MockStore)(nil).DeleteFreeBusyToken), arg0)
+}
+
 // DeleteLinkedChannelFromEvent mocks base method.
 func (m *MockStore) DeleteLinkedChannelFromEvent(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -147,6 +293,20 @@ func (mr *MockStoreMockRecorder) DeleteUserFromIndex(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserFromIndex", reflect.TypeOf((*MockStore)(nil).DeleteUserFromIndex), arg0)
 }
 
+// DeleteUserLinkedEvent mocks base method.
+func (m *MockStore) DeleteUserLinkedEvent(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLinkedEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLinkedEvent indicates an expected call of DeleteUserLinkedEvent.
+func (mr *MockStoreMockRecorder) DeleteUserLinkedEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLinkedEvent", reflect.TypeOf((*MockStore)(nil).DeleteUserLinkedEvent), arg0, arg1)
+}
+
 // DeleteUserSubscription mocks base method.
 func (m *MockStore) DeleteUserSubscription(arg0 *store.User, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -176,6 +336,18 @@ func (mr *MockStoreMockRecorder) DeleteUserWelcomePost(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserWelcomePost", reflect.TypeOf((*MockStore)(nil).DeleteUserWelcomePost), arg0)
 }
 
+// DisconnectUserFromStoreIfNecessary mocks base method.
+func (m *MockStore) DisconnectUserFromStoreIfNecessary(arg0 error, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DisconnectUserFromStoreIfNecessary", arg0, arg1)
+}
+
+// DisconnectUserFromStoreIfNecessary indicates an expected call of DisconnectUserFromStoreIfNecessary.
+func (mr *MockStoreMockRecorder) DisconnectUserFromStoreIfNecessary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectUserFromStoreIfNecessary", reflect.TypeOf((*MockStore)(nil).DisconnectUserFromStoreIfNecessary), arg0, arg1)
+}
+
 // GetCurrentStep mocks base method.
 func (m *MockStore) GetCurrentStep(arg0 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -236,6 +408,50 @@ func (mr *MockStoreMockRecorder) GetSetting(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSetting", reflect.TypeOf((*MockStore)(nil).GetSetting), arg0, arg1)
 }
 
+// InvalidateRemoteCache mocks base method.
+func (m *MockStore) InvalidateRemoteCache(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateRemoteCache", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateRemoteCache indicates an expected call of InvalidateRemoteCache.
+func (mr *MockStoreMockRecorder) InvalidateRemoteCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateRemoteCache", reflect.TypeOf((*MockStore)(nil).InvalidateRemoteCache), arg0, arg1)
+}
+
+// LoadCalendarCache mocks base method.
+func (m *MockStore) LoadCalendarCache(arg0 string) (*store.CalendarCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCalendarCache", arg0)
+	ret0, _ := ret[0].(*store.CalendarCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCalendarCache indicates an expected call of LoadCalendarCache.
+func (mr *MockStoreMockRecorder) LoadCalendarCache(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCalendarCache", reflect.TypeOf((*MockStore)(nil).LoadCalendarCache), arg0)
+}
+
+// LoadDeadLetterNotifications mocks base method.
+func (m *MockStore) LoadDeadLetterNotifications() ([]*store.QueuedNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDeadLetterNotifications")
+	ret0, _ := ret[0].([]*store.QueuedNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDeadLetterNotifications indicates an expected call of LoadDeadLetterNotifications.
+func (mr *MockStoreMockRecorder) LoadDeadLetterNotifications() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDeadLetterNotifications", reflect.TypeOf((*MockStore)(nil).LoadDeadLetterNotifications))
+}
+
 // LoadEventMetadata mocks base method.
 func (m *MockStore) LoadEventMetadata(arg0 string) (*store.EventMetadata, error) {
 	m.ctrl.T.Helper()
@@ -251,6 +467,36 @@ func (mr *MockStoreMockRecorder) LoadEventMetadata(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadEventMetadata", reflect.TypeOf((*MockStore)(nil).LoadEventMetadata), arg0)
 }
 
+// LoadFreeBusyToken mocks base method.
+func (m *MockStore) LoadFreeBusyToken(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadFreeBusyToken", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadFreeBusyToken indicates an expected call of LoadFreeBusyToken.
+func (mr *MockStoreMockRecorder) LoadFreeBusyToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadFreeBusyToken", reflect.TypeOf((*MockStore)(nil).LoadFreeBusyToken), arg0)
+}
+
+// LoadFreeBusyTokenUser mocks base method.
+func (m *MockStore) LoadFreeBusyTokenUser(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadFreeBusyTokenUser", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadFreeBusyTokenUser indicates an expected call of LoadFreeBusyTokenUser.
+func (mr *MockStoreMockRecorder) LoadFreeBusyTokenUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadFreeBusyTokenUser", reflect.TypeOf((*MockStore)(nil).LoadFreeBusyTokenUser), arg0)
+}
+
 // LoadMattermostUserID mocks base method.
 func (m *MockStore) LoadMattermostUserID(arg0 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -266,6 +512,81 @@ func (mr *MockStoreMockRecorder) LoadMattermostUserID(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadMattermostUserID", reflect.TypeOf((*MockStore)(nil).LoadMattermostUserID), arg0)
 }
 
+// LoadNotificationBatch mocks base method.
+func (m *MockStore) LoadNotificationBatch(arg0 string) (*store.NotificationBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadNotificationBatch", arg0)
+	ret0, _ := ret[0].(*store.NotificationBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadNotificationBatch indicates an expected call of LoadNotificationBatch.
+func (mr *MockStoreMockRecorder) LoadNotificationBatch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadNotificationBatch", reflect.TypeOf((*MockStore)(nil).LoadNotificationBatch), arg0)
+}
+
+// LoadNotificationCertificates mocks base method.
+func (m *MockStore) LoadNotificationCertificates() (*store.NotificationCertificates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadNotificationCertificates")
+	ret0, _ := ret[0].(*store.NotificationCertificates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadNotificationCertificates indicates an expected call of LoadNotificationCertificates.
+func (mr *MockStoreMockRecorder) LoadNotificationCertificates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadNotificationCertificates", reflect.TypeOf((*MockStore)(nil).LoadNotificationCertificates))
+}
+
+// LoadNotificationQueue mocks base method.
+func (m *MockStore) LoadNotificationQueue() ([]*store.QueuedNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadNotificationQueue")
+	ret0, _ := ret[0].([]*store.QueuedNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadNotificationQueue indicates an expected call of LoadNotificationQueue.
+func (mr *MockStoreMockRecorder) LoadNotificationQueue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadNotificationQueue", reflect.TypeOf((*MockStore)(nil).LoadNotificationQueue))
+}
+
+// LoadRSVPDigestSentUntil mocks base method.
+func (m *MockStore) LoadRSVPDigestSentUntil(arg0 string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadRSVPDigestSentUntil", arg0)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadRSVPDigestSentUntil indicates an expected call of LoadRSVPDigestSentUntil.
+func (mr *MockStoreMockRecorder) LoadRSVPDigestSentUntil(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadRSVPDigestSentUntil", reflect.TypeOf((*MockStore)(nil).LoadRSVPDigestSentUntil), arg0)
+}
+
+// LoadRemoteCache mocks base method.
+func (m *MockStore) LoadRemoteCache(arg0, arg1, arg2 string, arg3 interface{}) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadRemoteCache", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadRemoteCache indicates an expected call of LoadRemoteCache.
+func (mr *MockStoreMockRecorder) LoadRemoteCache(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadRemoteCache", reflect.TypeOf((*MockStore)(nil).LoadRemoteCache), arg0, arg1, arg2, arg3)
+}
+
 // LoadSubscription mocks base method.
 func (m *MockStore) LoadSubscription(arg0 string) (*store.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -311,6 +632,21 @@ func (mr *MockStoreMockRecorder) LoadUserEvent(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserEvent", reflect.TypeOf((*MockStore)(nil).LoadUserEvent), arg0, arg1)
 }
 
+// LoadUserEventByRemoteID mocks base method.
+func (m *MockStore) LoadUserEventByRemoteID(arg0, arg1 string) (*store.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserEventByRemoteID", arg0, arg1)
+	ret0, _ := ret[0].(*store.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserEventByRemoteID indicates an expected call of LoadUserEventByRemoteID.
+func (mr *MockStoreMockRecorder) LoadUserEventByRemoteID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserEventByRemoteID", reflect.TypeOf((*MockStore)(nil).LoadUserEventByRemoteID), arg0, arg1)
+}
+
 // LoadUserFromIndex mocks base method.
 func (m *MockStore) LoadUserFromIndex(arg0 string) (*store.UserShort, error) {
 	m.ctrl.T.Helper()
@@ -341,6 +677,21 @@ func (mr *MockStoreMockRecorder) LoadUserIndex() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserIndex", reflect.TypeOf((*MockStore)(nil).LoadUserIndex))
 }
 
+// LoadUserList mocks base method.
+func (m *MockStore) LoadUserList(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserList", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserList indicates an expected call of LoadUserList.
+func (mr *MockStoreMockRecorder) LoadUserList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserList", reflect.TypeOf((*MockStore)(nil).LoadUserList), arg0)
+}
+
 // LoadUserWelcomePost mocks base method.
 func (m *MockStore) LoadUserWelcomePost(arg0 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -356,47 +707,50 @@ func (mr *MockStoreMockRecorder) LoadUserWelcomePost(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserWelcomePost", reflect.TypeOf((*MockStore)(nil).LoadUserWelcomePost), arg0)
 }
 
-// DisconnectUserFromStoreIfNecessary mocks base method
-func (m *MockStore) DisconnectUserFromStoreIfNecessary(arg0 error, arg1 string) {
+// MarkNotificationDelivered mocks base method.
+func (m *MockStore) MarkNotificationDelivered(arg0 string, arg1 time.Duration) (bool, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "DisconnectUserFromStoreIfNecessary", arg0, arg1)
+	ret := m.ctrl.Call(m, "MarkNotificationDelivered", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DisconnectUserFromStoreIfNecessary indicates an expected call of DisconnectUserFromStoreIfNecessary
-func (mr *MockStoreMockRecorder) DisconnectUserFromStoreIfNecessary(arg0, arg1 interface{}) *gomock.Call {
+// MarkNotificationDelivered indicates an expected call of MarkNotificationDelivered.
+func (mr *MockStoreMockRecorder) MarkNotificationDelivered(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectUserFromStoreIfNecessary", reflect.TypeOf((*MockStore)(nil).DisconnectUserFromStoreIfNecessary), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationDelivered", reflect.TypeOf((*MockStore)(nil).MarkNotificationDelivered), arg0, arg1)
 }
 
-// CheckUserConnected mocks base method
-func (m *MockStore) CheckUserConnected(arg0 string) bool {
+// ModifyUserIndex mocks base method.
+func (m *MockStore) ModifyUserIndex(arg0 func(store.UserIndex) (store.UserIndex, error)) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckUserConnected", arg0)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "ModifyUserIndex", arg0)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CheckUserConnected indicates an expected call of CheckUserConnected
-func (mr *MockStoreMockRecorder) CheckUserConnected(arg0 interface{}) *gomock.Call {
+// ModifyUserIndex indicates an expected call of ModifyUserIndex.
+func (mr *MockStoreMockRecorder) ModifyUserIndex(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserConnected", reflect.TypeOf((*MockStore)(nil).CheckUserConnected), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyUserIndex", reflect.TypeOf((*MockStore)(nil).ModifyUserIndex), arg0)
 }
 
-// ModifyUserIndex mocks base method
-func (m *MockStore) ModifyUserIndex(arg0 func(store.UserIndex) (store.UserIndex, error)) error {
+// PushNotifications mocks base method.
+func (m *MockStore) PushNotifications(arg0 []*remote.Notification, arg1 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ModifyUserIndex", arg0)
+	ret := m.ctrl.Call(m, "PushNotifications", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ModifyUserIndex indicates an expected call of ModifyUserIndex
-func (mr *MockStoreMockRecorder) ModifyUserIndex(arg0 interface{}) *gomock.Call {
+// PushNotifications indicates an expected call of PushNotifications.
+func (mr *MockStoreMockRecorder) PushNotifications(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyUserIndex", reflect.TypeOf((*MockStore)(nil).ModifyUserIndex), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushNotifications", reflect.TypeOf((*MockStore)(nil).PushNotifications), arg0, arg1)
 }
 
-// RefreshAndStoreToken mocks base method
+// RefreshAndStoreToken mocks base method.
 func (m *MockStore) RefreshAndStoreToken(arg0 *oauth2.Token, arg1 *oauth2.Config, arg2 string) (*oauth2.Token, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RefreshAndStoreToken", arg0, arg1, arg2)
@@ -405,12 +759,26 @@ func (m *MockStore) RefreshAndStoreToken(arg0 *oauth2.Token, arg1 *oauth2.Config
 	return ret0, ret1
 }
 
-// RefreshAndStoreToken indicates an expected call of RefreshAndStoreToken
+// RefreshAndStoreToken indicates an expected call of RefreshAndStoreToken.
 func (mr *MockStoreMockRecorder) RefreshAndStoreToken(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAndStoreToken", reflect.TypeOf((*MockStore)(nil).RefreshAndStoreToken), arg0, arg1, arg2)
 }
 
+// RemoveFromUserList mocks base method.
+func (m *MockStore) RemoveFromUserList(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromUserList", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromUserList indicates an expected call of RemoveFromUserList.
+func (mr *MockStoreMockRecorder) RemoveFromUserList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromUserList", reflect.TypeOf((*MockStore)(nil).RemoveFromUserList), arg0, arg1)
+}
+
 // RemovePostID mocks base method.
 func (m *MockStore) RemovePostID(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -425,6 +793,20 @@ func (mr *MockStoreMockRecorder) RemovePostID(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePostID", reflect.TypeOf((*MockStore)(nil).RemovePostID), arg0, arg1)
 }
 
+// RetryNotification mocks base method.
+func (m *MockStore) RetryNotification(arg0 string, arg1 time.Time, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryNotification", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryNotification indicates an expected call of RetryNotification.
+func (mr *MockStoreMockRecorder) RetryNotification(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryNotification", reflect.TypeOf((*MockStore)(nil).RetryNotification), arg0, arg1, arg2)
+}
+
 // SearchInUserIndex mocks base method.
 func (m *MockStore) SearchInUserIndex(arg0 string, arg1 int) (store.UserIndex, error) {
 	m.ctrl.T.Helper()
@@ -510,6 +892,20 @@ func (mr *MockStoreMockRecorder) SetSetting(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSetting", reflect.TypeOf((*MockStore)(nil).SetSetting), arg0, arg1, arg2)
 }
 
+// StoreCalendarCache mocks base method.
+func (m *MockStore) StoreCalendarCache(arg0 string, arg1 *store.CalendarCache) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreCalendarCache", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreCalendarCache indicates an expected call of StoreCalendarCache.
+func (mr *MockStoreMockRecorder) StoreCalendarCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreCalendarCache", reflect.TypeOf((*MockStore)(nil).StoreCalendarCache), arg0, arg1)
+}
+
 // StoreEventMetadata mocks base method.
 func (m *MockStore) StoreEventMetadata(arg0 string, arg1 *store.EventMetadata) error {
 	m.ctrl.T.Helper()
@@ -524,6 +920,34 @@ func (mr *MockStoreMockRecorder) StoreEventMetadata(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreEventMetadata", reflect.TypeOf((*MockStore)(nil).StoreEventMetadata), arg0, arg1)
 }
 
+// StoreFreeBusyToken mocks base method.
+func (m *MockStore) StoreFreeBusyToken(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreFreeBusyToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreFreeBusyToken indicates an expected call of StoreFreeBusyToken.
+func (mr *MockStoreMockRecorder) StoreFreeBusyToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreFreeBusyToken", reflect.TypeOf((*MockStore)(nil).StoreFreeBusyToken), arg0, arg1)
+}
+
+// StoreNotificationCertificates mocks base method.
+func (m *MockStore) StoreNotificationCertificates(arg0 *store.NotificationCertificates) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreNotificationCertificates", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreNotificationCertificates indicates an expected call of StoreNotificationCertificates.
+func (mr *MockStoreMockRecorder) StoreNotificationCertificates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreNotificationCertificates", reflect.TypeOf((*MockStore)(nil).StoreNotificationCertificates), arg0)
+}
+
 // StoreOAuth2State mocks base method.
 func (m *MockStore) StoreOAuth2State(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -538,6 +962,48 @@ func (mr *MockStoreMockRecorder) StoreOAuth2State(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreOAuth2State", reflect.TypeOf((*MockStore)(nil).StoreOAuth2State), arg0)
 }
 
+// StoreRSVPDigestSentUntil mocks base method.
+func (m *MockStore) StoreRSVPDigestSentUntil(arg0 string, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreRSVPDigestSentUntil", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreRSVPDigestSentUntil indicates an expected call of StoreRSVPDigestSentUntil.
+func (mr *MockStoreMockRecorder) StoreRSVPDigestSentUntil(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreRSVPDigestSentUntil", reflect.TypeOf((*MockStore)(nil).StoreRSVPDigestSentUntil), arg0, arg1)
+}
+
+// StoreRemoteCache mocks base method.
+func (m *MockStore) StoreRemoteCache(arg0, arg1, arg2, arg3 string, arg4 interface{}, arg5 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreRemoteCache", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreRemoteCache indicates an expected call of StoreRemoteCache.
+func (mr *MockStoreMockRecorder) StoreRemoteCache(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreRemoteCache", reflect.TypeOf((*MockStore)(nil).StoreRemoteCache), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// StoreSubscription mocks base method.
+func (m *MockStore) StoreSubscription(arg0 *store.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreSubscription", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreSubscription indicates an expected call of StoreSubscription.
+func (mr *MockStoreMockRecorder) StoreSubscription(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreSubscription", reflect.TypeOf((*MockStore)(nil).StoreSubscription), arg0)
+}
+
 // StoreUser mocks base method.
 func (m *MockStore) StoreUser(arg0 *store.User) error {
 	m.ctrl.T.Helper()
@@ -650,6 +1116,50 @@ func (mr *MockStoreMockRecorder) StoreUserWelcomePost(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreUserWelcomePost", reflect.TypeOf((*MockStore)(nil).StoreUserWelcomePost), arg0, arg1)
 }
 
+// TakeDeadLetterNotifications mocks base method.
+func (m *MockStore) TakeDeadLetterNotifications() ([]*store.QueuedNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeDeadLetterNotifications")
+	ret0, _ := ret[0].([]*store.QueuedNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeDeadLetterNotifications indicates an expected call of TakeDeadLetterNotifications.
+func (mr *MockStoreMockRecorder) TakeDeadLetterNotifications() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeDeadLetterNotifications", reflect.TypeOf((*MockStore)(nil).TakeDeadLetterNotifications))
+}
+
+// TakeNotificationBatch mocks base method.
+func (m *MockStore) TakeNotificationBatch(arg0 string) (*store.NotificationBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeNotificationBatch", arg0)
+	ret0, _ := ret[0].(*store.NotificationBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeNotificationBatch indicates an expected call of TakeNotificationBatch.
+func (mr *MockStoreMockRecorder) TakeNotificationBatch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeNotificationBatch", reflect.TypeOf((*MockStore)(nil).TakeNotificationBatch), arg0)
+}
+
+// UnmarkNotificationDelivered mocks base method.
+func (m *MockStore) UnmarkNotificationDelivered(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmarkNotificationDelivered", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmarkNotificationDelivered indicates an expected call of UnmarkNotificationDelivered.
+func (mr *MockStoreMockRecorder) UnmarkNotificationDelivered(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmarkNotificationDelivered", reflect.TypeOf((*MockStore)(nil).UnmarkNotificationDelivered), arg0)
+}
+
 // VerifyOAuth2State mocks base method.
 func (m *MockStore) VerifyOAuth2State(arg0 string) error {
 	m.ctrl.T.Helper()
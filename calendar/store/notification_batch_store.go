@@ -0,0 +1,98 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+// MaxNotificationBatchSize is the number of notifications kept for a user
+// until they are delivered. Older notifications are dropped first.
+const MaxNotificationBatchSize = 50
+
+// NotificationBatch holds the event notifications of a user during their quiet
+// hours or batching period, so they are delivered together as one post.
+type NotificationBatch struct {
+	Attachments []*model.SlackAttachment `json:"attachments"`
+	Since       int64                    `json:"since"`   // Unix time of the first held notification
+	Dropped     int                      `json:"dropped"` // Notifications dropped because the batch was full
+}
+
+// NotificationBatchStore keeps the batches in the KV store, so they survive
+// plugin restarts. Batches are modified atomically, as notifications are added
+// by the notification processor while the batches are delivered by a job.
+type NotificationBatchStore interface {
+	LoadNotificationBatch(mattermostUserID string) (*NotificationBatch, error)
+	AddToNotificationBatch(mattermostUserID string, attachment *model.SlackAttachment, now time.Time) error
+	TakeNotificationBatch(mattermostUserID string) (*NotificationBatch, error)
+}
+
+func (s *pluginStore) LoadNotificationBatch(mattermostUserID string) (*NotificationBatch, error) {
+	batch := NotificationBatch{}
+	err := kvstore.LoadJSON(s.notificationBatchKV, mattermostUserID, &batch)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (s *pluginStore) AddToNotificationBatch(mattermostUserID string, attachment *model.SlackAttachment, now time.Time) error {
+	err := kvstore.AtomicModify(s.notificationBatchKV, mattermostUserID, func(initial []byte, storeErr error) ([]byte, error) {
+		if storeErr != nil && storeErr != ErrNotFound {
+			return nil, storeErr
+		}
+
+		batch := NotificationBatch{Since: now.Unix()}
+		if len(initial) > 0 {
+			err := json.Unmarshal(initial, &batch)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		batch.Attachments = append(batch.Attachments, attachment)
+		if extra := len(batch.Attachments) - MaxNotificationBatchSize; extra > 0 {
+			batch.Attachments = batch.Attachments[extra:]
+			batch.Dropped += extra
+		}
+
+		return json.Marshal(batch)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to add notification to batch")
+	}
+	return nil
+}
+
+// TakeNotificationBatch removes the batch of the user and returns it, or
+// returns ErrNotFound if no notification is held for the user.
+func (s *pluginStore) TakeNotificationBatch(mattermostUserID string) (*NotificationBatch, error) {
+	var batch *NotificationBatch
+	err := kvstore.AtomicModify(s.notificationBatchKV, mattermostUserID, func(initial []byte, storeErr error) ([]byte, error) {
+		batch = nil
+		if storeErr != nil {
+			return initial, storeErr
+		}
+
+		batch = &NotificationBatch{}
+		err := json.Unmarshal(initial, batch)
+		if err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if errors.Cause(err) == ErrNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to take notification batch")
+	}
+	return batch, nil
+}
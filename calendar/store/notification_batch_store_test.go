@@ -0,0 +1,92 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddToNotificationBatch(t *testing.T) {
+	now := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+	sa := &model.SlackAttachment{Title: "(new) Planning"}
+
+	fullBatch := NotificationBatch{Since: now.Add(-time.Hour).Unix()}
+	for i := 0; i < MaxNotificationBatchSize; i++ {
+		fullBatch.Attachments = append(fullBatch.Attachments, &model.SlackAttachment{Title: "older"})
+	}
+	fullBatchJSON, err := json.Marshal(fullBatch)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name            string
+		stored          []byte
+		expectedSince   int64
+		expectedCount   int
+		expectedDropped int
+	}{
+		{
+			name:          "first notification",
+			expectedSince: now.Unix(),
+			expectedCount: 1,
+		},
+		{
+			name:            "full batch",
+			stored:          fullBatchJSON,
+			expectedSince:   fullBatch.Since,
+			expectedCount:   MaxNotificationBatchSize,
+			expectedDropped: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+
+			var stored NotificationBatch
+			mockAPI.On("KVGet", MockString).Return(tc.stored, nil).Times(1)
+			mockAPI.On("KVSetWithOptions", MockString, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				opts := args.Get(2).(model.PluginKVSetOptions)
+				require.True(t, opts.Atomic)
+				require.Equal(t, tc.stored, opts.OldValue)
+				require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &stored))
+			}).Return(true, nil).Times(1)
+
+			err := store.AddToNotificationBatch(MockMMUserID, sa, now)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedSince, stored.Since)
+			require.Len(t, stored.Attachments, tc.expectedCount)
+			require.Equal(t, sa.Title, stored.Attachments[len(stored.Attachments)-1].Title)
+			require.Equal(t, tc.expectedDropped, stored.Dropped)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTakeNotificationBatch(t *testing.T) {
+	t.Run("no batch", func(t *testing.T) {
+		mockAPI, store, _, _, _ := GetMockSetup(t)
+		mockAPI.On("KVGet", MockString).Return(nil, nil).Times(1)
+
+		_, err := store.TakeNotificationBatch(MockMMUserID)
+		require.Equal(t, ErrNotFound, err)
+		mockAPI.AssertExpectations(t)
+	})
+
+	t.Run("batch removed", func(t *testing.T) {
+		mockAPI, store, _, _, _ := GetMockSetup(t)
+		batchJSON := []byte(`{"attachments":[{"title":"(new) Planning"}],"since":1581501600}`)
+		mockAPI.On("KVGet", MockString).Return(batchJSON, nil).Times(1)
+		mockAPI.On("KVSetWithOptions", MockString, []byte(nil), mock.Anything).Return(true, nil).Times(1)
+
+		batch, err := store.TakeNotificationBatch(MockMMUserID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1581501600), batch.Since)
+		require.Len(t, batch.Attachments, 1)
+		mockAPI.AssertExpectations(t)
+	})
+}
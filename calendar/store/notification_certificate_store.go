@@ -0,0 +1,63 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+const notificationCertificatesKey = "certificates"
+
+// NotificationCertificate is a certificate the remote encrypts the content of
+// the notifications with, and its private key.
+type NotificationCertificate struct {
+	ID          string `json:"id"`
+	Certificate []byte `json:"certificate"`
+	PrivateKey  []byte `json:"private_key"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+// NotificationCertificates are the certificate new subscriptions are created
+// with, and the one it replaced, still used by the older subscriptions.
+type NotificationCertificates struct {
+	Current  *NotificationCertificate `json:"current"`
+	Previous *NotificationCertificate `json:"previous,omitempty"`
+}
+
+// Get returns the certificate with the ID, or nil.
+func (c *NotificationCertificates) Get(id string) *NotificationCertificate {
+	if c.Current != nil && c.Current.ID == id {
+		return c.Current
+	}
+	if c.Previous != nil && c.Previous.ID == id {
+		return c.Previous
+	}
+	return nil
+}
+
+// NotificationCertificateStore keeps the certificates of the notifications.
+// They hold private keys, so they are always encrypted.
+type NotificationCertificateStore interface {
+	LoadNotificationCertificates() (*NotificationCertificates, error)
+	StoreNotificationCertificates(*NotificationCertificates) error
+}
+
+func (s *pluginStore) LoadNotificationCertificates() (*NotificationCertificates, error) {
+	certificates := &NotificationCertificates{}
+	err := kvstore.LoadJSON(s.notificationCertificateKV, notificationCertificatesKey, certificates)
+	if err != nil {
+		return nil, err
+	}
+	return certificates, nil
+}
+
+func (s *pluginStore) StoreNotificationCertificates(certificates *NotificationCertificates) error {
+	err := kvstore.StoreJSON(s.notificationCertificateKV, notificationCertificatesKey, certificates)
+	if err != nil {
+		return errors.Wrap(err, "failed to store notification certificates")
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+)
+
+// NotificationDeliveryStore keeps track of the webhook deliveries received by
+// any node of the cluster, so that the repeated ones are dropped.
+type NotificationDeliveryStore interface {
+	// MarkNotificationDelivered returns false if the delivery was already
+	// marked, and did not expire yet.
+	MarkNotificationDelivered(deliveryID string, ttl time.Duration) (bool, error)
+	// UnmarkNotificationDelivered is for the deliveries that failed to be
+	// queued, so that they are accepted when repeated.
+	UnmarkNotificationDelivered(deliveryID string) error
+}
+
+func (s *pluginStore) MarkNotificationDelivered(deliveryID string, ttl time.Duration) (bool, error) {
+	ok, err := s.notificationDeliveryKV.StoreWithOptions(deliveryID, []byte{1}, model.PluginKVSetOptions{
+		Atomic:          true,
+		OldValue:        nil,
+		ExpireInSeconds: int64(ttl / time.Second),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark notification delivered")
+	}
+	return ok, nil
+}
+
+func (s *pluginStore) UnmarkNotificationDelivered(deliveryID string) error {
+	return s.notificationDeliveryKV.Delete(deliveryID)
+}
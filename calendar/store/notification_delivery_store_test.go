@@ -0,0 +1,43 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkNotificationDelivered(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		isNew    bool
+		expected bool
+	}{
+		{
+			name:     "new delivery",
+			isNew:    true,
+			expected: true,
+		},
+		{
+			name:     "repeated delivery",
+			isNew:    false,
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			mockAPI.On("KVSetWithOptions", MockString, []byte{1}, model.PluginKVSetOptions{
+				Atomic:          true,
+				ExpireInSeconds: 4 * 60 * 60,
+			}).Return(tc.isNew, nil).Times(1)
+
+			isNew, err := store.MarkNotificationDelivered("delivery_id", 4*time.Hour)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, isNew)
+		})
+	}
+}
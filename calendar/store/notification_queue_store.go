@@ -0,0 +1,424 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+// The queue and the failed notifications are lists of IDs, each notification
+// being stored under its own key.
+const (
+	notificationQueueKey        = "queue"
+	deadLetterNotificationKey   = "dead_letter"
+	queuedNotificationKeyPrefix = "notification_"
+)
+
+const (
+	// MaxNotificationQueueSize is the number of notifications waiting to be
+	// processed, new notifications are rejected above it.
+	MaxNotificationQueueSize = 1024
+
+	// MaxDeadLetterNotifications is the number of failed notifications kept
+	// for the admins. Older notifications are dropped first.
+	MaxDeadLetterNotifications = 100
+)
+
+var ErrNotificationQueueFull = errors.New("notification queue full")
+
+// QueuedNotification is a webhook notification waiting to be processed, or
+// one that failed to be processed.
+type QueuedNotification struct {
+	ID           string               `json:"id"`
+	Notification *remote.Notification `json:"notification"`
+	QueuedAt     int64                `json:"queued_at"`
+	Attempts     int                  `json:"attempts"`
+	NextAttempt  int64                `json:"next_attempt"`  // Unix time before which the notification is not processed
+	ClaimedUntil int64                `json:"claimed_until"` // Unix time until which a node is processing the notification
+	LastError    string               `json:"last_error,omitempty"`
+}
+
+// NotificationQueueStore keeps the webhook notifications in the KV store until
+// they are processed, so they are not lost on plugin restarts. The queue is
+// shared by all the nodes of a cluster: a notification is claimed by a node
+// for a while before processing it, and the claim expires if the node fails.
+type NotificationQueueStore interface {
+	PushNotifications(notifications []*remote.Notification, now time.Time) error
+	ClaimNotification(now time.Time, claimFor time.Duration) (*QueuedNotification, error)
+	CompleteNotification(id string) error
+	RetryNotification(id string, nextAttempt time.Time, lastError string) error
+	DeadLetterNotification(id string, lastError string) error
+	LoadNotificationQueue() ([]*QueuedNotification, error)
+	LoadDeadLetterNotifications() ([]*QueuedNotification, error)
+	TakeDeadLetterNotifications() ([]*QueuedNotification, error)
+	DeleteDeadLetterNotifications(ids []string) error
+	CountNotifications() (queued, deadLetters int, err error)
+}
+
+func (s *pluginStore) PushNotifications(notifications []*remote.Notification, now time.Time) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	// The notifications are stored before they are indexed, so that the
+	// nodes never claim an ID without a notification.
+	ids := []string{}
+	for _, n := range notifications {
+		queued := &QueuedNotification{
+			ID:           model.NewId(),
+			Notification: n,
+			QueuedAt:     now.Unix(),
+			NextAttempt:  now.Unix(),
+		}
+		err := kvstore.StoreJSON(s.notificationQueueKV, queuedNotificationKey(queued.ID), queued)
+		if err != nil {
+			s.deleteQueuedNotifications(ids)
+			return errors.Wrap(err, "failed to store queued notification")
+		}
+		ids = append(ids, queued.ID)
+	}
+
+	err := s.modifyNotificationIndex(notificationQueueKey, func(queue []string) ([]string, error) {
+		if len(queue)+len(ids) > MaxNotificationQueueSize {
+			return nil, ErrNotificationQueueFull
+		}
+		return append(queue, ids...), nil
+	})
+	if err != nil {
+		s.deleteQueuedNotifications(ids)
+		return err
+	}
+	return nil
+}
+
+// ClaimNotification returns the oldest notification that is ready to be
+// processed, and claims it so no other node processes it. It returns
+// ErrNotFound if no notification is ready.
+//
+// The notifications waiting for a retry or claimed by another node are
+// skipped. The IDs left in the queue without a notification are removed from
+// it.
+func (s *pluginStore) ClaimNotification(now time.Time, claimFor time.Duration) (*QueuedNotification, error) {
+	ids, err := s.loadNotificationIndex(notificationQueueKey)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	defer func() {
+		s.removeMissingNotifications(missing)
+	}()
+	for _, id := range ids {
+		key := queuedNotificationKey(id)
+		initial, err := s.notificationQueueKV.Load(key)
+		if err == ErrNotFound {
+			missing = append(missing, id)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load queued notification")
+		}
+		q := &QueuedNotification{}
+		err = json.Unmarshal(initial, q)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load queued notification")
+		}
+		if q.NextAttempt > now.Unix() || q.ClaimedUntil > now.Unix() {
+			continue
+		}
+
+		q.Attempts++
+		q.ClaimedUntil = now.Add(claimFor).Unix()
+		data, err := json.Marshal(q)
+		if err != nil {
+			return nil, err
+		}
+		// Another node claiming the notification first changes its value,
+		// in which case the next ready one is claimed.
+		claimed, err := s.notificationQueueKV.StoreWithOptions(key, data, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: initial,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to claim queued notification")
+		}
+		if claimed {
+			return q, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *pluginStore) CompleteNotification(id string) error {
+	err := s.modifyNotificationIndex(notificationQueueKey, func(queue []string) ([]string, error) {
+		return removeNotificationID(queue, id), nil
+	})
+	if err != nil {
+		return err
+	}
+	s.deleteQueuedNotifications([]string{id})
+	return nil
+}
+
+// RetryNotification releases the claim on the notification, so it is
+// processed again after nextAttempt, and moves it to the tail of the queue.
+func (s *pluginStore) RetryNotification(id string, nextAttempt time.Time, lastError string) error {
+	err := s.modifyQueuedNotification(id, func(q *QueuedNotification) {
+		q.NextAttempt = nextAttempt.Unix()
+		q.ClaimedUntil = 0
+		q.LastError = lastError
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.modifyNotificationIndex(notificationQueueKey, func(queue []string) ([]string, error) {
+		n := len(queue)
+		queue = removeNotificationID(queue, id)
+		if len(queue) == n {
+			return queue, nil
+		}
+		return append(queue, id), nil
+	})
+}
+
+// DeadLetterNotification moves the notification from the queue to the list
+// of failed notifications.
+func (s *pluginStore) DeadLetterNotification(id string, lastError string) error {
+	err := s.modifyQueuedNotification(id, func(q *QueuedNotification) {
+		q.ClaimedUntil = 0
+		q.LastError = lastError
+	})
+	if err == ErrNotFound {
+		s.removeMissingNotifications([]string{id})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.modifyNotificationIndex(notificationQueueKey, func(queue []string) ([]string, error) {
+		return removeNotificationID(queue, id), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var dropped []string
+	err = s.modifyNotificationIndex(deadLetterNotificationKey, func(deadLetters []string) ([]string, error) {
+		deadLetters = append(removeNotificationID(deadLetters, id), id)
+		dropped = nil
+		if extra := len(deadLetters) - MaxDeadLetterNotifications; extra > 0 {
+			dropped = append(dropped, deadLetters[:extra]...)
+			deadLetters = deadLetters[extra:]
+		}
+		return deadLetters, nil
+	})
+	if err != nil {
+		return err
+	}
+	s.deleteQueuedNotifications(dropped)
+	return nil
+}
+
+func (s *pluginStore) LoadNotificationQueue() ([]*QueuedNotification, error) {
+	return s.loadQueuedNotifications(notificationQueueKey)
+}
+
+func (s *pluginStore) LoadDeadLetterNotifications() ([]*QueuedNotification, error) {
+	return s.loadQueuedNotifications(deadLetterNotificationKey)
+}
+
+// CountNotifications returns the number of notifications in the queue and in
+// the dead-letter list, reading only their index.
+func (s *pluginStore) CountNotifications() (queued, deadLetters int, err error) {
+	queue, err := s.loadNotificationIndex(notificationQueueKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	deadLetterIDs, err := s.loadNotificationIndex(deadLetterNotificationKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(queue), len(deadLetterIDs), nil
+}
+
+// TakeDeadLetterNotifications removes the failed notifications and returns
+// them.
+func (s *pluginStore) TakeDeadLetterNotifications() ([]*QueuedNotification, error) {
+	var ids []string
+	err := s.modifyNotificationIndex(deadLetterNotificationKey, func(stored []string) ([]string, error) {
+		ids = stored
+		return []string{}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters, err := s.loadQueuedNotificationsByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	s.deleteQueuedNotifications(ids)
+	return deadLetters, nil
+}
+
+// DeleteDeadLetterNotifications removes the given failed notifications, once
+// they were queued again.
+func (s *pluginStore) DeleteDeadLetterNotifications(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	deleted := map[string]bool{}
+	for _, id := range ids {
+		deleted[id] = true
+	}
+	err := s.modifyNotificationIndex(deadLetterNotificationKey, func(stored []string) ([]string, error) {
+		kept := []string{}
+		for _, id := range stored {
+			if !deleted[id] {
+				kept = append(kept, id)
+			}
+		}
+		return kept, nil
+	})
+	if err != nil {
+		return err
+	}
+	s.deleteQueuedNotifications(ids)
+	return nil
+}
+
+func (s *pluginStore) loadQueuedNotifications(indexKey string) ([]*QueuedNotification, error) {
+	ids, err := s.loadNotificationIndex(indexKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadQueuedNotificationsByID(ids)
+}
+
+// loadQueuedNotificationsByID skips the notifications removed since the
+// index was loaded.
+func (s *pluginStore) loadQueuedNotificationsByID(ids []string) ([]*QueuedNotification, error) {
+	queue := []*QueuedNotification{}
+	for _, id := range ids {
+		q := &QueuedNotification{}
+		err := kvstore.LoadJSON(s.notificationQueueKV, queuedNotificationKey(id), q)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load queued notification")
+		}
+		queue = append(queue, q)
+	}
+	return queue, nil
+}
+
+func (s *pluginStore) modifyQueuedNotification(id string, modify func(q *QueuedNotification)) error {
+	err := kvstore.AtomicModify(s.notificationQueueKV, queuedNotificationKey(id), func(initial []byte, storeErr error) ([]byte, error) {
+		if storeErr != nil {
+			return nil, storeErr
+		}
+		q := &QueuedNotification{}
+		err := json.Unmarshal(initial, q)
+		if err != nil {
+			return nil, err
+		}
+		modify(q)
+		return json.Marshal(q)
+	})
+	if errors.Cause(err) == ErrNotFound {
+		return ErrNotFound
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to modify queued notification")
+	}
+	return nil
+}
+
+func (s *pluginStore) deleteQueuedNotifications(ids []string) {
+	for _, id := range ids {
+		err := s.notificationQueueKV.Delete(queuedNotificationKey(id))
+		if err != nil {
+			s.Logger.Warnf("Failed to delete queued notification %s. err=%v", id, err)
+		}
+	}
+}
+
+// removeMissingNotifications removes from the queue the IDs whose
+// notification is missing, so they are not looked at again.
+func (s *pluginStore) removeMissingNotifications(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	err := s.modifyNotificationIndex(notificationQueueKey, func(queue []string) ([]string, error) {
+		for _, id := range ids {
+			queue = removeNotificationID(queue, id)
+		}
+		return queue, nil
+	})
+	if err != nil {
+		s.Logger.Warnf("Failed to remove missing notifications from the queue. err=%v", err)
+	}
+}
+
+func (s *pluginStore) loadNotificationIndex(key string) ([]string, error) {
+	ids := []string{}
+	err := kvstore.LoadJSON(s.notificationQueueKV, key, &ids)
+	if err != nil && err != ErrNotFound {
+		return nil, errors.Wrap(err, "failed to load notification queue")
+	}
+	return ids, nil
+}
+
+func (s *pluginStore) modifyNotificationIndex(key string, modify func(ids []string) ([]string, error)) error {
+	err := kvstore.AtomicModify(s.notificationQueueKV, key, func(initial []byte, storeErr error) ([]byte, error) {
+		if storeErr != nil && storeErr != ErrNotFound {
+			return nil, storeErr
+		}
+
+		ids := []string{}
+		if len(initial) > 0 {
+			err := json.Unmarshal(initial, &ids)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		ids, err := modify(ids)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(ids)
+	})
+	if errors.Cause(err) == ErrNotificationQueueFull {
+		return ErrNotificationQueueFull
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to modify notification queue")
+	}
+	return nil
+}
+
+func queuedNotificationKey(id string) string {
+	return queuedNotificationKeyPrefix + id
+}
+
+func removeNotificationID(ids []string, id string) []string {
+	for i, v := range ids {
+		if v == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
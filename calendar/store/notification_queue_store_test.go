@@ -0,0 +1,340 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+// notificationQueueKV keeps the values written to the KV store by the
+// notification queue, by their hashed key.
+type notificationQueueKV struct {
+	plugin.API
+	values map[string][]byte
+	// Atomic writes to the conflicting keys fail, as if another node
+	// modified them first.
+	conflicting map[string]bool
+}
+
+func newNotificationQueueStore() (Store, *notificationQueueKV) {
+	kv := &notificationQueueKV{
+		values:      map[string][]byte{},
+		conflicting: map[string]bool{},
+	}
+	return NewPluginStore(kv, &bot.NilLogger{}, nil, nil, false, nil), kv
+}
+
+func (kv *notificationQueueKV) KVGet(key string) ([]byte, *model.AppError) {
+	return kv.values[key], nil
+}
+
+func (kv *notificationQueueKV) KVSet(key string, value []byte) *model.AppError {
+	kv.values[key] = value
+	return nil
+}
+
+func (kv *notificationQueueKV) KVSetWithOptions(key string, value []byte, opts model.PluginKVSetOptions) (bool, *model.AppError) {
+	if opts.Atomic && (kv.conflicting[key] || !bytes.Equal(kv.values[key], opts.OldValue)) {
+		return false, nil
+	}
+	kv.values[key] = value
+	return true, nil
+}
+
+func (kv *notificationQueueKV) KVDelete(key string) *model.AppError {
+	delete(kv.values, key)
+	return nil
+}
+
+func (kv *notificationQueueKV) hashedKey(key string) string {
+	return fmt.Sprintf("%s%x", NotificationQueuePrefix, md5.Sum([]byte(key))) //nolint:gosec
+}
+
+func (kv *notificationQueueKV) set(t *testing.T, indexKey string, queue []*QueuedNotification) {
+	ids := []string{}
+	for _, q := range queue {
+		data, err := json.Marshal(q)
+		require.NoError(t, err)
+		kv.values[kv.hashedKey(queuedNotificationKey(q.ID))] = data
+		ids = append(ids, q.ID)
+	}
+	data, err := json.Marshal(ids)
+	require.NoError(t, err)
+	kv.values[kv.hashedKey(indexKey)] = data
+}
+
+func (kv *notificationQueueKV) ids(t *testing.T, indexKey string) []string {
+	ids := []string{}
+	if data := kv.values[kv.hashedKey(indexKey)]; data != nil {
+		require.NoError(t, json.Unmarshal(data, &ids))
+	}
+	return ids
+}
+
+func (kv *notificationQueueKV) get(t *testing.T, id string) *QueuedNotification {
+	data := kv.values[kv.hashedKey(queuedNotificationKey(id))]
+	if data == nil {
+		return nil
+	}
+	q := &QueuedNotification{}
+	require.NoError(t, json.Unmarshal(data, q))
+	return q
+}
+
+func TestPushNotifications(t *testing.T) {
+	now := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+
+	t.Run("queued", func(t *testing.T) {
+		store, kv := newNotificationQueueStore()
+
+		err := store.PushNotifications([]*remote.Notification{{SubscriptionID: MockSubscriptionID}}, now)
+		require.NoError(t, err)
+
+		ids := kv.ids(t, notificationQueueKey)
+		require.Len(t, ids, 1)
+		queued := kv.get(t, ids[0])
+		require.NotNil(t, queued)
+		require.Equal(t, MockSubscriptionID, queued.Notification.SubscriptionID)
+		require.Equal(t, now.Unix(), queued.NextAttempt)
+		require.Len(t, kv.values, 2)
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		store, kv := newNotificationQueueStore()
+		full := make([]string, MaxNotificationQueueSize)
+		for i := range full {
+			full[i] = fmt.Sprintf("id%d", i)
+		}
+		fullJSON, err := json.Marshal(full)
+		require.NoError(t, err)
+		kv.values[kv.hashedKey(notificationQueueKey)] = fullJSON
+
+		err = store.PushNotifications([]*remote.Notification{{SubscriptionID: MockSubscriptionID}}, now)
+		require.Equal(t, ErrNotificationQueueFull, err)
+		require.Len(t, kv.values, 1, "the rejected notification is deleted")
+		require.Len(t, kv.ids(t, notificationQueueKey), MaxNotificationQueueSize)
+	})
+}
+
+func TestClaimNotification(t *testing.T) {
+	now := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+	newQueued := func(id string, nextAttempt, claimedUntil time.Time) *QueuedNotification {
+		return &QueuedNotification{
+			ID:           id,
+			Notification: &remote.Notification{SubscriptionID: MockSubscriptionID},
+			NextAttempt:  nextAttempt.Unix(),
+			ClaimedUntil: claimedUntil.Unix(),
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		queue      []*QueuedNotification
+		expectedID string
+	}{
+		{
+			name: "empty queue",
+		},
+		{
+			name: "oldest ready notification",
+			queue: []*QueuedNotification{
+				newQueued("claimed", now, now.Add(time.Minute)),
+				newQueued("retry later", now.Add(time.Minute), time.Time{}),
+				newQueued("claim expired", now, now.Add(-time.Second)),
+				newQueued("ready", now, time.Time{}),
+			},
+			expectedID: "claim expired",
+		},
+		{
+			name: "none ready",
+			queue: []*QueuedNotification{
+				newQueued("claimed", now, now.Add(time.Minute)),
+				newQueued("retry later", now.Add(time.Minute), time.Time{}),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store, kv := newNotificationQueueStore()
+			kv.set(t, notificationQueueKey, tc.queue)
+
+			claimed, err := store.ClaimNotification(now, time.Minute)
+			if tc.expectedID == "" {
+				require.Equal(t, ErrNotFound, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedID, claimed.ID)
+			require.Equal(t, 1, claimed.Attempts)
+			require.Equal(t, now.Add(time.Minute).Unix(), claimed.ClaimedUntil)
+			require.Equal(t, now.Add(time.Minute).Unix(), kv.get(t, tc.expectedID).ClaimedUntil)
+			require.Len(t, kv.ids(t, notificationQueueKey), len(tc.queue))
+		})
+	}
+
+	t.Run("claimed by another node meanwhile", func(t *testing.T) {
+		store, kv := newNotificationQueueStore()
+		kv.set(t, notificationQueueKey, []*QueuedNotification{
+			newQueued("first", now, time.Time{}),
+			newQueued("second", now, time.Time{}),
+		})
+		kv.conflicting[kv.hashedKey(queuedNotificationKey("first"))] = true
+
+		claimed, err := store.ClaimNotification(now, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, "second", claimed.ID)
+		require.Zero(t, kv.get(t, "first").Attempts)
+	})
+
+	t.Run("entries not ready are skipped", func(t *testing.T) {
+		store, kv := newNotificationQueueStore()
+		queue := []*QueuedNotification{}
+		for i := 0; i < 30; i++ {
+			queue = append(queue, newQueued(fmt.Sprintf("claimed%d", i), now, now.Add(time.Minute)))
+		}
+		queue = append(queue, newQueued("ready", now, time.Time{}))
+		kv.set(t, notificationQueueKey, queue)
+
+		claimed, err := store.ClaimNotification(now, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, "ready", claimed.ID)
+	})
+
+	t.Run("missing notifications are removed from the queue", func(t *testing.T) {
+		store, kv := newNotificationQueueStore()
+		kv.set(t, notificationQueueKey, []*QueuedNotification{
+			newQueued("missing", now, time.Time{}),
+			newQueued("ready", now, time.Time{}),
+		})
+		delete(kv.values, kv.hashedKey(queuedNotificationKey("missing")))
+
+		claimed, err := store.ClaimNotification(now, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, "ready", claimed.ID)
+		require.Equal(t, []string{"ready"}, kv.ids(t, notificationQueueKey))
+	})
+}
+
+func TestCompleteNotification(t *testing.T) {
+	store, kv := newNotificationQueueStore()
+	kv.set(t, notificationQueueKey, []*QueuedNotification{{ID: "done"}, {ID: "pending"}})
+
+	err := store.CompleteNotification("done")
+	require.NoError(t, err)
+	require.Equal(t, []string{"pending"}, kv.ids(t, notificationQueueKey))
+	require.Nil(t, kv.get(t, "done"))
+	require.NotNil(t, kv.get(t, "pending"))
+}
+
+func TestRetryNotification(t *testing.T) {
+	store, kv := newNotificationQueueStore()
+	now := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+	kv.set(t, notificationQueueKey, []*QueuedNotification{{ID: "id", Attempts: 1, ClaimedUntil: now.Unix()}, {ID: "pending"}})
+
+	err := store.RetryNotification("id", now.Add(time.Minute), "service unavailable")
+	require.NoError(t, err)
+	require.Equal(t, &QueuedNotification{
+		ID:          "id",
+		Attempts:    1,
+		NextAttempt: now.Add(time.Minute).Unix(),
+		LastError:   "service unavailable",
+	}, kv.get(t, "id"))
+	require.Equal(t, []string{"pending", "id"}, kv.ids(t, notificationQueueKey), "the notification is moved to the tail")
+}
+
+func TestDeadLetterNotification(t *testing.T) {
+	t.Run("moved", func(t *testing.T) {
+		store, kv := newNotificationQueueStore()
+		kv.set(t, notificationQueueKey, []*QueuedNotification{{ID: "failed", Attempts: 5, ClaimedUntil: 1}, {ID: "pending"}})
+		kv.set(t, deadLetterNotificationKey, []*QueuedNotification{{ID: "older"}})
+
+		err := store.DeadLetterNotification("failed", "subscription not found")
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"pending"}, kv.ids(t, notificationQueueKey))
+		require.Equal(t, []string{"older", "failed"}, kv.ids(t, deadLetterNotificationKey))
+		require.Equal(t, &QueuedNotification{
+			ID:        "failed",
+			Attempts:  5,
+			LastError: "subscription not found",
+		}, kv.get(t, "failed"))
+	})
+
+	t.Run("missing notification removed from the queue", func(t *testing.T) {
+		store, kv := newNotificationQueueStore()
+		kv.set(t, notificationQueueKey, []*QueuedNotification{{ID: "missing"}, {ID: "pending"}})
+		delete(kv.values, kv.hashedKey(queuedNotificationKey("missing")))
+
+		err := store.DeadLetterNotification("missing", "subscription not found")
+		require.NoError(t, err)
+		require.Equal(t, []string{"pending"}, kv.ids(t, notificationQueueKey))
+		require.Empty(t, kv.ids(t, deadLetterNotificationKey))
+	})
+
+	t.Run("oldest dropped", func(t *testing.T) {
+		store, kv := newNotificationQueueStore()
+		kv.set(t, notificationQueueKey, []*QueuedNotification{{ID: "failed"}})
+		deadLetters := []*QueuedNotification{}
+		for i := 0; i < MaxDeadLetterNotifications; i++ {
+			deadLetters = append(deadLetters, &QueuedNotification{ID: fmt.Sprintf("id%d", i)})
+		}
+		kv.set(t, deadLetterNotificationKey, deadLetters)
+
+		err := store.DeadLetterNotification("failed", "subscription not found")
+		require.NoError(t, err)
+
+		ids := kv.ids(t, deadLetterNotificationKey)
+		require.Len(t, ids, MaxDeadLetterNotifications)
+		require.Equal(t, "id1", ids[0])
+		require.Equal(t, "failed", ids[len(ids)-1])
+		require.Nil(t, kv.get(t, "id0"))
+	})
+}
+
+func TestTakeDeadLetterNotifications(t *testing.T) {
+	store, kv := newNotificationQueueStore()
+	kv.set(t, deadLetterNotificationKey, []*QueuedNotification{{ID: "first"}, {ID: "second"}})
+
+	deadLetters, err := store.TakeDeadLetterNotifications()
+	require.NoError(t, err)
+	require.Equal(t, []*QueuedNotification{{ID: "first"}, {ID: "second"}}, deadLetters)
+	require.Empty(t, kv.ids(t, deadLetterNotificationKey))
+	require.Nil(t, kv.get(t, "first"))
+	require.Nil(t, kv.get(t, "second"))
+}
+
+func TestDeleteDeadLetterNotifications(t *testing.T) {
+	store, kv := newNotificationQueueStore()
+	kv.set(t, deadLetterNotificationKey, []*QueuedNotification{{ID: "retried"}, {ID: "newer"}})
+
+	err := store.DeleteDeadLetterNotifications([]string{"retried"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"newer"}, kv.ids(t, deadLetterNotificationKey))
+	require.Nil(t, kv.get(t, "retried"))
+	require.NotNil(t, kv.get(t, "newer"))
+}
+
+func TestCountNotifications(t *testing.T) {
+	store, kv := newNotificationQueueStore()
+	kv.set(t, notificationQueueKey, []*QueuedNotification{{ID: "first"}, {ID: "second"}})
+	kv.set(t, deadLetterNotificationKey, []*QueuedNotification{{ID: "failed"}})
+	// Only the index is read, not the notifications.
+	delete(kv.values, kv.hashedKey(queuedNotificationKey("first")))
+
+	queued, deadLetters, err := store.CountNotifications()
+	require.NoError(t, err)
+	require.Equal(t, 2, queued)
+	require.Equal(t, 1, deadLetters)
+}
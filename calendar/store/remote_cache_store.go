@@ -0,0 +1,85 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+// Kinds of data kept in the remote cache, each invalidated on its own.
+const (
+	RemoteCacheEvents    = "events"
+	RemoteCacheMailbox   = "mailbox"
+	RemoteCacheCalendars = "calendars"
+)
+
+// RemoteCacheStore keeps the responses of the remote for a while, so that
+// features reading the same data do not request it again. The entries of a
+// remote user are grouped by kind of data, and invalidating a kind makes all
+// its entries unreachable until they expire.
+//
+// LoadRemoteCache returns the generation the entry was looked up in, found or
+// not. The data fetched from the remote after a miss is stored in that
+// generation, so that it is discarded if the kind was invalidated meanwhile.
+type RemoteCacheStore interface {
+	LoadRemoteCache(remoteUserID, kind, key string, v interface{}) (string, error)
+	StoreRemoteCache(remoteUserID, kind, generation, key string, v interface{}, ttl time.Duration) error
+	InvalidateRemoteCache(remoteUserID, kind string) error
+}
+
+func (s *pluginStore) LoadRemoteCache(remoteUserID, kind, key string, v interface{}) (string, error) {
+	generation, err := s.loadRemoteCacheGeneration(remoteUserID, kind)
+	if err != nil {
+		return "", err
+	}
+	return generation, kvstore.LoadJSON(s.remoteCacheKV, remoteCacheEntryKey(remoteUserID, kind, generation, key), v)
+}
+
+func (s *pluginStore) StoreRemoteCache(remoteUserID, kind, generation, key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	err = s.remoteCacheKV.StoreTTL(remoteCacheEntryKey(remoteUserID, kind, generation, key), data, int64(ttl.Seconds()))
+	if err != nil {
+		return errors.Wrap(err, "failed to store remote cache entry")
+	}
+	return nil
+}
+
+func (s *pluginStore) InvalidateRemoteCache(remoteUserID, kind string) error {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 10)
+	err := kvstore.StoreJSON(s.remoteCacheKV, remoteCacheGenerationKey(remoteUserID, kind), generation)
+	if err != nil {
+		return errors.Wrap(err, "failed to invalidate remote cache")
+	}
+	return nil
+}
+
+func (s *pluginStore) loadRemoteCacheGeneration(remoteUserID, kind string) (string, error) {
+	var generation string
+	err := kvstore.LoadJSON(s.remoteCacheKV, remoteCacheGenerationKey(remoteUserID, kind), &generation)
+	if err == ErrNotFound {
+		return "0", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return generation, nil
+}
+
+func remoteCacheGenerationKey(remoteUserID, kind string) string {
+	return fmt.Sprintf("generation:%s:%s", remoteUserID, kind)
+}
+
+func remoteCacheEntryKey(remoteUserID, kind, generation, key string) string {
+	return fmt.Sprintf("entry:%s:%s:%s:%s", remoteUserID, kind, generation, key)
+}
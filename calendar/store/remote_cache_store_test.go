@@ -0,0 +1,52 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteCacheInvalidation(t *testing.T) {
+	mockAPI, store, _, _, _ := GetMockSetup(t)
+
+	var entryKey string
+	var entryValue []byte
+	mockAPI.On("KVGet", MockString).Return(nil, nil).Times(3)
+	cached := []string{}
+	generation, err := store.LoadRemoteCache(MockRemoteUserID, RemoteCacheEvents, "range", &cached)
+	require.Equal(t, ErrNotFound, err)
+
+	mockAPI.On("KVSetWithExpiry", MockString, MockByteValue, int64(300)).Run(func(args mock.Arguments) {
+		entryKey = args.String(0)
+		entryValue = args.Get(1).([]byte)
+	}).Return(nil).Times(1)
+
+	err = store.StoreRemoteCache(MockRemoteUserID, RemoteCacheEvents, generation, "range", []string{"event_id"}, 5*time.Minute)
+	require.NoError(t, err)
+
+	mockAPI.On("KVGet", entryKey).Return(entryValue, nil).Times(1)
+	_, err = store.LoadRemoteCache(MockRemoteUserID, RemoteCacheEvents, "range", &cached)
+	require.NoError(t, err)
+	require.Equal(t, []string{"event_id"}, cached)
+
+	var generationKey string
+	var generationValue []byte
+	mockAPI.On("KVSet", MockString, MockByteValue).Run(func(args mock.Arguments) {
+		generationKey = args.String(0)
+		generationValue = args.Get(1).([]byte)
+	}).Return(nil).Times(1)
+	err = store.InvalidateRemoteCache(MockRemoteUserID, RemoteCacheEvents)
+	require.NoError(t, err)
+
+	mockAPI.ExpectedCalls = nil
+	mockAPI.On("KVGet", generationKey).Return(generationValue, nil).Times(1)
+	mockAPI.On("KVGet", mock.MatchedBy(func(key string) bool { return key != generationKey && key != entryKey })).Return(nil, nil).Times(1)
+	_, err = store.LoadRemoteCache(MockRemoteUserID, RemoteCacheEvents, "range", &cached)
+	require.Equal(t, ErrNotFound, err)
+	mockAPI.AssertExpectations(t)
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+// RSVPDigestStore keeps, for each user, the time up to which the meetings
+// starting before it had their RSVP digest sent, so that no meeting is
+// missed or sent twice when a job run is skipped or repeated.
+type RSVPDigestStore interface {
+	LoadRSVPDigestSentUntil(mattermostUserID string) (time.Time, error)
+	StoreRSVPDigestSentUntil(mattermostUserID string, sentUntil time.Time) error
+}
+
+func (s *pluginStore) LoadRSVPDigestSentUntil(mattermostUserID string) (time.Time, error) {
+	var sentUntil int64
+	err := kvstore.LoadJSON(s.rsvpDigestKV, mattermostUserID, &sentUntil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sentUntil, 0), nil
+}
+
+func (s *pluginStore) StoreRSVPDigestSentUntil(mattermostUserID string, sentUntil time.Time) error {
+	return kvstore.StoreJSON(s.rsvpDigestKV, mattermostUserID, sentUntil.Unix())
+}
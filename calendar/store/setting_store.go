@@ -14,8 +14,31 @@ const (
 	SetCustomStatusSettingID         = "set_custom_status"
 	ReceiveRemindersSettingID        = "get_reminders"
 	DailySummarySettingID            = "summary_setting"
+	EventChangesSettingID            = "event_changes"
+	MuteOwnEventsSettingID           = "mute_own_events"
 )
 
+// Kinds of event changes users can choose to be notified of.
+const (
+	EventChangeSubject      = "Subject"
+	EventChangeTime         = "Time"
+	EventChangeLocation     = "Location"
+	EventChangeAttendees    = "Attendees"
+	EventChangeDescription  = "Description"
+	EventChangeImportance   = "Importance"
+	EventChangeCancellation = "Cancellation"
+)
+
+var EventChangeOptions = []string{
+	EventChangeSubject,
+	EventChangeTime,
+	EventChangeLocation,
+	EventChangeAttendees,
+	EventChangeDescription,
+	EventChangeImportance,
+	EventChangeCancellation,
+}
+
 func (s *pluginStore) SetSetting(userID, settingID string, value interface{}) error {
 	user, err := s.LoadUser(userID)
 	if err != nil {
@@ -53,6 +76,20 @@ func (s *pluginStore) SetSetting(userID, settingID string, value interface{}) er
 		user.Settings.ReceiveReminders = storableValue
 	case DailySummarySettingID:
 		s.updateDailySummarySettingForUser(user, value)
+	case EventChangesSettingID:
+		storableValue, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("cannot read value %v for setting %s (expecting []string)", value, settingID)
+		}
+		user.Settings.EventNotifications = user.Settings.GetEventNotifications()
+		user.Settings.EventNotifications.Changes = storableValue
+	case MuteOwnEventsSettingID:
+		storableValue, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("cannot read value %v for setting %s (expecting bool)", value, settingID)
+		}
+		user.Settings.EventNotifications = user.Settings.GetEventNotifications()
+		user.Settings.EventNotifications.MuteOwnEvents = storableValue
 	default:
 		return fmt.Errorf("setting %s not found", settingID)
 	}
@@ -83,6 +120,10 @@ func (s *pluginStore) GetSetting(userID, settingID string) (interface{}, error)
 	case DailySummarySettingID:
 		dsum := user.Settings.DailySummary
 		return dsum, nil
+	case EventChangesSettingID:
+		return user.Settings.GetEventNotifications().Changes, nil
+	case MuteOwnEventsSettingID:
+		return user.Settings.GetEventNotifications().MuteOwnEvents, nil
 	default:
 		return nil, fmt.Errorf("setting %s not found", settingID)
 	}
@@ -96,6 +137,36 @@ func DefaultDailySummaryUserSettings() *DailySummaryUserSettings {
 		Enable:   false,
 	}
 }
+
+// DefaultEventNotificationUserSettings notifies of the changes to the subject
+// and the time of events, and of cancellations.
+func DefaultEventNotificationUserSettings() *EventNotificationUserSettings {
+	return &EventNotificationUserSettings{
+		Changes: []string{EventChangeSubject, EventChangeTime, EventChangeCancellation},
+	}
+}
+
+// GetEventNotifications returns a copy of the event notification settings, or
+// the default settings if the user has not changed them.
+func (settings Settings) GetEventNotifications() *EventNotificationUserSettings {
+	if settings.EventNotifications == nil {
+		return DefaultEventNotificationUserSettings()
+	}
+	copied := *settings.EventNotifications
+	copied.Changes = append([]string{}, settings.EventNotifications.Changes...)
+	return &copied
+}
+
+func DefaultWeeklySummaryUserSettings() *WeeklySummaryUserSettings {
+	return &WeeklySummaryUserSettings{
+		Day:      "Monday",
+		PostTime: "8:00AM",
+
+		Timezone: "Eastern Standard Time",
+		Enable:   false,
+	}
+}
+
 func (s *pluginStore) updateDailySummarySettingForUser(user *User, value interface{}) error {
 	if user.Settings.DailySummary == nil {
 		user.Settings.DailySummary = DefaultDailySummaryUserSettings()
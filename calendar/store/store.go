@@ -16,68 +16,116 @@ import (
 )
 
 const (
-	UserKeyPrefix             = "user_"
-	UserIndexKeyPrefix        = "userindex_"
-	MattermostUserIDKeyPrefix = "mmuid_"
-	OAuth2KeyPrefix           = "oauth2_"
-	SubscriptionKeyPrefix     = "sub_"
-	EventKeyPrefix            = "ev_"
-	WelcomeKeyPrefix          = "welcome_"
-	SettingsPanelPrefix       = "settings_panel_"
+	UserKeyPrefix              = "user_"
+	UserIndexKeyPrefix         = "userindex_"
+	MattermostUserIDKeyPrefix  = "mmuid_"
+	OAuth2KeyPrefix            = "oauth2_"
+	SubscriptionKeyPrefix      = "sub_"
+	EventKeyPrefix             = "ev_"
+	WelcomeKeyPrefix           = "welcome_"
+	SettingsPanelPrefix        = "settings_panel_"
+	FreeBusyKeyPrefix          = "freebusy_"
+	NotificationBatchPrefix    = "notifbatch_"
+	NotificationQueuePrefix    = "notifqueue_"
+	CalendarCachePrefix        = "calcache_"
+	RemoteCachePrefix          = "remotecache_"
+	NotificationCertPrefix     = "notifcert_"
+	NotificationDeliveryPrefix = "notifdelivery_"
+	UserListPrefix             = "userlist_"
+	RSVPDigestPrefix           = "rsvpdigest_"
 )
 
 const OAuth2KeyExpiration = 15 * time.Minute
 
 var ErrNotFound = kvstore.ErrNotFound
 
+var ErrEncryptionKeyNotSet = kvstore.ErrEncryptionKeyNotSet
+
 type Store interface {
 	UserStore
 	OAuth2StateStore
 	SubscriptionStore
 	EventStore
 	WelcomeStore
+	FreeBusyStore
+	NotificationBatchStore
+	NotificationQueueStore
+	CalendarCacheStore
+	RemoteCacheStore
+	NotificationCertificateStore
+	NotificationDeliveryStore
+	UserListStore
+	RSVPDigestStore
 	flow.Store
 	settingspanel.SettingStore
 	settingspanel.PanelStore
 }
 
 type pluginStore struct {
-	basicKV            kvstore.KVStore
-	oauth2KV           kvstore.KVStore
-	userKV             kvstore.KVStore
-	mattermostUserIDKV kvstore.KVStore
-	userIndexKV        kvstore.KVStore
-	subscriptionKV     kvstore.KVStore
-	eventKV            kvstore.KVStore
-	welcomeIndexKV     kvstore.KVStore
-	settingsPanelKV    kvstore.KVStore
-	Logger             bot.Logger
-	Poster             bot.Poster
-	Tracker            tracker.Tracker
+	basicKV                   kvstore.KVStore
+	oauth2KV                  kvstore.KVStore
+	userKV                    kvstore.KVStore
+	mattermostUserIDKV        kvstore.KVStore
+	userIndexKV               kvstore.KVStore
+	subscriptionKV            kvstore.KVStore
+	eventKV                   kvstore.KVStore
+	welcomeIndexKV            kvstore.KVStore
+	settingsPanelKV           kvstore.KVStore
+	freeBusyKV                kvstore.KVStore
+	notificationBatchKV       kvstore.KVStore
+	notificationQueueKV       kvstore.KVStore
+	calendarCacheKV           kvstore.KVStore
+	remoteCacheKV             kvstore.KVStore
+	notificationCertificateKV kvstore.KVStore
+	notificationDeliveryKV    kvstore.KVStore
+	userListKV                kvstore.KVStore
+	rsvpDigestKV              kvstore.KVStore
+	Logger                    bot.Logger
+	Poster                    bot.Poster
+	Tracker                   tracker.Tracker
 }
 
 func NewPluginStore(api plugin.API, logger bot.Logger, poster bot.Poster, tracker tracker.Tracker, enableEncryption bool, encryptionKey []byte) Store {
 	basicKV := kvstore.NewPluginStore(api)
 	oauth2KV := kvstore.NewHashedKeyStore(kvstore.NewOneTimePluginStore(api, OAuth2KeyExpiration), OAuth2KeyPrefix)
 	user2KV := kvstore.NewHashedKeyStore(basicKV, UserKeyPrefix)
+	calendarCacheKV := kvstore.NewHashedKeyStore(basicKV, CalendarCachePrefix)
+	remoteCacheKV := kvstore.NewHashedKeyStore(basicKV, RemoteCachePrefix)
+
+	// Free/busy feed tokens grant access without a Mattermost session, so
+	// they are always encrypted, regardless of the provider.
+	freeBusyKV := kvstore.NewEncryptedKeyStore(kvstore.NewHashedKeyStore(basicKV, FreeBusyKeyPrefix), encryptionKey)
+	// So are the private keys of the notification certificates.
+	notificationCertificateKV := kvstore.NewEncryptedKeyStore(kvstore.NewHashedKeyStore(basicKV, NotificationCertPrefix), encryptionKey)
 
 	if enableEncryption {
 		oauth2KV = kvstore.NewEncryptedKeyStore(oauth2KV, encryptionKey)
 		user2KV = kvstore.NewEncryptedKeyStore(user2KV, encryptionKey)
+		calendarCacheKV = kvstore.NewEncryptedKeyStore(calendarCacheKV, encryptionKey)
+		remoteCacheKV = kvstore.NewEncryptedKeyStore(remoteCacheKV, encryptionKey)
 	}
 
 	return &pluginStore{
-		basicKV:            basicKV,
-		userKV:             user2KV,
-		userIndexKV:        kvstore.NewHashedKeyStore(basicKV, UserIndexKeyPrefix),
-		mattermostUserIDKV: kvstore.NewHashedKeyStore(basicKV, MattermostUserIDKeyPrefix),
-		subscriptionKV:     kvstore.NewHashedKeyStore(basicKV, SubscriptionKeyPrefix),
-		eventKV:            kvstore.NewHashedKeyStore(basicKV, EventKeyPrefix),
-		oauth2KV:           oauth2KV,
-		welcomeIndexKV:     kvstore.NewHashedKeyStore(basicKV, WelcomeKeyPrefix),
-		settingsPanelKV:    kvstore.NewHashedKeyStore(basicKV, SettingsPanelPrefix),
-		Logger:             logger,
-		Poster:             poster,
-		Tracker:            tracker,
+		basicKV:                   basicKV,
+		userKV:                    user2KV,
+		userIndexKV:               kvstore.NewHashedKeyStore(basicKV, UserIndexKeyPrefix),
+		mattermostUserIDKV:        kvstore.NewHashedKeyStore(basicKV, MattermostUserIDKeyPrefix),
+		subscriptionKV:            kvstore.NewHashedKeyStore(basicKV, SubscriptionKeyPrefix),
+		eventKV:                   kvstore.NewHashedKeyStore(basicKV, EventKeyPrefix),
+		oauth2KV:                  oauth2KV,
+		welcomeIndexKV:            kvstore.NewHashedKeyStore(basicKV, WelcomeKeyPrefix),
+		settingsPanelKV:           kvstore.NewHashedKeyStore(basicKV, SettingsPanelPrefix),
+		freeBusyKV:                freeBusyKV,
+		notificationBatchKV:       kvstore.NewHashedKeyStore(basicKV, NotificationBatchPrefix),
+		notificationQueueKV:       kvstore.NewHashedKeyStore(basicKV, NotificationQueuePrefix),
+		calendarCacheKV:           calendarCacheKV,
+		remoteCacheKV:             remoteCacheKV,
+		notificationCertificateKV: notificationCertificateKV,
+		notificationDeliveryKV:    kvstore.NewHashedKeyStore(basicKV, NotificationDeliveryPrefix),
+		userListKV:                kvstore.NewHashedKeyStore(basicKV, UserListPrefix),
+		rsvpDigestKV:              kvstore.NewHashedKeyStore(basicKV, RSVPDigestPrefix),
+		Logger:                    logger,
+		Poster:                    poster,
+		Tracker:                   tracker,
 	}
 }
@@ -14,6 +14,7 @@ import (
 type SubscriptionStore interface {
 	LoadSubscription(subscriptionID string) (*Subscription, error)
 	StoreUserSubscription(user *User, subscription *Subscription) error
+	StoreSubscription(subscription *Subscription) error
 	DeleteUserSubscription(user *User, subscriptionID string) error
 }
 
@@ -21,6 +22,12 @@ type Subscription struct {
 	PluginVersion       string
 	Remote              *remote.Subscription
 	MattermostCreatorID string
+	// CreatedAt is the Unix time the subscription and its client state were
+	// created, 0 for the subscriptions stored before it was tracked.
+	CreatedAt int64
+	// ReplacedAt is the Unix time the subscription was replaced by a new one
+	// of its creator, 0 while it is in use.
+	ReplacedAt int64
 }
 
 func (s *pluginStore) LoadSubscription(subscriptionID string) (*Subscription, error) {
@@ -55,6 +62,12 @@ func (s *pluginStore) StoreUserSubscription(user *User, subscription *Subscripti
 	return nil
 }
 
+// StoreSubscription stores the subscription without changing the one of its
+// creator.
+func (s *pluginStore) StoreSubscription(subscription *Subscription) error {
+	return kvstore.StoreJSON(s.subscriptionKV, subscription.Remote.ID, subscription)
+}
+
 func (s *pluginStore) DeleteUserSubscription(user *User, subscriptionID string) error {
 	err := s.subscriptionKV.Delete(subscriptionID)
 	if err != nil {
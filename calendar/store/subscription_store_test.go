@@ -184,3 +184,15 @@ func TestDeleteUserSubscription(t *testing.T) {
 		})
 	}
 }
+
+func TestStoreSubscription(t *testing.T) {
+	mockAPI, store, _, _, _ := GetMockSetup(t)
+	mockSubscription := GetMockSubscription()
+	mockSubscription.ReplacedAt = 1
+	mockAPI.On("KVSet", "sub_0c47c5b7e2a88ec9256c8ac0e71b0f6e", mock.Anything).Return(nil).Times(1)
+
+	err := store.StoreSubscription(mockSubscription)
+
+	require.NoError(t, err)
+	mockAPI.AssertExpectations(t)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+// The lists of the users that opted in to a feature processed by a job, so
+// the job does not load every connected user.
+const (
+	RSVPDigestUserList = "rsvp_digest"
+)
+
+// UserListStore keeps lists of Mattermost user IDs, modified atomically as
+// the users change their settings while the jobs go through the lists.
+type UserListStore interface {
+	LoadUserList(list string) ([]string, error)
+	AddToUserList(list, mattermostUserID string) error
+	RemoveFromUserList(list, mattermostUserID string) error
+}
+
+// LoadUserList returns the users of the list, or an empty list if there is
+// none.
+func (s *pluginStore) LoadUserList(list string) ([]string, error) {
+	mattermostUserIDs := []string{}
+	err := kvstore.LoadJSON(s.userListKV, list, &mattermostUserIDs)
+	if err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	return mattermostUserIDs, nil
+}
+
+func (s *pluginStore) AddToUserList(list, mattermostUserID string) error {
+	return s.modifyUserList(list, func(mattermostUserIDs []string) []string {
+		for _, id := range mattermostUserIDs {
+			if id == mattermostUserID {
+				return mattermostUserIDs
+			}
+		}
+		return append(mattermostUserIDs, mattermostUserID)
+	})
+}
+
+func (s *pluginStore) RemoveFromUserList(list, mattermostUserID string) error {
+	return s.modifyUserList(list, func(mattermostUserIDs []string) []string {
+		for i, id := range mattermostUserIDs {
+			if id == mattermostUserID {
+				return append(mattermostUserIDs[:i], mattermostUserIDs[i+1:]...)
+			}
+		}
+		return mattermostUserIDs
+	})
+}
+
+func (s *pluginStore) modifyUserList(list string, modify func(mattermostUserIDs []string) []string) error {
+	err := kvstore.AtomicModify(s.userListKV, list, func(initial []byte, storeErr error) ([]byte, error) {
+		if storeErr != nil && storeErr != ErrNotFound {
+			return nil, storeErr
+		}
+
+		mattermostUserIDs := []string{}
+		if len(initial) > 0 {
+			err := json.Unmarshal(initial, &mattermostUserIDs)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		updated, err := json.Marshal(modify(mattermostUserIDs))
+		if err != nil {
+			return nil, err
+		}
+		if len(initial) == 0 && string(updated) == "[]" {
+			return initial, nil
+		}
+		return updated, nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to modify user list %s", list)
+	}
+	return nil
+}
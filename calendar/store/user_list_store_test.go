@@ -0,0 +1,66 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifyUserList(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		stored   []string
+		modify   func(s Store) error
+		expected []string
+	}{
+		{
+			name:     "added",
+			stored:   []string{"user1"},
+			modify:   func(s Store) error { return s.AddToUserList(RSVPDigestUserList, "user2") },
+			expected: []string{"user1", "user2"},
+		},
+		{
+			name:   "already added",
+			stored: []string{"user1"},
+			modify: func(s Store) error { return s.AddToUserList(RSVPDigestUserList, "user1") },
+		},
+		{
+			name:     "removed",
+			stored:   []string{"user1", "user2"},
+			modify:   func(s Store) error { return s.RemoveFromUserList(RSVPDigestUserList, "user1") },
+			expected: []string{"user2"},
+		},
+		{
+			name:   "not in the list",
+			modify: func(s Store) error { return s.RemoveFromUserList(RSVPDigestUserList, "user1") },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			var storedJSON []byte
+			if tc.stored != nil {
+				var err error
+				storedJSON, err = json.Marshal(tc.stored)
+				require.NoError(t, err)
+			}
+			mockAPI.On("KVGet", MockString).Return(storedJSON, nil)
+			var written []string
+			mockAPI.On("KVSetWithOptions", MockString, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &written))
+			}).Return(true, nil)
+
+			err := tc.modify(store)
+			require.NoError(t, err)
+			if tc.expected == nil {
+				mockAPI.AssertNotCalled(t, "KVSetWithOptions", MockString, mock.Anything, mock.Anything)
+				return
+			}
+			require.Equal(t, tc.expected, written)
+		})
+	}
+}
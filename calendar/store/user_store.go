@@ -38,6 +38,7 @@ type UserStore interface {
 	DeleteUserFromIndex(mattermostUserID string) error
 	StoreUserActiveEvents(mattermostUserID string, events []string) error
 	StoreUserLinkedEvent(mattermostUserID, eventID, channelID string) error
+	DeleteUserLinkedEvent(mattermostUserID, eventID string) error
 	RefreshAndStoreToken(token *oauth2.Token, oconf *oauth2.Config, mattermostUserID string) (*oauth2.Token, error)
 	CheckUserConnected(mattermostUserID string) bool
 	DisconnectUserFromStoreIfNecessary(err error, mattermostUserID string)
@@ -93,12 +94,20 @@ var DefaultSettings = Settings{
 
 type Settings struct {
 	DailySummary            *DailySummaryUserSettings
+	WeeklySummary           *WeeklySummaryUserSettings
+	RSVPDigest              *RSVPDigestUserSettings
+	EventNotifications      *EventNotificationUserSettings
+	NotificationBatch       *NotificationBatchUserSettings
 	EventSubscriptionID     string
 	UpdateStatusFromOptions string
 	GetConfirmation         bool
 	ReceiveReminders        bool
 	SetCustomStatus         bool
 
+	// PreviousEventSubscriptionID is the subscription replaced last, whose
+	// notifications are still accepted for a while.
+	PreviousEventSubscriptionID string
+
 	// Legacy settings
 	UpdateStatus                      bool
 	ReceiveNotificationsDuringMeeting bool
@@ -111,6 +120,31 @@ type DailySummaryUserSettings struct {
 	Enable       bool   `json:"enable"`
 }
 
+type WeeklySummaryUserSettings struct {
+	Day          string `json:"day"`       // Weekday name, i.e. Monday
+	PostTime     string `json:"post_time"` // Kitchen format, i.e. 8:30AM
+	Timezone     string `json:"tz"`        // Timezone in MSCal when PostTime is set/updated
+	LastPostTime string `json:"last_post_time"`
+	Enable       bool   `json:"enable"`
+}
+
+type EventNotificationUserSettings struct {
+	Changes       []string `json:"changes"` // The kinds of event changes to be notified of
+	MuteOwnEvents bool     `json:"mute_own_events"`
+}
+
+type NotificationBatchUserSettings struct {
+	QuietHoursStart string `json:"quiet_hours_start"` // Kitchen format, i.e. 10:00PM. Empty if quiet hours are off
+	QuietHoursEnd   string `json:"quiet_hours_end"`   // Kitchen format, i.e. 7:00AM
+	Timezone        string `json:"tz"`                // Timezone in MSCal when the quiet hours are set, used if the current one cannot be read
+	BatchMinutes    int    `json:"batch_minutes"`     // How often held notifications are delivered, 0 to deliver them right away
+}
+
+type RSVPDigestUserSettings struct {
+	MinutesBefore int  `json:"minutes_before"` // How long before the meeting the digest is sent
+	Enable        bool `json:"enable"`
+}
+
 type WelcomeFlowStatus struct {
 	PostIDs map[string]string
 	Step    int
@@ -405,6 +439,20 @@ func (s *pluginStore) StoreUserLinkedEvent(mattermostUserID, eventID, channelID
 	return kvstore.StoreJSON(s.userKV, mattermostUserID, u)
 }
 
+func (s *pluginStore) DeleteUserLinkedEvent(mattermostUserID, eventID string) error {
+	u, err := s.LoadUser(mattermostUserID)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := u.ChannelEvents[eventID]; !ok {
+		return nil
+	}
+	delete(u.ChannelEvents, eventID)
+
+	return kvstore.StoreJSON(s.userKV, mattermostUserID, u)
+}
+
 func (index UserIndex) ToDTO() (result []UserShortDTO) {
 	for _, u := range index {
 		result = append(result, u.ToDTO())
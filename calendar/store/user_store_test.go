@@ -4,6 +4,7 @@
 package store
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -680,6 +681,56 @@ func TestStoreUserLinkedEvent(t *testing.T) {
 	}
 }
 
+func TestDeleteUserLinkedEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(*testutil.MockPluginAPI)
+		assertions func(*testing.T, error)
+	}{
+		{
+			name: "Error loading user",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", "user_c3b5020d58a049787bc969768465b890").Return(nil, &model.AppError{Message: "User not found"}).Times(1)
+			},
+			assertions: func(t *testing.T, err error) {
+				require.EqualError(t, err, "failed plugin KVGet: User not found")
+			},
+		},
+		{
+			name: "Event not linked",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", "user_c3b5020d58a049787bc969768465b890").Return([]byte(`{"mm_id":"mockUserID","linkedEvents": {"mockEventID2": "mockChannelID"}}`), nil).Times(1)
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Linked event deleted successfully",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", "user_c3b5020d58a049787bc969768465b890").Return([]byte(`{"mm_id":"mockUserID","linkedEvents": {"mockEventID": "mockChannelID", "mockEventID2": "mockChannelID"}}`), nil).Times(1)
+				mockAPI.On("KVSet", "user_c3b5020d58a049787bc969768465b890", mock.MatchedBy(func(data []byte) bool {
+					return !strings.Contains(string(data), `"mockEventID"`) && strings.Contains(string(data), `"mockEventID2"`)
+				})).Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			tt.setup(mockAPI)
+
+			err := store.DeleteUserLinkedEvent(MockMMUserID, MockEventID)
+
+			tt.assertions(t, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
 func TestStoreUserCustomStatusUpdates(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -68,6 +68,25 @@ func (mr *MockPosterMockRecorder) DM(arg0, arg1 interface{}, arg2 ...interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DM", reflect.TypeOf((*MockPoster)(nil).DM), varargs...)
 }
 
+// DMAdmins mocks base method.
+func (m *MockPoster) DMAdmins(arg0 string, arg1 ...interface{}) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DMAdmins", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DMAdmins indicates an expected call of DMAdmins.
+func (mr *MockPosterMockRecorder) DMAdmins(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DMAdmins", reflect.TypeOf((*MockPoster)(nil).DMAdmins), varargs...)
+}
+
 // DMUpdate mocks base method.
 func (m *MockPoster) DMUpdate(arg0, arg1 string, arg2 ...interface{}) error {
 	m.ctrl.T.Helper()
@@ -107,6 +126,21 @@ func (mr *MockPosterMockRecorder) DMWithAttachments(arg0 interface{}, arg1 ...in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DMWithAttachments", reflect.TypeOf((*MockPoster)(nil).DMWithAttachments), varargs...)
 }
 
+// DMWithFile mocks base method.
+func (m *MockPoster) DMWithFile(arg0, arg1, arg2 string, arg3 []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DMWithFile", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DMWithFile indicates an expected call of DMWithFile.
+func (mr *MockPosterMockRecorder) DMWithFile(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DMWithFile", reflect.TypeOf((*MockPoster)(nil).DMWithFile), arg0, arg1, arg2, arg3)
+}
+
 // DMWithMessageAndAttachments mocks base method.
 func (m *MockPoster) DMWithMessageAndAttachments(arg0, arg1 string, arg2 ...*model.SlackAttachment) (string, error) {
 	m.ctrl.T.Helper()
@@ -14,6 +14,9 @@ type Poster interface {
 	// DM posts a simple Direct Message to the specified user
 	DM(mattermostUserID, format string, args ...interface{}) (string, error)
 
+	// DMAdmins posts a simple Direct Message to the configured admin users
+	DMAdmins(format string, args ...interface{}) error
+
 	// DMWithAttachments posts a Direct Message that contains Slack attachments.
 	// Often used to include post actions.
 	DMWithAttachments(mattermostUserID string, attachments ...*model.SlackAttachment) (string, error)
@@ -21,6 +24,9 @@ type Poster interface {
 	// DMWithMessageAndAttachments posts a Direct Message that contains Slack attachments and a message.
 	DMWithMessageAndAttachments(mattermostUserID, message string, attachments ...*model.SlackAttachment) (string, error)
 
+	// DMWithFile posts a Direct Message with a file attached to it.
+	DMWithFile(mattermostUserID, message, fileName string, data []byte) (string, error)
+
 	// Ephemeral sends an ephemeral message to a user
 	Ephemeral(mattermostUserID, channelID, format string, args ...interface{})
 
@@ -63,6 +69,25 @@ func (bot *bot) DMWithMessageAndAttachments(mattermostUserID, message string, at
 	return bot.dm(mattermostUserID, &post)
 }
 
+// DMWithFile posts a Direct Message with a file attached to it.
+func (bot *bot) DMWithFile(mattermostUserID, message, fileName string, data []byte) (string, error) {
+	channel, appErr := bot.pluginAPI.GetDirectChannel(mattermostUserID, bot.mattermostUserID)
+	if appErr != nil {
+		bot.pluginAPI.LogInfo("Couldn't get bot's DM channel", "user_id", mattermostUserID)
+		return "", appErr
+	}
+
+	fileInfo, appErr := bot.pluginAPI.UploadFile(data, channel.Id, fileName)
+	if appErr != nil {
+		return "", appErr
+	}
+
+	return bot.dm(mattermostUserID, &model.Post{
+		Message: message,
+		FileIds: []string{fileInfo.Id},
+	})
+}
+
 func (bot *bot) dm(mattermostUserID string, post *model.Post) (string, error) {
 	channel, err := bot.pluginAPI.GetDirectChannel(mattermostUserID, bot.mattermostUserID)
 	if err != nil {
@@ -78,17 +103,23 @@ func (bot *bot) dm(mattermostUserID string, post *model.Post) (string, error) {
 	return sentPost.Id, nil
 }
 
-// DM posts a simple Direct Message to the specified user
-func (bot *bot) dmAdmins(format string, args ...interface{}) error {
+// DMAdmins posts a simple Direct Message to the configured admin users. All of
+// them are attempted, the first error is returned.
+func (bot *bot) DMAdmins(format string, args ...interface{}) error {
+	var firstErr error
 	for _, id := range strings.Split(bot.AdminUserIDs, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
 		_, err := bot.dm(id, &model.Post{
 			Message: fmt.Sprintf(format, args...),
 		})
-		if err != nil {
-			return err
+		if err != nil && firstErr == nil {
+			firstErr = err
 		}
 	}
-	return nil
+	return firstErr
 }
 
 // Ephemeral sends an ephemeral message to a user
@@ -0,0 +1,103 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package ical implements a subset of the iCalendar format (RFC 5545) that is
+// enough to exchange events with other calendar applications.
+package ical
+
+import "time"
+
+const (
+	// ContentType is the MIME type of iCalendar data.
+	ContentType = "text/calendar"
+
+	// FileExtension is the usual extension of iCalendar files.
+	FileExtension = ".ics"
+)
+
+const (
+	RoleChair          = "CHAIR"
+	RoleRequired       = "REQ-PARTICIPANT"
+	RoleOptional       = "OPT-PARTICIPANT"
+	RoleNonParticipant = "NON-PARTICIPANT"
+
+	PartStatNeedsAction = "NEEDS-ACTION"
+	PartStatAccepted    = "ACCEPTED"
+	PartStatDeclined    = "DECLINED"
+	PartStatTentative   = "TENTATIVE"
+
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+
+	FreeBusyTypeBusy            = "BUSY"
+	FreeBusyTypeBusyTentative   = "BUSY-TENTATIVE"
+	FreeBusyTypeBusyUnavailable = "BUSY-UNAVAILABLE"
+)
+
+// Calendar is a VCALENDAR object.
+type Calendar struct {
+	ProdID   string
+	Method   string
+	Events   []*Event
+	FreeBusy []*FreeBusy
+}
+
+// Event is a VEVENT component.
+//
+// Start and End are expressed in the location they should be written in.
+// Times in UTC are written in UTC form, any other location is written with a
+// TZID parameter and the matching VTIMEZONE component. Stamp defaults to the
+// time the event is encoded.
+//
+// Recurring is set when parsing an event that repeats, with RRULE, RDATE or
+// EXDATE properties, or that is an occurrence of a series, with a
+// RECURRENCE-ID. The recurrence itself is not parsed.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+	AllDay      bool
+	Recurring   bool
+	Organizer   *Person
+	Attendees   []*Attendee
+}
+
+// Person is a calendar user identified by their email address.
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Attendee is a participant of an event.
+type Attendee struct {
+	Person
+	Role     string
+	PartStat string
+	RSVP     bool
+}
+
+// FreeBusy is a VFREEBUSY component, publishing the busy time of a calendar
+// user without any detail about the events.
+type FreeBusy struct {
+	UID       string
+	Start     time.Time
+	End       time.Time
+	Stamp     time.Time
+	Organizer *Person
+	Periods   []*Period
+}
+
+// Period is a busy time range of a VFREEBUSY component. Type is one of the
+// FreeBusyType* values.
+type Period struct {
+	Start time.Time
+	End   time.Time
+	Type  string
+}
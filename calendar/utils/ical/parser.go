@@ -0,0 +1,625 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+// ErrNoCalendar is returned when the data doesn't contain a VCALENDAR object.
+var ErrNoCalendar = errors.New("no calendar found")
+
+// contentLine is a single, unfolded, iCalendar property.
+type contentLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// component is a generic BEGIN/END block with its properties and
+// subcomponents.
+type component struct {
+	name       string
+	properties []*contentLine
+	children   []*component
+}
+
+// Parse reads iCalendar data. Floating date-times, which are not bound to any
+// time zone, are interpreted as UTC.
+func Parse(r io.Reader) (*Calendar, error) {
+	return ParseInLocation(r, time.UTC)
+}
+
+// ParseInLocation reads iCalendar data, interpreting floating date-times in the
+// given location.
+//
+// TZID parameters are resolved to Go locations from their IANA or Windows
+// names. Unknown time zones fall back to the offsets described by the matching
+// VTIMEZONE component, if any.
+func ParseInLocation(r io.Reader, floating *time.Location) (*Calendar, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseComponents(unfold(string(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var vcalendar *component
+	for _, c := range root.children {
+		if c.name == "VCALENDAR" {
+			vcalendar = c
+			break
+		}
+	}
+	if vcalendar == nil {
+		return nil, ErrNoCalendar
+	}
+
+	p := &parser{
+		floating:    floating,
+		timezones:   map[string]*time.Location{},
+		customZones: map[string][]*observance{},
+	}
+	for _, c := range vcalendar.children {
+		if c.name == "VTIMEZONE" {
+			p.addTimezone(c)
+		}
+	}
+
+	cal := &Calendar{}
+	for _, prop := range vcalendar.properties {
+		switch prop.name {
+		case "PRODID":
+			cal.ProdID = prop.value
+		case "METHOD":
+			cal.Method = prop.value
+		}
+	}
+
+	for _, c := range vcalendar.children {
+		if c.name != "VEVENT" {
+			continue
+		}
+		evt, err := p.event(c)
+		if err != nil {
+			return nil, err
+		}
+		cal.Events = append(cal.Events, evt)
+	}
+
+	return cal, nil
+}
+
+type parser struct {
+	floating    *time.Location
+	timezones   map[string]*time.Location
+	customZones map[string][]*observance
+}
+
+func (p *parser) event(c *component) (*Event, error) {
+	evt := &Event{}
+	var duration time.Duration
+	hasDuration := false
+	for _, prop := range c.properties {
+		var err error
+		switch prop.name {
+		case "UID":
+			evt.UID = prop.value
+		case "SUMMARY":
+			evt.Summary = unescapeText(prop.value)
+		case "DESCRIPTION":
+			evt.Description = unescapeText(prop.value)
+		case "LOCATION":
+			evt.Location = unescapeText(prop.value)
+		case "URL":
+			evt.URL = prop.value
+		case "STATUS":
+			evt.Status = strings.ToUpper(prop.value)
+		case "DTSTAMP":
+			evt.Stamp, _, err = p.dateTime(prop)
+		case "DTSTART":
+			evt.Start, evt.AllDay, err = p.dateTime(prop)
+		case "DTEND":
+			evt.End, _, err = p.dateTime(prop)
+		case "DURATION":
+			duration, err = parseDuration(prop.value)
+			hasDuration = true
+		case "RRULE", "RDATE", "EXDATE", "RECURRENCE-ID":
+			evt.Recurring = true
+		case "ORGANIZER":
+			evt.Organizer = parsePerson(prop)
+		case "ATTENDEE":
+			evt.Attendees = append(evt.Attendees, &Attendee{
+				Person:   *parsePerson(prop),
+				Role:     strings.ToUpper(prop.params["ROLE"]),
+				PartStat: strings.ToUpper(prop.params["PARTSTAT"]),
+				RSVP:     strings.EqualFold(prop.params["RSVP"], "TRUE"),
+			})
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", prop.name)
+		}
+	}
+
+	if evt.Start.IsZero() {
+		return nil, errors.Errorf("event %q has no start", evt.UID)
+	}
+
+	if evt.End.IsZero() {
+		switch {
+		case hasDuration:
+			evt.End = evt.Start.Add(duration)
+		case evt.AllDay:
+			// A single day event, see RFC 5545 section 3.6.1.
+			evt.End = evt.Start.AddDate(0, 0, 1)
+		default:
+			evt.End = evt.Start
+		}
+	}
+
+	return evt, nil
+}
+
+func (p *parser) dateTime(prop *contentLine) (t time.Time, allDay bool, err error) {
+	value := strings.TrimSpace(prop.value)
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(dateFormat) {
+		t, err = time.Parse(dateFormat, value)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(utcFormat, value)
+		return t, false, err
+	}
+
+	loc := p.floating
+	if tzid, ok := prop.params["TZID"]; ok {
+		if observances, ok := p.customZones[tzid]; ok {
+			t, err = time.Parse(dateTimeFormat, value)
+			if err != nil {
+				return t, false, err
+			}
+			offset := customOffset(observances, t)
+			return t.Add(-time.Duration(offset) * time.Second).In(time.FixedZone(tzid, offset)), false, nil
+		}
+		loc = p.location(tzid)
+	}
+	t, err = time.ParseInLocation(dateTimeFormat, value, loc)
+	return t, false, err
+}
+
+// location resolves a TZID to a Go location.
+func (p *parser) location(tzid string) *time.Location {
+	if loc, ok := p.timezones[tzid]; ok {
+		return loc
+	}
+	if loc := loadLocation(tzid); loc != nil {
+		return loc
+	}
+	return p.floating
+}
+
+func (p *parser) addTimezone(c *component) {
+	tzid := ""
+	for _, prop := range c.properties {
+		if prop.name == "TZID" {
+			tzid = prop.value
+		}
+	}
+	if tzid == "" {
+		return
+	}
+
+	if loc := loadLocation(tzid); loc != nil {
+		p.timezones[tzid] = loc
+		return
+	}
+
+	// The name is unknown, the offsets are taken from the observances of the
+	// component instead.
+	observances := []*observance{}
+	for _, child := range c.children {
+		if o := parseObservance(child); o != nil {
+			observances = append(observances, o)
+		}
+	}
+	if len(observances) > 0 {
+		p.customZones[tzid] = observances
+	}
+}
+
+// observance is a STANDARD or DAYLIGHT part of a VTIMEZONE component. Its
+// onsets are kept as local wall clock times, in UTC.
+type observance struct {
+	start      time.Time
+	offsetFrom int
+	offsetTo   int
+
+	// A yearly recurrence on the nth weekday of a month, week is negative
+	// when counting from the end of the month. Month is zero when the
+	// observance has a single onset.
+	month   time.Month
+	week    int
+	weekday time.Weekday
+}
+
+func parseObservance(c *component) *observance {
+	o := &observance{}
+	hasStart, hasOffset := false, false
+	for _, prop := range c.properties {
+		var err error
+		switch prop.name {
+		case "DTSTART":
+			o.start, err = time.Parse(dateTimeFormat, prop.value)
+			hasStart = err == nil
+		case "TZOFFSETFROM":
+			o.offsetFrom, _ = parseOffset(prop.value)
+		case "TZOFFSETTO":
+			o.offsetTo, err = parseOffset(prop.value)
+			hasOffset = err == nil
+		case "RRULE":
+			o.parseYearlyRule(prop.value)
+		}
+	}
+	if !hasStart || !hasOffset {
+		return nil
+	}
+	return o
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// parseYearlyRule reads rules like FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU, the only
+// ones used by the time zones in practice. Other rules are ignored.
+func (o *observance) parseYearlyRule(rule string) {
+	var month, week int
+	var weekday time.Weekday
+	yearly, hasDay := false, false
+	for _, part := range strings.Split(rule, ";") {
+		name, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			yearly = strings.EqualFold(value, "YEARLY")
+		case "BYMONTH":
+			month, _ = strconv.Atoi(value)
+		case "BYDAY":
+			if len(value) < 3 {
+				return
+			}
+			var ok bool
+			weekday, ok = weekdays[strings.ToUpper(value[len(value)-2:])]
+			if !ok {
+				return
+			}
+			n, err := strconv.Atoi(value[:len(value)-2])
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return
+			}
+			week = n
+			hasDay = true
+		}
+	}
+	if !yearly || !hasDay || month < 1 || month > 12 {
+		return
+	}
+	o.month = time.Month(month)
+	o.week = week
+	o.weekday = weekday
+}
+
+// lastOnset returns the latest onset of the observance at or before the wall
+// clock time.
+func (o *observance) lastOnset(wall time.Time) (time.Time, bool) {
+	if wall.Before(o.start) {
+		return time.Time{}, false
+	}
+	if o.month == 0 {
+		return o.start, true
+	}
+	for year := wall.Year(); year >= o.start.Year(); year-- {
+		onset := o.onsetIn(year)
+		if !onset.After(wall) && !onset.Before(o.start) {
+			return onset, true
+		}
+	}
+	return o.start, true
+}
+
+func (o *observance) onsetIn(year int) time.Time {
+	hour, minute, second := o.start.Clock()
+	if o.week > 0 {
+		first := time.Date(year, o.month, 1, hour, minute, second, 0, time.UTC)
+		days := (int(o.weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, days+7*(o.week-1))
+	}
+	last := time.Date(year, o.month+1, 0, hour, minute, second, 0, time.UTC)
+	days := (int(last.Weekday()) - int(o.weekday) + 7) % 7
+	return last.AddDate(0, 0, -days+7*(o.week+1))
+}
+
+// customOffset returns the UTC offset in use at a wall clock time, according
+// to the observance with the latest onset.
+func customOffset(observances []*observance, wall time.Time) int {
+	var current *observance
+	var latest time.Time
+	for _, o := range observances {
+		onset, ok := o.lastOnset(wall)
+		if ok && (current == nil || onset.After(latest)) {
+			current = o
+			latest = onset
+		}
+	}
+	if current != nil {
+		return current.offsetTo
+	}
+
+	// The time precedes every onset, use the offset in force before the
+	// earliest one.
+	earliest := observances[0]
+	for _, o := range observances[1:] {
+		if o.start.Before(earliest.start) {
+			earliest = o
+		}
+	}
+	return earliest.offsetFrom
+}
+
+// loadLocation finds a Go location matching an IANA or Windows time zone name.
+// Names prefixed by a path, like "/mozilla.org/20050126_1/America/New_York",
+// are also supported.
+func loadLocation(tzid string) *time.Location {
+	tzid = strings.Trim(tzid, `"`)
+	candidates := []string{tzid}
+	parts := strings.Split(strings.Trim(tzid, "/"), "/")
+	for i := len(parts) - 2; i > 0; i-- {
+		candidates = append(candidates, strings.Join(parts[i:], "/"))
+	}
+
+	for _, name := range candidates {
+		goName := tz.Go(name)
+		if goName == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(goName); err == nil {
+			return loc
+		}
+	}
+	return nil
+}
+
+func parsePerson(prop *contentLine) *Person {
+	email := prop.value
+	if len(email) > len("mailto:") && strings.EqualFold(email[:len("mailto:")], "mailto:") {
+		email = email[len("mailto:"):]
+	}
+	return &Person{
+		Name:  prop.params["CN"],
+		Email: email,
+	}
+}
+
+// parseDuration parses a RFC 5545 duration such as "PT1H30M" or "-P1W".
+func parseDuration(s string) (time.Duration, error) {
+	orig := s
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, errors.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	number := ""
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, errors.Errorf("invalid duration %q", orig)
+		}
+		number = ""
+
+		switch {
+		case r == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, errors.Errorf("invalid duration %q", orig)
+		}
+	}
+	if number != "" {
+		return 0, errors.Errorf("invalid duration %q", orig)
+	}
+
+	return sign * d, nil
+}
+
+// parseOffset parses a UTC offset such as "-0500" or "+053000" into seconds.
+func parseOffset(s string) (int, error) {
+	if len(s) != 5 && len(s) != 7 {
+		return 0, errors.Errorf("invalid offset %q", s)
+	}
+	sign := 1
+	switch s[0] {
+	case '-':
+		sign = -1
+	case '+':
+	default:
+		return 0, errors.Errorf("invalid offset %q", s)
+	}
+
+	parts := []int{}
+	for i := 1; i < len(s); i += 2 {
+		v, err := strconv.Atoi(s[i : i+2])
+		if err != nil {
+			return 0, errors.Errorf("invalid offset %q", s)
+		}
+		parts = append(parts, v)
+	}
+	seconds := parts[0]*3600 + parts[1]*60
+	if len(parts) == 3 {
+		seconds += parts[2]
+	}
+	return sign * seconds, nil
+}
+
+// unfold joins the lines that were folded, see RFC 5545 section 3.1. Lines
+// separated by a bare LF are accepted as well.
+func unfold(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+
+	lines := []string{}
+	for _, line := range strings.Split(data, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func parseComponents(lines []string) (*component, error) {
+	root := &component{}
+	stack := []*component{root}
+	for i, line := range lines {
+		prop, err := parseContentLine(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+
+		current := stack[len(stack)-1]
+		switch prop.name {
+		case "BEGIN":
+			c := &component{name: strings.ToUpper(prop.value)}
+			current.children = append(current.children, c)
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 1 || current.name != strings.ToUpper(prop.value) {
+				return nil, errors.Errorf("line %d: unexpected END:%s", i+1, prop.value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			current.properties = append(current.properties, prop)
+		}
+	}
+	if len(stack) != 1 {
+		return nil, errors.Errorf("unterminated %s", stack[len(stack)-1].name)
+	}
+	return root, nil
+}
+
+func parseContentLine(line string) (*contentLine, error) {
+	prop := &contentLine{params: map[string]string{}}
+
+	// The name ends at the first ';' or ':'. Parameter values may contain
+	// those characters when quoted.
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return nil, errors.Errorf("invalid content line %q", line)
+	}
+	prop.name = strings.ToUpper(line[:end])
+
+	rest := line[end:]
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.Index(rest, "=")
+		if eq <= 0 {
+			return nil, errors.Errorf("invalid parameter in %q", line)
+		}
+		key := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			closing := strings.Index(rest[1:], `"`)
+			if closing < 0 {
+				return nil, errors.Errorf("unterminated quoted parameter in %q", line)
+			}
+			value = rest[1 : closing+1]
+			rest = rest[closing+2:]
+		} else {
+			next := strings.IndexAny(rest, ";:")
+			if next < 0 {
+				return nil, errors.Errorf("missing value in %q", line)
+			}
+			value = rest[:next]
+			rest = rest[next:]
+		}
+		prop.params[key] = value
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return nil, errors.Errorf("missing value in %q", line)
+	}
+	prop.value = rest[1:]
+	return prop, nil
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const outlookInvite = "BEGIN:VCALENDAR\r\n" +
+	"METHOD:REQUEST\r\n" +
+	"PRODID:Microsoft Exchange Server 2010\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Pacific Standard Time\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:16010101T020000\r\n" +
+	"TZOFFSETFROM:-0700\r\n" +
+	"TZOFFSETTO:-0800\r\n" +
+	"RRULE:FREQ=YEARLY;INTERVAL=1;BYDAY=1SU;BYMONTH=11\r\n" +
+	"END:STANDARD\r\n" +
+	"BEGIN:DAYLIGHT\r\n" +
+	"DTSTART:16010101T020000\r\n" +
+	"TZOFFSETFROM:-0800\r\n" +
+	"TZOFFSETTO:-0700\r\n" +
+	"RRULE:FREQ=YEARLY;INTERVAL=1;BYDAY=2SU;BYMONTH=3\r\n" +
+	"END:DAYLIGHT\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"ORGANIZER;CN=\"Doe, Jane\":mailto:jane@example.com\r\n" +
+	"ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE;CN=John Smit\r\n" +
+	" h:mailto:john@example.com\r\n" +
+	"DESCRIPTION;LANGUAGE=en-US:Agenda:\\n- Budget\\, forecast\\n- Hiring\\; roles\r\n" +
+	"UID:040000008200E00074C5B7101A82E00800000000\r\n" +
+	"SUMMARY;LANGUAGE=en-US:Quarterly planning\r\n" +
+	"DTSTART;TZID=Pacific Standard Time:20240315T090000\r\n" +
+	"DTEND;TZID=Pacific Standard Time:20240315T100000\r\n" +
+	"LOCATION;LANGUAGE=en-US:Room 4\r\n" +
+	"STATUS:CONFIRMED\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseOutlookInvite(t *testing.T) {
+	cal, err := Parse(strings.NewReader(outlookInvite))
+	require.NoError(t, err)
+	require.Equal(t, "REQUEST", cal.Method)
+	require.Equal(t, "Microsoft Exchange Server 2010", cal.ProdID)
+	require.Len(t, cal.Events, 1)
+
+	evt := cal.Events[0]
+	require.Equal(t, "040000008200E00074C5B7101A82E00800000000", evt.UID)
+	require.Equal(t, "Quarterly planning", evt.Summary)
+	require.Equal(t, "Agenda:\n- Budget, forecast\n- Hiring; roles", evt.Description)
+	require.Equal(t, "Room 4", evt.Location)
+	require.Equal(t, StatusConfirmed, evt.Status)
+	require.False(t, evt.AllDay)
+
+	require.Equal(t, "America/Los_Angeles", evt.Start.Location().String())
+	require.True(t, time.Date(2024, 3, 15, 16, 0, 0, 0, time.UTC).Equal(evt.Start))
+	require.True(t, time.Date(2024, 3, 15, 17, 0, 0, 0, time.UTC).Equal(evt.End))
+
+	require.Equal(t, &Person{Name: "Doe, Jane", Email: "jane@example.com"}, evt.Organizer)
+	require.Len(t, evt.Attendees, 1)
+	require.Equal(t, &Attendee{
+		Person:   Person{Name: "John Smith", Email: "john@example.com"},
+		Role:     RoleRequired,
+		PartStat: PartStatNeedsAction,
+		RSVP:     true,
+	}, evt.Attendees[0])
+}
+
+// customZone is a VTIMEZONE with an unknown name, following the rules of
+// Central Europe. The daylight observance is listed first on purpose.
+const customZone = "BEGIN:VTIMEZONE\nTZID:Custom Zone\n" +
+	"BEGIN:DAYLIGHT\nDTSTART:19810329T020000\nTZOFFSETFROM:+0100\nTZOFFSETTO:+0200\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\nEND:DAYLIGHT\n" +
+	"BEGIN:STANDARD\nDTSTART:19961027T030000\nTZOFFSETFROM:+0200\nTZOFFSETTO:+0100\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\nEND:STANDARD\n" +
+	"END:VTIMEZONE\n"
+
+func TestParseDateTimes(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		props         string
+		expectedStart time.Time
+		expectedEnd   time.Time
+		allDay        bool
+	}{
+		{
+			name:          "UTC",
+			props:         "DTSTART:20240315T090000Z\nDTEND:20240315T093000Z\n",
+			expectedStart: time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:          "floating",
+			props:         "DTSTART:20240315T090000\nDTEND:20240315T093000\n",
+			expectedStart: time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:          "duration",
+			props:         "DTSTART:20240315T090000Z\nDURATION:PT1H15M\n",
+			expectedStart: time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 3, 15, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:          "all day without end",
+			props:         "DTSTART;VALUE=DATE:20240315\n",
+			expectedStart: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC),
+			allDay:        true,
+		},
+		{
+			name:          "IANA name with a path prefix",
+			props:         "DTSTART;TZID=/mozilla.org/20050126_1/Europe/Paris:20240315T090000\nDTEND;TZID=\"Europe/Paris\":20240315T100000\n",
+			expectedStart: time.Date(2024, 3, 15, 8, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "unknown time zone with a VTIMEZONE",
+			props: "DTSTART;TZID=Custom Zone:20240315T090000\nDTEND;TZID=Custom Zone:20240315T100000\n" +
+				"END:VEVENT\nBEGIN:VTIMEZONE\nTZID:Custom Zone\nBEGIN:STANDARD\nDTSTART:19700101T000000\nTZOFFSETFROM:+0300\nTZOFFSETTO:+0300\nEND:STANDARD\nEND:VTIMEZONE\nBEGIN:VEVENT\nDTSTART:20240101T000000Z\n",
+			expectedStart: time.Date(2024, 3, 15, 6, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 3, 15, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "unknown time zone in daylight saving time",
+			props: "DTSTART;TZID=Custom Zone:20240715T090000\nDTEND;TZID=Custom Zone:20240715T100000\n" +
+				"END:VEVENT\n" + customZone + "BEGIN:VEVENT\nDTSTART:20240101T000000Z\n",
+			expectedStart: time.Date(2024, 7, 15, 7, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 7, 15, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "unknown time zone in standard time",
+			props: "DTSTART;TZID=Custom Zone:20241215T090000\nDTEND;TZID=Custom Zone:20241215T100000\n" +
+				"END:VEVENT\n" + customZone + "BEGIN:VEVENT\nDTSTART:20240101T000000Z\n",
+			expectedStart: time.Date(2024, 12, 15, 8, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 12, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "unknown time zone on the day of the change",
+			props: "DTSTART;TZID=Custom Zone:20240331T010000\nDTEND;TZID=Custom Zone:20240331T040000\n" +
+				"END:VEVENT\n" + customZone + "BEGIN:VEVENT\nDTSTART:20240101T000000Z\n",
+			expectedStart: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 3, 31, 2, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:uid\n" + tc.props + "END:VEVENT\nEND:VCALENDAR\n"
+			cal, err := Parse(strings.NewReader(data))
+			require.NoError(t, err)
+			require.NotEmpty(t, cal.Events)
+
+			evt := cal.Events[0]
+			require.True(t, tc.expectedStart.Equal(evt.Start), evt.Start.String())
+			require.True(t, tc.expectedEnd.Equal(evt.End), evt.End.String())
+			require.Equal(t, tc.allDay, evt.AllDay)
+		})
+	}
+}
+
+func TestParseInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	data := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:uid\nDTSTART:20240315T090000\nEND:VEVENT\nEND:VCALENDAR\n"
+	cal, err := ParseInLocation(strings.NewReader(data), loc)
+	require.NoError(t, err)
+	require.True(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC).Equal(cal.Events[0].Start))
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	in := &Calendar{
+		Events: []*Event{{
+			UID:         "uid",
+			Summary:     "A; very, long \\ summary " + strings.Repeat("x", 100),
+			Description: "first\nsecond",
+			Start:       time.Date(2024, 11, 1, 9, 0, 0, 0, ny),
+			End:         time.Date(2024, 11, 4, 9, 0, 0, 0, ny),
+			Stamp:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Organizer:   &Person{Name: "Jane; Doe", Email: "jane@example.com"},
+		}},
+	}
+	data, err := in.Marshal()
+	require.NoError(t, err)
+
+	out, err := Parse(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	require.Len(t, out.Events, 1)
+
+	evt := out.Events[0]
+	require.Equal(t, in.Events[0].Summary, evt.Summary)
+	require.Equal(t, in.Events[0].Description, evt.Description)
+	require.True(t, in.Events[0].Start.Equal(evt.Start))
+	require.True(t, in.Events[0].End.Equal(evt.End))
+	require.True(t, in.Events[0].Stamp.Equal(evt.Stamp))
+	require.Equal(t, in.Events[0].Organizer, evt.Organizer)
+}
+
+func TestParseRecurrence(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		property  string
+		recurring bool
+	}{
+		{"single event", "", false},
+		{"RRULE", "RRULE:FREQ=WEEKLY;BYDAY=MO\n", true},
+		{"RDATE", "RDATE:20240322T090000Z\n", true},
+		{"EXDATE", "EXDATE:20240322T090000Z\n", true},
+		{"RECURRENCE-ID", "RECURRENCE-ID:20240322T090000Z\n", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:uid\nDTSTART:20240315T090000Z\n" + tc.property + "END:VEVENT\nEND:VCALENDAR\n"
+			cal, err := Parse(strings.NewReader(data))
+			require.NoError(t, err)
+			require.Len(t, cal.Events, 1)
+			require.Equal(t, tc.recurring, cal.Events[0].Recurring)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		data          string
+		expectedError string
+	}{
+		{
+			name:          "empty",
+			data:          "",
+			expectedError: ErrNoCalendar.Error(),
+		},
+		{
+			name:          "not a calendar",
+			data:          "BEGIN:VCARD\nFN:Jane\nEND:VCARD\n",
+			expectedError: ErrNoCalendar.Error(),
+		},
+		{
+			name:          "invalid line",
+			data:          "BEGIN:VCALENDAR\nnot a content line\nEND:VCALENDAR\n",
+			expectedError: "line 2: invalid content line \"not a content line\"",
+		},
+		{
+			name:          "unterminated component",
+			data:          "BEGIN:VCALENDAR\nBEGIN:VEVENT\n",
+			expectedError: "unterminated VEVENT",
+		},
+		{
+			name:          "mismatched end",
+			data:          "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n",
+			expectedError: "line 3: unexpected END:VCALENDAR",
+		},
+		{
+			name:          "event without start",
+			data:          "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:uid\nEND:VEVENT\nEND:VCALENDAR\n",
+			expectedError: "event \"uid\" has no start",
+		},
+		{
+			name:          "invalid date",
+			data:          "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:2024-03-15\nEND:VEVENT\nEND:VCALENDAR\n",
+			expectedError: "invalid DTSTART",
+		},
+		{
+			name:          "invalid duration",
+			data:          "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20240315T090000Z\nDURATION:1H\nEND:VEVENT\nEND:VCALENDAR\n",
+			expectedError: "invalid DURATION: invalid duration \"1H\"",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.data))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for in, expected := range map[string]time.Duration{
+		"PT15M":      15 * time.Minute,
+		"-PT15M":     -15 * time.Minute,
+		"P1W":        7 * 24 * time.Hour,
+		"P1DT2H3S":   26*time.Hour + 3*time.Second,
+		"+PT1H30M0S": 90 * time.Minute,
+	} {
+		d, err := parseDuration(in)
+		require.NoError(t, err, in)
+		require.Equal(t, expected, d, in)
+	}
+
+	for _, in := range []string{"", "P", "PT", "P1H", "PT1D", "PT1", "1D"} {
+		_, err := parseDuration(in)
+		require.Error(t, err, in)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"time"
+)
+
+// timezoneRange is a location along with the time range a VTIMEZONE component
+// needs to describe.
+type timezoneRange struct {
+	loc  *time.Location
+	from time.Time
+	to   time.Time
+}
+
+type transition struct {
+	at         time.Time
+	offsetFrom int
+	offsetTo   int
+	name       string
+	isDST      bool
+}
+
+// timezone writes a VTIMEZONE component describing the offsets in use by the
+// location during the range. Go locations don't expose the rules they are
+// built from, so the transitions are listed explicitly instead of using
+// recurrence rules.
+func (e *encoder) timezone(r *timezoneRange) {
+	e.line("BEGIN", "VTIMEZONE")
+	e.line("TZID", paramValue(r.loc.String()))
+	for _, t := range findTransitions(r.loc, r.from, r.to) {
+		component := "STANDARD"
+		if t.isDST {
+			component = "DAYLIGHT"
+		}
+		e.line("BEGIN", component)
+		// DTSTART is the local time of the onset, expressed with the offset in
+		// use before the transition.
+		e.line("DTSTART", t.at.In(time.FixedZone("", t.offsetFrom)).Format(dateTimeFormat))
+		e.line("TZOFFSETFROM", formatOffset(t.offsetFrom))
+		e.line("TZOFFSETTO", formatOffset(t.offsetTo))
+		if t.name != "" {
+			e.line("TZNAME", escapeText(t.name))
+		}
+		e.line("END", component)
+	}
+	e.line("END", "VTIMEZONE")
+}
+
+// findTransitions returns the offset in use at the beginning of the range,
+// followed by every offset change happening until the end of the range.
+func findTransitions(loc *time.Location, from, to time.Time) []transition {
+	// Start a day early so that the first observance precedes the first
+	// date-time using it.
+	cursor := from.In(loc).Add(-24 * time.Hour).Truncate(time.Hour)
+	end := to.In(loc)
+
+	name, offset := cursor.Zone()
+	result := []transition{{
+		at:         cursor,
+		offsetFrom: offset,
+		offsetTo:   offset,
+		name:       name,
+		isDST:      cursor.IsDST(),
+	}}
+
+	for cursor.Before(end) {
+		next := cursor.Add(24 * time.Hour)
+		_, nextOffset := next.Zone()
+		if nextOffset != offset {
+			at := findOffsetChange(cursor, next)
+			nextName, _ := at.Zone()
+			result = append(result, transition{
+				at:         at,
+				offsetFrom: offset,
+				offsetTo:   nextOffset,
+				name:       nextName,
+				isDST:      at.IsDST(),
+			})
+			offset = nextOffset
+		}
+		cursor = next
+	}
+
+	return result
+}
+
+// findOffsetChange returns the first instant after lo that uses the offset in
+// effect at hi.
+func findOffsetChange(lo, hi time.Time) time.Time {
+	_, loOffset := lo.Zone()
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+		if _, midOffset := mid.Zone(); midOffset == loOffset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
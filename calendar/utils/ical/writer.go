@@ -0,0 +1,278 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultProdID = "-//Mattermost//Microsoft Calendar Plugin//EN"
+
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+	utcFormat      = "20060102T150405Z"
+
+	maxLineOctets = 75
+)
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// Marshal returns the iCalendar representation of the calendar.
+func (c *Calendar) Marshal() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := c.Encode(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode writes the iCalendar representation of the calendar to w.
+func (c *Calendar) Encode(w io.Writer) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = defaultProdID
+	}
+
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", prodID)
+	e.line("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		e.line("METHOD", c.Method)
+	}
+
+	for _, tz := range c.timezones() {
+		e.timezone(tz)
+	}
+
+	now := time.Now()
+	for _, evt := range c.Events {
+		e.event(evt, now)
+	}
+	for _, fb := range c.FreeBusy {
+		e.freeBusy(fb, now)
+	}
+
+	e.line("END", "VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// timezones returns the locations used by the events, along with the time
+// range they need to cover.
+func (c *Calendar) timezones() []*timezoneRange {
+	byName := map[string]*timezoneRange{}
+	for _, evt := range c.Events {
+		if evt.AllDay {
+			continue
+		}
+		for _, t := range []time.Time{evt.Start, evt.End} {
+			if t.IsZero() || isUTC(t.Location()) {
+				continue
+			}
+			name := t.Location().String()
+			r, ok := byName[name]
+			if !ok {
+				byName[name] = &timezoneRange{loc: t.Location(), from: t, to: t}
+				continue
+			}
+			if t.Before(r.from) {
+				r.from = t
+			}
+			if t.After(r.to) {
+				r.to = t
+			}
+		}
+	}
+
+	result := []*timezoneRange{}
+	for _, r := range byName {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].loc.String() < result[j].loc.String()
+	})
+	return result
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) event(evt *Event, now time.Time) {
+	stamp := evt.Stamp
+	if stamp.IsZero() {
+		stamp = now
+	}
+
+	e.line("BEGIN", "VEVENT")
+	e.line("UID", evt.UID)
+	e.line("DTSTAMP", stamp.UTC().Format(utcFormat))
+	e.dateTime("DTSTART", evt.Start, evt.AllDay)
+	if !evt.End.IsZero() {
+		e.dateTime("DTEND", evt.End, evt.AllDay)
+	}
+	if evt.Summary != "" {
+		e.line("SUMMARY", escapeText(evt.Summary))
+	}
+	if evt.Description != "" {
+		e.line("DESCRIPTION", escapeText(evt.Description))
+	}
+	if evt.Location != "" {
+		e.line("LOCATION", escapeText(evt.Location))
+	}
+	if evt.URL != "" {
+		e.line("URL", evt.URL)
+	}
+	if evt.Status != "" {
+		e.line("STATUS", evt.Status)
+	}
+	if evt.Organizer != nil && evt.Organizer.Email != "" {
+		e.line("ORGANIZER"+personParams(evt.Organizer), mailto(evt.Organizer.Email))
+	}
+	for _, a := range evt.Attendees {
+		if a == nil || a.Email == "" {
+			continue
+		}
+		params := personParams(&a.Person)
+		if a.Role != "" {
+			params += ";ROLE=" + a.Role
+		}
+		if a.PartStat != "" {
+			params += ";PARTSTAT=" + a.PartStat
+		}
+		if a.RSVP {
+			params += ";RSVP=TRUE"
+		}
+		e.line("ATTENDEE"+params, mailto(a.Email))
+	}
+	e.line("END", "VEVENT")
+}
+
+func (e *encoder) freeBusy(fb *FreeBusy, now time.Time) {
+	stamp := fb.Stamp
+	if stamp.IsZero() {
+		stamp = now
+	}
+
+	e.line("BEGIN", "VFREEBUSY")
+	if fb.UID != "" {
+		e.line("UID", fb.UID)
+	}
+	e.line("DTSTAMP", stamp.UTC().Format(utcFormat))
+	e.line("DTSTART", fb.Start.UTC().Format(utcFormat))
+	e.line("DTEND", fb.End.UTC().Format(utcFormat))
+	if fb.Organizer != nil && fb.Organizer.Email != "" {
+		e.line("ORGANIZER"+personParams(fb.Organizer), mailto(fb.Organizer.Email))
+	}
+	for _, p := range fb.Periods {
+		fbType := p.Type
+		if fbType == "" {
+			fbType = FreeBusyTypeBusy
+		}
+		e.line("FREEBUSY;FBTYPE="+fbType, p.Start.UTC().Format(utcFormat)+"/"+p.End.UTC().Format(utcFormat))
+	}
+	e.line("END", "VFREEBUSY")
+}
+
+func (e *encoder) dateTime(name string, t time.Time, allDay bool) {
+	switch {
+	case allDay:
+		e.line(name+";VALUE=DATE", t.Format(dateFormat))
+	case isUTC(t.Location()):
+		e.line(name, t.UTC().Format(utcFormat))
+	default:
+		e.line(name+";TZID="+paramValue(t.Location().String()), t.Format(dateTimeFormat))
+	}
+}
+
+// line writes a content line, folding it so that no line is longer than 75
+// octets, as required by RFC 5545 section 3.1.
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+
+	s := name + ":" + value
+	var b strings.Builder
+	lineLen := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if lineLen+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			lineLen = 1
+		}
+		b.WriteRune(r)
+		lineLen += size
+	}
+	b.WriteString("\r\n")
+
+	_, e.err = e.w.WriteString(b.String())
+}
+
+func personParams(p *Person) string {
+	if p.Name == "" {
+		return ""
+	}
+	return ";CN=" + paramValue(p.Name)
+}
+
+func mailto(email string) string {
+	return "mailto:" + email
+}
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// paramValue quotes a parameter value when it contains characters that are
+// not allowed in an unquoted value.
+func paramValue(s string) string {
+	s = strings.ReplaceAll(s, `"`, "'")
+	if strings.ContainsAny(s, ";:,") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+func isUTC(loc *time.Location) bool {
+	return loc == time.UTC || loc.String() == "UTC"
+}
+
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	hours := offset / 3600
+	minutes := (offset % 3600) / 60
+	seconds := offset % 60
+	if seconds != 0 {
+		return fmt.Sprintf("%s%02d%02d%02d", sign, hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, hours, minutes)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	stamp := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cal := &Calendar{
+		Events: []*Event{
+			{
+				UID:         "uid-1",
+				Summary:     "Planning, Q1; budget",
+				Description: "Line one\nLine two",
+				Location:    "Room 1",
+				Start:       time.Date(2024, 3, 8, 10, 0, 0, 0, ny),
+				End:         time.Date(2024, 3, 11, 11, 0, 0, 0, ny),
+				Stamp:       stamp,
+				Organizer:   &Person{Name: "Jane Doe", Email: "jane@example.com"},
+				Attendees: []*Attendee{
+					{Person: Person{Name: "Jo", Email: "jo@example.com"}, Role: RoleRequired, PartStat: PartStatAccepted},
+					{Person: Person{Email: "room@example.com"}, Role: RoleNonParticipant},
+				},
+			},
+			{
+				UID:    "uid-2",
+				Start:  time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+				End:    time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+				AllDay: true,
+				Stamp:  stamp,
+			},
+			{
+				UID:   "uid-3",
+				Start: time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC),
+				Stamp: stamp,
+			},
+		},
+	}
+
+	data, err := cal.Marshal()
+	require.NoError(t, err)
+	out := string(data)
+
+	require.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	require.Equal(t, 3, strings.Count(out, "BEGIN:VEVENT"))
+	require.Contains(t, out, "UID:uid-1\r\n")
+	require.Contains(t, out, "DTSTAMP:20240101T000000Z\r\n")
+	require.Contains(t, out, "DTSTART;TZID=America/New_York:20240308T100000\r\n")
+	require.Contains(t, out, "DTEND;TZID=America/New_York:20240311T110000\r\n")
+	require.Contains(t, out, `SUMMARY:Planning\, Q1\; budget`+"\r\n")
+	require.Contains(t, out, `DESCRIPTION:Line one\nLine two`+"\r\n")
+	require.Contains(t, out, "ORGANIZER;CN=Jane Doe:mailto:jane@example.com\r\n")
+	require.Contains(t, out, "ATTENDEE;CN=Jo;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED:mailto:jo@example.com\r\n")
+	require.Contains(t, out, "ATTENDEE;ROLE=NON-PARTICIPANT:mailto:room@example.com\r\n")
+	require.Contains(t, out, "DTSTART;VALUE=DATE:20240309\r\n")
+	require.Contains(t, out, "DTEND;VALUE=DATE:20240310\r\n")
+	require.Contains(t, out, "DTSTART:20240309T150000Z\r\n")
+
+	// A single VTIMEZONE covering the DST change of March 10th.
+	require.Equal(t, 1, strings.Count(out, "BEGIN:VTIMEZONE"))
+	require.Contains(t, out, "TZID:America/New_York\r\n")
+	require.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20240307T")
+	require.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20240310T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nTZNAME:EDT\r\nEND:DAYLIGHT\r\n")
+}
+
+func TestLineFolding(t *testing.T) {
+	cal := &Calendar{
+		Events: []*Event{{
+			UID:     "uid",
+			Summary: strings.Repeat("é", 100),
+			Start:   time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	data, err := cal.Marshal()
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\r\n"), "\r\n")
+	folded := 0
+	for _, line := range lines {
+		require.LessOrEqual(t, len(line), 75, line)
+		if strings.HasPrefix(line, " ") {
+			folded++
+		}
+	}
+	require.Equal(t, 2, folded)
+}
+
+func TestFormatOffset(t *testing.T) {
+	require.Equal(t, "+0000", formatOffset(0))
+	require.Equal(t, "-0500", formatOffset(-5*3600))
+	require.Equal(t, "+0530", formatOffset(5*3600+30*60))
+	require.Equal(t, "+001730", formatOffset(17*60+30))
+}
+
+func TestMarshalFreeBusy(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	cal := &Calendar{
+		FreeBusy: []*FreeBusy{{
+			Start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+			Stamp: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Periods: []*Period{
+				{Start: time.Date(2024, 3, 1, 10, 0, 0, 0, paris), End: time.Date(2024, 3, 1, 11, 0, 0, 0, paris)},
+				{Start: time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC), Type: FreeBusyTypeBusyTentative},
+			},
+		}},
+	}
+
+	data, err := cal.Marshal()
+	require.NoError(t, err)
+	out := string(data)
+
+	require.NotContains(t, out, "VTIMEZONE")
+	require.Contains(t, out, "BEGIN:VFREEBUSY\r\nDTSTAMP:20240301T000000Z\r\nDTSTART:20240301T000000Z\r\nDTEND:20240302T000000Z\r\n")
+	require.Contains(t, out, "FREEBUSY;FBTYPE=BUSY:20240301T090000Z/20240301T100000Z\r\n")
+	require.Contains(t, out, "FREEBUSY;FBTYPE=BUSY-TENTATIVE:20240301T140000Z/20240301T150000Z\r\n")
+}
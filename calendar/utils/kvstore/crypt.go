@@ -14,6 +14,10 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrEncryptionKeyNotSet is returned by the encrypted stores when the plugin
+// has no encryption key.
+var ErrEncryptionKeyNotSet = errors.New("the encryption key of the plugin is not set, generate it in the plugin settings")
+
 func encode(encrypted []byte) []byte {
 	encoded := make([]byte, base64.URLEncoding.EncodedLen(len(encrypted)))
 	base64.URLEncoding.Encode(encoded, encrypted)
@@ -30,6 +34,9 @@ func decode(encoded []byte) ([]byte, error) {
 }
 
 func encrypt(key []byte, data []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return []byte(""), ErrEncryptionKeyNotSet
+	}
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return []byte(""), errors.Wrap(err, "could not create a cipher block, check key")
@@ -50,6 +57,9 @@ func encrypt(key []byte, data []byte) ([]byte, error) {
 }
 
 func decrypt(key []byte, data []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return []byte(""), ErrEncryptionKeyNotSet
+	}
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return []byte(""), errors.Wrap(err, "could not create a cipher block, check key")
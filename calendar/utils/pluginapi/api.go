@@ -4,6 +4,7 @@
 package pluginapi
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -12,6 +13,8 @@ import (
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
 )
 
+const membersPerPage = 200
+
 type API struct {
 	api plugin.API
 }
@@ -147,3 +150,84 @@ func (a *API) GetPost(postID string) (*model.Post, error) {
 func (a *API) PublishWebsocketEvent(mattermostUserID, event string, payload map[string]any) {
 	a.api.PublishWebSocketEvent(event, payload, &model.WebsocketBroadcast{UserId: mattermostUserID})
 }
+
+func (a *API) CanReadChannel(channelID, userID string) bool {
+	return a.api.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel)
+}
+
+func (a *API) GetDirectChannel(userID, otherUserID string) (*model.Channel, error) {
+	channel, appErr := a.api.GetDirectChannel(userID, otherUserID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return channel, nil
+}
+
+func (a *API) GetFileInfo(fileID string) (*model.FileInfo, error) {
+	info, appErr := a.api.GetFileInfo(fileID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return info, nil
+}
+
+func (a *API) GetFile(fileID string) ([]byte, error) {
+	data, appErr := a.api.GetFile(fileID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return data, nil
+}
+
+func (a *API) OpenInteractiveDialog(dialog model.OpenDialogRequest) error {
+	appErr := a.api.OpenInteractiveDialog(dialog)
+	if appErr != nil {
+		return appErr
+	}
+	return nil
+}
+
+func (a *API) GetMattermostUsersInChannel(channelID string) ([]*model.User, error) {
+	users := []*model.User{}
+	for page := 0; ; page++ {
+		u, appErr := a.api.GetUsersInChannel(channelID, model.ChannelSortByUsername, page, membersPerPage)
+		if appErr != nil {
+			return nil, appErr
+		}
+		users = append(users, u...)
+		if len(u) < membersPerPage {
+			return users, nil
+		}
+	}
+}
+
+func (a *API) GetMattermostGroupByName(name string) (*model.Group, error) {
+	for strings.HasPrefix(name, "@") {
+		name = name[1:]
+	}
+	g, appErr := a.api.GetGroupByName(name)
+	if appErr != nil {
+		if appErr.StatusCode == http.StatusNotFound {
+			return nil, store.ErrNotFound
+		}
+		return nil, appErr
+	}
+	if g.DeleteAt != 0 {
+		return nil, store.ErrNotFound
+	}
+	return g, nil
+}
+
+func (a *API) GetMattermostGroupMembers(groupID string) ([]*model.User, error) {
+	users := []*model.User{}
+	for page := 0; ; page++ {
+		u, appErr := a.api.GetGroupMemberUsers(groupID, page, membersPerPage)
+		if appErr != nil {
+			return nil, appErr
+		}
+		users = append(users, u...)
+		if len(u) < membersPerPage {
+			return users, nil
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package settingspanel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// multiOptionSetting lets the user select any number of options. Each option
+// is a button that toggles it.
+type multiOptionSetting struct {
+	store       SettingStore
+	title       string
+	description string
+	id          string
+	dependsOn   string
+	options     []string
+}
+
+func NewMultiOptionSetting(id, title, description, dependsOn string, options []string, store SettingStore) Setting {
+	return &multiOptionSetting{
+		title:       title,
+		description: description,
+		id:          id,
+		dependsOn:   dependsOn,
+		options:     options,
+		store:       store,
+	}
+}
+
+// Set toggles the option given as value.
+func (s *multiOptionSetting) Set(userID string, value interface{}) error {
+	option, ok := value.(string)
+	if !ok || !s.isOption(option) {
+		return fmt.Errorf("%v is not a valid option", value)
+	}
+
+	current, err := s.getSelected(userID)
+	if err != nil {
+		return err
+	}
+
+	selected := []string{}
+	found := false
+	for _, o := range current {
+		if o == option {
+			found = true
+			continue
+		}
+		selected = append(selected, o)
+	}
+	if !found {
+		selected = append(selected, option)
+	}
+
+	// Keep the order of the options
+	ordered := []string{}
+	for _, o := range s.options {
+		for _, sel := range selected {
+			if o == sel {
+				ordered = append(ordered, o)
+			}
+		}
+	}
+
+	return s.store.SetSetting(userID, s.id, ordered)
+}
+
+func (s *multiOptionSetting) Get(userID string) (interface{}, error) {
+	return s.getSelected(userID)
+}
+
+func (s *multiOptionSetting) getSelected(userID string) ([]string, error) {
+	value, err := s.store.GetSetting(userID, s.id)
+	if err != nil {
+		return nil, err
+	}
+	selected, ok := value.([]string)
+	if !ok {
+		return nil, errors.New("current value is not a list")
+	}
+	return selected, nil
+}
+
+func (s *multiOptionSetting) isOption(option string) bool {
+	for _, o := range s.options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *multiOptionSetting) GetID() string {
+	return s.id
+}
+
+func (s *multiOptionSetting) GetTitle() string {
+	return s.title
+}
+
+func (s *multiOptionSetting) GetDescription() string {
+	return s.description
+}
+
+func (s *multiOptionSetting) GetDependency() string {
+	return s.dependsOn
+}
+
+func (s *multiOptionSetting) GetSlackAttachments(userID, settingHandler string, disabled bool) (*model.SlackAttachment, error) {
+	title := fmt.Sprintf("Setting: %s", s.title)
+	currentValueMessage := "Disabled"
+
+	actions := []*model.PostAction{}
+	if !disabled {
+		selected, err := s.getSelected(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		currentTextValue := "None"
+		if len(selected) > 0 {
+			currentTextValue = strings.Join(selected, ", ")
+		}
+		currentValueMessage = fmt.Sprintf("**Current value:** %s", currentTextValue)
+
+		for _, o := range s.options {
+			style := "default"
+			for _, sel := range selected {
+				if o == sel {
+					style = "primary"
+				}
+			}
+
+			actions = append(actions, &model.PostAction{
+				Name:  o,
+				Style: style,
+				Integration: &model.PostActionIntegration{
+					URL: settingHandler,
+					Context: map[string]interface{}{
+						ContextIDKey:          s.id,
+						ContextButtonValueKey: o,
+					},
+				},
+			})
+		}
+	}
+
+	text := fmt.Sprintf("%s\n%s", s.description, currentValueMessage)
+	sa := model.SlackAttachment{
+		Title:    title,
+		Text:     text,
+		Actions:  actions,
+		Fallback: fmt.Sprintf("%s: %s", title, text),
+	}
+	return &sa, nil
+}
+
+func (s *multiOptionSetting) IsDisabled(foreignValue interface{}) bool {
+	return foreignValue == "false"
+}
@@ -4,7 +4,10 @@
 package msgraph
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
+	"time"
 )
 
 const maxNumRequestsPerBatch = 20
@@ -21,11 +24,93 @@ type fullBatchRequest struct {
 	Requests []*singleRequest `json:"requests"`
 }
 
+type rawSingleResponse struct {
+	Headers map[string]string `json:"headers"`
+	ID      string            `json:"id"`
+	Body    json.RawMessage   `json:"body"`
+	Status  int               `json:"status"`
+}
+
+type rawBatchResponse struct {
+	Responses []*rawSingleResponse `json:"responses"`
+}
+
+// batchRequest sends the batch and unmarshals the responses into out. Graph
+// throttles each request of a batch on its own, so the throttled or failed
+// requests are sent again in a smaller batch, after the longest Retry-After
+// of their responses, and their last response is returned if they keep
+// failing or have to wait longer than the maximum delay. The batch itself is retried by the transport.
 func (c *client) batchRequest(req fullBatchRequest, out interface{}) error {
 	u := "https://graph.microsoft.com/v1.0/$batch"
 
-	_, err := c.CallJSON(http.MethodPost, u, req, out)
-	return err
+	policy := c.retryPolicy
+	if policy == nil {
+		policy = newRetryPolicy()
+	}
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	responses := map[string]*rawSingleResponse{}
+	pending := req.Requests
+	for retry := 1; ; retry++ {
+		res := &rawBatchResponse{}
+		_, err := c.CallJSON(http.MethodPost, u, fullBatchRequest{Requests: pending}, res)
+		if err != nil {
+			return err
+		}
+
+		var delay time.Duration
+		retryIDs := map[string]bool{}
+		for _, r := range res.Responses {
+			responses[r.ID] = r
+			if !isRetryableStatus(r.Status) {
+				continue
+			}
+			// The requests asked to wait longer than the maximum delay keep
+			// their throttled response.
+			d, ok := policy.delay(retry, r.Headers["Retry-After"])
+			if !ok {
+				continue
+			}
+			retryIDs[r.ID] = true
+			if d > delay {
+				delay = d
+			}
+		}
+		if len(retryIDs) == 0 || retry > policy.maxRetries {
+			break
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			break
+		}
+
+		retryRequests := []*singleRequest{}
+		for _, r := range pending {
+			if retryIDs[r.ID] {
+				retryRequests = append(retryRequests, r)
+			}
+		}
+		pending = retryRequests
+
+		err = policy.sleep(ctx, delay)
+		if err != nil {
+			return err
+		}
+	}
+
+	result := &rawBatchResponse{}
+	for _, r := range req.Requests {
+		if res, ok := responses[r.ID]; ok {
+			result.Responses = append(result.Responses, res)
+		}
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
 }
 
 func prepareBatchRequests(requests []*singleRequest) []fullBatchRequest {
@@ -14,6 +14,8 @@ import (
 
 	"github.com/pkg/errors"
 	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
 
 func (c *client) CallJSON(method, path string, in, out interface{}) (responseData []byte, err error) {
@@ -93,10 +95,15 @@ func (c *client) call(method, path, contentType string, inBody io.Reader, out in
 	}
 
 	errResp := msgraph.ErrorResponse{Response: resp}
+	var callErr error = &errResp
 	err = json.Unmarshal(responseData, &errResp)
 	if err != nil {
-		return responseData, errors.WithMessagef(err, "status: %s. response: %s", resp.Status, string(responseData))
+		callErr = errors.WithMessagef(err, "status: %s. response: %s", resp.Status, string(responseData))
 	}
 
-	return responseData, &errResp
+	// Throttled and failed requests may succeed later
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		callErr = &remote.TransientError{Err: callErr}
+	}
+	return responseData, callErr
 }
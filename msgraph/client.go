@@ -24,6 +24,7 @@ type client struct {
 	mattermostUserID string
 	conf             *config.Config
 	tokenHelpers     remote.UserTokenHelpers
+	retryPolicy      *retryPolicy
 
 	bot.Logger
 	bot.Poster
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
-	msgraph "github.com/yaegashi/msgraph.go/v1.0"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
@@ -56,13 +55,13 @@ func (c *client) GetEvent(remoteUserID, eventID string) (*remote.Event, error) {
 	return e, nil
 }
 
-func (c *client) AcceptEvent(remoteUserID, eventID string) error {
-	dummy := &msgraph.EventAcceptRequestParameter{}
+func (c *client) AcceptEvent(remoteUserID, eventID string, opts *remote.EventResponseOptions) error {
+	req := newEventResponseRequest(opts)
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
 		return errors.New(ErrorUserInactive)
 	}
-	err := c.rbuilder.Users().ID(remoteUserID).Events().ID(eventID).Accept(dummy).Request().Post(c.ctx)
+	err := c.rbuilder.Users().ID(remoteUserID).Events().ID(eventID).Request().JSONRequest(c.ctx, http.MethodPost, "/accept", req, nil)
 	if err != nil {
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
 		return errors.Wrap(err, "msgraph Accept Event")
@@ -70,13 +69,31 @@ func (c *client) AcceptEvent(remoteUserID, eventID string) error {
 	return nil
 }
 
-func (c *client) DeclineEvent(remoteUserID, eventID string) error {
-	dummy := &msgraph.EventDeclineRequestParameter{}
+// eventResponseRequest is the body of the Graph event response actions. It is
+// used instead of the msgraph.go parameters, which lack proposedNewTime.
+type eventResponseRequest struct {
+	SendResponse    *bool            `json:"sendResponse,omitempty"`
+	ProposedNewTime *remote.TimeSlot `json:"proposedNewTime,omitempty"`
+	Comment         string           `json:"comment,omitempty"`
+}
+
+func newEventResponseRequest(opts *remote.EventResponseOptions) *eventResponseRequest {
+	req := &eventResponseRequest{}
+	if opts != nil {
+		req.SendResponse = opts.SendResponse
+		req.ProposedNewTime = opts.ProposedNewTime
+		req.Comment = opts.Comment
+	}
+	return req
+}
+
+func (c *client) DeclineEvent(remoteUserID, eventID string, opts *remote.EventResponseOptions) error {
+	req := newEventResponseRequest(opts)
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
 		return errors.New(ErrorUserInactive)
 	}
-	err := c.rbuilder.Users().ID(remoteUserID).Events().ID(eventID).Decline(dummy).Request().Post(c.ctx)
+	err := c.rbuilder.Users().ID(remoteUserID).Events().ID(eventID).Request().JSONRequest(c.ctx, http.MethodPost, "/decline", req, nil)
 	if err != nil {
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
 		return errors.Wrap(err, "msgraph DeclineEvent")
@@ -84,13 +101,13 @@ func (c *client) DeclineEvent(remoteUserID, eventID string) error {
 	return nil
 }
 
-func (c *client) TentativelyAcceptEvent(remoteUserID, eventID string) error {
-	dummy := &msgraph.EventTentativelyAcceptRequestParameter{}
+func (c *client) TentativelyAcceptEvent(remoteUserID, eventID string, opts *remote.EventResponseOptions) error {
+	req := newEventResponseRequest(opts)
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
 		return errors.New(ErrorUserInactive)
 	}
-	err := c.rbuilder.Users().ID(remoteUserID).Events().ID(eventID).TentativelyAccept(dummy).Request().Post(c.ctx)
+	err := c.rbuilder.Users().ID(remoteUserID).Events().ID(eventID).Request().JSONRequest(c.ctx, http.MethodPost, "/tentativelyAccept", req, nil)
 	if err != nil {
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
 		return errors.Wrap(err, "msgraph TentativelyAcceptEvent")
@@ -112,5 +129,17 @@ func (c *client) GetEventsBetweenDates(remoteUserID string, start, end time.Time
 		return nil, errors.Wrap(err, "msgraph GetEventsBetweenDates")
 	}
 
-	return normalizeEvents(res.Value), nil
+	// The events are returned a page at a time
+	events := res.Value
+	for res.NextLink != "" {
+		nextLink := res.NextLink
+		res = &calendarViewResponse{}
+		_, err = c.call(http.MethodGet, nextLink, "", nil, res)
+		if err != nil {
+			return nil, errors.Wrap(err, "msgraph GetEventsBetweenDates")
+		}
+		events = append(events, res.Value...)
+	}
+
+	return normalizeEvents(events), nil
 }
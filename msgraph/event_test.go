@@ -0,0 +1,57 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+type connectedTokenHelpers struct{}
+
+func (connectedTokenHelpers) CheckUserConnected(string) bool                   { return true }
+func (connectedTokenHelpers) DisconnectUserFromStoreIfNecessary(error, string) {}
+func (connectedTokenHelpers) RefreshAndStoreToken(token *oauth2.Token, _ *oauth2.Config, _ string) (*oauth2.Token, error) {
+	return token, nil
+}
+
+func TestGetEventsBetweenDates(t *testing.T) {
+	start := time.Date(2020, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 90)
+
+	pages := 0
+	fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1.0/users/user1/calendarView", r.URL.Path)
+		pages++
+		switch r.URL.Query().Get("$skiptoken") {
+		case "":
+			require.Equal(t, "2020-02-11T00:00:00Z", r.URL.Query().Get("startDateTime"))
+			_, _ = w.Write([]byte(`{
+				"value":[{"id":"event1","responseStatus":{"response":"accepted"}}],
+				"@odata.nextLink":"https://graph.microsoft.com/v1.0/users/user1/calendarView?$skiptoken=page2"}`))
+		case "page2":
+			_, _ = w.Write([]byte(`{
+				"value":[{"id":"event2","responseStatus":{"response":"accepted"}}],
+				"@odata.nextLink":"https://graph.microsoft.com/v1.0/users/user1/calendarView?$skiptoken=page3"}`))
+		case "page3":
+			_, _ = w.Write([]byte(`{"value":[{"id":"event3","responseStatus":{"response":"accepted"}}]}`))
+		default:
+			require.Fail(t, "unexpected request", r.URL.String())
+		}
+	})
+	c := fake.newClient(newCircuitBreaker())
+	c.tokenHelpers = connectedTokenHelpers{}
+
+	events, err := c.GetEventsBetweenDates("user1", start, end)
+	require.NoError(t, err)
+	require.Equal(t, 3, pages)
+	require.Len(t, events, 3)
+	for i, id := range []string{"event1", "event2", "event3"} {
+		require.Equal(t, id, events[i].ID)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+// maxRoomsPerRequest is the maximum page size accepted by the places API.
+const maxRoomsPerRequest = 100
+
+type findRoomsResponse struct {
+	Value    []*remote.Room `json:"value"`
+	NextLink string         `json:"@odata.nextLink,omitempty"`
+}
+
+// FindRooms lists the meeting rooms defined in the tenant.
+func (c *client) FindRooms() ([]*remote.Room, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	res := &findRoomsResponse{}
+	u := c.rbuilder.URL() + "/places/microsoft.graph.room?$top=" + strconv.Itoa(maxRoomsPerRequest)
+	_, err := c.CallJSON(http.MethodGet, u, nil, res)
+	if err != nil {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		if isForbiddenError(err) {
+			return nil, remote.ErrRoomsNotPermitted
+		}
+		return nil, errors.Wrap(err, "msgraph FindRooms")
+	}
+
+	// The rooms are returned a page at a time
+	rooms := res.Value
+	for res.NextLink != "" {
+		nextLink := res.NextLink
+		res = &findRoomsResponse{}
+		_, err = c.CallJSON(http.MethodGet, nextLink, nil, res)
+		if err != nil {
+			return nil, errors.Wrap(err, "msgraph FindRooms")
+		}
+		rooms = append(rooms, res.Value...)
+	}
+
+	return rooms, nil
+}
+
+// isForbiddenError tells if the tenant did not consent to the rooms permission.
+func isForbiddenError(err error) bool {
+	var errResp *msgraph.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusForbidden
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestFindRooms(t *testing.T) {
+	pages := 0
+	fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1.0/places/microsoft.graph.room", r.URL.Path)
+		pages++
+		switch r.URL.Query().Get("$skiptoken") {
+		case "":
+			require.Equal(t, "100", r.URL.Query().Get("$top"))
+			_, _ = w.Write([]byte(`{
+				"value":[{"emailAddress":"room1@example.com"}],
+				"@odata.nextLink":"https://graph.microsoft.com/v1.0/places/microsoft.graph.room?$top=100&$skiptoken=page2"}`))
+		case "page2":
+			_, _ = w.Write([]byte(`{"value":[{"emailAddress":"room2@example.com"}]}`))
+		default:
+			require.Fail(t, "unexpected request", r.URL.String())
+		}
+	})
+	c := fake.newClient(newCircuitBreaker())
+	c.tokenHelpers = connectedTokenHelpers{}
+
+	rooms, err := c.FindRooms()
+	require.NoError(t, err)
+	require.Equal(t, 2, pages)
+	require.Len(t, rooms, 2)
+	require.Equal(t, "room1@example.com", rooms[0].EmailAddress)
+	require.Equal(t, "room2@example.com", rooms[1].EmailAddress)
+}
+
+func TestFindRoomsForbidden(t *testing.T) {
+	fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":"ErrorAccessDenied","message":"Access is denied."}}`))
+	})
+	c := fake.newClient(newCircuitBreaker())
+	c.tokenHelpers = connectedTokenHelpers{}
+
+	_, err := c.FindRooms()
+	require.ErrorIs(t, err, remote.ErrRoomsNotPermitted)
+}
@@ -14,8 +14,9 @@ import (
 )
 
 type calendarViewResponse struct {
-	Error *remote.APIError `json:"error,omitempty"`
-	Value []*remote.Event  `json:"value,omitempty"`
+	Error    *remote.APIError `json:"error,omitempty"`
+	Value    []*remote.Event  `json:"value,omitempty"`
+	NextLink string           `json:"@odata.nextLink,omitempty"`
 }
 
 type calendarViewSingleResponse struct {
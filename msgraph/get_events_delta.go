@@ -0,0 +1,141 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+type eventsDeltaEvent struct {
+	remote.Event
+	Removed *struct {
+		Reason string `json:"reason"`
+	} `json:"@removed,omitempty"`
+}
+
+type eventsDeltaResponse struct {
+	Error     *remote.APIError    `json:"error,omitempty"`
+	Value     []*eventsDeltaEvent `json:"value,omitempty"`
+	NextLink  string              `json:"@odata.nextLink,omitempty"`
+	DeltaLink string              `json:"@odata.deltaLink,omitempty"`
+}
+
+type eventsDeltaSingleResponse struct {
+	Headers map[string]string   `json:"headers"`
+	ID      string              `json:"id"`
+	Body    eventsDeltaResponse `json:"body"`
+	Status  int                 `json:"status"`
+}
+
+type eventsDeltaBatchResponse struct {
+	Responses []*eventsDeltaSingleResponse `json:"responses"`
+}
+
+// DoBatchEventsDeltaRequests gets the changes to the calendar views of the
+// users with the Graph delta queries. The first page of each user is
+// requested in batches, and the following pages, if any, one by one.
+func (c *client) DoBatchEventsDeltaRequests(allParams []*remote.EventsDeltaParams) ([]*remote.EventsDeltaResponse, error) {
+	requests := []*singleRequest{}
+	for _, params := range allParams {
+		requests = append(requests, &singleRequest{
+			ID:      params.RemoteUserID,
+			URL:     c.getEventsDeltaURL(params),
+			Method:  http.MethodGet,
+			Headers: map[string]string{},
+		})
+	}
+
+	result := []*remote.EventsDeltaResponse{}
+	for _, req := range prepareBatchRequests(requests) {
+		batchRes := &eventsDeltaBatchResponse{}
+		err := c.batchRequest(req, batchRes)
+		if err != nil {
+			return nil, errors.Wrap(err, "msgraph EventsDelta batch request")
+		}
+
+		for _, res := range batchRes.Responses {
+			deltaRes := &remote.EventsDeltaResponse{
+				RemoteUserID: res.ID,
+			}
+			result = append(result, deltaRes)
+
+			if res.Status == http.StatusGone {
+				deltaRes.Expired = true
+				continue
+			}
+
+			page := &res.Body
+			for {
+				if page.Error != nil {
+					deltaRes.Error = page.Error
+					break
+				}
+				addEventsDeltaPage(deltaRes, page)
+				if page.NextLink == "" {
+					break
+				}
+
+				nextLink := page.NextLink
+				page = &eventsDeltaResponse{}
+				_, err = c.call(http.MethodGet, nextLink, "", nil, page)
+				if isGoneError(err) {
+					deltaRes.Expired = true
+					break
+				}
+				if err != nil {
+					deltaRes.Error = &remote.APIError{Message: err.Error()}
+					break
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (c *client) getEventsDeltaURL(params *remote.EventsDeltaParams) string {
+	if params.DeltaLink != "" {
+		// Batched requests need URLs relative to the API root
+		return strings.TrimPrefix(params.DeltaLink, c.rbuilder.URL())
+	}
+	paramStr := getQueryParamStringForEventsDelta(params)
+	return "/Users/" + url.PathEscape(params.RemoteUserID) + "/calendarView/delta" + paramStr
+}
+
+func getQueryParamStringForEventsDelta(params *remote.EventsDeltaParams) string {
+	q := url.Values{}
+	q.Add("startDateTime", params.StartTime.Format(time.RFC3339))
+	q.Add("endDateTime", params.EndTime.Format(time.RFC3339))
+	return "?" + q.Encode()
+}
+
+func addEventsDeltaPage(res *remote.EventsDeltaResponse, page *eventsDeltaResponse) {
+	for _, e := range page.Value {
+		if e.Removed != nil {
+			res.RemovedIDs = append(res.RemovedIDs, e.ID)
+			continue
+		}
+		event := e.Event
+		if event.ResponseStatus != nil {
+			event.ResponseStatus.Response = responseStatusConversion[event.ResponseStatus.Response]
+		}
+		res.Events = append(res.Events, &event)
+	}
+	if page.DeltaLink != "" {
+		res.DeltaLink = page.DeltaLink
+	}
+}
+
+func isGoneError(err error) bool {
+	var errResp *msgraph.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusGone
+}
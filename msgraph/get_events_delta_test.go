@@ -0,0 +1,71 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestDoBatchEventsDeltaRequests(t *testing.T) {
+	start := time.Date(2020, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 9)
+
+	fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/$batch":
+			req := fullBatchRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Len(t, req.Requests, 3)
+			require.Equal(t, "/Users/user1/calendarView/delta?endDateTime=2020-02-20T00%3A00%3A00Z&startDateTime=2020-02-11T00%3A00%3A00Z", req.Requests[0].URL)
+			require.Equal(t, "/users/user2/calendarView/delta?$deltatoken=expired", req.Requests[1].URL)
+			require.Equal(t, "/users/user3/calendarView/delta?$deltatoken=token3", req.Requests[2].URL)
+
+			_, _ = w.Write([]byte(`{"responses":[
+				{"id":"user1","status":200,"body":{
+					"value":[{"id":"event1","subject":"First","responseStatus":{"response":"accepted"}}],
+					"@odata.nextLink":"https://graph.microsoft.com/v1.0/users/user1/calendarView/delta?$skiptoken=page2"}},
+				{"id":"user2","status":410,"body":{"error":{"code":"SyncStateNotFound"}}},
+				{"id":"user3","status":403,"body":{"error":{"code":"ErrorAccessDenied","message":"Access is denied."}}}
+			]}`))
+		case "/v1.0/users/user1/calendarView/delta":
+			require.Equal(t, "page2", r.URL.Query().Get("$skiptoken"))
+			_, _ = w.Write([]byte(`{
+				"value":[{"id":"event2","@removed":{"reason":"deleted"}}],
+				"@odata.deltaLink":"https://graph.microsoft.com/v1.0/users/user1/calendarView/delta?$deltatoken=token1"}`))
+		default:
+			require.Fail(t, "unexpected request", r.URL.String())
+		}
+	})
+	c := fake.newClient(newCircuitBreaker())
+
+	responses, err := c.DoBatchEventsDeltaRequests([]*remote.EventsDeltaParams{
+		{RemoteUserID: "user1", StartTime: start, EndTime: end},
+		{RemoteUserID: "user2", StartTime: start, EndTime: end, DeltaLink: "https://graph.microsoft.com/v1.0/users/user2/calendarView/delta?$deltatoken=expired"},
+		{RemoteUserID: "user3", StartTime: start, EndTime: end, DeltaLink: "https://graph.microsoft.com/v1.0/users/user3/calendarView/delta?$deltatoken=token3"},
+	})
+	require.NoError(t, err)
+	require.Len(t, responses, 3)
+
+	require.Equal(t, "user1", responses[0].RemoteUserID)
+	require.Nil(t, responses[0].Error)
+	require.Len(t, responses[0].Events, 1)
+	require.Equal(t, "event1", responses[0].Events[0].ID)
+	require.Equal(t, remote.EventResponseStatusAccepted, responses[0].Events[0].ResponseStatus.Response)
+	require.Equal(t, []string{"event2"}, responses[0].RemovedIDs)
+	require.Equal(t, "https://graph.microsoft.com/v1.0/users/user1/calendarView/delta?$deltatoken=token1", responses[0].DeltaLink)
+
+	require.Equal(t, "user2", responses[1].RemoteUserID)
+	require.True(t, responses[1].Expired)
+
+	require.Equal(t, "user3", responses[2].RemoteUserID)
+	require.False(t, responses[2].Expired)
+	require.Equal(t, "Access is denied.", responses[2].Error.Message)
+}
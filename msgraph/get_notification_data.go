@@ -4,6 +4,7 @@
 package msgraph
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/pkg/errors"
@@ -14,13 +15,43 @@ import (
 
 func (c *client) GetNotificationData(orig *remote.Notification) (*remote.Notification, error) {
 	n := *orig
-	wh := n.Webhook.(*webhook)
+	wh, ok := n.Webhook.(*webhook)
+	if !ok {
+		// The notification was persisted without the decoded webhook
+		wh = &webhook{}
+		err := json.Unmarshal(n.WebhookRawData, wh)
+		if err != nil {
+			return nil, errors.Wrap(err, "msgraph GetNotificationData: invalid webhook")
+		}
+		n.Webhook = wh
+	}
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
 		return nil, errors.New(ErrorUserInactive)
 	}
 	switch wh.ResourceData.DataType {
 	case "#Microsoft.Graph.Event":
+		if wh.ChangeType == remote.ChangeTypeDeleted {
+			// The event can no longer be fetched
+			n.Event = &remote.Event{ID: wh.ResourceData.ID}
+			n.ChangeType = wh.ChangeType
+			n.IsBare = false
+			break
+		}
+
+		if wh.EncryptedContent != nil && n.DecryptionKey != nil {
+			event, err := decryptEvent(wh.EncryptedContent, n.DecryptionKey)
+			if err == nil {
+				n.Event = event
+				n.ChangeType = wh.ChangeType
+				n.IsBare = false
+				break
+			}
+			c.Logger.With(bot.LogContext{
+				"subscriptionID": wh.SubscriptionID,
+			}).Infof("msgraph: failed to decrypt notification data, fetching it instead: `%v`.", err)
+		}
+
 		event := remote.Event{}
 		_, err := c.CallJSON(http.MethodGet, wh.Resource, nil, &event)
 		if err != nil {
@@ -44,3 +75,16 @@ func (c *client) GetNotificationData(orig *remote.Notification) (*remote.Notific
 
 	return &n, nil
 }
+
+func decryptEvent(ec *encryptedContent, privateKey []byte) (*remote.Event, error) {
+	data, err := ec.decrypt(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	event := &remote.Event{}
+	err = json.Unmarshal(data, event)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid event")
+	}
+	return event, nil
+}
@@ -6,6 +6,7 @@ package msgraph
 import (
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 
@@ -53,8 +54,12 @@ func (c *client) GetSchedule(requests []*remote.ScheduleUserInfo, startTime, end
 	}
 
 	allRequests := []*singleRequest{}
-	for _, req := range requests {
-		allRequests = append(allRequests, makeSingleRequestForGetSchedule(req, params))
+	for i, req := range requests {
+		singleReq := makeSingleRequestForGetSchedule(req, params)
+		// Several schedules may be requested on behalf of the same remote user
+		// (e.g. rooms), so the batch ID needs to be unique per request.
+		singleReq.ID = strconv.Itoa(i)
+		allRequests = append(allRequests, singleReq)
 	}
 	batchRequests := prepareBatchRequests(allRequests)
 
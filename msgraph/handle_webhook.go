@@ -4,28 +4,43 @@
 package msgraph
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/microcosm-cc/bluemonday"
+	"github.com/pkg/errors"
 
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/metrics"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/httputils"
 )
 
 const renewSubscriptionBeforeExpiration = 12 * time.Hour
 
+// maxWebhookSize is the largest webhook accepted, rich notifications included.
+const maxWebhookSize = utils.ByteSize(4 * 1024 * 1024)
+
 type webhook struct {
+	ID                             string `json:"id,omitempty"`
 	ChangeType                     string `json:"changeType"`
+	LifecycleEvent                 string `json:"lifecycleEvent,omitempty"`
 	ClientState                    string `json:"clientState,omitempty"`
 	Resource                       string `json:"resource,omitempty"`
 	SubscriptionExpirationDateTime string `json:"subscriptionExpirationDateTime,omitempty"`
 	SubscriptionID                 string `json:"subscriptionId"`
 	ResourceData                   struct {
 		DataType string `json:"@odata.type"`
+		ETag     string `json:"@odata.etag,omitempty"`
+		ID       string `json:"id"`
 	} `json:"resourceData"`
+	EncryptedContent *encryptedContent `json:"encryptedContent,omitempty"`
 }
 
 func (r *impl) HandleWebhook(w http.ResponseWriter, req *http.Request) []*remote.Notification {
@@ -34,8 +49,7 @@ func (r *impl) HandleWebhook(w http.ResponseWriter, req *http.Request) []*remote
 	vtok := req.FormValue("validationToken")
 	var policy = bluemonday.StrictPolicy()
 	if vtok != policy.Sanitize(vtok) {
-		w.WriteHeader(http.StatusBadRequest)
-		r.logger.Debugf("msgraph: validation token is invalid.")
+		r.rejectWebhook(w, req, http.StatusBadRequest, "invalid endpoint validation token", nil)
 		return nil
 	}
 
@@ -47,40 +61,71 @@ func (r *impl) HandleWebhook(w http.ResponseWriter, req *http.Request) []*remote
 		return nil
 	}
 
-	rawData, err := io.ReadAll(req.Body)
+	if req.ContentLength > int64(maxWebhookSize) {
+		r.rejectWebhook(w, req, http.StatusRequestEntityTooLarge, "webhook too large", nil)
+		return nil
+	}
+	body := &httputils.LimitReadCloser{
+		ReadCloser: req.Body,
+		Limit:      maxWebhookSize + 1,
+	}
+	rawData, err := io.ReadAll(body)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		r.logger.Infof("msgraph: failed to process webhook: `%v`.", err)
 		return nil
 	}
+	if body.TotalRead > maxWebhookSize {
+		r.rejectWebhook(w, req, http.StatusRequestEntityTooLarge, "webhook too large", nil)
+		return nil
+	}
 
 	// Get the list of webhooks
 	var v struct {
-		Value []*webhook `json:"value"`
+		Value            []*webhook `json:"value"`
+		ValidationTokens []string   `json:"validationTokens,omitempty"`
 	}
 	err = json.Unmarshal(rawData, &v)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		r.logger.Infof("msgraph: failed to process webhook: `%v`.", err)
+		r.rejectWebhook(w, req, http.StatusBadRequest, "invalid webhook", err)
+		return nil
+	}
+
+	// Webhooks with resource data come with tokens proving they are sent by
+	// the remote, as anyone could encrypt data with the public certificate.
+	err = r.verifyValidationTokens(v.ValidationTokens, v.Value, time.Now())
+	if err != nil {
+		r.rejectWebhook(w, req, http.StatusUnauthorized, "invalid validation tokens", err)
 		return nil
 	}
 
 	notifications := []*remote.Notification{}
 	for _, wh := range v.Value {
+		whRawData, err := json.Marshal(wh)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			r.logger.Infof("msgraph: failed to process webhook: `%v`.", err)
+			return nil
+		}
+
+		// Lifecycle notifications are about the subscription, not an event.
 		n := &remote.Notification{
 			SubscriptionID: wh.SubscriptionID,
 			ChangeType:     wh.ChangeType,
+			LifecycleEvent: wh.LifecycleEvent,
 			ClientState:    wh.ClientState,
-			IsBare:         true,
-			WebhookRawData: rawData,
+			IsBare:         wh.LifecycleEvent == "",
+			DeliveryID:     wh.deliveryID(),
+			WebhookRawData: whRawData,
 			Webhook:        wh,
 		}
+		if wh.EncryptedContent != nil {
+			n.EncryptionCertificateID = wh.EncryptedContent.EncryptionCertificateID
+		}
 
 		expires, err := time.Parse(time.RFC3339, wh.SubscriptionExpirationDateTime)
 		if err != nil {
-			r.logger.With(bot.LogContext{
-				"SubscriptionID": wh.SubscriptionID,
-			}).Infof("msgraph: invalid subscription expiration in webhook: `%v`.", err)
+			r.rejectWebhook(w, req, http.StatusBadRequest, "invalid subscription expiration", err)
 			return nil
 		}
 		expires = expires.Add(-renewSubscriptionBeforeExpiration)
@@ -91,6 +136,68 @@ func (r *impl) HandleWebhook(w http.ResponseWriter, req *http.Request) []*remote
 		notifications = append(notifications, n)
 	}
 
-	w.WriteHeader(http.StatusAccepted)
+	// Accepted by the caller once the notifications are persisted, so that
+	// the remote delivers them again if they cannot be.
 	return notifications
 }
+
+// deliveryID identifies the webhook regardless of its encrypted content,
+// which differs between deliveries.
+func (wh *webhook) deliveryID() string {
+	h := sha256.New()
+	for _, v := range []string{
+		wh.SubscriptionID,
+		wh.ID,
+		wh.ChangeType,
+		wh.LifecycleEvent,
+		wh.Resource,
+		wh.ResourceData.ID,
+		wh.ResourceData.ETag,
+		wh.SubscriptionExpirationDateTime,
+	} {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (r *impl) verifyValidationTokens(tokens []string, webhooks []*webhook, now time.Time) error {
+	if len(tokens) == 0 {
+		for _, wh := range webhooks {
+			if wh.EncryptedContent != nil {
+				return errors.New("missing validation tokens")
+			}
+		}
+		return nil
+	}
+
+	for _, token := range tokens {
+		err := r.verifyValidationToken(token, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rejectWebhook responds with the status, and keeps track of the rejected
+// requests for the admins to audit.
+func (r *impl) rejectWebhook(w http.ResponseWriter, req *http.Request, status int, reason string, err error) {
+	w.WriteHeader(status)
+	metrics.IncNotificationsDropped(metrics.DropRejected)
+
+	logContext := bot.LogContext{
+		"audit":      "webhook_rejected",
+		"reason":     reason,
+		"status":     status,
+		"remoteAddr": req.RemoteAddr,
+		"userAgent":  req.UserAgent(),
+	}
+	if forwardedFor := req.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		logContext["forwardedFor"] = strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+	}
+	if err != nil {
+		logContext["err"] = err.Error()
+	}
+	r.logger.With(logContext).Warnf("msgraph: rejected webhook: %s.", reason)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+func TestHandleWebhook(t *testing.T) {
+	const expiration = "2100-01-01T00:00:00Z"
+
+	for _, tc := range []struct {
+		name           string
+		body           string
+		expectedStatus int // 0 if the response is left to the caller
+		expected       []*remote.Notification
+	}{
+		{
+			name: "change notification",
+			body: `{"value":[{"subscriptionId":"sub_id","clientState":"state","changeType":"created","subscriptionExpirationDateTime":"` + expiration + `","resourceData":{"id":"event_id"}}]}`,
+			expected: []*remote.Notification{{
+				SubscriptionID: "sub_id",
+				ClientState:    "state",
+				ChangeType:     remote.ChangeTypeCreated,
+				IsBare:         true,
+			}},
+		},
+		{
+			name: "reauthorization required",
+			body: `{"value":[{"subscriptionId":"sub_id","clientState":"state","lifecycleEvent":"reauthorizationRequired","subscriptionExpirationDateTime":"` + expiration + `"}]}`,
+			expected: []*remote.Notification{{
+				SubscriptionID: "sub_id",
+				ClientState:    "state",
+				LifecycleEvent: remote.LifecycleReauthorizationRequired,
+			}},
+		},
+		{
+			name: "missed",
+			body: `{"value":[{"subscriptionId":"sub_id","clientState":"state","lifecycleEvent":"missed","subscriptionExpirationDateTime":"` + expiration + `"}]}`,
+			expected: []*remote.Notification{{
+				SubscriptionID: "sub_id",
+				ClientState:    "state",
+				LifecycleEvent: remote.LifecycleMissed,
+			}},
+		},
+		{
+			name: "subscription removed",
+			body: `{"value":[{"subscriptionId":"sub_id","clientState":"state","lifecycleEvent":"subscriptionRemoved","subscriptionExpirationDateTime":"` + expiration + `"}]}`,
+			expected: []*remote.Notification{{
+				SubscriptionID: "sub_id",
+				ClientState:    "state",
+				LifecycleEvent: remote.LifecycleSubscriptionRemoved,
+			}},
+		},
+		{
+			name:           "resource data without validation tokens",
+			body:           `{"value":[{"subscriptionId":"sub_id","clientState":"state","changeType":"created","subscriptionExpirationDateTime":"` + expiration + `","encryptedContent":{"data":"data"}}]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid validation tokens",
+			body:           `{"value":[{"subscriptionId":"sub_id","clientState":"state","changeType":"created","subscriptionExpirationDateTime":"` + expiration + `"}],"validationTokens":["not a token"]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "too large",
+			body:           `{"value":[],"padding":"` + strings.Repeat("x", int(maxWebhookSize)) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "invalid payload",
+			body:           `{"value":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &impl{logger: &bot.NilLogger{}}
+			req := httptest.NewRequest(http.MethodPost, "/notification/v1/lifecycle", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			notifications := r.HandleWebhook(rec, req)
+			if tc.expectedStatus == 0 {
+				// Accepted by the caller once the notifications are queued
+				require.NotNil(t, notifications)
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.Zero(t, rec.Body.Len())
+			} else {
+				require.Equal(t, tc.expectedStatus, rec.Code)
+			}
+			for _, n := range notifications {
+				require.NotEmpty(t, n.WebhookRawData)
+				require.NotEmpty(t, n.DeliveryID)
+				n.DeliveryID = ""
+				n.WebhookRawData = nil
+				n.Webhook = nil
+			}
+			require.Equal(t, tc.expected, notifications)
+		})
+	}
+}
+
+func TestHandleWebhookRepeatedDelivery(t *testing.T) {
+	const body = `{"value":[{"subscriptionId":"sub_id","clientState":"state","changeType":"updated","subscriptionExpirationDateTime":"2100-01-01T00:00:00Z","resourceData":{"id":"event_id","@odata.etag":"%s"}}]}`
+	r := &impl{logger: &bot.NilLogger{}}
+	deliveryID := func(etag string) string {
+		req := httptest.NewRequest(http.MethodPost, "/notification/v1/event", strings.NewReader(fmt.Sprintf(body, etag)))
+		notifications := r.HandleWebhook(httptest.NewRecorder(), req)
+		require.Len(t, notifications, 1)
+		return notifications[0].DeliveryID
+	}
+
+	require.Equal(t, deliveryID("etag_1"), deliveryID("etag_1"))
+	require.NotEqual(t, deliveryID("etag_1"), deliveryID("etag_2"))
+}
+
+func TestHandleWebhookValidation(t *testing.T) {
+	r := &impl{logger: &bot.NilLogger{}}
+	req := httptest.NewRequest(http.MethodPost, "/notification/v1/lifecycle?validationToken=token", nil)
+	rec := httptest.NewRecorder()
+
+	notifications := r.HandleWebhook(rec, req)
+	require.Nil(t, notifications)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "token", rec.Body.String())
+}
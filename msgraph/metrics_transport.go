@@ -0,0 +1,50 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/metrics"
+)
+
+// metricsTransport counts the requests to the remote, each retry included.
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	metrics.ObserveGraphRequest(req.Method, metricsEndpoint(req.URL.Path), status, time.Since(start))
+	return resp, err
+}
+
+// metricsEndpoint returns the path without the IDs it contains, so that the
+// requests to the same endpoint are counted together. The segments of the
+// endpoints are words, those of the IDs have digits or symbols.
+func metricsEndpoint(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		// API version
+		if i == 0 {
+			continue
+		}
+		if strings.HasPrefix(segment, "$") {
+			continue
+		}
+		if strings.IndexFunc(segment, func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z')
+		}) >= 0 {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/v1.0/me/events":                         "/v1.0/me/events",
+		"/v1.0/me/events/AAMkAGI2TG93AAA=/accept": "/v1.0/me/events/{id}/accept",
+		"/v1.0/users/6e7b768e-07e2-4810-8459-485f84f8f204/calendar/getSchedule": "/v1.0/users/{id}/calendar/getSchedule",
+		"/v1.0/users/user@example.com/mailboxSettings":                          "/v1.0/users/{id}/mailboxSettings",
+		"/v1.0/subscriptions/7f105c7d-2dc5-4530-97cd-4e7ae6534c07":              "/v1.0/subscriptions/{id}",
+		"/v1.0/$batch": "/v1.0/$batch",
+	} {
+		require.Equal(t, expected, metricsEndpoint(path), path)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+)
+
+// richNotificationEventProperties are the properties of the events included
+// in the notifications, those fetched for the bare ones.
+const richNotificationEventProperties = "id,iCalUId,subject,bodyPreview,body,importance,start,end,isAllDay,location,attendees,organizer,isOrganizer,isCancelled,responseRequested,responseStatus,showAs,webLink,reminderMinutesBeforeStart"
+
+// encryptedContent is the resource data of a rich notification, see
+// https://learn.microsoft.com/en-us/graph/change-notifications-with-resource-data#decrypting-resource-data-from-change-notifications
+type encryptedContent struct {
+	Data                            string `json:"data"`
+	DataSignature                   string `json:"dataSignature"`
+	DataKey                         string `json:"dataKey"`
+	EncryptionCertificateID         string `json:"encryptionCertificateId"`
+	EncryptionCertificateThumbprint string `json:"encryptionCertificateThumbprint"`
+}
+
+// decrypt returns the resource data. The symmetric key is encrypted with the
+// public key of the certificate, and the data is signed and encrypted with
+// the symmetric key.
+func (ec *encryptedContent) decrypt(privateKeyDER []byte) ([]byte, error) {
+	key, err := x509.ParsePKCS8PrivateKey(privateKeyDER)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+
+	encryptedKey, err := base64.StdEncoding.DecodeString(ec.DataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data key")
+	}
+	symmetricKey, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, rsaKey, encryptedKey, nil) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt data key")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(ec.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data")
+	}
+	signature, err := base64.StdEncoding.DecodeString(ec.DataSignature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data signature")
+	}
+	mac := hmac.New(sha256.New, symmetricKey)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return nil, errors.New("data signature does not match")
+	}
+
+	block, err := aes.NewCipher(symmetricKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data key")
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid data length")
+	}
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, symmetricKey[:aes.BlockSize]).CryptBlocks(decrypted, data)
+
+	// PKCS7 padding
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, errors.New("invalid data padding")
+	}
+	return decrypted[:len(decrypted)-padding], nil
+}
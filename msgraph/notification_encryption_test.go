@@ -0,0 +1,104 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// encryptTestContent encrypts the data the way the remote does.
+func encryptTestContent(t *testing.T, key *rsa.PrivateKey, data []byte) *encryptedContent {
+	symmetricKey := make([]byte, 32)
+	_, err := rand.Read(symmetricKey)
+	require.NoError(t, err)
+
+	encryptedKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, &key.PublicKey, symmetricKey, nil) //nolint:gosec
+	require.NoError(t, err)
+
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	padded := append([]byte{}, data...)
+	for i := 0; i < padding; i++ {
+		padded = append(padded, byte(padding))
+	}
+	block, err := aes.NewCipher(symmetricKey)
+	require.NoError(t, err)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, symmetricKey[:aes.BlockSize]).CryptBlocks(encrypted, padded)
+
+	mac := hmac.New(sha256.New, symmetricKey)
+	mac.Write(encrypted)
+
+	return &encryptedContent{
+		Data:                    base64.StdEncoding.EncodeToString(encrypted),
+		DataSignature:           base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+		DataKey:                 base64.StdEncoding.EncodeToString(encryptedKey),
+		EncryptionCertificateID: "certificate_id",
+	}
+}
+
+func TestDecryptEvent(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherPrivateKey, err := x509.MarshalPKCS8PrivateKey(otherKey)
+	require.NoError(t, err)
+
+	data := []byte(`{"id":"event_id","iCalUId":"ical_id","subject":"Daily standup"}`)
+
+	for _, tc := range []struct {
+		name        string
+		content     func() *encryptedContent
+		privateKey  []byte
+		expectedErr string
+	}{
+		{
+			name:       "decrypted",
+			content:    func() *encryptedContent { return encryptTestContent(t, key, data) },
+			privateKey: privateKey,
+		},
+		{
+			name: "tampered data",
+			content: func() *encryptedContent {
+				ec := encryptTestContent(t, key, data)
+				other := encryptTestContent(t, key, []byte(`{"id":"other_event_id"}`))
+				ec.Data = other.Data
+				return ec
+			},
+			privateKey:  privateKey,
+			expectedErr: "data signature does not match",
+		},
+		{
+			name:        "wrong private key",
+			content:     func() *encryptedContent { return encryptTestContent(t, key, data) },
+			privateKey:  otherPrivateKey,
+			expectedErr: "failed to decrypt data key: crypto/rsa: decryption error",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := decryptEvent(tc.content(), tc.privateKey)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "event_id", event.ID)
+			require.Equal(t, "ical_id", event.ICalUID)
+			require.Equal(t, "Daily standup", event.Subject)
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	msgraph "github.com/yaegashi/msgraph.go/v1.0"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/metrics"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
 )
@@ -22,8 +23,9 @@ import (
 const Kind = "mscalendar"
 
 type impl struct {
-	conf   *config.Config
-	logger bot.Logger
+	conf     *config.Config
+	logger   bot.Logger
+	breakers *circuitBreakers
 }
 
 func init() {
@@ -32,14 +34,21 @@ func init() {
 
 func NewRemote(conf *config.Config, logger bot.Logger) remote.Remote {
 	return &impl{
-		conf:   conf,
-		logger: logger,
+		conf:     conf,
+		logger:   logger,
+		breakers: newCircuitBreakers(),
 	}
 }
 
 // MakeClient creates a new client for user-delegated permissions.
 func (r *impl) makeClient(ctx context.Context, token *oauth2.Token, mattermostUserID string, poster bot.Poster, userTokenHelpers remote.UserTokenHelpers) remote.Client {
+	policy := newRetryPolicy()
 	httpClient := r.NewOAuth2Config().Client(ctx, token)
+	httpClient.Transport = &retryTransport{
+		base:    &metricsTransport{base: httpClient.Transport},
+		policy:  policy,
+		breaker: r.breakers.get(mattermostUserID),
+	}
 	c := &client{
 		conf:             r.conf,
 		ctx:              ctx,
@@ -49,6 +58,7 @@ func (r *impl) makeClient(ctx context.Context, token *oauth2.Token, mattermostUs
 		tokenHelpers:     userTokenHelpers,
 		mattermostUserID: mattermostUserID,
 		Poster:           poster,
+		retryPolicy:      policy,
 	}
 
 	return c
@@ -60,6 +70,7 @@ func (r *impl) MakeUserClient(ctx context.Context, oauthToken *oauth2.Token, mat
 
 	token, err := userTokenHelpers.RefreshAndStoreToken(oauthToken, config, mattermostUserID)
 	if err != nil {
+		metrics.IncTokenRefreshFailures()
 		r.logger.Warnf("Not able to refresh or store the token", "error", err.Error())
 		return &client{}
 	}
@@ -92,18 +103,25 @@ func (r *impl) MakeSuperuserClient(ctx context.Context) (remote.Client, error) {
 }
 
 func (r *impl) NewOAuth2Config() *oauth2.Config {
+	scopes := []string{
+		"offline_access",
+		"User.Read",
+		"Calendars.ReadWrite",
+		"Calendars.ReadWrite.Shared",
+		"MailboxSettings.Read",
+	}
+	// Reading the rooms needs the consent of an admin, it is not asked for
+	// unless the room finder is enabled.
+	if r.conf.EnableRoomFinder {
+		scopes = append(scopes, "Place.Read.All")
+	}
+
 	return &oauth2.Config{
 		ClientID:     r.conf.OAuth2ClientID,
 		ClientSecret: r.conf.OAuth2ClientSecret,
 		RedirectURL:  r.conf.PluginURL + config.FullPathOAuth2Redirect,
-		Scopes: []string{
-			"offline_access",
-			"User.Read",
-			"Calendars.ReadWrite",
-			"Calendars.ReadWrite.Shared",
-			"MailboxSettings.Read",
-		},
-		Endpoint: microsoft.AzureADEndpoint(r.conf.OAuth2Authority),
+		Scopes:       scopes,
+		Endpoint:     microsoft.AzureADEndpoint(r.conf.OAuth2Authority),
 	}
 }
 
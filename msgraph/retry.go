@@ -0,0 +1,280 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const (
+	maxRetries      = 3
+	retryBaseDelay  = time.Second
+	retryMaxDelay   = 30 * time.Second
+	breakerFailures = 5
+	breakerCooldown = 30 * time.Second
+	breakerIdleTime = time.Hour
+)
+
+// ErrCircuitOpen is returned without calling Graph while it keeps failing.
+var ErrCircuitOpen = &remote.TransientError{Err: errors.New("msgraph: too many failed requests, try again later")}
+
+// retryPolicy decides how long to wait before retrying a throttled or failed
+// request to Graph.
+type retryPolicy struct {
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryPolicy() *retryPolicy {
+	return &retryPolicy{
+		maxRetries: maxRetries,
+		baseDelay:  retryBaseDelay,
+		maxDelay:   retryMaxDelay,
+		sleep:      sleepContext,
+	}
+}
+
+// delay returns the time to wait before the given retry, starting at 1. Graph
+// tells throttled clients how long to wait with the Retry-After header, which
+// is used instead of the exponential backoff when present. It returns false
+// when Retry-After is longer than the maximum delay: the request is not to be
+// retried, and the throttled response is returned instead.
+func (p *retryPolicy) delay(retry int, retryAfter string) (time.Duration, bool) {
+	d := p.baseDelay << (retry - 1)
+	hasRetryAfter := true
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		d = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(retryAfter); err == nil {
+		d = time.Until(at)
+	} else {
+		hasRetryAfter = false
+	}
+	if d < 0 {
+		d = 0
+	}
+	if d > p.maxDelay {
+		if hasRetryAfter {
+			return d, false
+		}
+		d = p.maxDelay
+	}
+	return d, true
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests ||
+		status == http.StatusServiceUnavailable ||
+		status == http.StatusGatewayTimeout
+}
+
+// isRetryable tells if the request can be sent again. A request that failed
+// may have been processed, so only the idempotent ones are retried, unless
+// Graph throttled it: throttled requests are not processed.
+func isRetryable(req *http.Request, resp *http.Response) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return isRetryableStatus(resp.StatusCode)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("Retry-After") != ""
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// circuitBreaker stops calling Graph for a while after several requests in a
+// row found the service unavailable, timed out or could not reach it even
+// after being retried, so it is not flooded with requests. Throttling is left
+// to the retries, as it only concerns the user sending too many requests.
+type circuitBreaker struct {
+	failures  int
+	cooldown  time.Duration
+	now       func() time.Time
+	lock      sync.Mutex
+	failed    int
+	openUntil time.Time
+	lastUsed  time.Time
+}
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{
+		failures: breakerFailures,
+		cooldown: breakerCooldown,
+		now:      time.Now,
+	}
+}
+
+// circuitBreakers keeps a breaker per user, so that the requests failing for
+// one of them do not stop those of the others. The breakers of the users that
+// have not sent any request for a while are evicted.
+type circuitBreakers struct {
+	idleTime   time.Duration
+	now        func() time.Time
+	lock       sync.Mutex
+	breakers   map[string]*circuitBreaker
+	lastPruned time.Time
+}
+
+func newCircuitBreakers() *circuitBreakers {
+	return &circuitBreakers{
+		idleTime: breakerIdleTime,
+		now:      time.Now,
+		breakers: map[string]*circuitBreaker{},
+	}
+}
+
+// get returns the breaker of the Mattermost user, the empty ID being the
+// application itself.
+func (b *circuitBreakers) get(mattermostUserID string) *circuitBreaker {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.prune()
+	breaker, ok := b.breakers[mattermostUserID]
+	if !ok {
+		breaker = newCircuitBreaker()
+		breaker.now = b.now
+		breaker.lastUsed = b.now()
+		b.breakers[mattermostUserID] = breaker
+	}
+	return breaker
+}
+
+// prune evicts the idle breakers, at most once per idle time so that getting
+// a breaker does not go through all of them.
+func (b *circuitBreakers) prune() {
+	now := b.now()
+	if now.Sub(b.lastPruned) < b.idleTime {
+		return
+	}
+	b.lastPruned = now
+	for id, breaker := range b.breakers {
+		if breaker.idle(now, b.idleTime) {
+			delete(b.breakers, id)
+		}
+	}
+}
+
+// idle tells if the breaker was not used for the given time and is closed.
+func (b *circuitBreaker) idle(now time.Time, idleTime time.Duration) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return now.Sub(b.lastUsed) >= idleTime && !now.Before(b.openUntil)
+}
+
+// Allow returns false while the breaker is open. Once the cooldown is over,
+// requests are let through again, and the breaker opens again on the next
+// failure.
+func (b *circuitBreaker) Allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.lastUsed = b.now()
+	return !b.lastUsed.Before(b.openUntil)
+}
+
+func (b *circuitBreaker) Success() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.failed = 0
+	b.openUntil = time.Time{}
+}
+
+func (b *circuitBreaker) Failure() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.failed++
+	if b.failed >= b.failures {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// Observe records the final response to a request.
+func (b *circuitBreaker) Observe(resp *http.Response) {
+	if resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout {
+		b.Failure()
+	} else {
+		b.Success()
+	}
+}
+
+// retryTransport retries the requests to Graph that were throttled or failed
+// with a transient error. It is the only layer retrying the requests, and it
+// stops when the request context would be done before the next attempt.
+type retryTransport struct {
+	base    http.RoundTripper
+	policy  *retryPolicy
+	breaker *circuitBreaker
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.breaker.Allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	for retry := 1; ; retry++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			// The requests given up by the caller say nothing of Graph.
+			if req.Context().Err() == nil {
+				t.breaker.Failure()
+			}
+			return nil, err
+		}
+		if !isRetryable(req, resp) || retry > t.policy.maxRetries || (req.Body != nil && req.GetBody == nil) {
+			t.breaker.Observe(resp)
+			return resp, nil
+		}
+
+		delay, ok := t.policy.delay(retry, resp.Header.Get("Retry-After"))
+		if !ok {
+			t.breaker.Observe(resp)
+			return resp, nil
+		}
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+			t.breaker.Observe(resp)
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err = t.policy.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		req, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to rewind request body")
+	}
+	newReq := req.Clone(req.Context())
+	newReq.Body = body
+	return newReq, nil
+}
@@ -0,0 +1,399 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+// graphFake sends all the requests of the client to an httptest server
+// instead of Graph, and records the delays the client waited for.
+type graphFake struct {
+	server *httptest.Server
+	lock   sync.Mutex
+	delays []time.Duration
+}
+
+func newGraphFake(t *testing.T, handler http.HandlerFunc) *graphFake {
+	fake := &graphFake{server: httptest.NewServer(handler)}
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func (f *graphFake) RoundTrip(req *http.Request) (*http.Response, error) {
+	serverURL, _ := url.Parse(f.server.URL)
+	req = req.Clone(req.Context())
+	req.URL.Scheme = serverURL.Scheme
+	req.URL.Host = serverURL.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (f *graphFake) newClient(breaker *circuitBreaker) *client {
+	policy := newRetryPolicy()
+	policy.sleep = func(_ context.Context, d time.Duration) error {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		f.delays = append(f.delays, d)
+		return nil
+	}
+	httpClient := &http.Client{
+		Transport: &retryTransport{
+			base:    f,
+			policy:  policy,
+			breaker: breaker,
+		},
+	}
+	return &client{
+		ctx:         context.Background(),
+		httpClient:  httpClient,
+		rbuilder:    msgraph.NewClient(httpClient),
+		retryPolicy: policy,
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		method         string
+		responses      []int
+		retryAfter     string
+		expectedCalls  int
+		expectedDelays []time.Duration
+		expectedErr    bool
+	}{
+		{
+			name:          "success",
+			method:        http.MethodPost,
+			responses:     []int{http.StatusOK},
+			expectedCalls: 1,
+		},
+		{
+			name:          "client error is not retried",
+			method:        http.MethodGet,
+			responses:     []int{http.StatusBadRequest},
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+		{
+			name:           "throttled with Retry-After",
+			method:         http.MethodPost,
+			responses:      []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:     "7",
+			expectedCalls:  2,
+			expectedDelays: []time.Duration{7 * time.Second},
+		},
+		{
+			name:           "idempotent request throttled without Retry-After",
+			method:         http.MethodGet,
+			responses:      []int{http.StatusTooManyRequests, http.StatusOK},
+			expectedCalls:  2,
+			expectedDelays: []time.Duration{time.Second},
+		},
+		{
+			name:          "other request throttled without Retry-After is not retried",
+			method:        http.MethodPost,
+			responses:     []int{http.StatusTooManyRequests},
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+		{
+			name:           "unavailable with exponential backoff",
+			method:         http.MethodGet,
+			responses:      []int{http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusOK},
+			expectedCalls:  3,
+			expectedDelays: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:          "other request unavailable is not retried",
+			method:        http.MethodPost,
+			responses:     []int{http.StatusServiceUnavailable},
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+		{
+			name:          "Retry-After above the maximum delay is not retried",
+			method:        http.MethodPost,
+			responses:     []int{http.StatusTooManyRequests},
+			retryAfter:    "3600",
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+		{
+			name:           "still failing after the retries",
+			method:         http.MethodGet,
+			responses:      []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			expectedCalls:  4,
+			expectedDelays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+			expectedErr:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tc.method, r.Method)
+				if r.Method == http.MethodPost {
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					require.JSONEq(t, `{"subject":"test"}`, string(body))
+				}
+
+				status := tc.responses[calls]
+				calls++
+				if tc.retryAfter != "" {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"id":"event_id"}`))
+			})
+			c := fake.newClient(newCircuitBreaker())
+
+			var in interface{}
+			if tc.method == http.MethodPost {
+				in = map[string]string{"subject": "test"}
+			}
+			out := &remote.Event{}
+			_, err := c.CallJSON(tc.method, "/me/events", in, out)
+			if tc.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "event_id", out.ID)
+			}
+			require.Equal(t, tc.expectedCalls, calls)
+			require.Equal(t, tc.expectedDelays, fake.delays)
+		})
+	}
+}
+
+func TestRetryTransportContextDeadline(t *testing.T) {
+	calls := 0
+	fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "20")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	c := fake.newClient(newCircuitBreaker())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c.ctx = ctx
+
+	_, err := c.CallJSON(http.MethodGet, "/me", nil, nil)
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+	require.Empty(t, fake.delays)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+	breakers := newCircuitBreakers()
+	breaker := breakers.get("user1")
+	breaker.now = func() time.Time { return now }
+	require.Same(t, breaker, breakers.get("user1"))
+	require.NotSame(t, breaker, breakers.get("user2"))
+
+	status := http.StatusTooManyRequests
+	calls := 0
+	fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	})
+	c := fake.newClient(breaker)
+	callsPerRequest := maxRetries + 1
+
+	// Throttling only concerns the user
+	for i := 0; i < breakerFailures; i++ {
+		_, err := c.CallJSON(http.MethodGet, "/me", nil, nil)
+		require.Error(t, err)
+	}
+	require.True(t, breaker.Allow())
+
+	status = http.StatusServiceUnavailable
+	calls = 0
+	for i := 0; i < breakerFailures; i++ {
+		_, err := c.CallJSON(http.MethodGet, "/me", nil, nil)
+		require.Error(t, err)
+		require.True(t, remote.IsTransientError(err))
+	}
+	require.Equal(t, breakerFailures*callsPerRequest, calls)
+
+	_, err := c.CallJSON(http.MethodGet, "/me", nil, nil)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.True(t, remote.IsTransientError(err))
+	require.Equal(t, breakerFailures*callsPerRequest, calls)
+	require.True(t, breakers.get("user2").Allow())
+
+	now = now.Add(breakerCooldown)
+	_, err = c.CallJSON(http.MethodGet, "/me", nil, nil)
+	require.NotErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, (breakerFailures+1)*callsPerRequest, calls)
+
+	_, err = c.CallJSON(http.MethodGet, "/me", nil, nil)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	breaker.Success()
+	require.True(t, breaker.Allow())
+}
+
+func TestBatchRequestRetry(t *testing.T) {
+	batches := 0
+	throttled := 0
+	fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1.0/$batch", r.URL.Path)
+		req := fullBatchRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		batches++
+		if batches == 1 {
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		res := &calendarViewBatchResponse{}
+		for _, single := range req.Requests {
+			response := &calendarViewSingleResponse{
+				ID:     single.ID,
+				Status: http.StatusOK,
+			}
+			if single.ID == "throttled" && throttled == 0 {
+				throttled++
+				response.Status = http.StatusTooManyRequests
+				response.Headers = map[string]string{"Retry-After": "5"}
+			}
+			res.Responses = append(res.Responses, response)
+		}
+		if batches == 3 {
+			require.Len(t, req.Requests, 1)
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+	c := fake.newClient(newCircuitBreaker())
+
+	out := &calendarViewBatchResponse{}
+	err := c.batchRequest(fullBatchRequest{Requests: []*singleRequest{
+		{ID: "ok", URL: "/me/calendarView", Method: http.MethodGet},
+		{ID: "throttled", URL: "/me/calendarView", Method: http.MethodGet},
+	}}, out)
+	require.NoError(t, err)
+
+	// The batch is retried by the transport, and the request throttled on
+	// its own is sent again in a batch of its own after its Retry-After.
+	require.Equal(t, 3, batches)
+	require.Equal(t, []time.Duration{10 * time.Second, 5 * time.Second}, fake.delays)
+	require.Len(t, out.Responses, 2)
+	require.Equal(t, "ok", out.Responses[0].ID)
+	require.Equal(t, http.StatusOK, out.Responses[0].Status)
+	require.Equal(t, "throttled", out.Responses[1].ID)
+	require.Equal(t, http.StatusOK, out.Responses[1].Status)
+}
+
+func TestBatchRequestRetryAfterAboveMaximum(t *testing.T) {
+	batches := 0
+	fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+		req := fullBatchRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		batches++
+
+		res := &calendarViewBatchResponse{}
+		for _, single := range req.Requests {
+			response := &calendarViewSingleResponse{
+				ID:     single.ID,
+				Status: http.StatusOK,
+			}
+			if single.ID == "throttled" {
+				response.Status = http.StatusTooManyRequests
+				response.Headers = map[string]string{"Retry-After": "3600"}
+			}
+			res.Responses = append(res.Responses, response)
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+	c := fake.newClient(newCircuitBreaker())
+
+	out := &calendarViewBatchResponse{}
+	err := c.batchRequest(fullBatchRequest{Requests: []*singleRequest{
+		{ID: "ok", URL: "/me/calendarView", Method: http.MethodGet},
+		{ID: "throttled", URL: "/me/calendarView", Method: http.MethodGet},
+	}}, out)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, batches)
+	require.Empty(t, fake.delays)
+	require.Len(t, out.Responses, 2)
+	require.Equal(t, http.StatusOK, out.Responses[0].Status)
+	require.Equal(t, http.StatusTooManyRequests, out.Responses[1].Status)
+}
+
+func TestCircuitBreakerFailures(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		failure func(w http.ResponseWriter)
+	}{
+		{
+			name: "gateway timeout",
+			failure: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusGatewayTimeout)
+			},
+		},
+		{
+			name: "transport error",
+			failure: func(w http.ResponseWriter) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				conn.Close()
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newGraphFake(t, func(w http.ResponseWriter, r *http.Request) {
+				tc.failure(w)
+			})
+			breaker := newCircuitBreaker()
+			c := fake.newClient(breaker)
+
+			for i := 0; i < breakerFailures; i++ {
+				_, err := c.CallJSON(http.MethodGet, "/me", nil, nil)
+				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrCircuitOpen)
+			}
+			_, err := c.CallJSON(http.MethodGet, "/me", nil, nil)
+			require.ErrorIs(t, err, ErrCircuitOpen)
+		})
+	}
+}
+
+func TestCircuitBreakersEviction(t *testing.T) {
+	now := time.Date(2020, 2, 12, 10, 0, 0, 0, time.UTC)
+	breakers := newCircuitBreakers()
+	breakers.now = func() time.Time { return now }
+
+	idle := breakers.get("idle")
+	active := breakers.get("active")
+	open := breakers.get("open")
+	open.cooldown = 2 * breakerIdleTime
+	for i := 0; i < breakerFailures; i++ {
+		open.Failure()
+	}
+
+	now = now.Add(breakerIdleTime / 2)
+	require.True(t, active.Allow())
+
+	now = now.Add(breakerIdleTime / 2)
+	require.Same(t, active, breakers.get("active"))
+	require.Same(t, open, breakers.get("open"))
+	require.NotSame(t, idle, breakers.get("idle"))
+	require.Len(t, breakers.breakers, 3)
+}
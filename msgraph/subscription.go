@@ -15,7 +15,9 @@ import (
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
 )
 
-const subscribeTTL = 48 * time.Hour
+// subscribeTTL is how long the subscriptions last, under the 4230 minutes the
+// remote allows for the events.
+const subscribeTTL = 70 * time.Hour
 
 func newRandomString() string {
 	b := make([]byte, 96)
@@ -23,13 +25,21 @@ func newRandomString() string {
 	return base64.URLEncoding.EncodeToString(b)
 }
 
-func (c *client) CreateMySubscription(notificationURL, _ string) (*remote.Subscription, error) {
+func (c *client) CreateMySubscription(notificationURL, _ string, encryption *remote.NotificationEncryption) (*remote.Subscription, error) {
 	sub := &remote.Subscription{
-		Resource:           "me/events",
-		ChangeType:         "created,updated,deleted",
-		NotificationURL:    notificationURL,
-		ExpirationDateTime: time.Now().Add(subscribeTTL).Format(time.RFC3339),
-		ClientState:        newRandomString(),
+		Resource:                 "me/events",
+		ChangeType:               "created,updated,deleted",
+		NotificationURL:          notificationURL,
+		LifecycleNotificationURL: c.conf.GetLifecycleNotificationURL(),
+		ExpirationDateTime:       time.Now().Add(subscribeTTL).Format(time.RFC3339),
+		ClientState:              newRandomString(),
+	}
+	if encryption != nil {
+		// Outlook resources only include the selected properties
+		sub.Resource = "me/events?$select=" + richNotificationEventProperties
+		sub.IncludeResourceData = true
+		sub.EncryptionCertificate = base64.StdEncoding.EncodeToString(encryption.Certificate)
+		sub.EncryptionCertificateID = encryption.CertificateID
 	}
 
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
@@ -90,6 +100,11 @@ func (c *client) RenewSubscription(_, _ string, oldSub *remote.Subscription) (*r
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
 		return nil, errors.Wrap(err, "msgraph RenewSubscription")
 	}
+	// The client state is not always returned, it is kept to validate the
+	// webhooks of the renewed subscription.
+	if sub.ClientState == "" {
+		sub.ClientState = oldSub.ClientState
+	}
 
 	c.Logger.With(bot.LogContext{
 		"subscriptionID":     oldSub.ID,
@@ -0,0 +1,199 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// graphChangeTrackingAppID is the application the remote sends the
+	// notifications with resource data as, see
+	// https://learn.microsoft.com/en-us/graph/change-notifications-with-resource-data#validating-the-authenticity-of-notifications
+	graphChangeTrackingAppID = "0bf30f3b-4a52-48df-9a82-234910c4a086"
+
+	validationTokenIssuerPrefix = "https://sts.windows.net/"
+	validationTokenClockSkew    = 5 * time.Minute
+
+	signingKeysTTL = 24 * time.Hour
+	// Unknown key IDs refresh the keys, at most this often.
+	signingKeysMinRefresh = 5 * time.Minute
+)
+
+var signingKeys = &signingKeyCache{
+	url:        "https://login.microsoftonline.com/common/discovery/v2.0/keys",
+	httpClient: &http.Client{Timeout: 10 * time.Second},
+}
+
+// signingKeyCache keeps the public keys the validation tokens are signed
+// with, shared by all the remotes of the plugin.
+type signingKeyCache struct {
+	url        string
+	httpClient *http.Client
+
+	lock      sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func (c *signingKeyCache) get(kid string, now time.Time) (*rsa.PublicKey, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := c.keys[kid]
+	expired := now.Sub(c.fetchedAt) > signingKeysTTL
+	if (key == nil && now.Sub(c.fetchedAt) > signingKeysMinRefresh) || expired {
+		keys, err := c.fetch()
+		if err != nil {
+			if key != nil {
+				return key, nil
+			}
+			return nil, err
+		}
+		c.keys = keys
+		c.fetchedAt = now
+		key = keys[kid]
+	}
+	if key == nil {
+		return nil, errors.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (c *signingKeyCache) fetch() (map[string]*rsa.PublicKey, error) {
+	resp, err := c.httpClient.Get(c.url)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch signing keys")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch signing keys: %s", resp.Status)
+	}
+
+	var v struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&v)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signing keys")
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range v.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// verifyValidationToken checks that the token was issued by the remote to
+// this application, for the notifications it comes with.
+func (r *impl) verifyValidationToken(token string, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed validation token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	err := decodeTokenPart(parts[0], &header)
+	if err != nil {
+		return err
+	}
+	if header.Alg != "RS256" {
+		return errors.Errorf("unexpected validation token algorithm %q", header.Alg)
+	}
+
+	key, err := signingKeys.get(header.Kid, now)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errors.Wrap(err, "malformed validation token signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	if err != nil {
+		return errors.Wrap(err, "invalid validation token signature")
+	}
+
+	var claims struct {
+		Aud string `json:"aud"`
+		Iss string `json:"iss"`
+		Azp string `json:"azp"`
+		Exp int64  `json:"exp"`
+		Nbf int64  `json:"nbf"`
+	}
+	err = decodeTokenPart(parts[1], &claims)
+	if err != nil {
+		return err
+	}
+	if claims.Aud != r.conf.OAuth2ClientID {
+		return errors.Errorf("validation token is for another application %q", claims.Aud)
+	}
+	if claims.Azp != graphChangeTrackingAppID {
+		return errors.Errorf("validation token is from an unexpected application %q", claims.Azp)
+	}
+	if !strings.HasPrefix(claims.Iss, validationTokenIssuerPrefix) {
+		return errors.Errorf("validation token is from an unexpected issuer %q", claims.Iss)
+	}
+	switch r.conf.OAuth2Authority {
+	case "common", "organizations", "consumers":
+	default:
+		if claims.Iss != validationTokenIssuerPrefix+r.conf.OAuth2Authority+"/" {
+			return errors.Errorf("validation token is from an unexpected tenant %q", claims.Iss)
+		}
+	}
+	if now.Add(-validationTokenClockSkew).Unix() > claims.Exp {
+		return errors.New("validation token expired")
+	}
+	if now.Add(validationTokenClockSkew).Unix() < claims.Nbf {
+		return errors.New("validation token is not valid yet")
+	}
+	return nil
+}
+
+func decodeTokenPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.Wrap(err, "malformed validation token")
+	}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return errors.Wrap(err, "malformed validation token")
+	}
+	return nil
+}
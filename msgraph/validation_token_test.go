@@ -0,0 +1,145 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const (
+	testClientID = "client_id"
+	testTenantID = "tenant_id"
+	testKeyID    = "key_id"
+)
+
+// setupTestSigningKeys serves the public key of the returned private key as
+// the signing keys of the remote.
+func setupTestSigningKeys(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": testKeyID,
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	prev := signingKeys
+	signingKeys = &signingKeyCache{url: server.URL, httpClient: server.Client()}
+	t.Cleanup(func() { signingKeys = prev })
+	return key
+}
+
+func newTestValidationToken(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(map[string]string{"alg": "RS256", "kid": testKeyID}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newTestValidationClaims(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"aud": testClientID,
+		"azp": graphChangeTrackingAppID,
+		"iss": validationTokenIssuerPrefix + testTenantID + "/",
+		"nbf": now.Add(-time.Hour).Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifyValidationToken(t *testing.T) {
+	now := time.Now()
+	key := setupTestSigningKeys(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		token       func() string
+		expectedErr string
+	}{
+		{
+			name:  "valid token",
+			token: func() string { return newTestValidationToken(t, key, newTestValidationClaims(now)) },
+		},
+		{
+			name:        "signed with another key",
+			token:       func() string { return newTestValidationToken(t, otherKey, newTestValidationClaims(now)) },
+			expectedErr: "invalid validation token signature: crypto/rsa: verification error",
+		},
+		{
+			name: "another application",
+			token: func() string {
+				claims := newTestValidationClaims(now)
+				claims["aud"] = "other_client_id"
+				return newTestValidationToken(t, key, claims)
+			},
+			expectedErr: `validation token is for another application "other_client_id"`,
+		},
+		{
+			name: "another tenant",
+			token: func() string {
+				claims := newTestValidationClaims(now)
+				claims["iss"] = validationTokenIssuerPrefix + "other_tenant_id/"
+				return newTestValidationToken(t, key, claims)
+			},
+			expectedErr: `validation token is from an unexpected tenant "https://sts.windows.net/other_tenant_id/"`,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := newTestValidationClaims(now)
+				claims["exp"] = now.Add(-time.Hour).Unix()
+				return newTestValidationToken(t, key, claims)
+			},
+			expectedErr: "validation token expired",
+		},
+		{
+			name:        "malformed",
+			token:       func() string { return "not a token" },
+			expectedErr: "malformed validation token",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &impl{
+				conf:   &config.Config{StoredConfig: config.StoredConfig{OAuth2ClientID: testClientID, OAuth2Authority: testTenantID}},
+				logger: &bot.NilLogger{},
+			}
+
+			err := r.verifyValidationToken(tc.token(), now)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}